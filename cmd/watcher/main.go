@@ -9,13 +9,13 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
-	"strings"
 	"syscall"
 	"time"
 
 	"ta-watcher/internal/assets"
 	"ta-watcher/internal/config"
 	"ta-watcher/internal/datasource"
+	"ta-watcher/internal/instrument"
 	"ta-watcher/internal/watcher"
 
 	"gopkg.in/yaml.v3"
@@ -137,7 +137,7 @@ func performSingleRun(ctx context.Context, w *watcher.Watcher, cfg *config.Confi
 	// 1. 首先进行资产验证
 	log.Println("📋 开始资产验证...")
 	factory := datasource.NewFactory()
-	dataSource, err := factory.CreateDataSource(cfg.DataSource.Primary, cfg)
+	dataSource, err := factory.CreateFromConfig(cfg)
 	if err != nil {
 		return fmt.Errorf("创建数据源失败: %w", err)
 	}
@@ -183,14 +183,14 @@ func performSingleRun(ctx context.Context, w *watcher.Watcher, cfg *config.Confi
 
 		// 添加基础货币对（如 BTCUSDT）
 		for _, symbol := range validationResult.ValidSymbols {
-			basePair := symbol + cfg.Assets.BaseCurrency
+			basePair := cfg.Assets.Instrument(symbol).Symbol()
 			symbols = append(symbols, basePair)
 		}
 
 		// 添加所有验证通过的币币交易对（如 ETHBTC）
 		for _, pair := range validationResult.ValidPairs {
 			// 避免重复添加基础货币对
-			if !strings.HasSuffix(pair, cfg.Assets.BaseCurrency) {
+			if inst, err := instrument.Parse(pair); err != nil || inst.Quote != cfg.Assets.BaseCurrency {
 				symbols = append(symbols, pair)
 			}
 		}
@@ -231,6 +231,9 @@ func statusReporter(w *watcher.Watcher) {
 		log.Printf("=== 状态报告 ===")
 		log.Printf("运行状态: %t", status["running"])
 		log.Printf("数据源: %s", status["data_source"])
+		if active, ok := status["active_source"]; ok {
+			log.Printf("当前数据源: %s", active)
+		}
 		log.Printf("策略数量: %d", status["strategies"])
 	}
 }
@@ -346,8 +349,12 @@ func printBanner() {
 
 // printConfigSummary 打印配置概要
 func printConfigSummary(cfg *config.Config) {
+	dataSourceDesc := cfg.DataSource.Primary
+	if cfg.DataSource.Fallback != "" {
+		dataSourceDesc += "(备用:" + cfg.DataSource.Fallback + ")"
+	}
 	log.Printf("📊 配置: %s数据源, %d币种, %d时间框架, 邮件:%t",
-		cfg.DataSource.Primary,
+		dataSourceDesc,
 		len(cfg.Assets.Symbols),
 		len(cfg.Assets.Timeframes),
 		cfg.Notifiers.Email.Enabled)
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"ta-watcher/internal/datasource"
+	"ta-watcher/internal/instrument"
 )
 
 // RateCalculator 汇率计算器
@@ -29,14 +30,14 @@ func (rc *RateCalculator) CalculateRate(ctx context.Context, baseSymbol, quoteSy
 	log.Printf("计算 %s/%s 汇率，通过 %s 桥接 (%s)", baseSymbol, quoteSymbol, bridgeCurrency, interval)
 
 	// 获取基础币种对桥接货币的价格 (如 ETH/USDT)
-	basePair := baseSymbol + bridgeCurrency
+	basePair := instrument.New(baseSymbol, bridgeCurrency).Symbol()
 	baseKlines, err := rc.client.GetKlines(ctx, basePair, interval, startTime, endTime, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get %s klines: %w", basePair, err)
 	}
 
 	// 获取报价币种对桥接货币的价格 (如 BTC/USDT)
-	quotePair := quoteSymbol + bridgeCurrency
+	quotePair := instrument.New(quoteSymbol, bridgeCurrency).Symbol()
 	quoteKlines, err := rc.client.GetKlines(ctx, quotePair, interval, startTime, endTime, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get %s klines: %w", quotePair, err)
@@ -151,6 +152,8 @@ func (rc *RateCalculator) alignKlinesByTime(baseKlines, quoteKlines []*datasourc
 			Low:       low,
 			Close:     close,
 			Volume:    0, // 计算的汇率对没有实际交易量
+			IsClosed:  baseK.IsClosed && quoteK.IsClosed,
+			Synthetic: true,
 		}
 
 		// 验证数据有效性：确保Open、Close、High、Low都为正数且High>=Low
@@ -204,7 +207,7 @@ func (rc *RateCalculator) GetAvailableRatePairs(ctx context.Context, symbols []s
 	unavailableSymbols := make([]string, 0)
 
 	for _, symbol := range symbols {
-		pair := symbol + bridgeCurrency
+		pair := instrument.New(symbol, bridgeCurrency).Symbol()
 		_, err := rc.client.GetKlines(ctx, pair, datasource.Timeframe1d, time.Time{}, time.Time{}, 1)
 		if err != nil {
 			log.Printf("币种 %s 对 %s 的交易对不存在: %v", symbol, bridgeCurrency, err)
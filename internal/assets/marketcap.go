@@ -9,6 +9,8 @@ import (
 	"sort"
 	"strings"
 	"time"
+
+	"ta-watcher/internal/instrument"
 )
 
 // MarketCapProvider 市值数据提供者接口
@@ -34,6 +36,11 @@ func NewCoinGeckoProvider(apiKey string) *CoinGeckoProvider {
 	}
 }
 
+// SetHTTPTransport 替换底层 HTTP 传输层（用于录制/回放测试）
+func (p *CoinGeckoProvider) SetHTTPTransport(rt http.RoundTripper) {
+	p.client.Transport = rt
+}
+
 // CoinGeckoResponse CoinGecko API 响应结构
 type CoinGeckoResponse []struct {
 	ID                 string  `json:"id"`
@@ -212,7 +219,7 @@ func GenerateCrossRatePairs(symbols []string, marketCaps map[string]float64, max
 	// 生成符合交易所约定的交易对：市值低的在前（基础货币），市值高的在后（报价货币）
 	for i := 0; i < len(sortedSymbols) && len(pairs) < maxPairs; i++ {
 		for j := i + 1; j < len(sortedSymbols) && len(pairs) < maxPairs; j++ {
-			pair := sortedSymbols[j] + sortedSymbols[i] // 低市值+高市值，如ETHBTC
+			pair := instrument.New(sortedSymbols[j], sortedSymbols[i]).Symbol() // 低市值+高市值，如ETHBTC
 			pairs = append(pairs, pair)
 		}
 	}
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"ta-watcher/internal/datasource"
+	"ta-watcher/internal/httpreplay"
 )
 
 func TestMockMarketCapProvider(t *testing.T) {
@@ -46,6 +47,28 @@ func TestMockMarketCapProvider(t *testing.T) {
 	})
 }
 
+func TestCoinGeckoProvider_Replay(t *testing.T) {
+	provider := NewCoinGeckoProvider("")
+	provider.SetHTTPTransport(httpreplay.NewForTest(t, "coingecko"))
+	ctx := context.Background()
+
+	t.Run("获取市值", func(t *testing.T) {
+		marketCaps, err := provider.GetMarketCaps(ctx, []string{"bitcoin", "ethereum"})
+
+		require.NoError(t, err)
+		assert.Len(t, marketCaps, 2)
+		assert.Greater(t, marketCaps["BTC"], marketCaps["ETH"])
+		assert.Greater(t, marketCaps["ETH"], 0.0)
+	})
+
+	t.Run("触发限流", func(t *testing.T) {
+		_, err := provider.GetMarketCaps(ctx, []string{"bitcoin"})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "429")
+	})
+}
+
 func TestMarketCapManager(t *testing.T) {
 	provider := NewMockMarketCapProvider()
 	manager := NewMarketCapManager(provider, 1*time.Second)
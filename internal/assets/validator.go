@@ -10,6 +10,7 @@ import (
 
 	"ta-watcher/internal/config"
 	"ta-watcher/internal/datasource"
+	"ta-watcher/internal/instrument"
 )
 
 // Validator 资产验证器
@@ -47,7 +48,7 @@ func (v *Validator) ValidateAssets(ctx context.Context) (*ValidationResult, erro
 	// 1. 验证所有币种对基准货币的交易对
 	log.Printf("验证币种对%s的交易对...", v.config.BaseCurrency)
 	for _, symbol := range v.config.Symbols {
-		pair := symbol + v.config.BaseCurrency
+		pair := v.config.Instrument(symbol).Symbol()
 		if err := v.validateSymbolPair(ctx, pair); err != nil {
 			log.Printf("警告: %s 不存在，跳过该币种: %v", pair, err)
 			result.MissingSymbols = append(result.MissingSymbols, symbol)
@@ -121,7 +122,7 @@ func (v *Validator) calculateRatePairs(validSymbols []string) []string {
 			// 生成前几个币种的交叉对
 			for i := 0; i < len(validSymbols) && i < 3; i++ {
 				for j := i + 1; j < len(validSymbols) && j < 4; j++ {
-					pair := validSymbols[i] + validSymbols[j]
+					pair := instrument.New(validSymbols[i], validSymbols[j]).Symbol()
 					pairs = append(pairs, pair)
 				}
 			}
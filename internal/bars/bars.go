@@ -0,0 +1,142 @@
+// Package bars 将逐笔成交聚合为K线
+// 除固定时间间隔外，还支持按成交量、成交额和价格区间采样：行情活跃时生成更多K线，
+// 清淡时更少，突发行情不会被时间K线平均掉。生成的K线与普通K线结构相同，策略可直接使用
+package bars
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"ta-watcher/internal/datasource"
+)
+
+// Type K线采样方式
+type Type string
+
+const (
+	TypeTime   Type = "time"   // 固定时间间隔
+	TypeVolume Type = "volume" // 每累计固定成交量（基础货币数量）
+	TypeDollar Type = "dollar" // 每累计固定成交额（报价货币金额）
+	TypeRange  Type = "range"  // 最高价与最低价之差达到固定价格区间
+)
+
+// Spec K线采样规格
+type Spec struct {
+	Type      Type
+	Interval  time.Duration // 时间K线的间隔
+	Threshold float64       // 成交量/成交额/价格区间阈值
+	Percent   bool          // 价格区间阈值是否为开盘价的百分比
+}
+
+// ParseSpec 解析采样规格，格式为 "类型:参数"
+// 例如 "time:5m"、"volume:100"、"dollar:1000000"、"range:50"、"range:0.5%"
+func ParseSpec(s string) (Spec, error) {
+	kind, param, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if !ok || param == "" {
+		return Spec{}, fmt.Errorf("invalid bar spec %q, expected <type>:<value>", s)
+	}
+
+	spec := Spec{Type: Type(strings.ToLower(kind))}
+	switch spec.Type {
+	case TypeTime:
+		interval, err := parseInterval(param)
+		if err != nil {
+			return Spec{}, fmt.Errorf("invalid bar spec %q: %w", s, err)
+		}
+		spec.Interval = interval
+	case TypeVolume, TypeDollar, TypeRange:
+		if spec.Type == TypeRange && strings.HasSuffix(param, "%") {
+			spec.Percent = true
+			param = strings.TrimSuffix(param, "%")
+		}
+		threshold, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return Spec{}, fmt.Errorf("invalid bar spec %q: %w", s, err)
+		}
+		spec.Threshold = threshold
+	default:
+		return Spec{}, fmt.Errorf("invalid bar spec %q, type must be one of [time volume dollar range]", s)
+	}
+
+	if err := spec.Validate(); err != nil {
+		return Spec{}, fmt.Errorf("invalid bar spec %q: %w", s, err)
+	}
+	return spec, nil
+}
+
+// parseInterval 解析时间间隔，支持 Go 时长格式（30s、90m）和时间框架格式（1d、1w）
+func parseInterval(s string) (time.Duration, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+	// 月线长度不固定，无法按间隔对齐
+	if tf := datasource.Timeframe(s); tf != datasource.Timeframe1M && tf.Duration() > 0 {
+		return tf.Duration(), nil
+	}
+	return 0, fmt.Errorf("invalid interval: %s", s)
+}
+
+// Validate 验证采样规格
+func (s Spec) Validate() error {
+	switch s.Type {
+	case TypeTime:
+		if s.Interval < time.Second {
+			return fmt.Errorf("interval must be at least 1s")
+		}
+	case TypeVolume, TypeDollar, TypeRange:
+		if s.Threshold <= 0 {
+			return fmt.Errorf("threshold must be positive")
+		}
+		if s.Percent && s.Threshold >= 100 {
+			return fmt.Errorf("range percent must be less than 100")
+		}
+	default:
+		return fmt.Errorf("unknown bar type: %s", s.Type)
+	}
+	return nil
+}
+
+// String 返回规格的规范字符串，可由 ParseSpec 解析
+func (s Spec) String() string {
+	switch s.Type {
+	case TypeTime:
+		return string(TypeTime) + ":" + formatInterval(s.Interval)
+	case TypeRange:
+		if s.Percent {
+			return string(TypeRange) + ":" + formatFloat(s.Threshold) + "%"
+		}
+	}
+	return string(s.Type) + ":" + formatFloat(s.Threshold)
+}
+
+// Timeframe 返回在策略和报告中标识该K线序列的时间框架标签，如 "volume:100"
+func (s Spec) Timeframe() datasource.Timeframe {
+	return datasource.Timeframe(s.String())
+}
+
+// formatInterval 以最大的整数单位格式化时间间隔（1w、4h、90s）
+func formatInterval(d time.Duration) string {
+	units := []struct {
+		size  time.Duration
+		label string
+	}{
+		{7 * 24 * time.Hour, "w"},
+		{24 * time.Hour, "d"},
+		{time.Hour, "h"},
+		{time.Minute, "m"},
+		{time.Second, "s"},
+	}
+	for _, u := range units {
+		if d >= u.size && d%u.size == 0 {
+			return fmt.Sprintf("%d%s", d/u.size, u.label)
+		}
+	}
+	return d.String()
+}
+
+// formatFloat 以最短形式格式化阈值
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
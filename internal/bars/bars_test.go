@@ -0,0 +1,187 @@
+package bars
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"ta-watcher/internal/datasource"
+)
+
+var base = time.Date(2025, 10, 16, 0, 0, 0, 0, time.UTC)
+
+// trade 创建测试成交，offset 为相对 base 的秒数
+func trade(offset int, price, quantity float64) *datasource.Trade {
+	return &datasource.Trade{
+		Symbol:   "BTCUSDT",
+		Time:     base.Add(time.Duration(offset) * time.Second),
+		Price:    price,
+		Quantity: quantity,
+	}
+}
+
+func TestParseSpec(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Spec
+		str   string
+	}{
+		{"time:5m", Spec{Type: TypeTime, Interval: 5 * time.Minute}, "time:5m"},
+		{"time:90s", Spec{Type: TypeTime, Interval: 90 * time.Second}, "time:90s"},
+		{"time:1d", Spec{Type: TypeTime, Interval: 24 * time.Hour}, "time:1d"},
+		{"volume:100", Spec{Type: TypeVolume, Threshold: 100}, "volume:100"},
+		{"Dollar:1000000", Spec{Type: TypeDollar, Threshold: 1e6}, "dollar:1000000"},
+		{"range:50", Spec{Type: TypeRange, Threshold: 50}, "range:50"},
+		{"range:0.5%", Spec{Type: TypeRange, Threshold: 0.5, Percent: true}, "range:0.5%"},
+	}
+	for _, tt := range tests {
+		got, err := ParseSpec(tt.input)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseSpec(%q) = %+v, %v, want %+v", tt.input, got, err, tt.want)
+			continue
+		}
+		if got.String() != tt.str || got.Timeframe() != datasource.Timeframe(tt.str) {
+			t.Errorf("ParseSpec(%q).String() = %s, want %s", tt.input, got.String(), tt.str)
+		}
+	}
+
+	for _, invalid := range []string{"", "volume", "volume:", "volume:0", "dollar:-5", "range:100%", "time:1M", "time:500ms", "tick:10"} {
+		if _, err := ParseSpec(invalid); err == nil {
+			t.Errorf("ParseSpec(%q) should fail", invalid)
+		}
+	}
+}
+
+func TestBuild_TimeBars(t *testing.T) {
+	spec := Spec{Type: TypeTime, Interval: time.Minute}
+	trades := []*datasource.Trade{
+		trade(5, 100, 1), trade(30, 102, 1), trade(59, 99, 2),
+		trade(61, 101, 1),
+		trade(185, 103, 1), // 跳过无成交的第3分钟
+	}
+
+	klines := Build("BTCUSDT", trades, spec)
+	if len(klines) != 3 {
+		t.Fatalf("expected 3 bars, got %d", len(klines))
+	}
+
+	first := klines[0]
+	if !first.OpenTime.Equal(base) || !first.CloseTime.Equal(base.Add(time.Minute-time.Millisecond)) {
+		t.Errorf("unexpected first bar times: %v - %v", first.OpenTime, first.CloseTime)
+	}
+	if first.Open != 100 || first.High != 102 || first.Low != 99 || first.Close != 99 || first.Volume != 4 || !first.IsClosed {
+		t.Errorf("unexpected first bar %+v", first)
+	}
+	if !klines[2].OpenTime.Equal(base.Add(3*time.Minute)) || klines[2].IsClosed {
+		t.Errorf("last bar should be the open 4th minute, got %+v", klines[2])
+	}
+}
+
+func TestBuild_VolumeBarsSplitTrades(t *testing.T) {
+	spec := Spec{Type: TypeVolume, Threshold: 10}
+	trades := []*datasource.Trade{trade(0, 100, 4), trade(1, 101, 25), trade(2, 102, 3)}
+
+	klines := Build("BTCUSDT", trades, spec)
+	if len(klines) != 4 {
+		t.Fatalf("expected 4 bars, got %d", len(klines))
+	}
+	for i, k := range klines[:3] {
+		if k.Volume != 10 || !k.IsClosed {
+			t.Errorf("bar %d should be a closed 10-unit bar, got %+v", i, k)
+		}
+	}
+	// 25 = 6（补满第1根）+ 10 + 9，剩余 9 与下一笔 3 超出阈值 2
+	if last := klines[3]; last.Volume != 2 || last.IsClosed || last.Close != 102 {
+		t.Errorf("unexpected partial bar %+v", last)
+	}
+	if klines[0].Open != 100 || klines[0].Close != 101 {
+		t.Errorf("first bar should span both trades, got %+v", klines[0])
+	}
+}
+
+func TestBuild_DollarBars(t *testing.T) {
+	spec := Spec{Type: TypeDollar, Threshold: 1000}
+	trades := []*datasource.Trade{trade(0, 100, 6), trade(1, 200, 4), trade(2, 250, 1)}
+
+	klines := Build("BTCUSDT", trades, spec)
+	if len(klines) != 2 {
+		t.Fatalf("expected 2 bars, got %d", len(klines))
+	}
+	// 600 + 2*200 = 1000 完成第1根，其余 2*200 + 250 = 650 未完成
+	if k := klines[0]; !k.IsClosed || math.Abs(k.Volume-8) > 1e-9 || k.High != 200 {
+		t.Errorf("unexpected first dollar bar %+v", k)
+	}
+	if k := klines[1]; k.IsClosed || math.Abs(k.Volume-3) > 1e-9 {
+		t.Errorf("unexpected partial dollar bar %+v", k)
+	}
+}
+
+func TestBuild_RangeBars(t *testing.T) {
+	trades := []*datasource.Trade{
+		trade(0, 100, 1), trade(1, 102, 1), trade(2, 105, 1),
+		trade(3, 104, 1), trade(4, 101, 1), trade(5, 99, 1),
+	}
+
+	klines := Build("BTCUSDT", trades, Spec{Type: TypeRange, Threshold: 5})
+	if len(klines) != 2 {
+		t.Fatalf("expected 2 bars, got %d", len(klines))
+	}
+	if k := klines[0]; k.High-k.Low != 5 || k.Close != 105 || !k.CloseTime.Equal(base.Add(2*time.Second)) {
+		t.Errorf("unexpected first range bar %+v", k)
+	}
+	if k := klines[1]; k.Open != 104 || k.Low != 99 || !k.IsClosed {
+		t.Errorf("unexpected second range bar %+v", k)
+	}
+
+	// 1% 阈值以开盘价 100 为基准
+	percent := Build("BTCUSDT", trades[:2], Spec{Type: TypeRange, Threshold: 1, Percent: true})
+	if len(percent) != 1 || !percent[0].IsClosed {
+		t.Errorf("2%% move should close a 1%% range bar, got %+v", percent)
+	}
+}
+
+func TestBuilder_IgnoresInvalidTrades(t *testing.T) {
+	builder := NewBuilder("BTCUSDT", Spec{Type: TypeVolume, Threshold: 1})
+	if done := builder.Add(nil); done != nil {
+		t.Error("nil trade should be ignored")
+	}
+	if done := builder.Add(trade(0, 0, 5)); done != nil || builder.Current() != nil {
+		t.Error("zero price trade should be ignored")
+	}
+}
+
+func TestSeries_AccumulatesAcrossPolls(t *testing.T) {
+	// withID 为测试成交设置ID
+	withID := func(id string, tr *datasource.Trade) *datasource.Trade {
+		tr.ID = id
+		return tr
+	}
+	series := NewSeries("BTCUSDT", Spec{Type: TypeVolume, Threshold: 10}, 2)
+
+	first := []*datasource.Trade{withID("1", trade(0, 100, 4)), withID("2", trade(1, 101, 4)), withID("3", trade(2, 102, 4)), withID("4", trade(2, 103, 4))}
+	if added, gap := series.Add(first); added != 4 || gap {
+		t.Fatalf("first poll: added=%d gap=%v", added, gap)
+	}
+
+	// 第二次轮询与上次重叠，同一时刻的已处理成交按ID跳过
+	second := []*datasource.Trade{withID("3", trade(2, 102, 4)), withID("4", trade(2, 103, 4)), withID("5", trade(2, 104, 4)), withID("6", trade(3, 105, 8))}
+	if added, gap := series.Add(second); added != 2 || gap {
+		t.Fatalf("second poll: added=%d gap=%v", added, gap)
+	}
+
+	// 累计 28 个单位：2 根已完成的K线 + 8 个单位的未完成K线
+	klines := series.Klines()
+	if len(klines) != 3 || !klines[1].IsClosed || klines[2].IsClosed || klines[2].Volume != 8 {
+		t.Fatalf("unexpected bars %+v", klines)
+	}
+
+	// 与上次没有重叠时提示可能遗漏成交；25 个单位完成 3 根K线，只保留最近 2 根
+	third := []*datasource.Trade{withID("9", trade(10, 106, 25))}
+	if added, gap := series.Add(third); added != 1 || !gap {
+		t.Fatalf("third poll: added=%d gap=%v", added, gap)
+	}
+	klines = series.Klines()
+	if len(klines) != 3 || klines[0].Open != 106 || klines[1].Open != 106 || klines[2].Volume != 3 {
+		t.Errorf("series should keep the latest 2 closed bars plus the open bar, got %+v", klines)
+	}
+}
@@ -0,0 +1,173 @@
+package bars
+
+import (
+	"time"
+
+	"ta-watcher/internal/datasource"
+)
+
+// splitEpsilon 拆分成交后剩余数量的相对误差容忍度，避免浮点误差产生极小的K线
+const splitEpsilon = 1e-9
+
+// Builder 按采样规格将按时间升序到达的成交聚合为K线
+type Builder struct {
+	symbol  string
+	spec    Spec
+	current *datasource.Kline
+	filled  float64 // 当前K线已累计的成交量或成交额
+}
+
+// NewBuilder 创建K线构建器
+func NewBuilder(symbol string, spec Spec) *Builder {
+	return &Builder{symbol: symbol, spec: spec}
+}
+
+// Add 加入一笔成交，返回因此完成的K线（可能为空）
+// 成交量和成交额K线会把超出阈值的成交拆分到下一根，单笔大额成交可能一次完成多根K线
+func (b *Builder) Add(trade *datasource.Trade) []*datasource.Kline {
+	if trade == nil || trade.Price <= 0 || trade.Quantity <= 0 {
+		return nil
+	}
+
+	switch b.spec.Type {
+	case TypeTime:
+		return b.addTime(trade)
+	case TypeVolume, TypeDollar:
+		return b.addSized(trade)
+	case TypeRange:
+		return b.addRange(trade)
+	default:
+		return nil
+	}
+}
+
+// Current 返回尚未完成的K线副本，没有时返回 nil
+func (b *Builder) Current() *datasource.Kline {
+	if b.current == nil {
+		return nil
+	}
+	k := *b.current
+	return &k
+}
+
+// addTime 时间K线：开盘时间按间隔对齐，成交进入下一个间隔时完成当前K线
+// 没有成交的间隔不会生成K线
+func (b *Builder) addTime(trade *datasource.Trade) []*datasource.Kline {
+	var done []*datasource.Kline
+	start := trade.Time.Truncate(b.spec.Interval)
+	if b.current != nil && start.After(b.current.OpenTime) {
+		done = append(done, b.finish())
+	}
+	if b.current == nil {
+		b.open(trade, start)
+		b.current.CloseTime = start.Add(b.spec.Interval - time.Millisecond)
+	}
+	b.fill(trade, trade.Quantity)
+	return done
+}
+
+// addSized 成交量/成交额K线：累计达到阈值时完成当前K线，超出部分计入下一根
+func (b *Builder) addSized(trade *datasource.Trade) []*datasource.Kline {
+	var done []*datasource.Kline
+	remaining := trade.Quantity
+	for remaining > trade.Quantity*splitEpsilon {
+		if b.current == nil {
+			b.open(trade, trade.Time)
+		}
+
+		// 当前K线还能容纳的基础货币数量
+		capacity := b.spec.Threshold - b.filled
+		if b.spec.Type == TypeDollar {
+			capacity /= trade.Price
+		}
+
+		if remaining < capacity {
+			b.fill(trade, remaining)
+			b.filled += b.amount(trade, remaining)
+			break
+		}
+
+		b.fill(trade, capacity)
+		remaining -= capacity
+		done = append(done, b.finish())
+	}
+	return done
+}
+
+// amount 返回数量对应的累计量（成交量K线为数量，成交额K线为金额）
+func (b *Builder) amount(trade *datasource.Trade, quantity float64) float64 {
+	if b.spec.Type == TypeDollar {
+		return quantity * trade.Price
+	}
+	return quantity
+}
+
+// addRange 价格区间K线：最高价与最低价之差达到阈值时完成当前K线
+// 百分比阈值以K线开盘价为基准
+func (b *Builder) addRange(trade *datasource.Trade) []*datasource.Kline {
+	if b.current == nil {
+		b.open(trade, trade.Time)
+	}
+	b.fill(trade, trade.Quantity)
+
+	limit := b.spec.Threshold
+	if b.spec.Percent {
+		limit = b.current.Open * b.spec.Threshold / 100
+	}
+	if b.current.High-b.current.Low >= limit {
+		return []*datasource.Kline{b.finish()}
+	}
+	return nil
+}
+
+// open 以成交价开始一根新K线
+func (b *Builder) open(trade *datasource.Trade, openTime time.Time) {
+	b.current = &datasource.Kline{
+		Symbol:    b.symbol,
+		OpenTime:  openTime,
+		CloseTime: trade.Time,
+		Open:      trade.Price,
+		High:      trade.Price,
+		Low:       trade.Price,
+		Close:     trade.Price,
+	}
+	b.filled = 0
+}
+
+// fill 将成交计入当前K线，非时间K线的收盘时间为最后一笔成交时间
+func (b *Builder) fill(trade *datasource.Trade, quantity float64) {
+	k := b.current
+	if trade.Price > k.High {
+		k.High = trade.Price
+	}
+	if trade.Price < k.Low {
+		k.Low = trade.Price
+	}
+	k.Close = trade.Price
+	k.Volume += quantity
+	if b.spec.Type != TypeTime {
+		k.CloseTime = trade.Time
+	}
+}
+
+// finish 完成当前K线
+func (b *Builder) finish() *datasource.Kline {
+	k := b.current
+	k.IsClosed = true
+	b.current = nil
+	b.filled = 0
+	return k
+}
+
+// Build 将按时间升序排列的成交聚合为K线，最后一根未完成的K线 IsClosed 为 false
+func Build(symbol string, trades []*datasource.Trade, spec Spec) []*datasource.Kline {
+	builder := NewBuilder(symbol, spec)
+	klines := make([]*datasource.Kline, 0)
+	for _, trade := range trades {
+		klines = append(klines, builder.Add(trade)...)
+	}
+	if current := builder.Current(); current != nil {
+		klines = append(klines, current)
+	}
+	return klines
+}
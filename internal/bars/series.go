@@ -0,0 +1,90 @@
+package bars
+
+import (
+	"time"
+
+	"ta-watcher/internal/datasource"
+)
+
+// Series 跨多次轮询增量维护的K线序列
+// 交易所单次只返回最近约1000笔成交，成交量/成交额/价格区间K线需要持续累积才能得到足够的数据点。
+// 相邻两次获取的近期成交通常有重叠，Add 会跳过已处理的成交，只把新成交交给 Builder
+type Series struct {
+	builder  *Builder
+	closed   []*datasource.Kline
+	limit    int             // 保留的已完成K线数量上限
+	lastTime time.Time       // 已处理的最后一笔成交时间
+	lastIDs  map[string]bool // lastTime 时刻已处理的成交ID
+}
+
+// NewSeries 创建K线序列，limit 为保留的已完成K线数量上限
+func NewSeries(symbol string, spec Spec, limit int) *Series {
+	return &Series{
+		builder: NewBuilder(symbol, spec),
+		limit:   limit,
+		lastIDs: make(map[string]bool),
+	}
+}
+
+// Add 加入一批按时间升序排列的近期成交，返回新处理的成交笔数
+// gap 为 true 表示本批最早的成交晚于上次处理的最后一笔，两次获取之间可能有成交遗漏
+func (s *Series) Add(trades []*datasource.Trade) (added int, gap bool) {
+	if len(trades) == 0 {
+		return 0, false
+	}
+	gap = !s.lastTime.IsZero() && trades[0].Time.After(s.lastTime)
+
+	// 只与上一批的处理进度比较，同一批内时间相同的成交都计入
+	lastTime, lastIDs := s.lastTime, s.lastIDs
+	s.lastIDs = make(map[string]bool)
+	for _, trade := range trades {
+		if !isNewTrade(trade, lastTime, lastIDs) {
+			continue
+		}
+		if trade.Time.After(s.lastTime) {
+			s.lastTime = trade.Time
+			clear(s.lastIDs)
+		}
+		s.lastIDs[trade.ID] = true
+
+		s.closed = append(s.closed, s.builder.Add(trade)...)
+		added++
+	}
+	if added == 0 {
+		s.lastIDs = lastIDs
+		return 0, gap
+	}
+	if s.lastTime.Equal(lastTime) {
+		// 最后一笔仍在同一时刻，保留上一批已处理的ID
+		for id := range lastIDs {
+			s.lastIDs[id] = true
+		}
+	}
+
+	if s.limit > 0 && len(s.closed) > s.limit {
+		s.closed = append([]*datasource.Kline(nil), s.closed[len(s.closed)-s.limit:]...)
+	}
+	return added, gap
+}
+
+// isNewTrade 判断成交是否晚于已处理的进度
+// 与已处理的最后一笔同一时刻的成交按ID区分，缺少ID时视为已处理，宁可少计也不重复计入
+func isNewTrade(trade *datasource.Trade, lastTime time.Time, lastIDs map[string]bool) bool {
+	if trade.Time.Before(lastTime) {
+		return false
+	}
+	if trade.Time.Equal(lastTime) {
+		return trade.ID != "" && !lastIDs[trade.ID]
+	}
+	return true
+}
+
+// Klines 返回已完成的K线，以及最后一根未完成的K线（IsClosed 为 false）
+func (s *Series) Klines() []*datasource.Kline {
+	klines := make([]*datasource.Kline, 0, len(s.closed)+1)
+	klines = append(klines, s.closed...)
+	if current := s.builder.Current(); current != nil {
+		klines = append(klines, current)
+	}
+	return klines
+}
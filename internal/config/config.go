@@ -3,9 +3,12 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"ta-watcher/internal/instrument"
 )
 
 // DefaultConfig 返回默认配置
@@ -16,6 +19,11 @@ func DefaultConfig() *Config {
 			Fallback:   "",
 			Timeout:    30 * time.Second,
 			MaxRetries: 3,
+
+			FailoverCooldown: 5 * time.Minute,
+			Consensus: ConsensusConfig{
+				DivergenceBps: 50,
+			},
 			Binance: BinanceConfig{
 				RateLimit: RateLimitConfig{
 					RequestsPerMinute: 1200,
@@ -30,6 +38,35 @@ func DefaultConfig() *Config {
 					MaxRetries:        10,
 				},
 			},
+			OKX: OKXConfig{
+				RateLimit: RateLimitConfig{
+					RequestsPerMinute: 300,
+					RetryDelay:        2 * time.Second,
+					MaxRetries:        3,
+				},
+			},
+			Kraken: KrakenConfig{
+				RateLimit: RateLimitConfig{
+					RequestsPerMinute: 60,
+					RetryDelay:        5 * time.Second,
+					MaxRetries:        3,
+				},
+			},
+			Bybit: BybitConfig{
+				RateLimit: RateLimitConfig{
+					RequestsPerMinute: 600,
+					RetryDelay:        2 * time.Second,
+					MaxRetries:        3,
+				},
+			},
+			File: FileConfig{
+				Directory: "data",
+			},
+			Cache: CacheConfig{
+				Enabled:   false,
+				Directory: ".cache/klines",
+			},
+			WeekStart: "monday",
 		},
 		Binance: BinanceConfig{
 			RateLimit: RateLimitConfig{
@@ -44,6 +81,13 @@ func DefaultConfig() *Config {
 			BufferSize:    100,
 			LogLevel:      "info",
 			EnableMetrics: true,
+			Streaming:     false,
+
+			ClosedCandlesOnly: false,
+			DataQuality:       "forward_fill",
+
+			OrderBookDepth:    0,
+			DepthRangePercent: 1.0,
 		},
 		Notifiers: NotifiersConfig{
 			Email: EmailConfig{
@@ -211,7 +255,7 @@ func (c *DataSourceConfig) Validate() error {
 		return fmt.Errorf("primary datasource cannot be empty")
 	}
 
-	supportedSources := []string{"binance", "coinbase"}
+	supportedSources := []string{"binance", "coinbase", "okx", "kraken", "bybit", "file"}
 	primaryValid := false
 	for _, source := range supportedSources {
 		if c.Primary == source {
@@ -223,6 +267,31 @@ func (c *DataSourceConfig) Validate() error {
 		return fmt.Errorf("unsupported primary datasource: %s", c.Primary)
 	}
 
+	// 验证备用数据源（可选）
+	if c.Fallback != "" {
+		fallbackValid := false
+		for _, source := range supportedSources {
+			if c.Fallback == source {
+				fallbackValid = true
+				break
+			}
+		}
+		if !fallbackValid {
+			return fmt.Errorf("unsupported fallback datasource: %s", c.Fallback)
+		}
+		if c.Fallback == c.Primary {
+			return fmt.Errorf("fallback datasource must differ from primary: %s", c.Fallback)
+		}
+	}
+
+	if c.FailoverCooldown < 0 {
+		return fmt.Errorf("failover_cooldown cannot be negative")
+	}
+
+	if err := c.Consensus.Validate(supportedSources); err != nil {
+		return fmt.Errorf("consensus config: %w", err)
+	}
+
 	// 验证 Binance 配置
 	if err := c.Binance.Validate(); err != nil {
 		return fmt.Errorf("binance config: %w", err)
@@ -233,6 +302,99 @@ func (c *DataSourceConfig) Validate() error {
 		return fmt.Errorf("coinbase config: %w", err)
 	}
 
+	switch c.Derivatives {
+	case "", "binance", "okx":
+	default:
+		return fmt.Errorf("unsupported derivatives datasource: %s", c.Derivatives)
+	}
+
+	// OKX、Kraken、Bybit 配置仅在被选用时验证
+	if c.uses("okx") || c.Derivatives == "okx" {
+		if err := c.OKX.Validate(); err != nil {
+			return fmt.Errorf("okx config: %w", err)
+		}
+	}
+	if c.uses("kraken") {
+		if err := c.Kraken.Validate(); err != nil {
+			return fmt.Errorf("kraken config: %w", err)
+		}
+	}
+	if c.uses("bybit") {
+		if err := c.Bybit.Validate(); err != nil {
+			return fmt.Errorf("bybit config: %w", err)
+		}
+	}
+	if c.uses("file") {
+		if err := c.File.Validate(); err != nil {
+			return fmt.Errorf("file config: %w", err)
+		}
+	}
+	if err := c.Cache.Validate(); err != nil {
+		return fmt.Errorf("cache config: %w", err)
+	}
+	if _, err := c.WeekStartDay(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// WeekStartDay 解析周线起始日，未配置时为周一
+func (c *DataSourceConfig) WeekStartDay() (time.Weekday, error) {
+	if c.WeekStart == "" {
+		return time.Monday, nil
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(c.WeekStart, day.String()) {
+			return day, nil
+		}
+	}
+	return time.Monday, fmt.Errorf("invalid week_start: %s", c.WeekStart)
+}
+
+// uses 判断数据源是否被选为主数据源、备用数据源或共识数据源
+func (c *DataSourceConfig) uses(source string) bool {
+	if c.Primary == source || c.Fallback == source {
+		return true
+	}
+	for _, s := range c.Consensus.Sources {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate 验证共识配置
+func (c *ConsensusConfig) Validate(supportedSources []string) error {
+	if len(c.Sources) == 0 {
+		return nil
+	}
+	if len(c.Sources) < 2 {
+		return fmt.Errorf("at least 2 sources are required")
+	}
+
+	seen := make(map[string]bool, len(c.Sources))
+	for _, source := range c.Sources {
+		valid := false
+		for _, supported := range supportedSources {
+			if source == supported {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("unsupported datasource: %s", source)
+		}
+		if seen[source] {
+			return fmt.Errorf("duplicate datasource: %s", source)
+		}
+		seen[source] = true
+	}
+
+	if c.DivergenceBps <= 0 {
+		return fmt.Errorf("divergence_bps must be positive")
+	}
 	return nil
 }
 
@@ -258,6 +420,51 @@ func (c *CoinbaseConfig) Validate() error {
 	return nil
 }
 
+// Validate 验证 OKX 配置
+func (c *OKXConfig) Validate() error {
+	return c.RateLimit.Validate()
+}
+
+// Validate 验证 Kraken 配置
+func (c *KrakenConfig) Validate() error {
+	return c.RateLimit.Validate()
+}
+
+// Validate 验证 Bybit 配置
+func (c *BybitConfig) Validate() error {
+	return c.RateLimit.Validate()
+}
+
+// Validate 验证本地文件数据源配置
+func (c *FileConfig) Validate() error {
+	if c.Directory == "" {
+		return fmt.Errorf("directory cannot be empty")
+	}
+	return nil
+}
+
+// Validate 验证K线缓存配置
+func (c *CacheConfig) Validate() error {
+	if c.Enabled && c.Directory == "" {
+		return fmt.Errorf("directory cannot be empty when cache is enabled")
+	}
+	return nil
+}
+
+// Validate 验证限流配置
+func (c *RateLimitConfig) Validate() error {
+	if c.RequestsPerMinute <= 0 {
+		return fmt.Errorf("requests_per_minute must be positive")
+	}
+	if c.MaxRetries < 0 {
+		return fmt.Errorf("max_retries cannot be negative")
+	}
+	if c.Burst < 0 {
+		return fmt.Errorf("burst cannot be negative")
+	}
+	return nil
+}
+
 // Validate 验证 Watcher 配置
 func (c *WatcherConfig) Validate() error {
 	if c.Interval <= 0 {
@@ -280,6 +487,17 @@ func (c *WatcherConfig) Validate() error {
 	if !valid {
 		return fmt.Errorf("invalid log_level: %s, must be one of %v", c.LogLevel, validLogLevels)
 	}
+	switch c.DataQuality {
+	case "", "forward_fill", "drop", "fail":
+	default:
+		return fmt.Errorf("invalid data_quality: %s, must be one of [forward_fill drop fail]", c.DataQuality)
+	}
+	if c.OrderBookDepth < 0 {
+		return fmt.Errorf("order_book_depth cannot be negative")
+	}
+	if c.OrderBookDepth > 0 && (c.DepthRangePercent <= 0 || c.DepthRangePercent >= 100) {
+		return fmt.Errorf("depth_range_percent must be between 0 and 100")
+	}
 	return nil
 }
 
@@ -376,6 +594,14 @@ func (a *AssetsConfig) Validate() error {
 	if a.BaseCurrency == "" {
 		return fmt.Errorf("base_currency cannot be empty")
 	}
+	if !instrument.ValidAsset(a.BaseCurrency) {
+		return fmt.Errorf("invalid base_currency: %s", a.BaseCurrency)
+	}
+	for _, symbol := range a.Symbols {
+		if !instrument.ValidAsset(symbol) {
+			return fmt.Errorf("invalid symbol: %s, must be an asset code such as BTC", symbol)
+		}
+	}
 
 	// 验证市值更新间隔
 	if a.MarketCapUpdateInterval <= 0 {
@@ -385,12 +611,22 @@ func (a *AssetsConfig) Validate() error {
 	return nil
 }
 
+// Instrument 返回币种对基准货币的规范交易品种，如 BTC -> BTC/USDT
+func (a *AssetsConfig) Instrument(symbol string) instrument.Instrument {
+	return instrument.New(symbol, a.BaseCurrency)
+}
+
 // logRateLimitConfig 打印限流配置的调试日志
 func logRateLimitConfig(config *Config) {
 	fmt.Printf("🔧 限流配置调试信息:\n")
 	fmt.Printf("├── 数据源配置:\n")
 	fmt.Printf("│   ├── 主数据源: %s\n", config.DataSource.Primary)
 	fmt.Printf("│   ├── 备用数据源: %s\n", config.DataSource.Fallback)
+	fmt.Printf("│   ├── 切换冷却: %v\n", config.DataSource.FailoverCooldown)
+	fmt.Printf("│   ├── K线缓存: %v (%s)\n", config.DataSource.Cache.Enabled, config.DataSource.Cache.Directory)
+	fmt.Printf("│   ├── 周线起始日: %s\n", config.DataSource.WeekStart)
+	fmt.Printf("│   ├── 衍生品数据源: %s\n", config.DataSource.Derivatives)
+	fmt.Printf("│   ├── 共识数据源: %v (分歧阈值 %.0f bps)\n", config.DataSource.Consensus.Sources, config.DataSource.Consensus.DivergenceBps)
 	fmt.Printf("│   ├── 超时时间: %v\n", config.DataSource.Timeout)
 	fmt.Printf("│   └── 最大重试: %d\n", config.DataSource.MaxRetries)
 	fmt.Printf("├── Binance 限流配置:\n")
@@ -401,6 +637,18 @@ func logRateLimitConfig(config *Config) {
 	fmt.Printf("│   ├── 每分钟请求数: %d\n", config.DataSource.Coinbase.RateLimit.RequestsPerMinute)
 	fmt.Printf("│   ├── 重试延迟: %v\n", config.DataSource.Coinbase.RateLimit.RetryDelay)
 	fmt.Printf("│   └── 最大重试: %d\n", config.DataSource.Coinbase.RateLimit.MaxRetries)
+	fmt.Printf("├── OKX 限流配置:\n")
+	fmt.Printf("│   ├── 每分钟请求数: %d\n", config.DataSource.OKX.RateLimit.RequestsPerMinute)
+	fmt.Printf("│   ├── 重试延迟: %v\n", config.DataSource.OKX.RateLimit.RetryDelay)
+	fmt.Printf("│   └── 最大重试: %d\n", config.DataSource.OKX.RateLimit.MaxRetries)
+	fmt.Printf("├── Kraken 限流配置:\n")
+	fmt.Printf("│   ├── 每分钟请求数: %d\n", config.DataSource.Kraken.RateLimit.RequestsPerMinute)
+	fmt.Printf("│   ├── 重试延迟: %v\n", config.DataSource.Kraken.RateLimit.RetryDelay)
+	fmt.Printf("│   └── 最大重试: %d\n", config.DataSource.Kraken.RateLimit.MaxRetries)
+	fmt.Printf("├── Bybit 限流配置:\n")
+	fmt.Printf("│   ├── 每分钟请求数: %d\n", config.DataSource.Bybit.RateLimit.RequestsPerMinute)
+	fmt.Printf("│   ├── 重试延迟: %v\n", config.DataSource.Bybit.RateLimit.RetryDelay)
+	fmt.Printf("│   └── 最大重试: %d\n", config.DataSource.Bybit.RateLimit.MaxRetries)
 	fmt.Printf("└── 通知配置:\n")
 	fmt.Printf("    └── 邮件启用: %t\n", config.Notifiers.Email.Enabled)
 	if config.Notifiers.Email.Enabled {
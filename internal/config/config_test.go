@@ -22,6 +22,7 @@ func TestDefaultConfig(t *testing.T) {
 	assert.Equal(t, 100, config.Watcher.BufferSize)
 	assert.Equal(t, "info", config.Watcher.LogLevel)
 	assert.True(t, config.Watcher.EnableMetrics)
+	assert.False(t, config.Watcher.ClosedCandlesOnly, "closed_candles_only 默认关闭，保持原有行为")
 
 	assert.False(t, config.Notifiers.Email.Enabled)
 	assert.Equal(t, "smtp.gmail.com", config.Notifiers.Email.SMTP.Host)
@@ -78,6 +79,57 @@ func TestConfigValidation(t *testing.T) {
 			wantErr: true,
 			errMsg:  "invalid log_level",
 		},
+		{
+			name: "invalid data quality policy",
+			config: func() *Config {
+				c := DefaultConfig()
+				c.Watcher.DataQuality = "ignore"
+				return c
+			}(),
+			wantErr: true,
+			errMsg:  "invalid data_quality",
+		},
+		{
+			name: "consensus with single source",
+			config: func() *Config {
+				c := DefaultConfig()
+				c.DataSource.Consensus.Sources = []string{"binance"}
+				return c
+			}(),
+			wantErr: true,
+			errMsg:  "at least 2 sources are required",
+		},
+		{
+			name: "consensus with unsupported source",
+			config: func() *Config {
+				c := DefaultConfig()
+				c.DataSource.Consensus.Sources = []string{"binance", "ftx"}
+				return c
+			}(),
+			wantErr: true,
+			errMsg:  "unsupported datasource: ftx",
+		},
+		{
+			name: "trading pair in symbols",
+			config: func() *Config {
+				c := DefaultConfig()
+				c.Assets.Symbols = []string{"BTC", "ETH/USDT"}
+				return c
+			}(),
+			wantErr: true,
+			errMsg:  "invalid symbol: ETH/USDT",
+		},
+		{
+			name: "invalid depth range",
+			config: func() *Config {
+				c := DefaultConfig()
+				c.Watcher.OrderBookDepth = 50
+				c.Watcher.DepthRangePercent = 0
+				return c
+			}(),
+			wantErr: true,
+			errMsg:  "depth_range_percent must be between 0 and 100",
+		},
 		{
 			name: "empty assets",
 			config: func() *Config {
@@ -88,6 +140,46 @@ func TestConfigValidation(t *testing.T) {
 			wantErr: true,
 			errMsg:  "symbols list cannot be empty",
 		},
+		{
+			name: "unsupported fallback datasource",
+			config: func() *Config {
+				c := DefaultConfig()
+				c.DataSource.Fallback = "unknown"
+				return c
+			}(),
+			wantErr: true,
+			errMsg:  "unsupported fallback datasource",
+		},
+		{
+			name: "fallback same as primary",
+			config: func() *Config {
+				c := DefaultConfig()
+				c.DataSource.Fallback = c.DataSource.Primary
+				return c
+			}(),
+			wantErr: true,
+			errMsg:  "fallback datasource must differ from primary",
+		},
+		{
+			name: "invalid week start",
+			config: func() *Config {
+				c := DefaultConfig()
+				c.DataSource.WeekStart = "funday"
+				return c
+			}(),
+			wantErr: true,
+			errMsg:  "invalid week_start",
+		},
+		{
+			name: "invalid derivatives source",
+			config: func() *Config {
+				c := DefaultConfig()
+				c.DataSource.Derivatives = "kraken"
+				return c
+			}(),
+			wantErr: true,
+			errMsg:  "unsupported derivatives datasource",
+		},
 	}
 
 	for _, tt := range tests {
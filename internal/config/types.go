@@ -48,17 +48,44 @@ type RateLimitConfig struct {
 	RequestsPerMinute int           `yaml:"requests_per_minute"` // 每分钟请求数
 	RetryDelay        time.Duration `yaml:"retry_delay"`         // 重试延迟
 	MaxRetries        int           `yaml:"max_retries"`         // 最大重试次数
+	Burst             int           `yaml:"burst"`               // 令牌桶容量（允许的突发请求数），0 表示取每秒请求数
 }
 
 // DataSourceConfig 数据源配置
 type DataSourceConfig struct {
-	Primary    string        `yaml:"primary"`     // 主数据源: binance, coinbase
+	Primary    string        `yaml:"primary"`     // 主数据源: binance, coinbase, okx, kraken, bybit, file
 	Fallback   string        `yaml:"fallback"`    // 备用数据源
 	Timeout    time.Duration `yaml:"timeout"`     // 请求超时时间
 	MaxRetries int           `yaml:"max_retries"` // 最大重试次数
 
+	FailoverCooldown time.Duration `yaml:"failover_cooldown"` // 切换到备用数据源后重试主数据源的冷却时间
+
 	Binance  BinanceConfig  `yaml:"binance"`  // Binance 配置
 	Coinbase CoinbaseConfig `yaml:"coinbase"` // Coinbase 配置
+	OKX      OKXConfig      `yaml:"okx"`      // OKX 配置
+	Kraken   KrakenConfig   `yaml:"kraken"`   // Kraken 配置
+	Bybit    BybitConfig    `yaml:"bybit"`    // Bybit 配置
+	File     FileConfig     `yaml:"file"`     // 本地文件数据源配置
+
+	Cache CacheConfig `yaml:"cache"` // K线持久化缓存配置
+
+	WeekStart string `yaml:"week_start"` // 重采样周线的起始日: monday ~ sunday
+
+	Derivatives string `yaml:"derivatives"` // 永续合约数据源（资金费率、持仓量、多空比）: binance, okx，留空不启用
+
+	Consensus ConsensusConfig `yaml:"consensus"` // 多数据源共识配置
+}
+
+// ConsensusConfig 多数据源共识配置
+type ConsensusConfig struct {
+	Sources       []string `yaml:"sources"`        // 参与共识的数据源（至少2个），留空不启用，启用时替代主数据源
+	DivergenceBps float64  `yaml:"divergence_bps"` // 最新已收盘K线的分歧告警阈值（基点）：三个及以上数据源比较偏离中位数，两个数据源比较两者之差
+}
+
+// CacheConfig K线持久化缓存配置
+type CacheConfig struct {
+	Enabled   bool   `yaml:"enabled"`   // 是否启用缓存
+	Directory string `yaml:"directory"` // 缓存目录
 }
 
 // CoinbaseConfig Coinbase 配置
@@ -66,6 +93,26 @@ type CoinbaseConfig struct {
 	RateLimit RateLimitConfig `yaml:"rate_limit"`
 }
 
+// OKXConfig OKX 配置
+type OKXConfig struct {
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+}
+
+// KrakenConfig Kraken 配置
+type KrakenConfig struct {
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+}
+
+// BybitConfig Bybit 配置
+type BybitConfig struct {
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+}
+
+// FileConfig 本地文件数据源配置
+type FileConfig struct {
+	Directory string `yaml:"directory"` // 数据目录，结构为 <directory>/<SYMBOL>/<timeframe>.csv
+}
+
 // WatcherConfig 监控配置
 type WatcherConfig struct {
 	Interval      time.Duration `yaml:"interval"`       // 监控间隔
@@ -73,6 +120,16 @@ type WatcherConfig struct {
 	BufferSize    int           `yaml:"buffer_size"`    // 缓冲区大小
 	LogLevel      string        `yaml:"log_level"`      // 日志级别
 	EnableMetrics bool          `yaml:"enable_metrics"` // 是否启用指标收集
+	Streaming     bool          `yaml:"streaming"`      // 数据源支持时使用 WebSocket 推送，K线收盘即分析
+
+	ClosedCandlesOnly bool `yaml:"closed_candles_only"` // 只使用已收盘的K线评估策略，避免盘中噪音
+
+	DataQuality string `yaml:"data_quality"` // K线缺口/重复/无效数据的处理策略: forward_fill, drop, fail
+
+	OrderBookDepth    int     `yaml:"order_book_depth"`    // 信号交易对获取的订单簿档位数，0 表示不获取
+	DepthRangePercent float64 `yaml:"depth_range_percent"` // 统计深度和失衡度的价格范围（中间价 ±%）
+
+	Bars []string `yaml:"bars"` // 由近期逐笔成交构建的K线，如 "volume:100"、"dollar:1000000"、"range:0.5%"
 }
 
 // NotifiersConfig 通知配置
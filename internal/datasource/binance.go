@@ -7,19 +7,19 @@ import (
 	"log"
 	"net/http"
 	"strconv"
-	"sync"
 	"time"
 
 	"ta-watcher/internal/config"
+	"ta-watcher/internal/instrument"
 )
 
 // BinanceClient Binance数据源实现
 type BinanceClient struct {
-	baseURL      string
-	client       *http.Client
-	rateLimit    *config.RateLimitConfig
-	lastRequest  time.Time
-	requestMutex sync.Mutex
+	baseURL   string
+	wsURL     string
+	client    *http.Client
+	rateLimit *config.RateLimitConfig
+	symbols   symbolCatalog
 }
 
 // NewBinanceClient 创建Binance客户端（已废弃，请使用NewBinanceClientWithConfig）
@@ -33,6 +33,7 @@ func NewBinanceClientWithConfig(cfg *config.BinanceConfig) *BinanceClient {
 	log.Printf("🔗 初始化 Binance 数据源")
 	client := &BinanceClient{
 		baseURL: "https://api.binance.com",
+		wsURL:   "wss://stream.binance.com:9443/ws",
 		client:  &http.Client{Timeout: 30 * time.Second},
 	}
 
@@ -59,9 +60,14 @@ func (b *BinanceClient) Name() string {
 	return "binance"
 }
 
+// SetHTTPTransport 替换底层 HTTP 传输层（用于录制/回放测试）
+func (b *BinanceClient) SetHTTPTransport(rt http.RoundTripper) {
+	b.client.Transport = rt
+}
+
 // IsSymbolValid 检查交易对是否有效
 func (b *BinanceClient) IsSymbolValid(ctx context.Context, symbol string) (bool, error) {
-	url := fmt.Sprintf("%s/api/v3/ticker/price?symbol=%s", b.baseURL, symbol)
+	url := fmt.Sprintf("%s/api/v3/ticker/price?symbol=%s", b.baseURL, b.convertToBinanceSymbol(symbol))
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
@@ -82,15 +88,158 @@ func (b *BinanceClient) IsSymbolValid(ctx context.Context, symbol string) (bool,
 	return valid, nil
 }
 
-// GetKlines 获取K线数据
+// binanceSymbol exchangeInfo 中的交易对
+type binanceSymbol struct {
+	Symbol     string `json:"symbol"`
+	Status     string `json:"status"`
+	BaseAsset  string `json:"baseAsset"`
+	QuoteAsset string `json:"quoteAsset"`
+	Filters    []struct {
+		FilterType  string `json:"filterType"`
+		TickSize    string `json:"tickSize"`
+		StepSize    string `json:"stepSize"`
+		MinNotional string `json:"minNotional"`
+	} `json:"filters"`
+}
+
+// SymbolInfo 返回交易对元数据（来自缓存的 exchangeInfo）
+func (b *BinanceClient) SymbolInfo(ctx context.Context, symbol string) (*SymbolInfo, error) {
+	return b.symbols.lookup(ctx, b.Name(), b.convertToBinanceSymbol(symbol), b.fetchExchangeInfo)
+}
+
+// Symbols 返回全部交易对元数据
+func (b *BinanceClient) Symbols(ctx context.Context) ([]*SymbolInfo, error) {
+	return b.symbols.all(ctx, b.Name(), b.fetchExchangeInfo)
+}
+
+// fetchExchangeInfo 获取全部现货交易对的 exchangeInfo
+func (b *BinanceClient) fetchExchangeInfo(ctx context.Context) ([]*SymbolInfo, error) {
+	url := fmt.Sprintf("%s/api/v3/exchangeInfo", b.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := b.executeWithRateLimit(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("binance", resp)
+	}
+
+	var body struct {
+		Symbols []binanceSymbol `json:"symbols"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	infos := make([]*SymbolInfo, 0, len(body.Symbols))
+	for _, s := range body.Symbols {
+		info := &SymbolInfo{
+			Symbol: s.Symbol,
+			Base:   s.BaseAsset,
+			Quote:  s.QuoteAsset,
+			Status: b.convertSymbolStatus(s.Status),
+		}
+		for _, f := range s.Filters {
+			switch f.FilterType {
+			case "PRICE_FILTER":
+				info.TickSize, info.PricePrecision = parseIncrement(f.TickSize)
+			case "LOT_SIZE":
+				info.LotSize, _ = parseIncrement(f.StepSize)
+			case "NOTIONAL", "MIN_NOTIONAL":
+				info.MinNotional, _ = strconv.ParseFloat(f.MinNotional, 64)
+			}
+		}
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+// convertToBinanceSymbol 转换为Binance交易对格式
+// BTCUSD -> BTCUSDT
+func (b *BinanceClient) convertToBinanceSymbol(symbol string) string {
+	return nativeSymbol(instrument.Binance, symbol)
+}
+
+// convertSymbolStatus 转换 Binance 交易对状态
+func (b *BinanceClient) convertSymbolStatus(status string) SymbolStatus {
+	switch status {
+	case "TRADING":
+		return SymbolStatusTrading
+	case "BREAK", "END_OF_DAY":
+		// 已下架的交易对仍以 BREAK 状态出现在 exchangeInfo 中
+		return SymbolStatusDelisted
+	default: // HALT、PRE_TRADING、POST_TRADING 等
+		return SymbolStatusHalted
+	}
+}
+
+// binanceMaxLimit Binance klines 接口单次最大返回数量
+const binanceMaxLimit = 1000
+
+// GetKlines 获取K线数据（超过单次上限时按 startTime/endTime 自动分页）
 func (b *BinanceClient) GetKlines(ctx context.Context, symbol string, timeframe Timeframe, startTime, endTime time.Time, limit int) ([]*Kline, error) {
 	if limit <= 0 {
 		limit = 500
 	}
-	if limit > 1000 {
-		limit = 1000 // Binance API限制
+	if limit <= binanceMaxLimit {
+		return b.fetchKlinesPage(ctx, symbol, timeframe, startTime, endTime, limit)
+	}
+
+	if endTime.IsZero() {
+		endTime = time.Now()
+	}
+	if startTime.IsZero() {
+		startTime = endTime.Add(-time.Duration(limit) * timeframe.Duration())
+	}
+
+	seen := make(map[int64]bool)
+	var allKlines []*Kline
+
+	// 从开始时间向后分页，每页都经过限流器
+	for cursor := startTime; !cursor.After(endTime); {
+		klines, err := b.fetchKlinesPage(ctx, symbol, timeframe, cursor, endTime, binanceMaxLimit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch page: %w", err)
+		}
+		if len(klines) == 0 {
+			break
+		}
+
+		// 去除页边界处的重复K线
+		for _, kline := range klines {
+			ts := kline.OpenTime.UnixMilli()
+			if seen[ts] {
+				continue
+			}
+			seen[ts] = true
+			allKlines = append(allKlines, kline)
+		}
+
+		last := klines[len(klines)-1].OpenTime
+		if len(klines) < binanceMaxLimit || !last.After(cursor) {
+			break
+		}
+		cursor = last.Add(time.Millisecond)
+	}
+
+	sortKlinesByTime(allKlines)
+
+	if len(allKlines) > limit {
+		allKlines = allKlines[len(allKlines)-limit:]
 	}
 
+	return allKlines, nil
+}
+
+// fetchKlinesPage 获取单页K线数据
+func (b *BinanceClient) fetchKlinesPage(ctx context.Context, symbol string, timeframe Timeframe, startTime, endTime time.Time, limit int) ([]*Kline, error) {
 	url := fmt.Sprintf("%s/api/v3/klines", b.baseURL)
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
@@ -98,7 +247,7 @@ func (b *BinanceClient) GetKlines(ctx context.Context, symbol string, timeframe
 	}
 
 	q := req.URL.Query()
-	q.Add("symbol", symbol)
+	q.Add("symbol", b.convertToBinanceSymbol(symbol))
 	q.Add("interval", string(timeframe))
 	q.Add("limit", strconv.Itoa(limit))
 
@@ -119,7 +268,7 @@ func (b *BinanceClient) GetKlines(ctx context.Context, symbol string, timeframe
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("binance API returned status: %d", resp.StatusCode)
+		return nil, statusError("binance", resp)
 	}
 
 	var rawKlines [][]interface{}
@@ -135,6 +284,7 @@ func (b *BinanceClient) GetKlines(ctx context.Context, symbol string, timeframe
 		}
 		klines[i] = kline
 	}
+	markClosed(klines, time.Now())
 
 	return klines, nil
 }
@@ -192,47 +342,128 @@ func (b *BinanceClient) parseKline(symbol string, raw []interface{}) (*Kline, er
 	}, nil
 }
 
-// rateLimitSleep 根据限流配置进行休眠
-func (b *BinanceClient) rateLimitSleep() {
-	b.requestMutex.Lock()
-	defer b.requestMutex.Unlock()
+// binanceDepthMaxLimit /api/v3/depth 单次最多返回的档位数
+const binanceDepthMaxLimit = 5000
+
+// GetOrderBook 获取订单簿快照
+func (b *BinanceClient) GetOrderBook(ctx context.Context, symbol string, depth int) (*OrderBook, error) {
+	limit := depth
+	if limit <= 0 || limit > binanceDepthMaxLimit {
+		limit = binanceDepthMaxLimit
+	}
 
-	if b.rateLimit.RequestsPerMinute <= 0 {
-		return
+	url := fmt.Sprintf("%s/api/v3/depth", b.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	minInterval := time.Minute / time.Duration(b.rateLimit.RequestsPerMinute)
-	elapsed := time.Since(b.lastRequest)
-	if elapsed < minInterval {
-		sleepTime := minInterval - elapsed
-		time.Sleep(sleepTime)
+	q := req.URL.Query()
+	q.Add("symbol", b.convertToBinanceSymbol(symbol))
+	q.Add("limit", strconv.Itoa(limit))
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := b.executeWithRateLimit(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("binance", resp)
 	}
-	b.lastRequest = time.Now()
+
+	var body struct {
+		Bids [][]json.RawMessage `json:"bids"`
+		Asks [][]json.RawMessage `json:"asks"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	bids, err := parseBookLevels(body.Bids)
+	if err != nil {
+		return nil, err
+	}
+	asks, err := parseBookLevels(body.Asks)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewOrderBook(symbol, time.Now(), bids, asks, depth), nil
 }
 
-// executeWithRateLimit 执行带限流的HTTP请求
-func (b *BinanceClient) executeWithRateLimit(req *http.Request) (*http.Response, error) {
-	var resp *http.Response
-	var err error
+// binanceTradesMaxLimit /api/v3/aggTrades 单次最大返回数量
+const binanceTradesMaxLimit = 1000
 
-	for retry := 0; retry <= b.rateLimit.MaxRetries; retry++ {
-		b.rateLimitSleep()
+// GetRecentTrades 获取最近的聚合成交
+func (b *BinanceClient) GetRecentTrades(ctx context.Context, symbol string, limit int) ([]*Trade, error) {
+	if limit <= 0 || limit > binanceTradesMaxLimit {
+		limit = binanceTradesMaxLimit
+	}
 
-		resp, err = b.client.Do(req)
-		if err == nil && resp.StatusCode < 500 {
-			return resp, nil
-		}
+	url := fmt.Sprintf("%s/api/v3/aggTrades", b.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
 
-		if resp != nil {
-			resp.Body.Close()
-		}
+	q := req.URL.Query()
+	q.Add("symbol", b.convertToBinanceSymbol(symbol))
+	q.Add("limit", strconv.Itoa(limit))
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := b.executeWithRateLimit(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("binance", resp)
+	}
+
+	var rows []struct {
+		ID         int64  `json:"a"`
+		Price      string `json:"p"`
+		Quantity   string `json:"q"`
+		Time       int64  `json:"T"`
+		BuyerMaker bool   `json:"m"`
+		BestMatch  bool   `json:"M"` // 需显式声明，否则大小写不敏感匹配会覆盖 m
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rows); err != nil {
+		return nil, err
+	}
 
-		if retry < b.rateLimit.MaxRetries {
-			time.Sleep(b.rateLimit.RetryDelay)
+	trades := make([]*Trade, 0, len(rows))
+	for _, row := range rows {
+		price, err := strconv.ParseFloat(row.Price, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trade price: %w", err)
+		}
+		quantity, err := strconv.ParseFloat(row.Quantity, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trade quantity: %w", err)
 		}
+		trades = append(trades, &Trade{
+			Symbol:     symbol,
+			ID:         strconv.FormatInt(row.ID, 10),
+			Time:       time.UnixMilli(row.Time),
+			Price:      price,
+			Quantity:   quantity,
+			BuyerMaker: row.BuyerMaker,
+		})
 	}
 
-	return resp, err
+	sortTradesByTime(trades)
+	return trades, nil
+}
+
+// executeWithRateLimit 执行带限流的HTTP请求（被限流时按 Retry-After 等待后重试）
+func (b *BinanceClient) executeWithRateLimit(req *http.Request) (*http.Response, error) {
+	return executeWithRetry(b.client, req, b.rateLimit, func(resp *http.Response) bool {
+		return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	})
 }
 
 // parseFloat64 从interface{}解析float64
@@ -0,0 +1,203 @@
+package datasource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ta-watcher/internal/config"
+)
+
+const (
+	binanceFundingMaxLimit = 1000 // fundingRate 单次最大返回数量
+	binanceStatsMaxLimit   = 500  // futures/data 统计接口单次最大返回数量
+)
+
+// binanceStatPeriods futures/data 统计接口支持的周期（仅提供最近30天数据）
+var binanceStatPeriods = []Timeframe{
+	Timeframe5m, Timeframe15m, Timeframe30m, Timeframe1h, Timeframe2h,
+	Timeframe4h, Timeframe6h, Timeframe12h, Timeframe1d,
+}
+
+// BinanceFuturesClient Binance USDⓈ-M 永续合约衍生品数据源
+type BinanceFuturesClient struct {
+	baseURL   string
+	client    *http.Client
+	rateLimit *config.RateLimitConfig
+}
+
+// NewBinanceFuturesClientWithConfig 使用配置创建Binance合约客户端（与现货共用限流配置，按主机分别限流）
+func NewBinanceFuturesClientWithConfig(cfg *config.BinanceConfig) *BinanceFuturesClient {
+	log.Printf("🔗 初始化 Binance USDⓈ-M 合约数据源")
+	client := &BinanceFuturesClient{
+		baseURL: "https://fapi.binance.com",
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+
+	if cfg != nil {
+		client.rateLimit = &cfg.RateLimit
+	} else {
+		client.rateLimit = &config.RateLimitConfig{
+			RequestsPerMinute: 1200,
+			RetryDelay:        time.Second,
+			MaxRetries:        3,
+		}
+	}
+
+	return client
+}
+
+// Name 返回数据源名称
+func (b *BinanceFuturesClient) Name() string {
+	return "binance_futures"
+}
+
+// GetFundingRates 获取资金费率历史
+func (b *BinanceFuturesClient) GetFundingRates(ctx context.Context, symbol string, startTime, endTime time.Time, limit int) ([]*FundingRate, error) {
+	if limit <= 0 || limit > binanceFundingMaxLimit {
+		limit = binanceFundingMaxLimit
+	}
+
+	var rows []struct {
+		FundingTime int64  `json:"fundingTime"`
+		FundingRate string `json:"fundingRate"`
+		MarkPrice   string `json:"markPrice"`
+	}
+	if err := b.get(ctx, "/fapi/v1/fundingRate", symbol, "", startTime, endTime, limit, &rows); err != nil {
+		return nil, err
+	}
+
+	rates := make([]*FundingRate, 0, len(rows))
+	for _, row := range rows {
+		rate, err := strconv.ParseFloat(row.FundingRate, 64)
+		if err != nil {
+			continue // 跳过无法解析的数据
+		}
+		markPrice, _ := strconv.ParseFloat(row.MarkPrice, 64)
+		rates = append(rates, &FundingRate{
+			Symbol:    symbol,
+			Time:      time.UnixMilli(row.FundingTime),
+			Rate:      rate,
+			MarkPrice: markPrice,
+		})
+	}
+
+	return rates, nil
+}
+
+// GetOpenInterest 获取持仓量历史
+func (b *BinanceFuturesClient) GetOpenInterest(ctx context.Context, symbol string, period Timeframe, startTime, endTime time.Time, limit int) ([]*OpenInterest, error) {
+	if limit <= 0 || limit > binanceStatsMaxLimit {
+		limit = binanceStatsMaxLimit
+	}
+
+	var rows []struct {
+		SumOpenInterest      string `json:"sumOpenInterest"`
+		SumOpenInterestValue string `json:"sumOpenInterestValue"`
+		Timestamp            int64  `json:"timestamp"`
+	}
+	p := statPeriod(period, binanceStatPeriods)
+	if err := b.get(ctx, "/futures/data/openInterestHist", symbol, p, startTime, endTime, limit, &rows); err != nil {
+		return nil, err
+	}
+
+	series := make([]*OpenInterest, 0, len(rows))
+	for _, row := range rows {
+		contracts, err := strconv.ParseFloat(row.SumOpenInterest, 64)
+		if err != nil {
+			continue
+		}
+		value, _ := strconv.ParseFloat(row.SumOpenInterestValue, 64)
+		series = append(series, &OpenInterest{
+			Symbol:    symbol,
+			Time:      time.UnixMilli(row.Timestamp),
+			Contracts: contracts,
+			Value:     value,
+		})
+	}
+
+	return series, nil
+}
+
+// GetLongShortRatio 获取全市场多空账户比
+func (b *BinanceFuturesClient) GetLongShortRatio(ctx context.Context, symbol string, period Timeframe, startTime, endTime time.Time, limit int) ([]*LongShortRatio, error) {
+	if limit <= 0 || limit > binanceStatsMaxLimit {
+		limit = binanceStatsMaxLimit
+	}
+
+	var rows []struct {
+		LongShortRatio string `json:"longShortRatio"`
+		LongAccount    string `json:"longAccount"`
+		ShortAccount   string `json:"shortAccount"`
+		Timestamp      int64  `json:"timestamp"`
+	}
+	p := statPeriod(period, binanceStatPeriods)
+	if err := b.get(ctx, "/futures/data/globalLongShortAccountRatio", symbol, p, startTime, endTime, limit, &rows); err != nil {
+		return nil, err
+	}
+
+	series := make([]*LongShortRatio, 0, len(rows))
+	for _, row := range rows {
+		ratio, err := strconv.ParseFloat(row.LongShortRatio, 64)
+		if err != nil {
+			continue
+		}
+		long, _ := strconv.ParseFloat(row.LongAccount, 64)
+		short, _ := strconv.ParseFloat(row.ShortAccount, 64)
+		series = append(series, &LongShortRatio{
+			Symbol:       symbol,
+			Time:         time.UnixMilli(row.Timestamp),
+			Ratio:        ratio,
+			LongAccount:  long,
+			ShortAccount: short,
+		})
+	}
+
+	return series, nil
+}
+
+// get 请求合约接口并解析JSON数组响应（接口本身按时间升序返回）
+func (b *BinanceFuturesClient) get(ctx context.Context, path, symbol string, period Timeframe, startTime, endTime time.Time, limit int, out interface{}) error {
+	url := fmt.Sprintf("%s%s", b.baseURL, path)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	q := req.URL.Query()
+	q.Add("symbol", symbol)
+	if period != "" {
+		q.Add("period", string(period))
+	}
+	q.Add("limit", strconv.Itoa(limit))
+	if !startTime.IsZero() {
+		q.Add("startTime", strconv.FormatInt(startTime.UnixMilli(), 10))
+	}
+	if !endTime.IsZero() {
+		q.Add("endTime", strconv.FormatInt(endTime.UnixMilli(), 10))
+	}
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := b.executeWithRateLimit(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return statusError("binance", resp)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// executeWithRateLimit 执行带限流的HTTP请求
+func (b *BinanceFuturesClient) executeWithRateLimit(req *http.Request) (*http.Response, error) {
+	return executeWithRetry(b.client, req, b.rateLimit, func(resp *http.Response) bool {
+		return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	})
+}
@@ -0,0 +1,97 @@
+package datasource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// binanceKlineEvent Binance K线推送事件
+type binanceKlineEvent struct {
+	Event  string `json:"e"`
+	Symbol string `json:"s"`
+	Kline  struct {
+		OpenTime  int64  `json:"t"`
+		CloseTime int64  `json:"T"`
+		Interval  string `json:"i"`
+		Open      string `json:"o"`
+		Close     string `json:"c"`
+		High      string `json:"h"`
+		Low       string `json:"l"`
+		Volume    string `json:"v"`
+		Closed    bool   `json:"x"`
+	} `json:"k"`
+}
+
+// SubscribeKlines 通过 WebSocket 订阅K线，只推送已收盘的K线
+func (b *BinanceClient) SubscribeKlines(ctx context.Context, symbols []string, timeframe Timeframe) (<-chan *Kline, error) {
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("no symbols to subscribe")
+	}
+
+	// 推送中的交易对为原生格式，映射回订阅时的交易对
+	params := make([]string, len(symbols))
+	requested := make(map[string]string, len(symbols))
+	for i, symbol := range symbols {
+		native := b.convertToBinanceSymbol(symbol)
+		requested[native] = symbol
+		params[i] = fmt.Sprintf("%s@kline_%s", strings.ToLower(native), timeframe)
+	}
+
+	subscribe, err := json.Marshal(map[string]interface{}{
+		"method": "SUBSCRIBE",
+		"params": params,
+		"id":     1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stream := &klineStream{
+		name:      "Binance",
+		url:       b.wsURL,
+		subscribe: subscribe,
+		parse: func(msg []byte) []*Kline {
+			klines := b.parseKlineEvent(msg)
+			for _, kline := range klines {
+				if symbol, ok := requested[kline.Symbol]; ok {
+					kline.Symbol = symbol
+				}
+			}
+			return klines
+		},
+	}
+	return stream.start(ctx)
+}
+
+// parseKlineEvent 解析K线推送，忽略订阅确认和未收盘的K线
+func (b *BinanceClient) parseKlineEvent(msg []byte) []*Kline {
+	var event binanceKlineEvent
+	if err := json.Unmarshal(msg, &event); err != nil || event.Event != "kline" || !event.Kline.Closed {
+		return nil
+	}
+
+	values := make([]float64, 5)
+	for i, raw := range []string{event.Kline.Open, event.Kline.High, event.Kline.Low, event.Kline.Close, event.Kline.Volume} {
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil
+		}
+		values[i] = v
+	}
+
+	return []*Kline{{
+		Symbol:    event.Symbol,
+		OpenTime:  time.UnixMilli(event.Kline.OpenTime),
+		CloseTime: time.UnixMilli(event.Kline.CloseTime),
+		Open:      values[0],
+		High:      values[1],
+		Low:       values[2],
+		Close:     values[3],
+		Volume:    values[4],
+		IsClosed:  true,
+	}}
+}
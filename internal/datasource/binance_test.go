@@ -2,8 +2,16 @@ package datasource
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
+
+	"ta-watcher/internal/config"
+	"ta-watcher/internal/httpreplay"
 )
 
 func TestBinanceClient_New(t *testing.T) {
@@ -18,12 +26,19 @@ func TestBinanceClient_New(t *testing.T) {
 	}
 }
 
-func TestBinanceClient_IsSymbolValid(t *testing.T) {
-	if testing.Short() {
-		t.Skip("Skipping Binance API test in short mode")
-	}
+// newReplayBinanceClient 创建从 testdata/cassettes/binance.json 回放的客户端
+func newReplayBinanceClient(t *testing.T) *BinanceClient {
+	t.Helper()
+	resetHostState("api.binance.com")
+	client := NewBinanceClientWithConfig(&config.BinanceConfig{
+		RateLimit: config.RateLimitConfig{RequestsPerMinute: 6000},
+	})
+	client.SetHTTPTransport(httpreplay.NewForTest(t, "binance"))
+	return client
+}
 
-	client := NewBinanceClient()
+func TestBinanceClient_IsSymbolValid(t *testing.T) {
+	client := newReplayBinanceClient(t)
 	ctx := context.Background()
 
 	tests := []struct {
@@ -50,11 +65,7 @@ func TestBinanceClient_IsSymbolValid(t *testing.T) {
 }
 
 func TestBinanceClient_GetKlines(t *testing.T) {
-	if testing.Short() {
-		t.Skip("Skipping Binance API test in short mode")
-	}
-
-	client := NewBinanceClient()
+	client := newReplayBinanceClient(t)
 	ctx := context.Background()
 
 	tests := []struct {
@@ -103,11 +114,7 @@ func TestBinanceClient_GetKlines(t *testing.T) {
 }
 
 func TestBinanceClient_TimeframeSupport(t *testing.T) {
-	if testing.Short() {
-		t.Skip("Skipping Binance API test in short mode")
-	}
-
-	client := NewBinanceClient()
+	client := newReplayBinanceClient(t)
 	ctx := context.Background()
 
 	timeframes := []Timeframe{
@@ -133,3 +140,96 @@ func TestBinanceClient_TimeframeSupport(t *testing.T) {
 		})
 	}
 }
+
+// newBinanceKlinesTestServer 模拟Binance klines接口，提供count根小时K线
+// 每页额外返回起始时间之前的一根K线，用于验证页边界去重
+func newBinanceKlinesTestServer(t *testing.T, base time.Time, count int, requests *int) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		*requests++
+		mu.Unlock()
+
+		q := r.URL.Query()
+		limit, _ := strconv.Atoi(q.Get("limit"))
+		if limit > binanceMaxLimit {
+			t.Errorf("limit %d exceeds binance maximum", limit)
+		}
+		startMs, _ := strconv.ParseInt(q.Get("startTime"), 10, 64)
+		endMs, _ := strconv.ParseInt(q.Get("endTime"), 10, 64)
+
+		rows := [][]interface{}{}
+		for i := 0; i < count && len(rows) < limit; i++ {
+			open := base.Add(time.Duration(i) * time.Hour)
+			if open.UnixMilli() > endMs {
+				break
+			}
+			if open.UnixMilli() < startMs && open.Add(time.Hour).UnixMilli() <= startMs {
+				continue
+			}
+			price := strconv.Itoa(100 + i)
+			rows = append(rows, []interface{}{
+				open.UnixMilli(), price, price, price, price, "1",
+				open.Add(time.Hour - time.Millisecond).UnixMilli(), "0", 1, "0", "0", "0",
+			})
+		}
+		json.NewEncoder(w).Encode(rows)
+	}))
+}
+
+func TestBinanceClient_GetKlinesPagination(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	requests := 0
+	server := newBinanceKlinesTestServer(t, base, 2500, &requests)
+	defer server.Close()
+
+	client := NewBinanceClient()
+	client.baseURL = server.URL
+	client.rateLimit.RequestsPerMinute = 0
+
+	end := base.Add(2499 * time.Hour)
+	klines, err := client.GetKlines(context.Background(), "BTCUSDT", Timeframe1h, base, end, 2500)
+	if err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if len(klines) != 2500 {
+		t.Fatalf("expected 2500 klines, got %d", len(klines))
+	}
+	for i := 1; i < len(klines); i++ {
+		if !klines[i].OpenTime.After(klines[i-1].OpenTime) {
+			t.Fatalf("klines not strictly increasing at %d: %v <= %v", i, klines[i].OpenTime, klines[i-1].OpenTime)
+		}
+	}
+	if requests != 3 {
+		t.Errorf("expected 3 page requests, got %d", requests)
+	}
+
+	// 超出上限时只保留最新的 limit 根
+	klines, err = client.GetKlines(context.Background(), "BTCUSDT", Timeframe1h, base, end, 1200)
+	if err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if len(klines) != 1200 || !klines[len(klines)-1].OpenTime.Equal(end) {
+		t.Errorf("expected latest 1200 klines ending at %v, got %d ending at %v", end, len(klines), klines[len(klines)-1].OpenTime)
+	}
+}
+
+func TestBinanceClient_GetKlinesSinglePage(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	requests := 0
+	server := newBinanceKlinesTestServer(t, base, 50, &requests)
+	defer server.Close()
+
+	client := NewBinanceClient()
+	client.baseURL = server.URL
+
+	klines, err := client.GetKlines(context.Background(), "BTCUSDT", Timeframe1h, base, base.Add(49*time.Hour), 50)
+	if err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if len(klines) != 50 || requests != 1 {
+		t.Errorf("expected 50 klines in 1 request, got %d klines in %d requests", len(klines), requests)
+	}
+}
@@ -0,0 +1,135 @@
+package datasource
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CircuitState 熔断器状态
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"    // 正常放行
+	CircuitOpen     CircuitState = "open"      // 熔断中，直接拒绝请求
+	CircuitHalfOpen CircuitState = "half_open" // 冷却结束，放行一个探测请求
+)
+
+const (
+	defaultBreakerThreshold = 5                // 连续失败多少次后熔断
+	defaultBreakerCooldown  = 30 * time.Second // 熔断后多久尝试恢复
+)
+
+// CircuitBreaker 按主机的熔断器，交易所持续失败时暂停请求，避免反复冲击
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	state     CircuitState
+	failures  int
+	openedAt  time.Time
+	probing   bool // 半开状态下是否已有探测请求在进行
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+}
+
+// NewCircuitBreaker 创建熔断器
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		state:     CircuitClosed,
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Allow 判断是否放行请求，熔断时返回 ErrUnavailable
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return fmt.Errorf("circuit breaker open: %w", ErrUnavailable)
+		}
+		b.state = CircuitHalfOpen
+		b.probing = true
+		return nil
+	case CircuitHalfOpen:
+		if b.probing {
+			return fmt.Errorf("circuit breaker half-open: %w", ErrUnavailable)
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// RecordSuccess 记录成功，熔断器恢复正常
+func (b *CircuitBreaker) RecordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = CircuitClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// RecordFailure 记录失败，连续失败达到阈值或探测失败时熔断
+func (b *CircuitBreaker) RecordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.state == CircuitHalfOpen || b.failures >= b.threshold {
+		b.state = CircuitOpen
+		b.openedAt = b.now()
+	}
+}
+
+// Release 请求被取消时释放半开探测名额，不影响状态
+func (b *CircuitBreaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// State 返回当前状态
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// hostBreakers 按主机共享的熔断器
+var hostBreakers = struct {
+	sync.Mutex
+	m map[string]*CircuitBreaker
+}{m: make(map[string]*CircuitBreaker)}
+
+// breakerForHost 返回主机对应的共享熔断器
+func breakerForHost(host string) *CircuitBreaker {
+	hostBreakers.Lock()
+	defer hostBreakers.Unlock()
+
+	breaker, ok := hostBreakers.m[host]
+	if !ok {
+		breaker = NewCircuitBreaker(defaultBreakerThreshold, defaultBreakerCooldown)
+		hostBreakers.m[host] = breaker
+	}
+	return breaker
+}
+
+// CircuitBreakerStates 返回所有主机熔断器的当前状态
+func CircuitBreakerStates() map[string]CircuitState {
+	hostBreakers.Lock()
+	defer hostBreakers.Unlock()
+
+	states := make(map[string]CircuitState, len(hostBreakers.m))
+	for host, breaker := range hostBreakers.m {
+		states[host] = breaker.State()
+	}
+	return states
+}
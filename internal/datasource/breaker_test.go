@@ -0,0 +1,110 @@
+package datasource
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"ta-watcher/internal/config"
+)
+
+func TestCircuitBreaker_Transitions(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := NewCircuitBreaker(3, time.Minute)
+	b.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if err := b.Allow(); err != nil {
+			t.Fatalf("closed breaker should allow request %d: %v", i, err)
+		}
+		b.RecordFailure()
+	}
+	if b.State() != CircuitOpen {
+		t.Fatalf("breaker should open after 3 failures, got %s", b.State())
+	}
+	if err := b.Allow(); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("open breaker should reject with ErrUnavailable, got %v", err)
+	}
+
+	// 冷却结束后只放行一个探测请求
+	now = now.Add(time.Minute)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("breaker should allow a probe after cooldown: %v", err)
+	}
+	if b.State() != CircuitHalfOpen {
+		t.Errorf("expected half_open, got %s", b.State())
+	}
+	if err := b.Allow(); err == nil {
+		t.Error("only one probe should be allowed while half open")
+	}
+
+	// 探测失败重新熔断
+	b.RecordFailure()
+	if b.State() != CircuitOpen {
+		t.Errorf("failed probe should reopen breaker, got %s", b.State())
+	}
+
+	now = now.Add(time.Minute)
+	b.Allow()
+	b.RecordSuccess()
+	if b.State() != CircuitClosed {
+		t.Errorf("successful probe should close breaker, got %s", b.State())
+	}
+}
+
+func TestExecuteWithRetry_OpensBreaker(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewBybitClient()
+	client.baseURL = server.URL
+	client.rateLimit = &config.RateLimitConfig{RequestsPerMinute: 6000, MaxRetries: 0}
+
+	for i := 0; i < defaultBreakerThreshold; i++ {
+		if _, err := client.GetKlines(context.Background(), "BTCUSDT", Timeframe1h, time.Time{}, time.Time{}, 10); !errors.Is(err, ErrUnavailable) {
+			t.Fatalf("503 should map to ErrUnavailable, got %v", err)
+		}
+	}
+
+	_, err := client.GetKlines(context.Background(), "BTCUSDT", Timeframe1h, time.Time{}, time.Time{}, 10)
+	if !errors.Is(err, ErrUnavailable) || calls != defaultBreakerThreshold {
+		t.Errorf("open breaker should stop requests: calls=%d err=%v", calls, err)
+	}
+
+	states := CircuitBreakerStates()
+	if states[server.Listener.Addr().String()] != CircuitOpen {
+		t.Errorf("breaker state should be reported as open, got %v", states)
+	}
+}
+
+func TestExecuteWithRetry_IPBanOpensBreaker(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer server.Close()
+
+	client := NewBinanceClient()
+	client.baseURL = server.URL
+	client.rateLimit = &config.RateLimitConfig{RequestsPerMinute: 6000, MaxRetries: 0}
+
+	for i := 0; i < defaultBreakerThreshold; i++ {
+		if _, err := client.GetKlines(context.Background(), "BTCUSDT", Timeframe1h, time.Time{}, time.Time{}, 10); !errors.Is(err, ErrRateLimited) {
+			t.Fatalf("418 should map to ErrRateLimited, got %v", err)
+		}
+	}
+
+	// IP被封禁后熔断器打开，不再继续请求
+	_, err := client.GetKlines(context.Background(), "BTCUSDT", Timeframe1h, time.Time{}, time.Time{}, 10)
+	if !errors.Is(err, ErrUnavailable) || calls != defaultBreakerThreshold {
+		t.Errorf("open breaker should stop requests after IP ban: calls=%d err=%v", calls, err)
+	}
+}
@@ -0,0 +1,465 @@
+package datasource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"ta-watcher/internal/config"
+	"ta-watcher/internal/instrument"
+)
+
+// bybitPageSize Bybit kline 单次最大返回数量
+const bybitPageSize = 1000
+
+// BybitClient Bybit数据源实现
+type BybitClient struct {
+	baseURL   string
+	client    *http.Client
+	rateLimit *config.RateLimitConfig
+	resampling
+}
+
+// bybitResponse Bybit v5 API 通用响应结构
+type bybitResponse struct {
+	RetCode int             `json:"retCode"`
+	RetMsg  string          `json:"retMsg"`
+	Result  json.RawMessage `json:"result"`
+}
+
+// bybitListResult Bybit 列表类结果
+type bybitListResult struct {
+	Symbol string            `json:"symbol"`
+	List   []json.RawMessage `json:"list"`
+}
+
+// bybitAPIError Bybit 业务错误
+type bybitAPIError struct {
+	RetCode int
+	RetMsg  string
+}
+
+func (e *bybitAPIError) Error() string {
+	return fmt.Sprintf("bybit API error %d: %s", e.RetCode, e.RetMsg)
+}
+
+// Unwrap 将常见错误码映射为数据源错误类型
+func (e *bybitAPIError) Unwrap() error {
+	switch {
+	case e.RetCode == 10006 || e.RetCode == 10018: // 请求过于频繁
+		return ErrRateLimited
+	case e.RetCode == 10016: // 服务错误
+		return ErrUnavailable
+	case e.RetCode == 10001 && strings.Contains(strings.ToLower(e.RetMsg), "symbol"):
+		return ErrSymbolNotFound
+	default:
+		return nil
+	}
+}
+
+// NewBybitClient 创建Bybit客户端（建议使用NewBybitClientWithConfig）
+func NewBybitClient() *BybitClient {
+	return NewBybitClientWithConfig(nil)
+}
+
+// NewBybitClientWithConfig 使用配置创建Bybit客户端
+func NewBybitClientWithConfig(cfg *config.BybitConfig) *BybitClient {
+	log.Printf("🔗 初始化 Bybit 数据源")
+	client := &BybitClient{
+		baseURL: "https://api.bybit.com",
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+
+	if cfg != nil {
+		client.rateLimit = &cfg.RateLimit
+		fmt.Printf("🔧 [Bybit] 使用配置限流: 每分钟%d请求, 延迟%v, 重试%d次\n",
+			cfg.RateLimit.RequestsPerMinute, cfg.RateLimit.RetryDelay, cfg.RateLimit.MaxRetries)
+	} else {
+		// 默认配置（公共接口限制为每5秒600次/IP，此处保守设置）
+		client.rateLimit = &config.RateLimitConfig{
+			RequestsPerMinute: 600,
+			RetryDelay:        2 * time.Second,
+			MaxRetries:        3,
+		}
+		fmt.Printf("⚠️  [Bybit] 使用默认限流配置: 每分钟%d请求, 延迟%v, 重试%d次\n",
+			600, 2*time.Second, 3)
+	}
+
+	return client
+}
+
+// Name 返回数据源名称
+func (b *BybitClient) Name() string {
+	return "bybit"
+}
+
+// IsSymbolValid 检查交易对是否有效
+func (b *BybitClient) IsSymbolValid(ctx context.Context, symbol string) (bool, error) {
+	url := fmt.Sprintf("%s/v5/market/instruments-info", b.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return false, err
+	}
+
+	q := req.URL.Query()
+	q.Add("category", "spot")
+	q.Add("symbol", b.convertToBybitSymbol(symbol))
+	req.URL.RawQuery = q.Encode()
+
+	result, err := b.doRequest(req)
+	if err != nil {
+		return false, err
+	}
+
+	valid := len(result.List) > 0
+	if !valid {
+		log.Printf("❌ [Bybit] %s 不存在", symbol)
+	}
+
+	return valid, nil
+}
+
+// GetKlines 获取K线数据（按时间倒序分页）
+func (b *BybitClient) GetKlines(ctx context.Context, symbol string, timeframe Timeframe, startTime, endTime time.Time, limit int) ([]*Kline, error) {
+	if limit <= 0 {
+		limit = 500
+	}
+
+	interval := b.convertTimeframeToInterval(timeframe)
+	if interval == "" {
+		// Bybit 没有该周期（如8h、3d），由更小的原生周期重采样
+		return getResampledKlines(ctx, b.GetKlines, b.supportsTimeframe, b.resampleOptions(), symbol, timeframe, startTime, endTime, limit)
+	}
+
+	if endTime.IsZero() {
+		endTime = time.Now()
+	}
+	if startTime.IsZero() {
+		startTime = endTime.Add(-time.Duration(limit) * timeframe.Duration())
+	}
+
+	seen := make(map[int64]bool)
+	var allKlines []*Kline
+
+	currentEnd := endTime
+	for len(allKlines) < limit {
+		rows, err := b.fetchKlinesPage(ctx, symbol, interval, startTime, currentEnd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch page: %w", err)
+		}
+		if len(rows) == 0 {
+			break
+		}
+
+		oldest := currentEnd
+		for _, raw := range rows {
+			kline, err := b.parseCandle(symbol, timeframe, raw)
+			if err != nil {
+				continue // 跳过无法解析的数据
+			}
+			if kline.OpenTime.Before(oldest) {
+				oldest = kline.OpenTime
+			}
+			ts := kline.OpenTime.UnixMilli()
+			if seen[ts] {
+				continue
+			}
+			seen[ts] = true
+			allKlines = append(allKlines, kline)
+		}
+
+		// 返回不足一页说明已到达开始时间
+		if len(rows) < bybitPageSize || !oldest.After(startTime) {
+			break
+		}
+		currentEnd = oldest.Add(-time.Millisecond)
+	}
+
+	sortKlinesByTime(allKlines)
+
+	if len(allKlines) > limit {
+		allKlines = allKlines[len(allKlines)-limit:]
+	}
+	markClosed(allKlines, time.Now())
+
+	return allKlines, nil
+}
+
+// fetchKlinesPage 获取单页K线数据
+func (b *BybitClient) fetchKlinesPage(ctx context.Context, symbol, interval string, startTime, endTime time.Time) ([][]string, error) {
+	url := fmt.Sprintf("%s/v5/market/kline", b.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	q := req.URL.Query()
+	q.Add("category", "spot")
+	q.Add("symbol", b.convertToBybitSymbol(symbol))
+	q.Add("interval", interval)
+	q.Add("start", strconv.FormatInt(startTime.UnixMilli(), 10))
+	q.Add("end", strconv.FormatInt(endTime.UnixMilli(), 10))
+	q.Add("limit", strconv.Itoa(bybitPageSize))
+	req.URL.RawQuery = q.Encode()
+
+	result, err := b.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([][]string, 0, len(result.List))
+	for _, item := range result.List {
+		var row []string
+		if err := json.Unmarshal(item, &row); err != nil {
+			return nil, fmt.Errorf("invalid kline row: %w", err)
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// doRequest 执行请求并解析Bybit列表类响应
+func (b *BybitClient) doRequest(req *http.Request) (*bybitListResult, error) {
+	raw, err := b.doRawRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var result bybitListResult
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &result); err != nil {
+			return nil, fmt.Errorf("invalid result: %w", err)
+		}
+	}
+
+	return &result, nil
+}
+
+// doRawRequest 执行请求并解析Bybit通用响应，返回未解析的 result
+func (b *BybitClient) doRawRequest(req *http.Request) (json.RawMessage, error) {
+	resp, err := b.executeWithRateLimit(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("bybit", resp)
+	}
+
+	var body bybitResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	if body.RetCode != 0 {
+		return nil, &bybitAPIError{RetCode: body.RetCode, RetMsg: body.RetMsg}
+	}
+
+	return body.Result, nil
+}
+
+// parseCandle 解析Bybit K线数据
+// Bybit格式: [startTime, open, high, low, close, volume, turnover]
+func (b *BybitClient) parseCandle(symbol string, timeframe Timeframe, raw []string) (*Kline, error) {
+	if len(raw) < 6 {
+		return nil, fmt.Errorf("invalid kline data length: %d", len(raw))
+	}
+
+	values := make([]float64, 6)
+	for i := 0; i < 6; i++ {
+		v, err := strconv.ParseFloat(raw[i], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid kline field %d: %w", i, err)
+		}
+		values[i] = v
+	}
+
+	openTime := time.UnixMilli(int64(values[0]))
+
+	return &Kline{
+		Symbol:    symbol,
+		OpenTime:  openTime,
+		CloseTime: closeTimeFor(openTime, timeframe),
+		Open:      values[1],
+		High:      values[2],
+		Low:       values[3],
+		Close:     values[4],
+		Volume:    values[5],
+	}, nil
+}
+
+// convertToBybitSymbol 转换为Bybit交易对格式
+// BTCUSD -> BTCUSDT
+func (b *BybitClient) convertToBybitSymbol(symbol string) string {
+	return nativeSymbol(instrument.Bybit, symbol)
+}
+
+// supportsTimeframe 判断Bybit是否原生支持该时间框架
+func (b *BybitClient) supportsTimeframe(tf Timeframe) bool {
+	return b.convertTimeframeToInterval(tf) != ""
+}
+
+// convertTimeframeToInterval 转换时间框架为Bybit interval参数
+func (b *BybitClient) convertTimeframeToInterval(tf Timeframe) string {
+	switch tf {
+	case Timeframe1m:
+		return "1"
+	case Timeframe3m:
+		return "3"
+	case Timeframe5m:
+		return "5"
+	case Timeframe15m:
+		return "15"
+	case Timeframe30m:
+		return "30"
+	case Timeframe1h:
+		return "60"
+	case Timeframe2h:
+		return "120"
+	case Timeframe4h:
+		return "240"
+	case Timeframe6h:
+		return "360"
+	case Timeframe12h:
+		return "720"
+	case Timeframe1d:
+		return "D"
+	case Timeframe1w:
+		return "W"
+	case Timeframe1M:
+		return "M"
+	default:
+		return "" // 不支持的时间框架
+	}
+}
+
+// bybitBookMaxLimit 现货订单簿单次最多返回的档位数
+const bybitBookMaxLimit = 200
+
+// GetOrderBook 获取订单簿快照
+func (b *BybitClient) GetOrderBook(ctx context.Context, symbol string, depth int) (*OrderBook, error) {
+	limit := depth
+	if limit <= 0 || limit > bybitBookMaxLimit {
+		limit = bybitBookMaxLimit
+	}
+
+	url := fmt.Sprintf("%s/v5/market/orderbook", b.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	q := req.URL.Query()
+	q.Add("category", "spot")
+	q.Add("symbol", b.convertToBybitSymbol(symbol))
+	q.Add("limit", strconv.Itoa(limit))
+	req.URL.RawQuery = q.Encode()
+
+	raw, err := b.doRawRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 格式: b/a 为 [price, size]，ts 为毫秒时间戳
+	var book struct {
+		Bids [][]json.RawMessage `json:"b"`
+		Asks [][]json.RawMessage `json:"a"`
+		Ts   int64               `json:"ts"`
+	}
+	if err := json.Unmarshal(raw, &book); err != nil {
+		return nil, fmt.Errorf("invalid order book: %w", err)
+	}
+
+	bids, err := parseBookLevels(book.Bids)
+	if err != nil {
+		return nil, err
+	}
+	asks, err := parseBookLevels(book.Asks)
+	if err != nil {
+		return nil, err
+	}
+
+	t := time.Now()
+	if book.Ts > 0 {
+		t = time.UnixMilli(book.Ts)
+	}
+	return NewOrderBook(symbol, t, bids, asks, depth), nil
+}
+
+// bybitTradesMaxLimit 现货近期成交单次最大返回数量
+const bybitTradesMaxLimit = 60
+
+// GetRecentTrades 获取最近的成交
+func (b *BybitClient) GetRecentTrades(ctx context.Context, symbol string, limit int) ([]*Trade, error) {
+	if limit <= 0 || limit > bybitTradesMaxLimit {
+		limit = bybitTradesMaxLimit
+	}
+
+	url := fmt.Sprintf("%s/v5/market/recent-trade", b.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	q := req.URL.Query()
+	q.Add("category", "spot")
+	q.Add("symbol", b.convertToBybitSymbol(symbol))
+	q.Add("limit", strconv.Itoa(limit))
+	req.URL.RawQuery = q.Encode()
+
+	result, err := b.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// side 为吃单方方向（Buy/Sell）
+	trades := make([]*Trade, 0, len(result.List))
+	for _, raw := range result.List {
+		var row struct {
+			ExecID string `json:"execId"`
+			Price  string `json:"price"`
+			Size   string `json:"size"`
+			Side   string `json:"side"`
+			Time   string `json:"time"`
+		}
+		if err := json.Unmarshal(raw, &row); err != nil {
+			return nil, fmt.Errorf("invalid trade: %w", err)
+		}
+		price, err := strconv.ParseFloat(row.Price, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trade price: %w", err)
+		}
+		size, err := strconv.ParseFloat(row.Size, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trade size: %w", err)
+		}
+		ts, err := strconv.ParseInt(row.Time, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trade time: %w", err)
+		}
+		trades = append(trades, &Trade{
+			Symbol:     symbol,
+			ID:         row.ExecID,
+			Time:       time.UnixMilli(ts),
+			Price:      price,
+			Quantity:   size,
+			BuyerMaker: row.Side == "Sell",
+		})
+	}
+
+	sortTradesByTime(trades)
+	return trades, nil
+}
+
+// executeWithRateLimit 执行带限流的HTTP请求
+func (b *BybitClient) executeWithRateLimit(req *http.Request) (*http.Response, error) {
+	return executeWithRetry(b.client, req, b.rateLimit, func(resp *http.Response) bool {
+		return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	})
+}
@@ -0,0 +1,114 @@
+package datasource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// newBybitTestServer 模拟Bybit v5公共接口
+func newBybitTestServer(t *testing.T, base time.Time, count int) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/v5/market/instruments-info":
+			if q.Get("symbol") == "BTCUSDT" {
+				fmt.Fprint(w, `{"retCode":0,"retMsg":"OK","result":{"category":"spot","list":[{"symbol":"BTCUSDT"}]}}`)
+				return
+			}
+			fmt.Fprint(w, `{"retCode":0,"retMsg":"OK","result":{"category":"spot","list":[]}}`)
+		case "/v5/market/kline":
+			start, _ := strconv.ParseInt(q.Get("start"), 10, 64)
+			end, _ := strconv.ParseInt(q.Get("end"), 10, 64)
+			limit, _ := strconv.Atoi(q.Get("limit"))
+
+			var list [][]string
+			for i := count - 1; i >= 0 && len(list) < limit; i-- {
+				ts := base.Add(time.Duration(i) * time.Hour).UnixMilli()
+				if ts < start || ts > end {
+					continue
+				}
+				price := strconv.Itoa(100 + i)
+				list = append(list, []string{strconv.FormatInt(ts, 10), price, price, price, price, "3", "300"})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"retCode": 0,
+				"retMsg":  "OK",
+				"result":  map[string]interface{}{"symbol": q.Get("symbol"), "category": "spot", "list": list},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestBybitClient_New(t *testing.T) {
+	client := NewBybitClient()
+
+	if client.Name() != "bybit" {
+		t.Errorf("Expected name 'bybit', got '%s'", client.Name())
+	}
+}
+
+func TestBybitClient_GetKlinesPagination(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	server := newBybitTestServer(t, base, 2500)
+	defer server.Close()
+
+	client := NewBybitClient()
+	client.baseURL = server.URL
+
+	endTime := base.Add(2499 * time.Hour)
+	klines, err := client.GetKlines(context.Background(), "BTCUSDT", Timeframe1h, base, endTime, 2500)
+	if err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+
+	if len(klines) != 2500 {
+		t.Fatalf("expected 2500 klines across pages, got %d", len(klines))
+	}
+	for i := 1; i < len(klines); i++ {
+		if !klines[i].OpenTime.After(klines[i-1].OpenTime) {
+			t.Fatalf("klines not sorted or duplicated at %d", i)
+		}
+	}
+	if klines[0].Volume != 3 {
+		t.Errorf("Volume = %.0f, expected 3", klines[0].Volume)
+	}
+}
+
+func TestBybitClient_IsSymbolValid(t *testing.T) {
+	server := newBybitTestServer(t, time.Now(), 0)
+	defer server.Close()
+
+	client := NewBybitClient()
+	client.baseURL = server.URL
+
+	valid, err := client.IsSymbolValid(context.Background(), "BTCUSDT")
+	if err != nil || !valid {
+		t.Errorf("IsSymbolValid(BTCUSDT) = %v, %v", valid, err)
+	}
+
+	valid, err = client.IsSymbolValid(context.Background(), "FOOUSDT")
+	if err != nil || valid {
+		t.Errorf("IsSymbolValid(FOOUSDT) = %v, %v", valid, err)
+	}
+}
+
+func TestBybitClient_TimeframeMapping(t *testing.T) {
+	client := NewBybitClient()
+
+	if client.convertTimeframeToInterval(Timeframe1d) != "D" {
+		t.Error("1d should map to D")
+	}
+	if client.convertTimeframeToInterval(Timeframe3d) != "" {
+		t.Error("3d is not a native Bybit interval")
+	}
+}
@@ -0,0 +1,600 @@
+package datasource
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"ta-watcher/internal/resample"
+)
+
+// KlineStore 已收盘K线的持久化存储
+type KlineStore interface {
+	// Load 读取开盘时间位于 [start, end] 内的已缓存K线（按时间升序）
+	Load(source, symbol string, timeframe Timeframe, start, end time.Time) ([]*Kline, error)
+
+	// Save 写入K线（相同开盘时间覆盖），并把 covered 标记为已从上游完整获取的区间
+	Save(source, symbol string, timeframe Timeframe, klines []*Kline, covered TimeRange) error
+
+	// Coverage 返回已完整获取的开盘时间区间（按起点升序，互不重叠）
+	Coverage(source, symbol string, timeframe Timeframe) ([]TimeRange, error)
+	// SaveEmpty 记录上游在 checkedAt 时对 span 没有返回任何K线
+	SaveEmpty(source, symbol string, timeframe Timeframe, span TimeRange, checkedAt time.Time) error
+	// EmptySpans 返回检查时间不早于 since 的无数据区间（按起点升序）
+	EmptySpans(source, symbol string, timeframe Timeframe, since time.Time) ([]TimeRange, error)
+}
+
+// TimeRange 左闭右开的时间区间 [Start, End)
+type TimeRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// BoltKlineStore 基于 bbolt 的K线存储
+// 数据库文件: <directory>/klines.db，每个 数据源/交易对/时间框架 一个 bucket，
+// 其中 klines 子 bucket 以开盘时间（大端毫秒）为键，coverage 子 bucket 记录已覆盖区间，
+// empty 子 bucket 记录上游未返回数据的区间及检查时间
+type BoltKlineStore struct {
+	path string
+	db   *bolt.DB
+}
+
+var (
+	boltStoresMu sync.Mutex
+	boltStores   = make(map[string]*BoltKlineStore) // 数据库路径 -> 已打开的存储
+)
+
+var (
+	boltKlinesBucket   = []byte("klines")
+	boltCoverageBucket = []byte("coverage")
+	boltEmptyBucket    = []byte("empty")
+)
+
+// OpenBoltKlineStore 打开（或创建）K线存储
+// bbolt 对数据库文件加独占锁，因此同一进程内相同目录共享一个实例
+func OpenBoltKlineStore(directory string) (*BoltKlineStore, error) {
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	path, err := filepath.Abs(filepath.Join(directory, "klines.db"))
+	if err != nil {
+		return nil, err
+	}
+
+	boltStoresMu.Lock()
+	defer boltStoresMu.Unlock()
+
+	if store, ok := boltStores[path]; ok {
+		return store, nil
+	}
+
+	db, err := bolt.Open(path, 0644, &bolt.Options{Timeout: 3 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open kline cache %s: %w", path, err)
+	}
+
+	store := &BoltKlineStore{path: path, db: db}
+	boltStores[path] = store
+	return store, nil
+}
+
+// Close 关闭数据库
+func (s *BoltKlineStore) Close() error {
+	boltStoresMu.Lock()
+	delete(boltStores, s.path)
+	boltStoresMu.Unlock()
+	return s.db.Close()
+}
+
+// Load 按开盘时间范围读取已缓存的K线
+func (s *BoltKlineStore) Load(source, symbol string, timeframe Timeframe, start, end time.Time) ([]*Kline, error) {
+	var klines []*Kline
+	err := s.db.View(func(tx *bolt.Tx) error {
+		series := tx.Bucket(seriesBucketName(source, symbol, timeframe))
+		if series == nil {
+			return nil
+		}
+		bucket := series.Bucket(boltKlinesBucket)
+		if bucket == nil {
+			return nil
+		}
+
+		last := timeKey(end)
+		c := bucket.Cursor()
+		for k, v := c.Seek(timeKey(start)); k != nil && bytes.Compare(k, last) <= 0; k, v = c.Next() {
+			var kline Kline
+			if err := json.Unmarshal(v, &kline); err != nil {
+				return fmt.Errorf("corrupted cache entry: %w", err)
+			}
+			klines = append(klines, &kline)
+		}
+		return nil
+	})
+	return klines, err
+}
+
+// Save 在一个事务中写入K线并合并覆盖区间，只写入变化的部分
+func (s *BoltKlineStore) Save(source, symbol string, timeframe Timeframe, klines []*Kline, covered TimeRange) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		series, err := tx.CreateBucketIfNotExists(seriesBucketName(source, symbol, timeframe))
+		if err != nil {
+			return err
+		}
+
+		bucket, err := series.CreateBucketIfNotExists(boltKlinesBucket)
+		if err != nil {
+			return err
+		}
+		for _, k := range klines {
+			data, err := json.Marshal(k)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put(timeKey(k.OpenTime), data); err != nil {
+				return err
+			}
+		}
+
+		if !covered.End.After(covered.Start) {
+			return nil
+		}
+
+		ranges := readCoverage(series.Bucket(boltCoverageBucket))
+		ranges = mergeRanges(append(ranges, covered))
+
+		// 覆盖区间数量很少，直接整体重写
+		if series.Bucket(boltCoverageBucket) != nil {
+			if err := series.DeleteBucket(boltCoverageBucket); err != nil {
+				return err
+			}
+		}
+		coverage, err := series.CreateBucket(boltCoverageBucket)
+		if err != nil {
+			return err
+		}
+		for _, r := range ranges {
+			if err := coverage.Put(timeKey(r.Start), timeKey(r.End)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Coverage 返回已覆盖的区间
+func (s *BoltKlineStore) Coverage(source, symbol string, timeframe Timeframe) ([]TimeRange, error) {
+	var ranges []TimeRange
+	err := s.db.View(func(tx *bolt.Tx) error {
+		if series := tx.Bucket(seriesBucketName(source, symbol, timeframe)); series != nil {
+			ranges = readCoverage(series.Bucket(boltCoverageBucket))
+		}
+		return nil
+	})
+	return ranges, err
+}
+
+// SaveEmpty 记录无数据区间，相同起点的记录被覆盖
+func (s *BoltKlineStore) SaveEmpty(source, symbol string, timeframe Timeframe, span TimeRange, checkedAt time.Time) error {
+	if !span.End.After(span.Start) {
+		return nil
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		series, err := tx.CreateBucketIfNotExists(seriesBucketName(source, symbol, timeframe))
+		if err != nil {
+			return err
+		}
+		bucket, err := series.CreateBucketIfNotExists(boltEmptyBucket)
+		if err != nil {
+			return err
+		}
+		return bucket.Put(timeKey(span.Start), append(timeKey(span.End), timeKey(checkedAt)...))
+	})
+}
+
+// EmptySpans 返回未过期的无数据区间
+func (s *BoltKlineStore) EmptySpans(source, symbol string, timeframe Timeframe, since time.Time) ([]TimeRange, error) {
+	var ranges []TimeRange
+	err := s.db.View(func(tx *bolt.Tx) error {
+		series := tx.Bucket(seriesBucketName(source, symbol, timeframe))
+		if series == nil {
+			return nil
+		}
+		bucket := series.Bucket(boltEmptyBucket)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			if len(v) != 16 {
+				return fmt.Errorf("corrupted empty span entry")
+			}
+			if keyTime(v[8:]).Before(since) {
+				return nil
+			}
+			ranges = append(ranges, TimeRange{Start: keyTime(k), End: keyTime(v[:8])})
+			return nil
+		})
+	})
+	return ranges, err
+}
+
+// readCoverage 读取覆盖区间 bucket
+func readCoverage(bucket *bolt.Bucket) []TimeRange {
+	if bucket == nil {
+		return nil
+	}
+
+	var ranges []TimeRange
+	_ = bucket.ForEach(func(k, v []byte) error {
+		ranges = append(ranges, TimeRange{Start: keyTime(k), End: keyTime(v)})
+		return nil
+	})
+	return ranges
+}
+
+// seriesBucketName 返回序列的 bucket 名称（bbolt 键区分大小写，1m 与 1M 不会冲突）
+func seriesBucketName(source, symbol string, timeframe Timeframe) []byte {
+	return []byte(source + "/" + symbol + "/" + string(timeframe))
+}
+
+// timeKey 将时间编码为按字节序可排序的键
+func timeKey(t time.Time) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(t.UnixMilli())^(1<<63))
+	return key
+}
+
+// keyTime 解码 timeKey
+func keyTime(key []byte) time.Time {
+	return time.UnixMilli(int64(binary.BigEndian.Uint64(key) ^ (1 << 63))).UTC()
+}
+
+// mergeRanges 合并重叠或相接的区间
+func mergeRanges(ranges []TimeRange) []TimeRange {
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].Start.Before(ranges[j].Start)
+	})
+
+	merged := []TimeRange{ranges[0]}
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r.Start.After(last.End) {
+			merged = append(merged, r)
+			continue
+		}
+		if r.End.After(last.End) {
+			last.End = r.End
+		}
+	}
+	return merged
+}
+
+// subtractRanges 返回 want 中未被 covered 覆盖的部分（covered 需已合并排序）
+func subtractRanges(want TimeRange, covered []TimeRange) []TimeRange {
+	var missing []TimeRange
+	cursor := want.Start
+	for _, r := range covered {
+		if !r.End.After(cursor) {
+			continue
+		}
+		if !r.Start.Before(want.End) {
+			break
+		}
+		if r.Start.After(cursor) {
+			missing = append(missing, TimeRange{Start: cursor, End: r.Start})
+		}
+		cursor = r.End
+	}
+	if want.End.After(cursor) {
+		missing = append(missing, TimeRange{Start: cursor, End: want.End})
+	}
+	return missing
+}
+
+// CacheStats 缓存命中统计
+type CacheStats struct {
+	Source        string // 被缓存的数据源名称
+	Hits          int64  // 缓存已覆盖整个请求范围，仅需补齐尾部的请求数
+	Misses        int64  // 存在缺失区间需要请求上游的请求数
+	CachedKlines  int64  // 从缓存直接返回的K线数量
+	FetchedKlines int64  // 从上游获取的K线数量
+}
+
+// cacheFillRounds 单次请求中补齐缺失区间的最大轮数（上游单次返回条数有限时需要多轮）
+const cacheFillRounds = 8
+
+// cacheEmptySpanTTL 上游未返回数据的区间在此时间内不再重复请求
+// 空结果可能来自交易所的历史数据上限（如 Kraken 只返回最近720根）或暂时的空页，不能永久视为已覆盖
+const cacheEmptySpanTTL = time.Hour
+
+// CachedDataSource 带持久化缓存的数据源装饰器
+// 已收盘的K线不会再变化，缓存记录已从上游完整获取的区间，之后只请求缺失的区间和未收盘的尾部
+type CachedDataSource struct {
+	inner DataSource
+	store KlineStore
+	now   func() time.Time
+
+	mu    sync.Mutex
+	stats CacheStats
+}
+
+// NewCachedDataSource 创建带缓存的数据源
+func NewCachedDataSource(inner DataSource, store KlineStore) *CachedDataSource {
+	log.Printf("💾 启用K线缓存: %s", inner.Name())
+	return &CachedDataSource{
+		inner: inner,
+		store: store,
+		now:   time.Now,
+		stats: CacheStats{Source: inner.Name()},
+	}
+}
+
+// Name 返回数据源名称
+func (c *CachedDataSource) Name() string {
+	return c.inner.Name()
+}
+
+// IsSymbolValid 检查交易对是否有效
+func (c *CachedDataSource) IsSymbolValid(ctx context.Context, symbol string) (bool, error) {
+	return c.inner.IsSymbolValid(ctx, symbol)
+}
+
+// Unwrap 返回被包装的数据源
+func (c *CachedDataSource) Unwrap() []DataSource {
+	return []DataSource{c.inner}
+}
+
+// Stats 返回缓存命中统计
+func (c *CachedDataSource) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// GetKlines 获取K线数据，优先使用缓存，只向上游请求缺失的区间和未收盘的尾部
+func (c *CachedDataSource) GetKlines(ctx context.Context, symbol string, timeframe Timeframe, startTime, endTime time.Time, limit int) ([]*Kline, error) {
+	step := timeframe.Duration()
+	if step == 0 {
+		return c.inner.GetKlines(ctx, symbol, timeframe, startTime, endTime, limit)
+	}
+	if limit <= 0 {
+		limit = 500
+	}
+
+	now := c.now()
+	if endTime.IsZero() {
+		endTime = now
+	}
+	if startTime.IsZero() {
+		startTime = endTime.Add(-time.Duration(limit) * step)
+	}
+
+	// 开盘时间不晚于 closedBefore 的K线均已收盘，只有这部分会被缓存
+	closedBefore := lastClosedOpenTime(now, timeframe)
+	window := TimeRange{Start: startTime, End: minTime(endTime, closedBefore).Add(time.Millisecond)}
+
+	var fetched []*Kline
+	tailFetched := !endTime.After(closedBefore)
+	hit := window.End.After(window.Start)
+	for round := 0; round < cacheFillRounds && window.End.After(window.Start); round++ {
+		covered, err := c.coverage(symbol, timeframe, now)
+		if err != nil {
+			log.Printf("⚠️ [%s] 读取K线缓存失败，忽略缓存: %v", c.Name(), err)
+			c.record(false, 0, 0)
+			return c.inner.GetKlines(ctx, symbol, timeframe, startTime, endTime, limit)
+		}
+
+		missing := subtractRanges(window, covered)
+		if len(missing) == 0 {
+			break
+		}
+		hit = false
+
+		saved := true
+		for i, span := range missing {
+			// 最后一个缺失区间与未收盘尾部相接时一并获取
+			spanEnd := span.End.Add(-time.Millisecond)
+			withTail := !tailFetched && i == len(missing)-1 && span.End.Equal(window.End)
+			if withTail {
+				spanEnd = endTime
+			}
+
+			klines, err := c.inner.GetKlines(ctx, symbol, timeframe, span.Start, spanEnd, int(spanEnd.Sub(span.Start)/step)+2)
+			if err != nil {
+				return nil, err
+			}
+			tailFetched = tailFetched || withTail
+			fetched = append(fetched, klines...)
+
+			closed, coverage := fetchedCoverage(span, klines, timeframe)
+			if len(closed) == 0 {
+				err = c.store.SaveEmpty(c.inner.Name(), symbol, timeframe, span, now)
+			} else {
+				err = c.store.Save(c.inner.Name(), symbol, timeframe, closed, coverage)
+			}
+			if err != nil {
+				log.Printf("⚠️ [%s] 保存K线缓存失败: %v", c.Name(), err)
+				saved = false
+			}
+		}
+		if !saved {
+			break
+		}
+	}
+
+	cached, err := c.store.Load(c.inner.Name(), symbol, timeframe, startTime, endTime)
+	if err != nil {
+		log.Printf("⚠️ [%s] 读取K线缓存失败: %v", c.Name(), err)
+		cached = nil
+	}
+
+	if !tailFetched {
+		// 尾部从最后一根已缓存K线的下一周期开始
+		tailStart := maxTime(startTime, closedBefore.Add(time.Millisecond))
+		if len(cached) > 0 {
+			tailStart = maxTime(startTime, resample.PeriodEnd(cached[len(cached)-1].OpenTime, timeframe))
+		}
+		if !tailStart.After(endTime) {
+			klines, err := c.inner.GetKlines(ctx, symbol, timeframe, tailStart, endTime, int(endTime.Sub(tailStart)/step)+2)
+			if err != nil {
+				return nil, err
+			}
+			fetched = append(fetched, klines...)
+		}
+	}
+
+	merged := mergeKlines(cached, fetched)
+	result := make([]*Kline, 0, limit)
+	for _, k := range merged {
+		if k.OpenTime.Before(startTime) || k.OpenTime.After(endTime) {
+			continue
+		}
+		copied := *k
+		result = append(result, &copied)
+	}
+	if len(result) > limit {
+		result = result[len(result)-limit:]
+	}
+	markClosed(result, now)
+
+	c.record(hit, len(fetched), countCached(result, fetched))
+	return result, nil
+}
+
+// record 更新命中统计
+func (c *CachedDataSource) record(hit bool, fetched, cached int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if hit {
+		c.stats.Hits++
+	} else {
+		c.stats.Misses++
+	}
+	c.stats.FetchedKlines += int64(fetched)
+	c.stats.CachedKlines += int64(cached)
+}
+
+// coverage 返回已覆盖的区间，以及未过期的无数据区间
+func (c *CachedDataSource) coverage(symbol string, timeframe Timeframe, now time.Time) ([]TimeRange, error) {
+	covered, err := c.store.Coverage(c.inner.Name(), symbol, timeframe)
+	if err != nil {
+		return nil, err
+	}
+	empty, err := c.store.EmptySpans(c.inner.Name(), symbol, timeframe, now.Add(-cacheEmptySpanTTL))
+	if err != nil {
+		return nil, err
+	}
+	return mergeRanges(append(covered, empty...)), nil
+}
+
+// fetchedCoverage 返回本次获取结果中位于缺失区间内的已收盘K线，以及可以标记为已覆盖的区间
+// 上游没有返回数据时不标记覆盖（由调用方记录为有时效的无数据区间）；否则覆盖从首根K线到末根K线所在周期结束，
+// 首根K线距区间起点不足一个周期时延伸到起点，其余部分留给下一轮请求
+func fetchedCoverage(span TimeRange, klines []*Kline, timeframe Timeframe) ([]*Kline, TimeRange) {
+	var closed []*Kline
+	for _, k := range klines {
+		if !k.OpenTime.Before(span.Start) && k.OpenTime.Before(span.End) {
+			closed = append(closed, k)
+		}
+	}
+	if len(closed) == 0 {
+		return nil, TimeRange{}
+	}
+
+	sortKlinesByTime(closed)
+	first, last := closed[0].OpenTime, closed[len(closed)-1].OpenTime
+	coverage := TimeRange{Start: first, End: resample.PeriodEnd(last, timeframe)}
+	if first.Sub(span.Start) < timeframe.Duration() {
+		coverage.Start = span.Start
+	}
+	return closed, coverage
+}
+
+// lastClosedOpenTime 返回在 now 时已收盘K线的最晚开盘时间
+func lastClosedOpenTime(now time.Time, timeframe Timeframe) time.Time {
+	if timeframe == Timeframe1M {
+		return now.AddDate(0, -1, 0)
+	}
+	return now.Add(-timeframe.Duration())
+}
+
+// minTime 返回较早的时间
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// maxTime 返回较晚的时间
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// mergeKlines 合并两组K线，按开盘时间去重（后者覆盖前者）并排序
+func mergeKlines(base, updates []*Kline) []*Kline {
+	byTime := make(map[int64]*Kline, len(base)+len(updates))
+	for _, k := range base {
+		byTime[k.OpenTime.UnixMilli()] = k
+	}
+	for _, k := range updates {
+		byTime[k.OpenTime.UnixMilli()] = k
+	}
+
+	merged := make([]*Kline, 0, len(byTime))
+	for _, k := range byTime {
+		merged = append(merged, k)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].OpenTime.Before(merged[j].OpenTime)
+	})
+	return merged
+}
+
+// countCached 统计结果中来自缓存（非本次获取）的K线数量
+func countCached(result, fetched []*Kline) int {
+	fetchedTimes := make(map[int64]bool, len(fetched))
+	for _, k := range fetched {
+		fetchedTimes[k.OpenTime.UnixMilli()] = true
+	}
+
+	count := 0
+	for _, k := range result {
+		if !fetchedTimes[k.OpenTime.UnixMilli()] {
+			count++
+		}
+	}
+	return count
+}
+
+// CollectCacheStats 收集数据源（及其包装的数据源）的缓存统计
+func CollectCacheStats(ds DataSource) []CacheStats {
+	var stats []CacheStats
+	if cached, ok := ds.(*CachedDataSource); ok {
+		stats = append(stats, cached.Stats())
+	}
+	if wrapper, ok := ds.(Unwrapper); ok {
+		for _, inner := range wrapper.Unwrap() {
+			stats = append(stats, CollectCacheStats(inner)...)
+		}
+	}
+	return stats
+}
@@ -0,0 +1,357 @@
+package datasource
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestKlineStore 在临时目录中打开K线存储，测试结束时关闭
+func newTestKlineStore(t *testing.T) *BoltKlineStore {
+	t.Helper()
+	store, err := OpenBoltKlineStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("OpenBoltKlineStore() error = %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+// rangeDataSource 按时间范围返回数据并记录请求起点的测试数据源
+type rangeDataSource struct {
+	mu       sync.Mutex
+	klines   []*Kline
+	starts   []time.Time
+	err      error
+	maxBatch int // 大于0时每次最多返回从起点开始的 maxBatch 根K线
+}
+
+func (r *rangeDataSource) Name() string {
+	return "range"
+}
+
+func (r *rangeDataSource) GetKlines(ctx context.Context, symbol string, timeframe Timeframe, startTime, endTime time.Time, limit int) ([]*Kline, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.starts = append(r.starts, startTime)
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	var result []*Kline
+	for _, k := range r.klines {
+		if k.OpenTime.Before(startTime) || k.OpenTime.After(endTime) {
+			continue
+		}
+		copied := *k
+		result = append(result, &copied)
+		if r.maxBatch > 0 && len(result) == r.maxBatch {
+			break
+		}
+	}
+	return result, nil
+}
+
+func (r *rangeDataSource) IsSymbolValid(ctx context.Context, symbol string) (bool, error) {
+	return true, nil
+}
+
+func TestCachedDataSource_FetchesOnlyMissingTail(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	inner := &rangeDataSource{klines: makeTestKlines("BTCUSDT", base, time.Hour, 1, 2, 3, 4, 5, 6)}
+
+	store := newTestKlineStore(t)
+	cached := NewCachedDataSource(inner, store)
+	// 当前时间位于第5根K线内，第5、6根尚未收盘
+	cached.now = func() time.Time { return base.Add(4*time.Hour + 30*time.Minute) }
+
+	end := base.Add(5 * time.Hour)
+	klines, err := cached.GetKlines(context.Background(), "BTCUSDT", Timeframe1h, base, end, 10)
+	if err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if len(klines) != 6 {
+		t.Fatalf("expected 6 klines, got %d", len(klines))
+	}
+
+	stats := cached.Stats()
+	if stats.Misses != 1 || stats.Hits != 0 {
+		t.Errorf("unexpected stats after first request: %+v", stats)
+	}
+
+	// 第二次请求只应从最后一根已收盘K线之后开始获取
+	inner.klines[4].Close = 50
+	klines, err = cached.GetKlines(context.Background(), "BTCUSDT", Timeframe1h, base, end, 10)
+	if err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if got := inner.starts[1]; !got.Equal(base.Add(4 * time.Hour)) {
+		t.Errorf("tail fetch should start at first unclosed kline, got %v", got)
+	}
+	if klines[4].Close != 50 {
+		t.Errorf("unclosed kline should be refreshed from source, got close %v", klines[4].Close)
+	}
+
+	stats = cached.Stats()
+	if stats.Hits != 1 || stats.CachedKlines != 4 {
+		t.Errorf("unexpected stats after second request: %+v", stats)
+	}
+
+	// 重新创建后应从磁盘读取缓存
+	reloaded := NewCachedDataSource(inner, store)
+	reloaded.now = cached.now
+	if _, err := reloaded.GetKlines(context.Background(), "BTCUSDT", Timeframe1h, base, end, 10); err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if reloaded.Stats().Hits != 1 {
+		t.Errorf("persisted cache should be hit after reload: %+v", reloaded.Stats())
+	}
+}
+
+func TestCachedDataSource_MissBeforeCachedRange(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	inner := &rangeDataSource{klines: makeTestKlines("ETHUSDT", base, time.Hour, 1, 2, 3, 4)}
+
+	store := newTestKlineStore(t)
+	cached := NewCachedDataSource(inner, store)
+	cached.now = func() time.Time { return base.Add(24 * time.Hour) }
+
+	end := base.Add(3 * time.Hour)
+	if _, err := cached.GetKlines(context.Background(), "ETHUSDT", Timeframe1h, base.Add(2*time.Hour), end, 10); err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+
+	// 请求起点早于缓存时需要完整获取
+	klines, err := cached.GetKlines(context.Background(), "ETHUSDT", Timeframe1h, base, end, 10)
+	if err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if len(klines) != 4 {
+		t.Errorf("expected 4 klines, got %d", len(klines))
+	}
+	if stats := cached.Stats(); stats.Misses != 2 {
+		t.Errorf("expected 2 misses, got %+v", stats)
+	}
+}
+
+func TestCachedDataSource_PropagatesError(t *testing.T) {
+	inner := &rangeDataSource{err: errors.New("down")}
+	store := newTestKlineStore(t)
+
+	cached := NewCachedDataSource(inner, store)
+	if _, err := cached.GetKlines(context.Background(), "BTCUSDT", Timeframe1h, time.Time{}, time.Time{}, 10); err == nil {
+		t.Error("expected error from underlying source")
+	}
+}
+
+func TestCollectCacheStats(t *testing.T) {
+	store := newTestKlineStore(t)
+
+	primary := NewCachedDataSource(&stubDataSource{name: "primary"}, store)
+	fallback := &stubDataSource{name: "fallback"}
+	ds := NewFailoverDataSource(primary, fallback, time.Minute)
+
+	stats := CollectCacheStats(ds)
+	if len(stats) != 1 || stats[0].Source != "primary" {
+		t.Errorf("unexpected cache stats: %+v", stats)
+	}
+}
+
+func TestCachedDataSource_FillsTruncatedFetch(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// 上游每次最多返回4根K线（类似 Binance 按起点截断）
+	inner := &rangeDataSource{
+		klines:   makeTestKlines("BTCUSDT", base, time.Hour, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10),
+		maxBatch: 4,
+	}
+
+	cached := NewCachedDataSource(inner, newTestKlineStore(t))
+	cached.now = func() time.Time { return base.Add(48 * time.Hour) }
+
+	end := base.Add(9 * time.Hour)
+	klines, err := cached.GetKlines(context.Background(), "BTCUSDT", Timeframe1h, base, end, 20)
+	if err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if len(klines) != 10 {
+		t.Fatalf("truncated fetch should be continued until the range is covered, got %d klines", len(klines))
+	}
+
+	// 范围已完整覆盖，再次请求不应访问上游
+	requests := len(inner.starts)
+	if _, err := cached.GetKlines(context.Background(), "BTCUSDT", Timeframe1h, base, end, 20); err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if len(inner.starts) != requests {
+		t.Errorf("covered range should not be fetched again, got %d extra requests", len(inner.starts)-requests)
+	}
+	if stats := cached.Stats(); stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestCachedDataSource_RefillsInternalGap(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	inner := &rangeDataSource{klines: makeTestKlines("BTCUSDT", base, time.Hour, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10)}
+
+	cached := NewCachedDataSource(inner, newTestKlineStore(t))
+	cached.now = func() time.Time { return base.Add(48 * time.Hour) }
+	ctx := context.Background()
+
+	// 先缓存首尾两段，中间第4-6根K线缺失
+	if _, err := cached.GetKlines(ctx, "BTCUSDT", Timeframe1h, base, base.Add(2*time.Hour), 10); err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if _, err := cached.GetKlines(ctx, "BTCUSDT", Timeframe1h, base.Add(6*time.Hour), base.Add(9*time.Hour), 10); err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+
+	inner.starts = nil
+	klines, err := cached.GetKlines(ctx, "BTCUSDT", Timeframe1h, base, base.Add(9*time.Hour), 20)
+	if err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if len(klines) != 10 {
+		t.Fatalf("expected 10 klines, got %d", len(klines))
+	}
+	if len(inner.starts) != 1 || !inner.starts[0].Equal(base.Add(3*time.Hour)) {
+		t.Errorf("only the internal gap should be fetched, got requests at %v", inner.starts)
+	}
+	for i, k := range klines {
+		if k.Close != float64(i+1) {
+			t.Errorf("kline %d: expected close %d, got %v", i, i+1, k.Close)
+		}
+	}
+}
+
+func TestCachedDataSource_EmptySpanIsCovered(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// 上游数据从第5根K线开始（如新上市的交易对）
+	inner := &rangeDataSource{klines: makeTestKlines("NEWUSDT", base.Add(4*time.Hour), time.Hour, 5, 6, 7)}
+
+	cached := NewCachedDataSource(inner, newTestKlineStore(t))
+	cached.now = func() time.Time { return base.Add(48 * time.Hour) }
+	ctx := context.Background()
+
+	klines, err := cached.GetKlines(ctx, "NEWUSDT", Timeframe1h, base, base.Add(6*time.Hour), 10)
+	if err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if len(klines) != 3 {
+		t.Fatalf("expected 3 klines, got %d", len(klines))
+	}
+
+	requests := len(inner.starts)
+	if _, err := cached.GetKlines(ctx, "NEWUSDT", Timeframe1h, base, base.Add(6*time.Hour), 10); err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if len(inner.starts) != requests {
+		t.Errorf("span without upstream data should be remembered, got %d extra requests", len(inner.starts)-requests)
+	}
+
+	// 空结果可能来自历史数据上限，过期后重新请求并补上之后出现的数据
+	inner.klines = makeTestKlines("NEWUSDT", base, time.Hour, 1, 2, 3, 4, 5, 6, 7)
+	cached.now = func() time.Time { return base.Add(48*time.Hour + cacheEmptySpanTTL + time.Minute) }
+	klines, err = cached.GetKlines(ctx, "NEWUSDT", Timeframe1h, base, base.Add(6*time.Hour), 10)
+	if err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if len(inner.starts) == requests || len(klines) != 7 {
+		t.Errorf("expired empty span should be refetched, got %d klines", len(klines))
+	}
+}
+
+func TestBoltKlineStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := OpenBoltKlineStore(dir)
+	if err != nil {
+		t.Fatalf("OpenBoltKlineStore() error = %v", err)
+	}
+
+	// 同一目录共享实例，避免数据库文件锁冲突
+	again, err := OpenBoltKlineStore(dir)
+	if err != nil || again != store {
+		t.Fatalf("same directory should share one store: %v", err)
+	}
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	klines := makeTestKlines("BTCUSDT", base, time.Hour, 1, 2, 3, 4)
+	if err := store.Save("binance", "BTCUSDT", Timeframe1h, klines[:2], TimeRange{base, base.Add(2 * time.Hour)}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := store.Save("binance", "BTCUSDT", Timeframe1h, klines[2:], TimeRange{base.Add(2 * time.Hour), base.Add(4 * time.Hour)}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := store.Save("binance", "BTCUSDT", Timeframe1M, nil, TimeRange{base.AddDate(1, 0, 0), base.AddDate(1, 1, 0)}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	coverage, err := store.Coverage("binance", "BTCUSDT", Timeframe1h)
+	if err != nil {
+		t.Fatalf("Coverage() error = %v", err)
+	}
+	if len(coverage) != 1 || !coverage[0].Start.Equal(base) || !coverage[0].End.Equal(base.Add(4*time.Hour)) {
+		t.Errorf("adjacent ranges should be merged, got %+v", coverage)
+	}
+
+	loaded, err := store.Load("binance", "BTCUSDT", Timeframe1h, base.Add(time.Hour), base.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(loaded) != 2 || loaded[0].Close != 2 || loaded[1].Close != 3 {
+		t.Errorf("unexpected range load: %+v", loaded)
+	}
+
+	// 关闭后重新打开，数据仍在；1m 与 1M 互不影响
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	store, err = OpenBoltKlineStore(dir)
+	if err != nil {
+		t.Fatalf("OpenBoltKlineStore() error = %v", err)
+	}
+	defer store.Close()
+
+	loaded, err = store.Load("binance", "BTCUSDT", Timeframe1h, base, base.Add(24*time.Hour))
+	if err != nil || len(loaded) != 4 {
+		t.Errorf("persisted klines should be reloaded: %d, %v", len(loaded), err)
+	}
+	if coverage, _ := store.Coverage("binance", "BTCUSDT", Timeframe1m); len(coverage) != 0 {
+		t.Errorf("1m should not share coverage with 1M: %+v", coverage)
+	}
+
+	// 无数据区间按检查时间过期
+	checkedAt := base.Add(48 * time.Hour)
+	empty := TimeRange{base.Add(-24 * time.Hour), base}
+	if err := store.SaveEmpty("binance", "BTCUSDT", Timeframe1h, empty, checkedAt); err != nil {
+		t.Fatalf("SaveEmpty() error = %v", err)
+	}
+	if spans, err := store.EmptySpans("binance", "BTCUSDT", Timeframe1h, checkedAt); err != nil || len(spans) != 1 || spans[0] != empty {
+		t.Errorf("EmptySpans() = %+v, %v", spans, err)
+	}
+	if spans, _ := store.EmptySpans("binance", "BTCUSDT", Timeframe1h, checkedAt.Add(time.Second)); len(spans) != 0 {
+		t.Errorf("expired empty spans should be ignored: %+v", spans)
+	}
+}
+
+func TestSubtractRanges(t *testing.T) {
+	at := func(h int) time.Time { return time.Date(2024, 1, 1, h, 0, 0, 0, time.UTC) }
+	covered := []TimeRange{{at(2), at(4)}, {at(6), at(8)}}
+
+	missing := subtractRanges(TimeRange{at(0), at(10)}, covered)
+	expected := []TimeRange{{at(0), at(2)}, {at(4), at(6)}, {at(8), at(10)}}
+	if len(missing) != len(expected) {
+		t.Fatalf("expected %d missing ranges, got %+v", len(expected), missing)
+	}
+	for i := range expected {
+		if !missing[i].Start.Equal(expected[i].Start) || !missing[i].End.Equal(expected[i].End) {
+			t.Errorf("range %d: expected %+v, got %+v", i, expected[i], missing[i])
+		}
+	}
+
+	if missing := subtractRanges(TimeRange{at(2), at(4)}, covered); len(missing) != 0 {
+		t.Errorf("fully covered range should have no gaps, got %+v", missing)
+	}
+}
@@ -7,19 +7,20 @@ import (
 	"net/http"
 	"sort"
 	"strconv"
-	"sync"
 	"time"
 
 	"ta-watcher/internal/config"
+	"ta-watcher/internal/instrument"
 )
 
 // CoinbaseClient Coinbase数据源实现
 type CoinbaseClient struct {
-	baseURL      string
-	client       *http.Client
-	rateLimit    *config.RateLimitConfig
-	lastRequest  time.Time
-	requestMutex sync.Mutex
+	baseURL   string
+	wsURL     string
+	client    *http.Client
+	rateLimit *config.RateLimitConfig
+	symbols   symbolCatalog
+	resampling
 }
 
 // NewCoinbaseClient 创建Coinbase客户端（已废弃，请使用NewCoinbaseClientWithConfig）
@@ -32,6 +33,7 @@ func NewCoinbaseClient() *CoinbaseClient {
 func NewCoinbaseClientWithConfig(cfg *config.CoinbaseConfig) *CoinbaseClient {
 	client := &CoinbaseClient{
 		baseURL: "https://api.exchange.coinbase.com",
+		wsURL:   "wss://ws-feed.exchange.coinbase.com",
 		client:  &http.Client{Timeout: 60 * time.Second}, // 增加到60秒
 	}
 
@@ -58,6 +60,11 @@ func (c *CoinbaseClient) Name() string {
 	return "coinbase"
 }
 
+// SetHTTPTransport 替换底层 HTTP 传输层（用于录制/回放测试）
+func (c *CoinbaseClient) SetHTTPTransport(rt http.RoundTripper) {
+	c.client.Transport = rt
+}
+
 // IsSymbolValid 检查交易对是否有效
 func (c *CoinbaseClient) IsSymbolValid(ctx context.Context, symbol string) (bool, error) {
 	// 转换为Coinbase格式 (BTCUSDT -> BTC-USDT)
@@ -80,6 +87,81 @@ func (c *CoinbaseClient) IsSymbolValid(ctx context.Context, symbol string) (bool
 	return valid, nil
 }
 
+// coinbaseProduct /products 中的交易对
+type coinbaseProduct struct {
+	ID              string `json:"id"`
+	BaseCurrency    string `json:"base_currency"`
+	QuoteCurrency   string `json:"quote_currency"`
+	QuoteIncrement  string `json:"quote_increment"`
+	BaseIncrement   string `json:"base_increment"`
+	MinMarketFunds  string `json:"min_market_funds"`
+	Status          string `json:"status"`
+	TradingDisabled bool   `json:"trading_disabled"`
+	CancelOnly      bool   `json:"cancel_only"`
+}
+
+// SymbolInfo 返回交易对元数据（来自缓存的 /products）
+func (c *CoinbaseClient) SymbolInfo(ctx context.Context, symbol string) (*SymbolInfo, error) {
+	return c.symbols.lookup(ctx, c.Name(), c.convertToCoinbaseSymbol(symbol), c.fetchProducts)
+}
+
+// Symbols 返回全部交易对元数据
+func (c *CoinbaseClient) Symbols(ctx context.Context) ([]*SymbolInfo, error) {
+	return c.symbols.all(ctx, c.Name(), c.fetchProducts)
+}
+
+// fetchProducts 获取全部交易对
+func (c *CoinbaseClient) fetchProducts(ctx context.Context) ([]*SymbolInfo, error) {
+	url := fmt.Sprintf("%s/products", c.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.executeWithRateLimit(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("coinbase", resp)
+	}
+
+	var products []coinbaseProduct
+	if err := json.NewDecoder(resp.Body).Decode(&products); err != nil {
+		return nil, err
+	}
+
+	infos := make([]*SymbolInfo, 0, len(products))
+	for _, p := range products {
+		info := &SymbolInfo{
+			Symbol: normalizeSymbol(p.ID),
+			Base:   p.BaseCurrency,
+			Quote:  p.QuoteCurrency,
+			Status: c.convertProductStatus(p),
+		}
+		info.TickSize, info.PricePrecision = parseIncrement(p.QuoteIncrement)
+		info.LotSize, _ = parseIncrement(p.BaseIncrement)
+		info.MinNotional, _ = strconv.ParseFloat(p.MinMarketFunds, 64)
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+// convertProductStatus 转换 Coinbase 交易对状态
+func (c *CoinbaseClient) convertProductStatus(p coinbaseProduct) SymbolStatus {
+	switch {
+	case p.Status == "delisted":
+		return SymbolStatusDelisted
+	case p.Status != "online" || p.TradingDisabled || p.CancelOnly:
+		return SymbolStatusHalted
+	default:
+		return SymbolStatusTrading
+	}
+}
+
 // GetKlines 获取K线数据（支持分页和聚合）
 func (c *CoinbaseClient) GetKlines(ctx context.Context, symbol string, timeframe Timeframe, startTime, endTime time.Time, limit int) ([]*Kline, error) {
 	if limit <= 0 {
@@ -91,13 +173,8 @@ func (c *CoinbaseClient) GetKlines(ctx context.Context, symbol string, timeframe
 	granularity := c.convertTimeframeToGranularity(timeframe)
 
 	if granularity == 0 {
-		return nil, fmt.Errorf("unsupported timeframe: %s", timeframe)
-	}
-
-	// 对于日线以上的时间框架，可能需要获取更多数据进行聚合
-	baseGranularity := granularity
-	if timeframe == Timeframe1w || timeframe == Timeframe1M {
-		baseGranularity = 86400 // 使用日线数据进行聚合
+		// Coinbase 仅支持 1m/5m/15m/1h/6h/1d，其余周期由原生周期重采样
+		return getResampledKlines(ctx, c.GetKlines, c.supportsTimeframe, c.resampleOptions(), symbol, timeframe, startTime, endTime, limit)
 	}
 
 	// 设置默认时间范围
@@ -108,7 +185,7 @@ func (c *CoinbaseClient) GetKlines(ctx context.Context, symbol string, timeframe
 		}
 		if startTime.IsZero() {
 			// 根据请求的数据量设置开始时间
-			duration := time.Duration(limit*baseGranularity) * time.Second
+			duration := time.Duration(limit*granularity) * time.Second
 			startTime = endTime.Add(-duration)
 		}
 	}
@@ -120,19 +197,19 @@ func (c *CoinbaseClient) GetKlines(ctx context.Context, symbol string, timeframe
 	// 分批获取数据
 	for currentEnd := endTime; currentEnd.After(startTime); {
 		batchCount++
-		currentStart := currentEnd.Add(-time.Duration(batchSize) * time.Second * time.Duration(baseGranularity))
+		currentStart := currentEnd.Add(-time.Duration(batchSize) * time.Second * time.Duration(granularity))
 		if currentStart.Before(startTime) {
 			currentStart = startTime
 		}
 
-		klines, err := c.fetchKlinesBatch(ctx, coinbaseSymbol, baseGranularity, currentStart, currentEnd)
+		klines, err := c.fetchKlinesBatch(ctx, coinbaseSymbol, granularity, currentStart, currentEnd)
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch batch: %w", err)
 		}
 
 		// 转换为标准格式
 		for _, raw := range klines {
-			kline, err := c.parseCandle(symbol, raw)
+			kline, err := c.parseCandle(symbol, raw, granularity)
 			if err != nil {
 				continue // 跳过无法解析的数据
 			}
@@ -150,25 +227,19 @@ func (c *CoinbaseClient) GetKlines(ctx context.Context, symbol string, timeframe
 	// 按时间排序（Coinbase返回的数据可能是倒序）
 	sortKlinesByTime(allKlines)
 
-	// 如果需要聚合（周线、月线）
-	if timeframe == Timeframe1w || timeframe == Timeframe1M {
-		allKlines = c.aggregateKlines(allKlines, timeframe)
-	}
-
 	// 限制返回数量
 	if len(allKlines) > limit {
 		allKlines = allKlines[len(allKlines)-limit:]
 	}
 
-	if len(allKlines) > 0 {
-	}
+	markClosed(allKlines, time.Now())
 
 	return allKlines, nil
 }
 
 // parseCandle 解析Coinbase蜡烛图数据
 // Coinbase格式: [timestamp, low, high, open, close, volume]
-func (c *CoinbaseClient) parseCandle(symbol string, raw []float64) (*Kline, error) {
+func (c *CoinbaseClient) parseCandle(symbol string, raw []float64, granularity int) (*Kline, error) {
 	if len(raw) < 6 {
 		return nil, fmt.Errorf("invalid candle data length: %d", len(raw))
 	}
@@ -178,7 +249,7 @@ func (c *CoinbaseClient) parseCandle(symbol string, raw []float64) (*Kline, erro
 	return &Kline{
 		Symbol:    symbol,
 		OpenTime:  timestamp,
-		CloseTime: timestamp.Add(time.Minute), // 简化处理，实际应根据timeframe计算
+		CloseTime: timestamp.Add(time.Duration(granularity)*time.Second - time.Millisecond),
 		Open:      raw[3],
 		High:      raw[2],
 		Low:       raw[1],
@@ -190,25 +261,12 @@ func (c *CoinbaseClient) parseCandle(symbol string, raw []float64) (*Kline, erro
 // convertToCoinbaseSymbol 转换为Coinbase交易对格式
 // BTCUSDT -> BTC-USDT
 func (c *CoinbaseClient) convertToCoinbaseSymbol(symbol string) string {
-	// 简化处理，假设都是对USDT或USD的交易对
-	if len(symbol) >= 6 {
-		if symbol[len(symbol)-4:] == "USDT" {
-			base := symbol[:len(symbol)-4]
-			return base + "-USDT"
-		} else if symbol[len(symbol)-3:] == "USD" {
-			base := symbol[:len(symbol)-3]
-			return base + "-USD"
-		}
-	}
-
-	// 默认处理：假设最后3个字符是quote currency
-	if len(symbol) > 3 {
-		base := symbol[:len(symbol)-3]
-		quote := symbol[len(symbol)-3:]
-		return base + "-" + quote
-	}
+	return nativeSymbol(instrument.Coinbase, symbol)
+}
 
-	return symbol
+// supportsTimeframe 判断Coinbase是否原生支持该时间框架
+func (c *CoinbaseClient) supportsTimeframe(tf Timeframe) bool {
+	return c.convertTimeframeToGranularity(tf) != 0
 }
 
 // convertTimeframeToGranularity 转换时间框架为Coinbase粒度（秒）
@@ -226,8 +284,6 @@ func (c *CoinbaseClient) convertTimeframeToGranularity(tf Timeframe) int {
 		return 21600
 	case Timeframe1d:
 		return 86400
-	case Timeframe1w, Timeframe1M:
-		return 86400 // 使用日线数据进行聚合
 	default:
 		return 0 // 不支持的时间框架
 	}
@@ -260,7 +316,7 @@ func (c *CoinbaseClient) fetchKlinesBatch(ctx context.Context, coinbaseSymbol st
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("coinbase API returned status: %d", resp.StatusCode)
+		return nil, statusError("coinbase", resp)
 	}
 
 	var rawCandles [][]float64
@@ -278,162 +334,114 @@ func sortKlinesByTime(klines []*Kline) {
 	})
 }
 
-// aggregateKlines 聚合K线数据（日线->周线/月线）
-func (c *CoinbaseClient) aggregateKlines(dailyKlines []*Kline, targetTimeframe Timeframe) []*Kline {
-	if len(dailyKlines) == 0 {
-		return dailyKlines
+// GetOrderBook 获取订单簿快照（level=2 返回聚合后的完整订单簿，按 depth 截取）
+func (c *CoinbaseClient) GetOrderBook(ctx context.Context, symbol string, depth int) (*OrderBook, error) {
+	url := fmt.Sprintf("%s/products/%s/book?level=2", c.baseURL, c.convertToCoinbaseSymbol(symbol))
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	var aggregated []*Kline
-	var currentPeriod []*Kline
-
-	for _, kline := range dailyKlines {
-		// 判断是否需要开始新的聚合周期
-		if len(currentPeriod) == 0 {
-			currentPeriod = append(currentPeriod, kline)
-			continue
-		}
-
-		lastKline := currentPeriod[len(currentPeriod)-1]
-		shouldStartNew := false
-
-		switch targetTimeframe {
-		case Timeframe1w:
-			// 周线：计算周开始日期（以周一为起始）
-			lastWeekStart := getWeekStart(lastKline.OpenTime)
-			currentWeekStart := getWeekStart(kline.OpenTime)
-			if !lastWeekStart.Equal(currentWeekStart) {
-				shouldStartNew = true
-			}
-		case Timeframe1M:
-			// 月线：不同月份开始新周期
-			if kline.OpenTime.Year() != lastKline.OpenTime.Year() ||
-				kline.OpenTime.Month() != lastKline.OpenTime.Month() {
-				shouldStartNew = true
-			}
-		}
-
-		if shouldStartNew {
-			// 聚合当前周期并开始新周期
-			if len(currentPeriod) > 0 {
-				aggregated = append(aggregated, c.aggregatePeriod(currentPeriod))
-			}
-			currentPeriod = []*Kline{kline}
-		} else {
-			currentPeriod = append(currentPeriod, kline)
-		}
+	resp, err := c.executeWithRateLimit(req)
+	if err != nil {
+		return nil, err
 	}
+	defer resp.Body.Close()
 
-	// 聚合最后一个周期
-	if len(currentPeriod) > 0 {
-		aggregated = append(aggregated, c.aggregatePeriod(currentPeriod))
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("coinbase", resp)
 	}
 
-	return aggregated
-}
-
-// aggregatePeriod 聚合一个周期的K线数据
-func (c *CoinbaseClient) aggregatePeriod(klines []*Kline) *Kline {
-	if len(klines) == 0 {
-		return nil
+	// 格式: [price, size, num_orders]
+	var body struct {
+		Bids [][]json.RawMessage `json:"bids"`
+		Asks [][]json.RawMessage `json:"asks"`
+		Time time.Time           `json:"time"`
 	}
-
-	first := klines[0]
-	last := klines[len(klines)-1]
-
-	aggregated := &Kline{
-		Symbol:    first.Symbol,
-		OpenTime:  first.OpenTime,
-		CloseTime: last.CloseTime,
-		Open:      first.Open,
-		Close:     last.Close,
-		High:      first.High,
-		Low:       first.Low,
-		Volume:    0,
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
 	}
 
-	// 计算最高价、最低价和总成交量
-	for _, kline := range klines {
-		if kline.High > aggregated.High {
-			aggregated.High = kline.High
-		}
-		if kline.Low < aggregated.Low {
-			aggregated.Low = kline.Low
-		}
-		aggregated.Volume += kline.Volume
+	bids, err := parseBookLevels(body.Bids)
+	if err != nil {
+		return nil, err
+	}
+	asks, err := parseBookLevels(body.Asks)
+	if err != nil {
+		return nil, err
 	}
 
-	return aggregated
-}
-
-// getWeekStart 获取给定时间所在周的周一
-func getWeekStart(t time.Time) time.Time {
-	// Go中 Sunday=0, Monday=1, ..., Saturday=6
-	weekday := int(t.Weekday())
-	if weekday == 0 { // Sunday
-		weekday = 7 // 转换为 Monday=1, ..., Sunday=7
-	}
-	// 计算到周一的天数差
-	daysFromMonday := weekday - 1
-	// 返回周一的日期（保持时间部分）
-	return t.AddDate(0, 0, -daysFromMonday)
+	t := body.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return NewOrderBook(symbol, t, bids, asks, depth), nil
 }
 
-// rateLimitSleep 根据限流配置进行休眠
-func (c *CoinbaseClient) rateLimitSleep() {
-	c.requestMutex.Lock()
-	defer c.requestMutex.Unlock()
+// coinbaseTradesMaxLimit /products/{id}/trades 单次最大返回数量
+const coinbaseTradesMaxLimit = 1000
 
-	if c.rateLimit.RequestsPerMinute <= 0 {
-		return // 没有限流
+// GetRecentTrades 获取最近的成交
+func (c *CoinbaseClient) GetRecentTrades(ctx context.Context, symbol string, limit int) ([]*Trade, error) {
+	if limit <= 0 || limit > coinbaseTradesMaxLimit {
+		limit = coinbaseTradesMaxLimit
 	}
 
-	// 计算每个请求之间的最小间隔
-	minInterval := time.Minute / time.Duration(c.rateLimit.RequestsPerMinute)
-
-	// 计算距离上次请求的时间
-	elapsed := time.Since(c.lastRequest)
-
-	// 如果还没有达到最小间隔，则等待
-	if elapsed < minInterval {
-		sleepTime := minInterval - elapsed
-		time.Sleep(sleepTime)
+	url := fmt.Sprintf("%s/products/%s/trades?limit=%d", c.baseURL, c.convertToCoinbaseSymbol(symbol), limit)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	c.lastRequest = time.Now()
-}
-
-// executeWithRateLimit 执行带限流的HTTP请求
-func (c *CoinbaseClient) executeWithRateLimit(req *http.Request) (*http.Response, error) {
-	var resp *http.Response
-	var err error
-
-	for attempt := 0; attempt <= c.rateLimit.MaxRetries; attempt++ {
-		// 应用限流
-		c.rateLimitSleep()
+	resp, err := c.executeWithRateLimit(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
 
-		// 执行请求
-		resp, err = c.client.Do(req)
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("coinbase", resp)
+	}
 
-		// 如果成功或者非限流错误，直接返回
-		if err == nil && resp.StatusCode != http.StatusTooManyRequests {
-			return resp, nil
-		}
+	// side 为挂单方方向，"buy" 表示买方挂单、主动卖出成交
+	var rows []struct {
+		TradeID int64     `json:"trade_id"`
+		Price   string    `json:"price"`
+		Size    string    `json:"size"`
+		Side    string    `json:"side"`
+		Time    time.Time `json:"time"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rows); err != nil {
+		return nil, err
+	}
 
-		// 如果是限流错误，等待更长时间再重试
-		if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
-			if attempt < c.rateLimit.MaxRetries {
-				time.Sleep(c.rateLimit.RetryDelay)
-			}
-			resp.Body.Close()
-			continue
+	trades := make([]*Trade, 0, len(rows))
+	for _, row := range rows {
+		price, err := strconv.ParseFloat(row.Price, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trade price: %w", err)
 		}
-
-		// 其他错误，等待重试延迟
-		if attempt < c.rateLimit.MaxRetries {
-			time.Sleep(c.rateLimit.RetryDelay)
+		size, err := strconv.ParseFloat(row.Size, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trade size: %w", err)
 		}
-	}
+		trades = append(trades, &Trade{
+			Symbol:     symbol,
+			ID:         strconv.FormatInt(row.TradeID, 10),
+			Time:       row.Time,
+			Price:      price,
+			Quantity:   size,
+			BuyerMaker: row.Side == "buy",
+		})
+	}
+
+	sortTradesByTime(trades)
+	return trades, nil
+}
 
-	return resp, err
+// executeWithRateLimit 执行带限流的HTTP请求（仅限流错误会重试）
+func (c *CoinbaseClient) executeWithRateLimit(req *http.Request) (*http.Response, error) {
+	return executeWithRetry(c.client, req, c.rateLimit, func(resp *http.Response) bool {
+		return resp.StatusCode == http.StatusTooManyRequests
+	})
 }
@@ -0,0 +1,189 @@
+package datasource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"ta-watcher/internal/resample"
+)
+
+// coinbaseCloseGrace K线结束后等待迟到成交的时间
+const coinbaseCloseGrace = 2 * time.Second
+
+// coinbaseMatch Coinbase 成交推送
+type coinbaseMatch struct {
+	Type      string    `json:"type"`
+	ProductID string    `json:"product_id"`
+	Price     string    `json:"price"`
+	Size      string    `json:"size"`
+	Time      time.Time `json:"time"`
+}
+
+// SubscribeKlines 通过 WebSocket 订阅成交并在本地聚合为K线，只推送已收盘的K线
+// Coinbase Exchange 行情推送不提供K线频道，因此由 matches 频道的逐笔成交构建
+func (c *CoinbaseClient) SubscribeKlines(ctx context.Context, symbols []string, timeframe Timeframe) (<-chan *Kline, error) {
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("no symbols to subscribe")
+	}
+	if timeframe.Duration() == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedTimeframe, timeframe)
+	}
+
+	builder := newTradeKlineBuilder(timeframe, c.resampleOptions().WeekStart)
+	productIDs := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		productIDs[i] = c.convertToCoinbaseSymbol(symbol)
+		builder.symbols[productIDs[i]] = symbol
+	}
+
+	subscribe, err := json.Marshal(map[string]interface{}{
+		"type":        "subscribe",
+		"product_ids": productIDs,
+		"channels":    []string{"matches"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stream := &klineStream{
+		name:      "Coinbase",
+		url:       c.wsURL,
+		subscribe: subscribe,
+		parse:     builder.handleMessage,
+		flush:     builder.flush,
+		connected: builder.connected,
+	}
+	return stream.start(ctx)
+}
+
+// tradeKlineBuilder 将逐笔成交聚合为K线
+// 周期边界与 resample.PeriodStart 一致（日内及3日按Unix纪元对齐，周线按 weekStart 对齐）
+type tradeKlineBuilder struct {
+	timeframe Timeframe
+	weekStart time.Weekday
+	symbols   map[string]string // product_id -> 原始交易对
+
+	mu          sync.Mutex
+	current     map[string]*Kline    // 正在形成的K线
+	lastEmitted map[string]time.Time // 已推送的最后一根K线开盘时间
+	connectedAt time.Time            // 最近一次订阅成功的时间，早于该时间开始的周期缺少成交
+}
+
+// newTradeKlineBuilder 创建成交聚合器
+func newTradeKlineBuilder(timeframe Timeframe, weekStart time.Weekday) *tradeKlineBuilder {
+	return &tradeKlineBuilder{
+		timeframe:   timeframe,
+		weekStart:   weekStart,
+		symbols:     make(map[string]string),
+		current:     make(map[string]*Kline),
+		lastEmitted: make(map[string]time.Time),
+	}
+}
+
+// connected 记录订阅时间，断线期间正在形成的K线缺少成交，标记为 Partial
+func (b *tradeKlineBuilder) connected(now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.connectedAt = now
+	for _, current := range b.current {
+		current.Partial = true
+	}
+}
+
+// handleMessage 处理成交消息，进入新周期时返回上一根已收盘的K线
+func (b *tradeKlineBuilder) handleMessage(msg []byte) []*Kline {
+	var match coinbaseMatch
+	if err := json.Unmarshal(msg, &match); err != nil {
+		return nil
+	}
+	if match.Type != "match" && match.Type != "last_match" {
+		return nil
+	}
+
+	price, err := strconv.ParseFloat(match.Price, 64)
+	if err != nil {
+		return nil
+	}
+	size, err := strconv.ParseFloat(match.Size, 64)
+	if err != nil {
+		return nil
+	}
+
+	symbol, ok := b.symbols[match.ProductID]
+	if !ok {
+		return nil
+	}
+
+	return b.addTrade(match.ProductID, symbol, match.Time, price, size)
+}
+
+// addTrade 累加一笔成交
+func (b *tradeKlineBuilder) addTrade(productID, symbol string, ts time.Time, price, size float64) []*Kline {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	openTime := resample.PeriodStart(ts, b.timeframe, b.weekStart)
+	if last, ok := b.lastEmitted[productID]; ok && !openTime.After(last) {
+		return nil // 已推送周期的迟到成交
+	}
+
+	var closed []*Kline
+	current := b.current[productID]
+	if current != nil && openTime.After(current.OpenTime) {
+		current.IsClosed = true
+		closed = append(closed, current)
+		b.lastEmitted[productID] = current.OpenTime
+		current = nil
+	}
+	if current != nil && openTime.Before(current.OpenTime) {
+		return closed // 乱序的旧成交
+	}
+
+	if current == nil {
+		current = &Kline{
+			Symbol:    symbol,
+			OpenTime:  openTime,
+			CloseTime: closeTimeFor(openTime, b.timeframe),
+			Open:      price,
+			High:      price,
+			Low:       price,
+			// 订阅晚于周期开始时，之前的成交没有收到
+			Partial: openTime.Before(b.connectedAt),
+		}
+		b.current[productID] = current
+	}
+
+	if price > current.High {
+		current.High = price
+	}
+	if price < current.Low {
+		current.Low = price
+	}
+	current.Close = price
+	current.Volume += size
+
+	return closed
+}
+
+// flush 推送已过收盘时间（含宽限期）但尚未被新成交触发的K线
+func (b *tradeKlineBuilder) flush(now time.Time) []*Kline {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var closed []*Kline
+	for productID, current := range b.current {
+		if now.Sub(current.CloseTime) < coinbaseCloseGrace {
+			continue
+		}
+		current.IsClosed = true
+		closed = append(closed, current)
+		b.lastEmitted[productID] = current.OpenTime
+		delete(b.current, productID)
+	}
+	return closed
+}
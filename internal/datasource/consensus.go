@@ -0,0 +1,333 @@
+package datasource
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultDivergenceBps 默认价格分歧告警阈值（基点）
+const DefaultDivergenceBps = 50.0
+
+// Divergence 数据源之间的价格分歧
+// 三个及以上数据源时比较各数据源与中位数的偏离；只有两个数据源时中位数即均值，
+// 各自的偏离恰为价差的一半，因此改为比较两者之差，此时无法判断哪一方异常
+type Divergence struct {
+	Symbol       string             // 交易对
+	Timeframe    Timeframe          // 时间框架
+	OpenTime     time.Time          // 出现分歧的K线开盘时间
+	Median       float64            // 收盘价中位数（两个数据源时为均值）
+	Closes       map[string]float64 // 各数据源的收盘价
+	Source       string             // 偏离最大的数据源，两个数据源时为 "a/b"
+	DeviationBps float64            // 最大偏离（基点），两个数据源时为两者之差
+}
+
+// Summary 返回分歧的简要描述
+func (d Divergence) Summary() string {
+	if len(d.Closes) == 2 {
+		return fmt.Sprintf("%s 收盘价相差 %.1f bps", d.Source, d.DeviationBps)
+	}
+	return fmt.Sprintf("%s 偏离中位数 %.1f bps", d.Source, d.DeviationBps)
+}
+
+// DivergenceHandler 价格分歧回调
+type DivergenceHandler func(Divergence)
+
+// DivergenceReporter 可报告数据源价格分歧的数据源
+type DivergenceReporter interface {
+	// SetDivergenceHandler 设置价格分歧超过阈值时的回调
+	SetDivergenceHandler(handler DivergenceHandler)
+}
+
+// AsDivergenceReporter 查找数据源（或其包装的数据源）中可报告价格分歧的实现
+func AsDivergenceReporter(ds DataSource) (DivergenceReporter, bool) {
+	if reporter, ok := ds.(DivergenceReporter); ok {
+		return reporter, true
+	}
+	if wrapper, ok := ds.(Unwrapper); ok {
+		for _, inner := range wrapper.Unwrap() {
+			if reporter, ok := AsDivergenceReporter(inner); ok {
+				return reporter, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// ConsensusDataSource 多数据源共识
+// 并发请求所有数据源，按开盘时间对齐后逐字段取中位数，单个交易所的异常报价不会直接影响指标
+type ConsensusDataSource struct {
+	sources       []DataSource
+	divergenceBps float64
+
+	mu       sync.Mutex
+	handler  DivergenceHandler
+	reported map[string]time.Time // symbol|timeframe -> 已检查告警的K线开盘时间，避免重复告警
+}
+
+// NewConsensusDataSource 创建共识数据源，divergenceBps <= 0 时使用默认阈值
+func NewConsensusDataSource(sources []DataSource, divergenceBps float64) *ConsensusDataSource {
+	if divergenceBps <= 0 {
+		divergenceBps = DefaultDivergenceBps
+	}
+
+	names := make([]string, len(sources))
+	for i, ds := range sources {
+		names[i] = ds.Name()
+	}
+	log.Printf("🧮 启用多数据源共识: %s, 分歧阈值 %.0f bps", strings.Join(names, ", "), divergenceBps)
+
+	return &ConsensusDataSource{
+		sources:       sources,
+		divergenceBps: divergenceBps,
+		reported:      make(map[string]time.Time),
+	}
+}
+
+// Name 返回数据源名称
+func (c *ConsensusDataSource) Name() string {
+	names := make([]string, len(c.sources))
+	for i, ds := range c.sources {
+		names[i] = ds.Name()
+	}
+	return fmt.Sprintf("consensus(%s)", strings.Join(names, ","))
+}
+
+// SetDivergenceHandler 设置价格分歧超过阈值时的回调
+func (c *ConsensusDataSource) SetDivergenceHandler(handler DivergenceHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handler = handler
+}
+
+// Unwrap 返回参与共识的数据源
+func (c *ConsensusDataSource) Unwrap() []DataSource {
+	return c.sources
+}
+
+// sourceKlines 单个数据源的请求结果
+type sourceKlines struct {
+	name   string
+	klines []*Kline
+	err    error
+}
+
+// GetKlines 并发获取所有数据源的K线并按中位数合并，部分数据源失败时使用其余数据源
+func (c *ConsensusDataSource) GetKlines(ctx context.Context, symbol string, timeframe Timeframe, startTime, endTime time.Time, limit int) ([]*Kline, error) {
+	results := make([]sourceKlines, len(c.sources))
+	var wg sync.WaitGroup
+	for i, ds := range c.sources {
+		wg.Add(1)
+		go func(i int, ds DataSource) {
+			defer wg.Done()
+			klines, err := ds.GetKlines(ctx, symbol, timeframe, startTime, endTime, limit)
+			if err == nil && len(klines) == 0 {
+				err = fmt.Errorf("no klines returned")
+			}
+			results[i] = sourceKlines{name: ds.Name(), klines: klines, err: err}
+		}(i, ds)
+	}
+	wg.Wait()
+
+	var errs sourceErrors
+	succeeded := make([]sourceKlines, 0, len(results))
+	for _, r := range results {
+		if r.err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.name, r.err))
+			continue
+		}
+		succeeded = append(succeeded, r)
+	}
+	if len(succeeded) == 0 {
+		return nil, fmt.Errorf("all data sources failed: %w", errs)
+	}
+	if len(errs) > 0 {
+		log.Printf("⚠️ [%s %s] 部分数据源失败，使用 %d 个数据源计算共识: %v", symbol, timeframe, len(succeeded), errs)
+	}
+
+	merged, divergence := c.merge(symbol, timeframe, succeeded)
+	if divergence != nil {
+		c.report(*divergence)
+	}
+
+	if limit > 0 && len(merged) > limit {
+		merged = merged[len(merged)-limit:]
+	}
+	return merged, nil
+}
+
+// IsSymbolValid 检查交易对是否有效，任一数据源有效即视为有效
+func (c *ConsensusDataSource) IsSymbolValid(ctx context.Context, symbol string) (bool, error) {
+	var lastErr error
+	answered := false
+
+	for _, ds := range c.sources {
+		valid, err := ds.IsSymbolValid(ctx, symbol)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		answered = true
+		if valid {
+			return true, nil
+		}
+	}
+
+	if !answered && lastErr != nil {
+		return false, lastErr
+	}
+	return false, nil
+}
+
+// merge 按开盘时间对齐并逐字段取中位数，同时检查最新一根已收盘K线的价格分歧
+// 未收盘的K线由各交易所在不同时刻快照，收盘价本来就会不同，不参与比较
+func (c *ConsensusDataSource) merge(symbol string, timeframe Timeframe, results []sourceKlines) ([]*Kline, *Divergence) {
+	type bucket struct {
+		names  []string
+		klines []*Kline
+	}
+	buckets := make(map[int64]*bucket)
+	for _, r := range results {
+		for _, k := range r.klines {
+			key := k.OpenTime.UnixMilli()
+			b, ok := buckets[key]
+			if !ok {
+				b = &bucket{}
+				buckets[key] = b
+			}
+			b.names = append(b.names, r.name)
+			b.klines = append(b.klines, k)
+		}
+	}
+
+	keys := make([]int64, 0, len(buckets))
+	for key := range buckets {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	merged := make([]*Kline, len(keys))
+	var divergence *Divergence
+	checked := false
+	for i := len(keys) - 1; i >= 0; i-- {
+		b := buckets[keys[i]]
+		k := medianKline(b.klines)
+		merged[i] = k
+
+		if checked || len(b.klines) < 2 || !k.IsClosed {
+			continue
+		}
+		checked = true
+		divergence = c.divergence(symbol, timeframe, k, b.names, b.klines)
+	}
+
+	return merged, divergence
+}
+
+// divergence 计算同一根K线各数据源收盘价的分歧，未超过阈值时返回 nil
+func (c *ConsensusDataSource) divergence(symbol string, timeframe Timeframe, k *Kline, names []string, klines []*Kline) *Divergence {
+	if k.Close == 0 {
+		return nil
+	}
+
+	closes := make(map[string]float64, len(klines))
+	for i, kl := range klines {
+		closes[names[i]] = kl.Close
+	}
+	d := &Divergence{
+		Symbol:    symbol,
+		Timeframe: timeframe,
+		OpenTime:  k.OpenTime,
+		Median:    k.Close,
+		Closes:    closes,
+	}
+
+	if len(klines) == 2 {
+		d.Source = names[0] + "/" + names[1]
+		d.DeviationBps = math.Abs(klines[0].Close-klines[1].Close) / k.Close * 10000
+	} else {
+		for i, kl := range klines {
+			if deviation := math.Abs(kl.Close-k.Close) / k.Close * 10000; deviation > d.DeviationBps {
+				d.Source = names[i]
+				d.DeviationBps = deviation
+			}
+		}
+	}
+
+	if d.DeviationBps <= c.divergenceBps {
+		return nil
+	}
+	return d
+}
+
+// report 调用分歧回调，同一根K线只告警一次（只检查最新已收盘K线，记录最后一根即可）
+func (c *ConsensusDataSource) report(d Divergence) {
+	c.mu.Lock()
+	key := servedKey(d.Symbol, d.Timeframe)
+	if last, ok := c.reported[key]; ok && last.Equal(d.OpenTime) {
+		c.mu.Unlock()
+		return
+	}
+	c.reported[key] = d.OpenTime
+	handler := c.handler
+	c.mu.Unlock()
+
+	log.Printf("⚠️ [%s %s] 数据源价格分歧: %s（%s）",
+		d.Symbol, d.Timeframe, d.Summary(), d.OpenTime.Format("2006-01-02 15:04"))
+	if handler != nil {
+		handler(d)
+	}
+}
+
+// medianKline 逐字段取中位数合并同一开盘时间的K线，任一数据源未收盘或不完整则合并结果同样标记
+// 每个数据源都满足 low <= open/close <= high，逐字段取中位数后仍然成立
+func medianKline(klines []*Kline) *Kline {
+	first := klines[0]
+	if len(klines) == 1 {
+		k := *first
+		return &k
+	}
+
+	field := func(get func(*Kline) float64) float64 {
+		values := make([]float64, len(klines))
+		for i, k := range klines {
+			values[i] = get(k)
+		}
+		return median(values)
+	}
+
+	closed, partial := true, false
+	for _, k := range klines {
+		closed = closed && k.IsClosed
+		partial = partial || k.Partial
+	}
+
+	return &Kline{
+		Symbol:    first.Symbol,
+		OpenTime:  first.OpenTime,
+		CloseTime: first.CloseTime,
+		Open:      field(func(k *Kline) float64 { return k.Open }),
+		High:      field(func(k *Kline) float64 { return k.High }),
+		Low:       field(func(k *Kline) float64 { return k.Low }),
+		Close:     field(func(k *Kline) float64 { return k.Close }),
+		Volume:    field(func(k *Kline) float64 { return k.Volume }),
+		IsClosed:  closed,
+		Partial:   partial,
+	}
+}
+
+// median 返回中位数（偶数个时取中间两数的平均值）
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
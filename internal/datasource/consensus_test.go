@@ -0,0 +1,142 @@
+package datasource
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// consensusKlines 按收盘价生成连续的1小时K线
+func consensusKlines(start time.Time, closes ...float64) []*Kline {
+	klines := make([]*Kline, len(closes))
+	for i, c := range closes {
+		open := start.Add(time.Duration(i) * time.Hour)
+		klines[i] = &Kline{
+			Symbol:    "BTCUSDT",
+			OpenTime:  open,
+			CloseTime: open.Add(time.Hour - time.Millisecond),
+			Open:      c,
+			High:      c + 1,
+			Low:       c - 1,
+			Close:     c,
+			Volume:    10,
+			IsClosed:  true,
+		}
+	}
+	return klines
+}
+
+// forming 追加一根未收盘的K线
+func forming(klines []*Kline, close float64) []*Kline {
+	last := klines[len(klines)-1]
+	open := last.OpenTime.Add(time.Hour)
+	return append(klines, &Kline{
+		Symbol:    last.Symbol,
+		OpenTime:  open,
+		CloseTime: open.Add(time.Hour - time.Millisecond),
+		Open:      close,
+		High:      close + 1,
+		Low:       close - 1,
+		Close:     close,
+		Volume:    1,
+	})
+}
+
+func TestConsensusDataSource_Median(t *testing.T) {
+	start := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	a := &stubDataSource{name: "a", klines: forming(consensusKlines(start, 100, 101, 102), 103)}
+	b := &stubDataSource{name: "b", klines: forming(consensusKlines(start, 100.2, 101.2, 102.2), 103.2)}
+	// c 缺少第一根K线，在最新已收盘K线出现异常报价；未收盘K线的快照也不同，但不应告警
+	c := &stubDataSource{name: "c", klines: forming(consensusKlines(start.Add(time.Hour), 101.1, 150), 110)}
+
+	ds := NewConsensusDataSource([]DataSource{a, b, c}, 100)
+	var alerts []Divergence
+	ds.SetDivergenceHandler(func(d Divergence) { alerts = append(alerts, d) })
+
+	klines, err := ds.GetKlines(context.Background(), "BTCUSDT", Timeframe1h, time.Time{}, time.Time{}, 0)
+	if err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if len(klines) != 4 {
+		t.Fatalf("expected 4 merged klines, got %d", len(klines))
+	}
+	if klines[0].Close != 100.1 || klines[1].Close != 101.1 || klines[2].Close != 102.2 {
+		t.Errorf("unexpected median closes: %v %v %v", klines[0].Close, klines[1].Close, klines[2].Close)
+	}
+	if klines[1].High != 102.1 {
+		t.Errorf("High should be the median of highs, got %v", klines[1].High)
+	}
+	if klines[3].IsClosed {
+		t.Error("merged kline should stay open while any source is open")
+	}
+
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 divergence alert, got %d", len(alerts))
+	}
+	d := alerts[0]
+	if d.Source != "c" || !d.OpenTime.Equal(start.Add(2*time.Hour)) || d.Median != 102.2 || d.Closes["c"] != 150 {
+		t.Errorf("unexpected divergence: %+v", d)
+	}
+
+	// 同一根K线的分歧只告警一次
+	if _, err := ds.GetKlines(context.Background(), "BTCUSDT", Timeframe1h, time.Time{}, time.Time{}, 2); err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if len(alerts) != 1 {
+		t.Errorf("divergence should be reported once, got %d alerts", len(alerts))
+	}
+
+	// 下一根K线正常收盘后，窗口内旧的异常报价不会再次告警
+	for _, s := range []*stubDataSource{a, b, c} {
+		s.klines[len(s.klines)-1].IsClosed = true
+	}
+	c.klines[len(c.klines)-1].Close = 103.1
+	if _, err := ds.GetKlines(context.Background(), "BTCUSDT", Timeframe1h, time.Time{}, time.Time{}, 0); err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if len(alerts) != 1 {
+		t.Errorf("older divergence should not be re-alerted, got %d alerts", len(alerts))
+	}
+}
+
+func TestConsensusDataSource_TwoSourcesCompareSpread(t *testing.T) {
+	start := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	a := &stubDataSource{name: "a", klines: consensusKlines(start, 100)}
+	b := &stubDataSource{name: "b", klines: consensusKlines(start, 100.4)}
+
+	// 两者相差约40 bps，各自偏离均值只有约20 bps
+	ds := NewConsensusDataSource([]DataSource{a, b}, 30)
+	var alerts []Divergence
+	ds.SetDivergenceHandler(func(d Divergence) { alerts = append(alerts, d) })
+
+	if _, err := ds.GetKlines(context.Background(), "BTCUSDT", Timeframe1h, time.Time{}, time.Time{}, 0); err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 divergence alert, got %d", len(alerts))
+	}
+	if d := alerts[0]; d.Source != "a/b" || d.DeviationBps < 39 || d.DeviationBps > 41 {
+		t.Errorf("unexpected divergence: %+v", d)
+	}
+}
+
+func TestConsensusDataSource_PartialFailure(t *testing.T) {
+	start := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	good := &stubDataSource{name: "good", klines: consensusKlines(start, 100, 101, 102)}
+	bad := &stubDataSource{name: "bad", err: ErrRateLimited}
+
+	ds := NewConsensusDataSource([]DataSource{good, bad}, 0)
+	klines, err := ds.GetKlines(context.Background(), "BTCUSDT", Timeframe1h, time.Time{}, time.Time{}, 2)
+	if err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if len(klines) != 2 || klines[1].Close != 102 {
+		t.Errorf("expected last 2 klines from the healthy source, got %d", len(klines))
+	}
+
+	allBad := NewConsensusDataSource([]DataSource{bad, &stubDataSource{name: "empty"}}, 0)
+	if _, err := allBad.GetKlines(context.Background(), "BTCUSDT", Timeframe1h, time.Time{}, time.Time{}, 2); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected wrapped ErrRateLimited, got %v", err)
+	}
+}
@@ -56,7 +56,7 @@ func TestFactory_Basic(t *testing.T) {
 
 	// 测试支持的数据源列表
 	sources := factory.GetSupportedSources()
-	expectedSources := []string{"binance", "coinbase"}
+	expectedSources := []string{"binance", "coinbase", "okx", "kraken", "bybit", "file"}
 
 	if len(sources) != len(expectedSources) {
 		t.Errorf("支持的数据源数量不匹配: 期望 %d, 实际 %d", len(expectedSources), len(sources))
@@ -88,6 +88,10 @@ func TestFactory_CreateDataSource(t *testing.T) {
 	}{
 		{"创建Binance数据源", "binance", false},
 		{"创建Coinbase数据源", "coinbase", false},
+		{"创建OKX数据源", "okx", false},
+		{"创建Kraken数据源", "kraken", false},
+		{"创建Bybit数据源", "bybit", false},
+		{"创建本地文件数据源", "file", false},
 		{"创建不支持的数据源", "unsupported", true},
 		{"创建空数据源", "", true},
 	}
@@ -142,6 +146,27 @@ func TestTimeframes_Validation(t *testing.T) {
 	}
 }
 
+// TestMarkClosedAndClosedOnly 测试收盘标记和过滤
+func TestMarkClosedAndClosedOnly(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 30, 0, 0, time.UTC)
+	klines := makeTestKlines("BTCUSDT", now.Add(-3*time.Hour).Truncate(time.Hour), time.Hour, 1, 2, 3, 4)
+
+	markClosed(klines, now)
+	for i, k := range klines[:3] {
+		if !k.IsClosed {
+			t.Errorf("kline %d should be closed", i)
+		}
+	}
+	if klines[3].IsClosed {
+		t.Error("forming kline should not be closed")
+	}
+
+	closed := ClosedOnly(klines)
+	if len(closed) != 3 || closed[len(closed)-1].Close != 3 {
+		t.Errorf("ClosedOnly() should drop the forming kline, got %d klines", len(closed))
+	}
+}
+
 // TestKline_Structure 测试K线数据结构
 func TestKline_Structure(t *testing.T) {
 	now := time.Now()
@@ -184,162 +209,6 @@ func TestKline_Structure(t *testing.T) {
 	t.Logf("K线数据结构验证通过: %s %.2f", kline.Symbol, kline.Close)
 }
 
-// ============================================================================
-// 聚合功能测试
-// ============================================================================
-
-// TestCoinbase_WeeklyAggregation 测试Coinbase周线聚合功能
-func TestCoinbase_WeeklyAggregation(t *testing.T) {
-	client := NewCoinbaseClient()
-
-	// 创建测试数据：14天的日线数据，应该聚合成2周的周线数据
-	dailyKlines := []*Kline{}
-
-	// 第一周：2025-06-23 (周一) 到 2025-06-29 (周日)，7天
-	baseTime := time.Date(2025, 6, 23, 8, 0, 0, 0, time.UTC) // 周一
-	for i := 0; i < 7; i++ {
-		kline := &Kline{
-			Symbol:    "BTCUSD",
-			OpenTime:  baseTime.AddDate(0, 0, i),
-			CloseTime: baseTime.AddDate(0, 0, i).Add(24 * time.Hour),
-			Open:      float64(30000 + i*100),
-			High:      float64(30500 + i*100),
-			Low:       float64(29500 + i*100),
-			Close:     float64(30200 + i*100),
-			Volume:    1000.0,
-		}
-		dailyKlines = append(dailyKlines, kline)
-	}
-
-	// 第二周：2025-06-30 (周一) 到 2025-07-06 (周日)，7天
-	baseTime2 := time.Date(2025, 6, 30, 8, 0, 0, 0, time.UTC) // 第二周周一
-	for i := 0; i < 7; i++ {
-		kline := &Kline{
-			Symbol:    "BTCUSD",
-			OpenTime:  baseTime2.AddDate(0, 0, i),
-			CloseTime: baseTime2.AddDate(0, 0, i).Add(24 * time.Hour),
-			Open:      float64(31000 + i*100),
-			High:      float64(31500 + i*100),
-			Low:       float64(30500 + i*100),
-			Close:     float64(31200 + i*100),
-			Volume:    1000.0,
-		}
-		dailyKlines = append(dailyKlines, kline)
-	}
-
-	t.Logf("输入数据: %d条日线K线", len(dailyKlines))
-
-	// 执行聚合
-	weeklyKlines := client.aggregateKlines(dailyKlines, Timeframe1w)
-
-	t.Logf("输出数据: %d条周线K线", len(weeklyKlines))
-
-	// 验证结果
-	if len(weeklyKlines) != 2 {
-		t.Errorf("期望2条周线数据，实际得到 %d 条", len(weeklyKlines))
-	}
-
-	if len(weeklyKlines) >= 2 {
-		// 验证第一周数据
-		week1 := weeklyKlines[0]
-		if week1.Open != 30000 {
-			t.Errorf("第一周开盘价错误: 期望 30000, 实际 %.0f", week1.Open)
-		}
-		if week1.Close != 30800 {
-			t.Errorf("第一周收盘价错误: 期望 30800, 实际 %.0f", week1.Close)
-		}
-
-		// 验证第二周数据
-		week2 := weeklyKlines[1]
-		if week2.Open != 31000 {
-			t.Errorf("第二周开盘价错误: 期望 31000, 实际 %.0f", week2.Open)
-		}
-		if week2.Close != 31800 {
-			t.Errorf("第二周收盘价错误: 期望 31800, 实际 %.0f", week2.Close)
-		}
-	}
-}
-
-// TestCoinbase_MonthlyAggregation 测试Coinbase月线聚合功能
-func TestCoinbase_MonthlyAggregation(t *testing.T) {
-	client := NewCoinbaseClient()
-
-	// 创建跨月的测试数据
-	dailyKlines := []*Kline{}
-
-	// 2025年5月：1-31日，31天
-	may1 := time.Date(2025, 5, 1, 8, 0, 0, 0, time.UTC)
-	for i := 0; i < 31; i++ {
-		kline := &Kline{
-			Symbol:    "BTCUSD",
-			OpenTime:  may1.AddDate(0, 0, i),
-			CloseTime: may1.AddDate(0, 0, i).Add(24 * time.Hour),
-			Open:      float64(2100 + i),
-			High:      float64(2200 + i),
-			Low:       float64(2000 + i),
-			Close:     float64(2150 + i),
-			Volume:    1000.0,
-		}
-		dailyKlines = append(dailyKlines, kline)
-	}
-
-	t.Logf("输入数据: %d条日线K线", len(dailyKlines))
-
-	// 执行聚合
-	monthlyKlines := client.aggregateKlines(dailyKlines, Timeframe1M)
-
-	t.Logf("输出数据: %d条月线K线", len(monthlyKlines))
-
-	// 验证应该有1个月的数据
-	if len(monthlyKlines) != 1 {
-		t.Errorf("期望1条月线数据，实际得到 %d 条", len(monthlyKlines))
-	}
-
-	if len(monthlyKlines) >= 1 {
-		may := monthlyKlines[0]
-		if may.Open != 2100 { // 5月1日的开盘价
-			t.Errorf("5月开盘价错误: 期望 2100, 实际 %.0f", may.Open)
-		}
-	}
-}
-
-// TestGetWeekStart 测试周开始时间计算函数
-func TestGetWeekStart(t *testing.T) {
-	testCases := []struct {
-		name     string
-		input    time.Time
-		expected time.Time
-	}{
-		{
-			name:     "周一",
-			input:    time.Date(2025, 6, 23, 15, 30, 45, 0, time.UTC), // 周一
-			expected: time.Date(2025, 6, 23, 15, 30, 45, 0, time.UTC), // 保持时间部分
-		},
-		{
-			name:     "周三",
-			input:    time.Date(2025, 6, 25, 10, 20, 30, 0, time.UTC), // 周三
-			expected: time.Date(2025, 6, 23, 10, 20, 30, 0, time.UTC), // 周一的相同时间
-		},
-		{
-			name:     "周日",
-			input:    time.Date(2025, 6, 29, 23, 59, 59, 0, time.UTC), // 周日
-			expected: time.Date(2025, 6, 23, 23, 59, 59, 0, time.UTC), // 周一的相同时间
-		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			result := getWeekStart(tc.input)
-			if !result.Equal(tc.expected) {
-				t.Errorf("getWeekStart(%s) = %s, 期望 %s",
-					tc.input.Format("2006-01-02 15:04:05"),
-					result.Format("2006-01-02 15:04:05"),
-					tc.expected.Format("2006-01-02 15:04:05"))
-			}
-		})
-	}
-}
-
 // ============================================================================
 // API集成测试（需要实际API调用）
 // ============================================================================
@@ -0,0 +1,59 @@
+package datasource
+
+import (
+	"context"
+	"time"
+)
+
+// FundingRate 永续合约资金费率
+type FundingRate struct {
+	Symbol    string    `json:"symbol"`
+	Time      time.Time `json:"time"`       // 结算时间
+	Rate      float64   `json:"rate"`       // 资金费率（0.0001 表示 0.01%），正数为多头支付空头
+	MarkPrice float64   `json:"mark_price"` // 结算时的标记价格（交易所未提供时为0）
+}
+
+// OpenInterest 合约持仓量
+type OpenInterest struct {
+	Symbol    string    `json:"symbol"`
+	Time      time.Time `json:"time"`
+	Contracts float64   `json:"contracts"` // 持仓量（以基础货币计）
+	Value     float64   `json:"value"`     // 持仓名义价值（以报价货币/美元计）
+}
+
+// LongShortRatio 多空账户比
+type LongShortRatio struct {
+	Symbol       string    `json:"symbol"`
+	Time         time.Time `json:"time"`
+	Ratio        float64   `json:"ratio"`         // 多头账户数 / 空头账户数
+	LongAccount  float64   `json:"long_account"`  // 多头账户占比
+	ShortAccount float64   `json:"short_account"` // 空头账户占比
+}
+
+// DerivativesDataSource 衍生品（永续合约）数据源接口
+// symbol 使用与现货相同的格式（例如 "BTCUSDT"），由实现转换为对应的永续合约
+type DerivativesDataSource interface {
+	// GetFundingRates 获取资金费率历史（按时间升序）
+	GetFundingRates(ctx context.Context, symbol string, startTime, endTime time.Time, limit int) ([]*FundingRate, error)
+
+	// GetOpenInterest 获取持仓量序列（按时间升序），period 为统计周期
+	GetOpenInterest(ctx context.Context, symbol string, period Timeframe, startTime, endTime time.Time, limit int) ([]*OpenInterest, error)
+
+	// GetLongShortRatio 获取多空账户比序列（按时间升序），period 为统计周期
+	GetLongShortRatio(ctx context.Context, symbol string, period Timeframe, startTime, endTime time.Time, limit int) ([]*LongShortRatio, error)
+
+	// Name 返回数据源名称
+	Name() string
+}
+
+// statPeriod 选择交易所支持的统计周期：不超过 tf 的最大周期，tf 小于最小周期时取最小周期
+// supported 需按周期从小到大排列
+func statPeriod(tf Timeframe, supported []Timeframe) Timeframe {
+	period := supported[0]
+	for _, p := range supported {
+		if p.Duration() <= tf.Duration() {
+			period = p
+		}
+	}
+	return period
+}
@@ -0,0 +1,147 @@
+package datasource
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestStatPeriod(t *testing.T) {
+	tests := []struct {
+		tf   Timeframe
+		want Timeframe
+	}{
+		{Timeframe1m, Timeframe5m},
+		{Timeframe5m, Timeframe5m},
+		{Timeframe1h, Timeframe1h},
+		{Timeframe8h, Timeframe6h},
+		{Timeframe1w, Timeframe1d},
+	}
+	for _, tt := range tests {
+		if got := statPeriod(tt.tf, binanceStatPeriods); got != tt.want {
+			t.Errorf("statPeriod(%s) = %s, want %s", tt.tf, got, tt.want)
+		}
+	}
+	if got := statPeriod(Timeframe4h, okxLongShortPeriods); got != Timeframe1h {
+		t.Errorf("okx long/short period for 4h = %s, want 1h", got)
+	}
+}
+
+func TestBinanceFuturesClient(t *testing.T) {
+	var periods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("symbol") != "BTCUSDT" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":-1121,"msg":"Invalid symbol."}`))
+			return
+		}
+		periods = append(periods, q.Get("period"))
+		switch r.URL.Path {
+		case "/fapi/v1/fundingRate":
+			w.Write([]byte(`[
+				{"symbol":"BTCUSDT","fundingTime":1760572800000,"fundingRate":"0.00010000","markPrice":"111250.10"},
+				{"symbol":"BTCUSDT","fundingTime":1760601600000,"fundingRate":"-0.00002500","markPrice":"110980.55"}]`))
+		case "/futures/data/openInterestHist":
+			w.Write([]byte(`[
+				{"symbol":"BTCUSDT","sumOpenInterest":"81234.512","sumOpenInterestValue":"9037338401.23","timestamp":1760572800000}]`))
+		case "/futures/data/globalLongShortAccountRatio":
+			w.Write([]byte(`[
+				{"symbol":"BTCUSDT","longShortRatio":"1.5000","longAccount":"0.6000","shortAccount":"0.4000","timestamp":1760572800000}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewBinanceFuturesClientWithConfig(nil)
+	client.baseURL = server.URL
+	ctx := context.Background()
+
+	rates, err := client.GetFundingRates(ctx, "BTCUSDT", time.Time{}, time.Time{}, 100)
+	if err != nil {
+		t.Fatalf("GetFundingRates() error = %v", err)
+	}
+	if len(rates) != 2 || rates[0].Rate != 0.0001 || rates[1].Rate != -0.000025 || rates[1].MarkPrice != 110980.55 {
+		t.Errorf("unexpected funding rates: %+v %+v", rates[0], rates[len(rates)-1])
+	}
+
+	oi, err := client.GetOpenInterest(ctx, "BTCUSDT", Timeframe1w, time.Time{}, time.Time{}, 100)
+	if err != nil || len(oi) != 1 || oi[0].Contracts != 81234.512 || oi[0].Value != 9037338401.23 {
+		t.Errorf("GetOpenInterest() = %v, err %v", oi, err)
+	}
+
+	ratios, err := client.GetLongShortRatio(ctx, "BTCUSDT", Timeframe1m, time.Time{}, time.Time{}, 100)
+	if err != nil || len(ratios) != 1 || ratios[0].Ratio != 1.5 || ratios[0].LongAccount != 0.6 {
+		t.Errorf("GetLongShortRatio() = %v, err %v", ratios, err)
+	}
+
+	// 不支持的周期映射为最接近的统计周期
+	if want := []string{"", "1d", "5m"}; len(periods) != 3 || periods[1] != want[1] || periods[2] != want[2] {
+		t.Errorf("periods = %v, want %v", periods, want)
+	}
+
+	if _, err := client.GetFundingRates(ctx, "ETHBTC", time.Time{}, time.Time{}, 100); !errors.Is(err, ErrSymbolNotFound) {
+		t.Errorf("expected ErrSymbolNotFound for unlisted contract, got %v", err)
+	}
+}
+
+func TestOKXClient_Derivatives(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/api/v5/public/funding-rate-history":
+			if q.Get("instId") != "BTC-USDT-SWAP" {
+				w.Write([]byte(`{"code":"51001","msg":"Instrument ID does not exist","data":[]}`))
+				return
+			}
+			w.Write([]byte(`{"code":"0","msg":"","data":[
+				{"instId":"BTC-USDT-SWAP","fundingRate":"0.0002","fundingTime":"1760601600000","realizedRate":"0.0002"},
+				{"instId":"BTC-USDT-SWAP","fundingRate":"0.0001","fundingTime":"1760572800000","realizedRate":"0.0001"}]}`))
+		case "/api/v5/rubik/stat/contracts/open-interest-history":
+			if q.Get("instId") != "BTC-USDT-SWAP" || q.Get("period") != "4H" {
+				t.Errorf("unexpected open interest query: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"code":"0","msg":"","data":[
+				["1760587200000","3100000","31000","3450000000"],
+				["1760572800000","3000000","30000","3330000000"]]}`))
+		case "/api/v5/rubik/stat/contracts/long-short-account-ratio":
+			if q.Get("ccy") != "BTC" || q.Get("period") != "1H" {
+				t.Errorf("unexpected long/short query: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"code":"0","msg":"","data":[["1760576400000","3"],["1760572800000","1"]]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewOKXClient()
+	client.baseURL = server.URL
+	ctx := context.Background()
+
+	rates, err := client.GetFundingRates(ctx, "BTCUSDT", time.Time{}, time.Time{}, 10)
+	if err != nil {
+		t.Fatalf("GetFundingRates() error = %v", err)
+	}
+	if len(rates) != 2 || rates[0].Rate != 0.0001 || !rates[0].Time.Before(rates[1].Time) {
+		t.Errorf("funding rates should be ascending, got %+v", rates)
+	}
+
+	oi, err := client.GetOpenInterest(ctx, "BTCUSDT", Timeframe4h, time.Time{}, time.Time{}, 10)
+	if err != nil || len(oi) != 2 || oi[0].Contracts != 30000 || oi[1].Value != 3450000000 {
+		t.Errorf("GetOpenInterest() = %v, err %v", oi, err)
+	}
+
+	ratios, err := client.GetLongShortRatio(ctx, "BTCUSDT", Timeframe4h, time.Time{}, time.Time{}, 10)
+	if err != nil || len(ratios) != 2 || ratios[1].Ratio != 3 || ratios[1].LongAccount != 0.75 {
+		t.Errorf("GetLongShortRatio() = %v, err %v", ratios, err)
+	}
+
+	if _, err := client.GetFundingRates(ctx, "FOOUSDT", time.Time{}, time.Time{}, 10); !errors.Is(err, ErrSymbolNotFound) {
+		t.Errorf("expected ErrSymbolNotFound, got %v", err)
+	}
+}
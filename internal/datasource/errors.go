@@ -0,0 +1,65 @@
+package datasource
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// 数据源错误类型，可通过 errors.Is 判断
+var (
+	ErrSymbolNotFound       = errors.New("symbol not found")
+	ErrRateLimited          = errors.New("rate limited")
+	ErrUnavailable          = errors.New("data source unavailable")
+	ErrUnsupportedTimeframe = errors.New("unsupported timeframe")
+)
+
+// IsTransient 判断错误是否为暂时性错误（限流或服务不可用），稍后重试可能成功
+func IsTransient(err error) bool {
+	return errors.Is(err, ErrRateLimited) || errors.Is(err, ErrUnavailable)
+}
+
+// statusError 将非200响应转换为带类型的错误
+func statusError(source string, resp *http.Response) error {
+	var kind error
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusTeapot:
+		kind = ErrRateLimited
+	case resp.StatusCode >= 500:
+		kind = ErrUnavailable
+	case resp.StatusCode == http.StatusNotFound:
+		kind = ErrSymbolNotFound
+	case resp.StatusCode == http.StatusBadRequest && source == "binance":
+		// Binance 无效交易对返回 400 和错误码 -1121
+		var body struct {
+			Code int `json:"code"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(data, &body) == nil && body.Code == -1121 {
+			kind = ErrSymbolNotFound
+		}
+	}
+
+	if kind == nil {
+		return fmt.Errorf("%s API returned status: %d", source, resp.StatusCode)
+	}
+	return fmt.Errorf("%s API returned status: %d: %w", source, resp.StatusCode, kind)
+}
+
+// sourceErrors 多个数据源的失败原因，errors.Is 可匹配其中任一原因
+type sourceErrors []error
+
+func (e sourceErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e sourceErrors) Unwrap() []error {
+	return e
+}
@@ -0,0 +1,70 @@
+package datasource
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		source string
+		status int
+		body   string
+		want   error
+	}{
+		{"coinbase", http.StatusNotFound, `{"message":"NotFound"}`, ErrSymbolNotFound},
+		{"binance", http.StatusBadRequest, `{"code":-1121,"msg":"Invalid symbol."}`, ErrSymbolNotFound},
+		{"binance", http.StatusTeapot, ``, ErrRateLimited},
+		{"okx", http.StatusTooManyRequests, ``, ErrRateLimited},
+		{"kraken", http.StatusBadGateway, ``, ErrUnavailable},
+		{"binance", http.StatusBadRequest, `{"code":-1120,"msg":"Invalid interval."}`, nil},
+	}
+
+	for _, tc := range tests {
+		resp := &http.Response{StatusCode: tc.status, Body: io.NopCloser(strings.NewReader(tc.body))}
+		err := statusError(tc.source, resp)
+		if tc.want == nil {
+			if IsTransient(err) || errors.Is(err, ErrSymbolNotFound) {
+				t.Errorf("%s %d should be untyped, got %v", tc.source, tc.status, err)
+			}
+			continue
+		}
+		if !errors.Is(err, tc.want) {
+			t.Errorf("%s %d: expected %v, got %v", tc.source, tc.status, tc.want, err)
+		}
+	}
+}
+
+func TestAPIErrorTypes(t *testing.T) {
+	if !errors.Is(&okxAPIError{Code: "51001", Msg: "Instrument ID does not exist"}, ErrSymbolNotFound) {
+		t.Error("okx 51001 should be ErrSymbolNotFound")
+	}
+	if !errors.Is(&krakenAPIError{Messages: []string{"EAPI:Rate limit exceeded"}}, ErrRateLimited) {
+		t.Error("kraken rate limit should be ErrRateLimited")
+	}
+	if !errors.Is(&bybitAPIError{RetCode: 10001, RetMsg: "Not supported symbols"}, ErrSymbolNotFound) {
+		t.Error("bybit unsupported symbol should be ErrSymbolNotFound")
+	}
+	if _, err := NewKrakenClient().GetKlines(context.Background(), "XBTUSD", Timeframe("7h"), time.Time{}, time.Time{}, 1); !errors.Is(err, ErrUnsupportedTimeframe) {
+		t.Errorf("unknown timeframe should be ErrUnsupportedTimeframe, got %v", err)
+	}
+}
+
+func TestFailover_ErrorsAreTyped(t *testing.T) {
+	primary := &stubDataSource{name: "primary", err: ErrSymbolNotFound}
+	fallback := &stubDataSource{name: "fallback", err: ErrUnavailable}
+	ds := NewFailoverDataSource(primary, fallback, time.Minute)
+
+	_, err := ds.GetKlines(context.Background(), "BTCUSDT", Timeframe1h, time.Time{}, time.Time{}, 10)
+	if !errors.Is(err, ErrSymbolNotFound) || !errors.Is(err, ErrUnavailable) {
+		t.Errorf("failover error should wrap all causes, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "primary: symbol not found; fallback: data source unavailable") {
+		t.Errorf("unexpected message: %v", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 
 	"ta-watcher/internal/config"
+	"ta-watcher/internal/resample"
 )
 
 // Factory 数据源工厂
@@ -34,13 +35,136 @@ func (f *Factory) CreateDataSource(sourceType string, cfg *config.Config) (DataS
 		log.Printf("   └── 最大重试: %d", cfg.DataSource.Coinbase.RateLimit.MaxRetries)
 		client := NewCoinbaseClientWithConfig(&cfg.DataSource.Coinbase)
 		return client, nil
+	case "okx":
+		log.Printf("🔧 OKX 限流配置:")
+		log.Printf("   ├── 每分钟请求数: %d", cfg.DataSource.OKX.RateLimit.RequestsPerMinute)
+		log.Printf("   ├── 重试延迟: %v", cfg.DataSource.OKX.RateLimit.RetryDelay)
+		log.Printf("   └── 最大重试: %d", cfg.DataSource.OKX.RateLimit.MaxRetries)
+		client := NewOKXClientWithConfig(&cfg.DataSource.OKX)
+		return client, nil
+	case "kraken":
+		log.Printf("🔧 Kraken 限流配置:")
+		log.Printf("   ├── 每分钟请求数: %d", cfg.DataSource.Kraken.RateLimit.RequestsPerMinute)
+		log.Printf("   ├── 重试延迟: %v", cfg.DataSource.Kraken.RateLimit.RetryDelay)
+		log.Printf("   └── 最大重试: %d", cfg.DataSource.Kraken.RateLimit.MaxRetries)
+		client := NewKrakenClientWithConfig(&cfg.DataSource.Kraken)
+		return client, nil
+	case "bybit":
+		log.Printf("🔧 Bybit 限流配置:")
+		log.Printf("   ├── 每分钟请求数: %d", cfg.DataSource.Bybit.RateLimit.RequestsPerMinute)
+		log.Printf("   ├── 重试延迟: %v", cfg.DataSource.Bybit.RateLimit.RetryDelay)
+		log.Printf("   └── 最大重试: %d", cfg.DataSource.Bybit.RateLimit.MaxRetries)
+		client := NewBybitClientWithConfig(&cfg.DataSource.Bybit)
+		return client, nil
+	case "file":
+		log.Printf("📁 本地文件目录: %s", cfg.DataSource.File.Directory)
+		return NewFileDataSource(&cfg.DataSource.File), nil
 	default:
 		log.Printf("❌ 不支持的数据源类型: %s", sourceType)
 		return nil, fmt.Errorf("unsupported data source type: %s", sourceType)
 	}
 }
 
+// CreateFromConfig 根据配置创建数据源，配置了共识数据源时按中位数合并，配置了备用数据源时自动启用主备切换
+func (f *Factory) CreateFromConfig(cfg *config.Config) (DataSource, error) {
+	var primary DataSource
+	var err error
+	if len(cfg.DataSource.Consensus.Sources) > 0 {
+		primary, err = f.createConsensus(cfg)
+	} else {
+		primary, err = f.createSource(cfg.DataSource.Primary, cfg)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fallbackType := cfg.DataSource.Fallback
+	if fallbackType == "" || fallbackType == cfg.DataSource.Primary {
+		return primary, nil
+	}
+
+	fallback, err := f.createSource(fallbackType, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fallback data source: %w", err)
+	}
+
+	return NewFailoverDataSource(primary, fallback, cfg.DataSource.FailoverCooldown), nil
+}
+
+// createSource 创建单个数据源并应用重采样选项和缓存
+func (f *Factory) createSource(sourceType string, cfg *config.Config) (DataSource, error) {
+	ds, err := f.CreateDataSource(sourceType, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.applyResampleOptions(ds, cfg); err != nil {
+		return nil, err
+	}
+	return f.withCache(ds, cfg)
+}
+
+// createConsensus 创建多数据源共识，各数据源独立缓存
+func (f *Factory) createConsensus(cfg *config.Config) (DataSource, error) {
+	consensusCfg := cfg.DataSource.Consensus
+	sources := make([]DataSource, 0, len(consensusCfg.Sources))
+	for _, sourceType := range consensusCfg.Sources {
+		ds, err := f.createSource(sourceType, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create consensus data source: %w", err)
+		}
+		sources = append(sources, ds)
+	}
+	return NewConsensusDataSource(sources, consensusCfg.DivergenceBps), nil
+}
+
+// CreateDerivatives 根据配置创建衍生品数据源，未配置时返回 nil
+func (f *Factory) CreateDerivatives(cfg *config.Config) (DerivativesDataSource, error) {
+	switch cfg.DataSource.Derivatives {
+	case "":
+		return nil, nil
+	case "binance":
+		log.Printf("🏭 创建衍生品数据源: Binance USDⓈ-M 永续合约")
+		return NewBinanceFuturesClientWithConfig(&cfg.DataSource.Binance), nil
+	case "okx":
+		log.Printf("🏭 创建衍生品数据源: OKX 永续合约")
+		return NewOKXClientWithConfig(&cfg.DataSource.OKX), nil
+	default:
+		return nil, fmt.Errorf("unsupported derivatives data source: %s", cfg.DataSource.Derivatives)
+	}
+}
+
+// applyResampleOptions 将配置中的重采样选项应用到支持重采样回退的数据源
+func (f *Factory) applyResampleOptions(ds DataSource, cfg *config.Config) error {
+	configurable, ok := ds.(ResampleConfigurable)
+	if !ok {
+		return nil
+	}
+
+	weekStart, err := cfg.DataSource.WeekStartDay()
+	if err != nil {
+		return err
+	}
+	opts := resample.DefaultOptions()
+	opts.WeekStart = weekStart
+	configurable.SetResampleOptions(opts)
+	return nil
+}
+
+// withCache 启用缓存时为数据源添加持久化K线缓存（本地文件数据源无需缓存）
+func (f *Factory) withCache(ds DataSource, cfg *config.Config) (DataSource, error) {
+	cacheCfg := cfg.DataSource.Cache
+	if !cacheCfg.Enabled || ds.Name() == "file" {
+		return ds, nil
+	}
+
+	store, err := OpenBoltKlineStore(cacheCfg.Directory)
+	if err != nil {
+		return nil, err
+	}
+	return NewCachedDataSource(ds, store), nil
+}
+
 // GetSupportedSources 获取支持的数据源列表
 func (f *Factory) GetSupportedSources() []string {
-	return []string{"binance", "coinbase"}
+	return []string{"binance", "coinbase", "okx", "kraken", "bybit", "file"}
 }
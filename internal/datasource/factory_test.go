@@ -18,7 +18,7 @@ func TestFactory_GetSupportedSources(t *testing.T) {
 	factory := NewFactory()
 	sources := factory.GetSupportedSources()
 
-	expectedSources := []string{"binance", "coinbase"}
+	expectedSources := []string{"binance", "coinbase", "okx", "kraken", "bybit", "file"}
 
 	if len(sources) != len(expectedSources) {
 		t.Errorf("Expected %d sources, got %d", len(expectedSources), len(sources))
@@ -63,3 +63,50 @@ func TestFactory_CreateDataSource_Interface(t *testing.T) {
 		})
 	}
 }
+
+func TestFactory_CreateFromConfig_Fallback(t *testing.T) {
+	factory := NewFactory()
+
+	cfg := config.DefaultConfig()
+	cfg.DataSource.Primary = "coinbase"
+	cfg.DataSource.Fallback = ""
+
+	ds, err := factory.CreateFromConfig(cfg)
+	if err != nil {
+		t.Fatalf("CreateFromConfig() error = %v", err)
+	}
+	if _, ok := ds.(*FailoverDataSource); ok {
+		t.Error("expected plain data source without fallback")
+	}
+
+	cfg.DataSource.Fallback = "binance"
+	ds, err = factory.CreateFromConfig(cfg)
+	if err != nil {
+		t.Fatalf("CreateFromConfig() error = %v", err)
+	}
+	failover, ok := ds.(*FailoverDataSource)
+	if !ok {
+		t.Fatalf("expected *FailoverDataSource, got %T", ds)
+	}
+	if failover.ActiveSource() != "coinbase" {
+		t.Errorf("ActiveSource() = %s, expected coinbase", failover.ActiveSource())
+	}
+}
+
+func TestFactory_CreateFromConfig_Consensus(t *testing.T) {
+	factory := NewFactory()
+
+	cfg := config.DefaultConfig()
+	cfg.DataSource.Consensus.Sources = []string{"binance", "coinbase"}
+
+	ds, err := factory.CreateFromConfig(cfg)
+	if err != nil {
+		t.Fatalf("CreateFromConfig() error = %v", err)
+	}
+	if ds.Name() != "consensus(binance,coinbase)" {
+		t.Errorf("Name() = %s", ds.Name())
+	}
+	if _, ok := AsDivergenceReporter(ds); !ok {
+		t.Error("consensus data source should report divergence")
+	}
+}
@@ -0,0 +1,219 @@
+package datasource
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// DefaultFailoverCooldown 默认主数据源恢复冷却时间
+const DefaultFailoverCooldown = 5 * time.Minute
+
+// SourceHealth 单个数据源的健康状态
+type SourceHealth struct {
+	Name                string    // 数据源名称
+	Healthy             bool      // 当前是否健康
+	ConsecutiveFailures int       // 连续失败次数
+	TotalRequests       int       // 总请求数
+	TotalFailures       int       // 总失败数
+	LastError           string    // 最近一次错误
+	LastFailure         time.Time // 最近一次失败时间
+	LastSuccess         time.Time // 最近一次成功时间
+}
+
+// SourceReporter 可报告数据实际来源的数据源
+type SourceReporter interface {
+	// ServedBy 返回最近一次为指定交易对和时间框架提供数据的数据源名称
+	ServedBy(symbol string, timeframe Timeframe) string
+}
+
+// FailoverDataSource 主备自动切换数据源
+// 主数据源出错或返回空数据时切换到备用数据源，冷却时间过后重新尝试主数据源
+type FailoverDataSource struct {
+	primary  DataSource
+	fallback DataSource
+	cooldown time.Duration
+
+	mu         sync.Mutex
+	active     DataSource               // 当前优先使用的数据源
+	switchedAt time.Time                // 最近一次切换到备用数据源的时间
+	health     map[string]*SourceHealth // 各数据源健康状态
+	servedBy   map[string]string        // symbol|timeframe -> 数据源名称
+}
+
+// NewFailoverDataSource 创建主备切换数据源
+func NewFailoverDataSource(primary, fallback DataSource, cooldown time.Duration) *FailoverDataSource {
+	if cooldown <= 0 {
+		cooldown = DefaultFailoverCooldown
+	}
+
+	log.Printf("🔀 启用数据源自动切换: 主 %s, 备 %s, 冷却 %v", primary.Name(), fallback.Name(), cooldown)
+
+	return &FailoverDataSource{
+		primary:  primary,
+		fallback: fallback,
+		cooldown: cooldown,
+		active:   primary,
+		health: map[string]*SourceHealth{
+			primary.Name():  {Name: primary.Name(), Healthy: true},
+			fallback.Name(): {Name: fallback.Name(), Healthy: true},
+		},
+		servedBy: make(map[string]string),
+	}
+}
+
+// Name 返回数据源名称
+func (f *FailoverDataSource) Name() string {
+	return fmt.Sprintf("%s/%s", f.primary.Name(), f.fallback.Name())
+}
+
+// GetKlines 获取K线数据，失败或无数据时自动切换数据源
+func (f *FailoverDataSource) GetKlines(ctx context.Context, symbol string, timeframe Timeframe, startTime, endTime time.Time, limit int) ([]*Kline, error) {
+	var errs sourceErrors
+
+	for _, ds := range f.candidates() {
+		klines, err := ds.GetKlines(ctx, symbol, timeframe, startTime, endTime, limit)
+		if err == nil && len(klines) == 0 {
+			err = fmt.Errorf("no klines returned")
+		}
+
+		if err != nil {
+			f.recordFailure(ds, err)
+			errs = append(errs, fmt.Errorf("%s: %w", ds.Name(), err))
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+
+		f.recordSuccess(ds)
+		f.mu.Lock()
+		f.servedBy[servedKey(symbol, timeframe)] = ds.Name()
+		f.mu.Unlock()
+		return klines, nil
+	}
+
+	return nil, fmt.Errorf("all data sources failed: %w", errs)
+}
+
+// IsSymbolValid 检查交易对是否有效，任一数据源有效即视为有效
+func (f *FailoverDataSource) IsSymbolValid(ctx context.Context, symbol string) (bool, error) {
+	var lastErr error
+	answered := false
+
+	for _, ds := range f.candidates() {
+		valid, err := ds.IsSymbolValid(ctx, symbol)
+		if err != nil {
+			f.recordFailure(ds, err)
+			lastErr = err
+			continue
+		}
+
+		f.recordSuccess(ds)
+		answered = true
+		if valid {
+			return true, nil
+		}
+	}
+
+	if !answered && lastErr != nil {
+		return false, lastErr
+	}
+	return false, nil
+}
+
+// Unwrap 返回主数据源和备用数据源
+func (f *FailoverDataSource) Unwrap() []DataSource {
+	return []DataSource{f.primary, f.fallback}
+}
+
+// ServedBy 返回最近一次为指定交易对和时间框架提供数据的数据源名称
+func (f *FailoverDataSource) ServedBy(symbol string, timeframe Timeframe) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.servedBy[servedKey(symbol, timeframe)]
+}
+
+// ActiveSource 返回当前优先使用的数据源名称
+func (f *FailoverDataSource) ActiveSource() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.active.Name()
+}
+
+// Health 返回所有数据源的健康状态快照
+func (f *FailoverDataSource) Health() []SourceHealth {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return []SourceHealth{
+		*f.health[f.primary.Name()],
+		*f.health[f.fallback.Name()],
+	}
+}
+
+// candidates 返回本次请求的数据源尝试顺序
+func (f *FailoverDataSource) candidates() []DataSource {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.active == f.primary {
+		return []DataSource{f.primary, f.fallback}
+	}
+
+	// 冷却时间已过，优先尝试恢复主数据源
+	if time.Since(f.switchedAt) >= f.cooldown {
+		return []DataSource{f.primary, f.fallback}
+	}
+
+	return []DataSource{f.fallback, f.primary}
+}
+
+// recordSuccess 记录数据源请求成功
+func (f *FailoverDataSource) recordSuccess(ds DataSource) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	h := f.health[ds.Name()]
+	h.TotalRequests++
+	h.ConsecutiveFailures = 0
+	h.Healthy = true
+	h.LastSuccess = time.Now()
+
+	if ds == f.primary && f.active != f.primary {
+		log.Printf("✅ 主数据源 %s 已恢复，切换回主数据源", ds.Name())
+		f.active = f.primary
+	}
+}
+
+// recordFailure 记录数据源请求失败
+func (f *FailoverDataSource) recordFailure(ds DataSource, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	h := f.health[ds.Name()]
+	h.TotalRequests++
+	h.TotalFailures++
+	h.ConsecutiveFailures++
+	h.Healthy = false
+	h.LastError = err.Error()
+	h.LastFailure = time.Now()
+
+	if ds != f.primary {
+		return
+	}
+
+	if f.active == f.primary {
+		log.Printf("⚠️ 主数据源 %s 请求失败，切换到备用数据源 %s: %v", ds.Name(), f.fallback.Name(), err)
+		f.active = f.fallback
+	}
+	// 主数据源探测失败时重新开始冷却计时
+	f.switchedAt = time.Now()
+}
+
+// servedKey 生成数据来源记录的键
+func servedKey(symbol string, timeframe Timeframe) string {
+	return symbol + "|" + string(timeframe)
+}
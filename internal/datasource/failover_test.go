@@ -0,0 +1,176 @@
+package datasource
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stubDataSource 可控的测试数据源
+type stubDataSource struct {
+	name   string
+	mu     sync.Mutex
+	klines []*Kline
+	err    error
+	calls  int
+	valid  bool
+}
+
+func (s *stubDataSource) Name() string {
+	return s.name
+}
+
+func (s *stubDataSource) GetKlines(ctx context.Context, symbol string, timeframe Timeframe, startTime, endTime time.Time, limit int) ([]*Kline, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.klines, nil
+}
+
+func (s *stubDataSource) IsSymbolValid(ctx context.Context, symbol string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return false, s.err
+	}
+	return s.valid, nil
+}
+
+func (s *stubDataSource) set(klines []*Kline, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.klines = klines
+	s.err = err
+}
+
+func (s *stubDataSource) callCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+// makeTestKlines 生成连续的测试K线
+func makeTestKlines(symbol string, start time.Time, interval time.Duration, closes ...float64) []*Kline {
+	klines := make([]*Kline, len(closes))
+	for i, c := range closes {
+		open := start.Add(time.Duration(i) * interval)
+		klines[i] = &Kline{
+			Symbol:    symbol,
+			OpenTime:  open,
+			CloseTime: open.Add(interval - time.Millisecond),
+			Open:      c,
+			High:      c,
+			Low:       c,
+			Close:     c,
+			Volume:    1,
+		}
+	}
+	return klines
+}
+
+func TestFailover_SwitchesOnError(t *testing.T) {
+	primary := &stubDataSource{name: "primary", err: errors.New("geo blocked")}
+	fallback := &stubDataSource{name: "fallback"}
+	fallback.set(makeTestKlines("BTCUSDT", time.Now(), time.Hour, 1, 2, 3), nil)
+
+	ds := NewFailoverDataSource(primary, fallback, time.Hour)
+	klines, err := ds.GetKlines(context.Background(), "BTCUSDT", Timeframe1h, time.Time{}, time.Time{}, 3)
+	if err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if len(klines) != 3 {
+		t.Errorf("expected 3 klines, got %d", len(klines))
+	}
+	if got := ds.ServedBy("BTCUSDT", Timeframe1h); got != "fallback" {
+		t.Errorf("ServedBy() = %q, expected fallback", got)
+	}
+	if got := ds.ActiveSource(); got != "fallback" {
+		t.Errorf("ActiveSource() = %q, expected fallback", got)
+	}
+
+	// 冷却期内不应再请求主数据源
+	if _, err := ds.GetKlines(context.Background(), "BTCUSDT", Timeframe1h, time.Time{}, time.Time{}, 3); err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if primary.callCount() != 1 {
+		t.Errorf("primary should be skipped during cooldown, calls = %d", primary.callCount())
+	}
+
+	health := ds.Health()
+	if health[0].Healthy || health[0].ConsecutiveFailures != 1 {
+		t.Errorf("unexpected primary health: %+v", health[0])
+	}
+	if !health[1].Healthy || health[1].TotalRequests != 2 {
+		t.Errorf("unexpected fallback health: %+v", health[1])
+	}
+}
+
+func TestFailover_SwitchesOnEmptyResult(t *testing.T) {
+	primary := &stubDataSource{name: "primary"}
+	fallback := &stubDataSource{name: "fallback"}
+	fallback.set(makeTestKlines("ETHBTC", time.Now(), time.Hour, 1), nil)
+
+	ds := NewFailoverDataSource(primary, fallback, time.Hour)
+	if _, err := ds.GetKlines(context.Background(), "ETHBTC", Timeframe1d, time.Time{}, time.Time{}, 1); err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if got := ds.ServedBy("ETHBTC", Timeframe1d); got != "fallback" {
+		t.Errorf("ServedBy() = %q, expected fallback", got)
+	}
+}
+
+func TestFailover_SwitchesBackAfterCooldown(t *testing.T) {
+	primary := &stubDataSource{name: "primary", err: errors.New("timeout")}
+	fallback := &stubDataSource{name: "fallback"}
+	fallback.set(makeTestKlines("BTCUSDT", time.Now(), time.Hour, 1), nil)
+
+	ds := NewFailoverDataSource(primary, fallback, 20*time.Millisecond)
+	if _, err := ds.GetKlines(context.Background(), "BTCUSDT", Timeframe1h, time.Time{}, time.Time{}, 1); err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+
+	primary.set(makeTestKlines("BTCUSDT", time.Now(), time.Hour, 2), nil)
+	time.Sleep(30 * time.Millisecond)
+
+	if _, err := ds.GetKlines(context.Background(), "BTCUSDT", Timeframe1h, time.Time{}, time.Time{}, 1); err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if got := ds.ServedBy("BTCUSDT", Timeframe1h); got != "primary" {
+		t.Errorf("ServedBy() = %q, expected primary after cooldown", got)
+	}
+	if got := ds.ActiveSource(); got != "primary" {
+		t.Errorf("ActiveSource() = %q, expected primary", got)
+	}
+}
+
+func TestFailover_AllSourcesFail(t *testing.T) {
+	primary := &stubDataSource{name: "primary", err: errors.New("down")}
+	fallback := &stubDataSource{name: "fallback", err: errors.New("also down")}
+
+	ds := NewFailoverDataSource(primary, fallback, time.Minute)
+	if _, err := ds.GetKlines(context.Background(), "BTCUSDT", Timeframe1h, time.Time{}, time.Time{}, 1); err == nil {
+		t.Error("expected error when all sources fail")
+	}
+	if _, err := ds.IsSymbolValid(context.Background(), "BTCUSDT"); err == nil {
+		t.Error("expected error from IsSymbolValid when all sources fail")
+	}
+}
+
+func TestFailover_IsSymbolValidAnySource(t *testing.T) {
+	primary := &stubDataSource{name: "primary", valid: false}
+	fallback := &stubDataSource{name: "fallback", valid: true}
+
+	ds := NewFailoverDataSource(primary, fallback, time.Minute)
+	valid, err := ds.IsSymbolValid(context.Background(), "ADASOL")
+	if err != nil {
+		t.Fatalf("IsSymbolValid() error = %v", err)
+	}
+	if !valid {
+		t.Error("symbol valid on fallback should be reported as valid")
+	}
+}
@@ -0,0 +1,350 @@
+package datasource
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"ta-watcher/internal/config"
+	"ta-watcher/internal/resample"
+)
+
+// FileDataSource 本地文件数据源
+// 目录结构: <directory>/<SYMBOL>/<timeframe>.csv 或 .parquet，例如 data/BTCUSDT/1d.csv
+// 月线文件在大小写不敏感的文件系统上可命名为 1mo.csv 以避免与 1m.csv 冲突
+type FileDataSource struct {
+	directory string
+
+	mu    sync.Mutex
+	cache map[string]*fileCacheEntry // 文件路径 -> 已解析的K线
+	resampling
+}
+
+// fileCacheEntry 已解析文件的缓存
+type fileCacheEntry struct {
+	modTime time.Time
+	size    int64
+	klines  []*Kline
+}
+
+// supportedFileExtensions 支持的文件格式
+var supportedFileExtensions = []string{".csv", ".parquet"}
+
+// csvTimeLayouts CSV 中支持的时间格式
+var csvTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// NewFileDataSource 创建本地文件数据源
+func NewFileDataSource(cfg *config.FileConfig) *FileDataSource {
+	directory := "data"
+	if cfg != nil && cfg.Directory != "" {
+		directory = cfg.Directory
+	}
+
+	log.Printf("🔗 初始化本地文件数据源: %s", directory)
+
+	return &FileDataSource{
+		directory: directory,
+		cache:     make(map[string]*fileCacheEntry),
+	}
+}
+
+// Name 返回数据源名称
+func (f *FileDataSource) Name() string {
+	return "file"
+}
+
+// IsSymbolValid 检查交易对是否有数据文件
+func (f *FileDataSource) IsSymbolValid(ctx context.Context, symbol string) (bool, error) {
+	if symbol == "" {
+		return false, nil
+	}
+
+	entries, err := os.ReadDir(filepath.Join(f.directory, symbol))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() && isSupportedFile(entry.Name()) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// GetKlines 获取K线数据
+func (f *FileDataSource) GetKlines(ctx context.Context, symbol string, timeframe Timeframe, startTime, endTime time.Time, limit int) ([]*Kline, error) {
+	if limit <= 0 {
+		limit = 500
+	}
+
+	path, err := f.findFile(symbol, timeframe)
+	if err != nil {
+		// 没有该周期的文件时，尝试由更小周期的文件重采样
+		native := func(tf Timeframe) bool {
+			_, err := f.findFile(symbol, tf)
+			return err == nil
+		}
+		if _, ok := resample.SourceFor(timeframe, native); ok {
+			return getResampledKlines(ctx, f.GetKlines, native, f.resampleOptions(), symbol, timeframe, startTime, endTime, limit)
+		}
+		return nil, err
+	}
+
+	all, err := f.load(path, symbol, timeframe)
+	if err != nil {
+		return nil, err
+	}
+
+	// 按时间范围过滤（零值表示不限制）
+	from := 0
+	if !startTime.IsZero() {
+		from = sort.Search(len(all), func(i int) bool {
+			return !all[i].OpenTime.Before(startTime)
+		})
+	}
+	to := len(all)
+	if !endTime.IsZero() {
+		to = sort.Search(len(all), func(i int) bool {
+			return all[i].OpenTime.After(endTime)
+		})
+	}
+	if from >= to {
+		return []*Kline{}, nil
+	}
+
+	selected := all[from:to]
+	if len(selected) > limit {
+		selected = selected[len(selected)-limit:]
+	}
+
+	// 返回副本，避免调用方修改缓存
+	result := make([]*Kline, len(selected))
+	for i, k := range selected {
+		copied := *k
+		result[i] = &copied
+	}
+	markClosed(result, time.Now())
+
+	return result, nil
+}
+
+// findFile 查找交易对和时间框架对应的数据文件
+// 按目录项的文件名精确匹配，避免大小写不敏感的文件系统把 1M 匹配到 1m 的文件
+func (f *FileDataSource) findFile(symbol string, timeframe Timeframe) (string, error) {
+	names := []string{string(timeframe)}
+	if timeframe == Timeframe1M {
+		names = []string{"1mo", string(timeframe)}
+	}
+
+	dir := filepath.Join(f.directory, symbol)
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	files := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			files[entry.Name()] = true
+		}
+	}
+
+	for _, name := range names {
+		for _, ext := range supportedFileExtensions {
+			if files[name+ext] {
+				return filepath.Join(dir, name+ext), nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("no data file for %s %s in %s: %w", symbol, timeframe, dir, ErrSymbolNotFound)
+}
+
+// load 读取并缓存数据文件，文件修改后自动重新加载
+func (f *FileDataSource) load(path, symbol string, timeframe Timeframe) ([]*Kline, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if entry, ok := f.cache[path]; ok && entry.modTime.Equal(info.ModTime()) && entry.size == info.Size() {
+		return entry.klines, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var klines []*Kline
+	if strings.EqualFold(filepath.Ext(path), ".parquet") {
+		klines, err = parseKlineParquet(file, info.Size(), symbol, timeframe)
+	} else {
+		klines, err = parseKlineCSV(file, symbol, timeframe)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	f.cache[path] = &fileCacheEntry{
+		modTime: info.ModTime(),
+		size:    info.Size(),
+		klines:  klines,
+	}
+
+	return klines, nil
+}
+
+// parseKlineCSV 解析带表头的OHLCV CSV文件
+// 必需列: open_time(或 timestamp/time/date), open, high, low, close
+// 可选列: volume, close_time
+func parseKlineCSV(r io.Reader, symbol string, timeframe Timeframe) ([]*Kline, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	timeCol := -1
+	for _, name := range []string{"open_time", "timestamp", "time", "date"} {
+		if idx, ok := columns[name]; ok {
+			timeCol = idx
+			break
+		}
+	}
+	if timeCol < 0 {
+		return nil, fmt.Errorf("missing time column (open_time/timestamp/time/date)")
+	}
+
+	priceCols := make([]int, 4)
+	for i, name := range []string{"open", "high", "low", "close"} {
+		idx, ok := columns[name]
+		if !ok {
+			return nil, fmt.Errorf("missing column: %s", name)
+		}
+		priceCols[i] = idx
+	}
+
+	volumeCol, hasVolume := columns["volume"]
+	closeTimeCol, hasCloseTime := columns["close_time"]
+
+	var klines []*Kline
+	line := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		openTime, err := parseCSVTime(record[timeCol])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid time: %w", line, err)
+		}
+
+		values := make([]float64, 4)
+		for i, idx := range priceCols {
+			v, err := strconv.ParseFloat(strings.TrimSpace(record[idx]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid price: %w", line, err)
+			}
+			values[i] = v
+		}
+
+		kline := &Kline{
+			Symbol:    symbol,
+			OpenTime:  openTime,
+			CloseTime: closeTimeFor(openTime, timeframe),
+			Open:      values[0],
+			High:      values[1],
+			Low:       values[2],
+			Close:     values[3],
+		}
+
+		if hasVolume && strings.TrimSpace(record[volumeCol]) != "" {
+			v, err := strconv.ParseFloat(strings.TrimSpace(record[volumeCol]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid volume: %w", line, err)
+			}
+			kline.Volume = v
+		}
+
+		if hasCloseTime && strings.TrimSpace(record[closeTimeCol]) != "" {
+			closeTime, err := parseCSVTime(record[closeTimeCol])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid close_time: %w", line, err)
+			}
+			kline.CloseTime = closeTime
+		}
+
+		klines = append(klines, kline)
+	}
+
+	sortKlinesByTime(klines)
+	return klines, nil
+}
+
+// parseCSVTime 解析时间字段，支持秒/毫秒时间戳和常见日期格式（无时区视为UTC）
+func parseCSVTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		// 大于 1e11 视为毫秒时间戳
+		if n > 1e11 {
+			return time.UnixMilli(n).UTC(), nil
+		}
+		return time.Unix(n, 0).UTC(), nil
+	}
+
+	for _, layout := range csvTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognized time format: %s", value)
+}
+
+// isSupportedFile 判断文件扩展名是否受支持
+func isSupportedFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, supported := range supportedFileExtensions {
+		if ext == supported {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,220 @@
+package datasource
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/deprecated"
+)
+
+// parquetReadBatch 每次从 Parquet 文件读取的行数
+const parquetReadBatch = 512
+
+// parquetColumn Parquet 文件中的一列
+type parquetColumn struct {
+	index int
+	node  parquet.Node
+}
+
+// parseKlineParquet 解析 Parquet 格式的OHLCV文件
+// 列名规则与 CSV 相同: open_time(或 timestamp/time/date), open, high, low, close，可选 volume, close_time
+// 时间列支持 TIMESTAMP/DATE 逻辑类型、秒/毫秒/微秒/纳秒整数时间戳以及字符串日期
+func parseKlineParquet(r io.ReaderAt, size int64, symbol string, timeframe Timeframe) ([]*Kline, error) {
+	file, err := parquet.OpenFile(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parquet file: %w", err)
+	}
+
+	schema := file.Schema()
+	columns := make(map[string]parquetColumn)
+	for _, path := range schema.Columns() {
+		leaf, ok := schema.Lookup(path...)
+		if !ok {
+			continue
+		}
+		name := strings.ToLower(strings.TrimSpace(path[len(path)-1]))
+		columns[name] = parquetColumn{index: leaf.ColumnIndex, node: leaf.Node}
+	}
+
+	var timeCol parquetColumn
+	hasTime := false
+	for _, name := range []string{"open_time", "timestamp", "time", "date"} {
+		if col, ok := columns[name]; ok {
+			timeCol, hasTime = col, true
+			break
+		}
+	}
+	if !hasTime {
+		return nil, fmt.Errorf("missing time column (open_time/timestamp/time/date)")
+	}
+
+	priceCols := make([]parquetColumn, 4)
+	for i, name := range []string{"open", "high", "low", "close"} {
+		col, ok := columns[name]
+		if !ok {
+			return nil, fmt.Errorf("missing column: %s", name)
+		}
+		priceCols[i] = col
+	}
+
+	volumeCol, hasVolume := columns["volume"]
+	closeTimeCol, hasCloseTime := columns["close_time"]
+
+	reader := parquet.NewReader(file)
+	defer reader.Close()
+
+	var klines []*Kline
+	rows := make([]parquet.Row, parquetReadBatch)
+	rowNum := 0
+	for {
+		n, err := reader.ReadRows(rows)
+		for _, row := range rows[:n] {
+			rowNum++
+			values := make(map[int]parquet.Value, len(row))
+			for _, v := range row {
+				values[v.Column()] = v
+			}
+
+			openTime, err := parquetTime(values[timeCol.index], timeCol.node)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: invalid time: %w", rowNum, err)
+			}
+
+			prices := make([]float64, 4)
+			for i, col := range priceCols {
+				v, err := parquetFloat(values[col.index])
+				if err != nil {
+					return nil, fmt.Errorf("row %d: invalid price: %w", rowNum, err)
+				}
+				prices[i] = v
+			}
+
+			kline := &Kline{
+				Symbol:    symbol,
+				OpenTime:  openTime,
+				CloseTime: closeTimeFor(openTime, timeframe),
+				Open:      prices[0],
+				High:      prices[1],
+				Low:       prices[2],
+				Close:     prices[3],
+			}
+
+			if v, ok := values[volumeCol.index]; hasVolume && ok && !v.IsNull() {
+				volume, err := parquetFloat(v)
+				if err != nil {
+					return nil, fmt.Errorf("row %d: invalid volume: %w", rowNum, err)
+				}
+				kline.Volume = volume
+			}
+
+			if v, ok := values[closeTimeCol.index]; hasCloseTime && ok && !v.IsNull() {
+				closeTime, err := parquetTime(v, closeTimeCol.node)
+				if err != nil {
+					return nil, fmt.Errorf("row %d: invalid close_time: %w", rowNum, err)
+				}
+				kline.CloseTime = closeTime
+			}
+
+			klines = append(klines, kline)
+		}
+
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rows: %w", err)
+		}
+	}
+
+	sortKlinesByTime(klines)
+	return klines, nil
+}
+
+// parquetFloat 将数值列转换为 float64，字符串列按十进制解析
+func parquetFloat(v parquet.Value) (float64, error) {
+	if v.IsNull() {
+		return 0, errors.New("null value")
+	}
+
+	switch v.Kind() {
+	case parquet.Int32:
+		return float64(v.Int32()), nil
+	case parquet.Int64:
+		return float64(v.Int64()), nil
+	case parquet.Float:
+		return float64(v.Float()), nil
+	case parquet.Double:
+		return v.Double(), nil
+	case parquet.ByteArray, parquet.FixedLenByteArray:
+		var f float64
+		if _, err := fmt.Sscan(strings.TrimSpace(string(v.ByteArray())), &f); err != nil {
+			return 0, err
+		}
+		return f, nil
+	default:
+		return 0, fmt.Errorf("unsupported column type %s", v.Kind())
+	}
+}
+
+// parquetTime 按列的逻辑类型解析时间，无逻辑类型的整数按数量级推断时间戳单位
+func parquetTime(v parquet.Value, node parquet.Node) (time.Time, error) {
+	if v.IsNull() {
+		return time.Time{}, errors.New("null value")
+	}
+
+	switch v.Kind() {
+	case parquet.ByteArray, parquet.FixedLenByteArray:
+		return parseCSVTime(string(v.ByteArray()))
+	case parquet.Int32, parquet.Int64:
+	default:
+		return time.Time{}, fmt.Errorf("unsupported column type %s", v.Kind())
+	}
+
+	n := v.Int64()
+	if v.Kind() == parquet.Int32 {
+		n = int64(v.Int32())
+	}
+
+	typ := node.Type()
+	if logical := typ.LogicalType(); logical != nil {
+		switch {
+		case logical.Timestamp != nil:
+			unit := logical.Timestamp.Unit
+			switch {
+			case unit.Nanos != nil:
+				return time.Unix(0, n).UTC(), nil
+			case unit.Micros != nil:
+				return time.UnixMicro(n).UTC(), nil
+			default:
+				return time.UnixMilli(n).UTC(), nil
+			}
+		case logical.Date != nil:
+			return time.Unix(n*86400, 0).UTC(), nil
+		}
+	}
+	if converted := typ.ConvertedType(); converted != nil {
+		switch *converted {
+		case deprecated.TimestampMillis:
+			return time.UnixMilli(n).UTC(), nil
+		case deprecated.TimestampMicros:
+			return time.UnixMicro(n).UTC(), nil
+		case deprecated.Date:
+			return time.Unix(n*86400, 0).UTC(), nil
+		}
+	}
+
+	switch {
+	case n > 1e17:
+		return time.Unix(0, n).UTC(), nil
+	case n > 1e14:
+		return time.UnixMicro(n).UTC(), nil
+	case n > 1e11:
+		return time.UnixMilli(n).UTC(), nil
+	default:
+		return time.Unix(n, 0).UTC(), nil
+	}
+}
@@ -0,0 +1,258 @@
+package datasource
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"ta-watcher/internal/config"
+)
+
+// writeTestFile 在测试目录中写入数据文件
+func writeTestFile(t *testing.T, dir, symbol, name, content string) {
+	t.Helper()
+	symbolDir := filepath.Join(dir, symbol)
+	if err := os.MkdirAll(symbolDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(symbolDir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileDataSource_GetKlines(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "AAPL", "1d.csv", `date,open,high,low,close,volume
+2025-01-03,10,12,9,11,100
+2025-01-01,8,9,7,8.5,80
+2025-01-02,8.5,10,8,10,90
+2025-01-06,11,13,10,12,120
+`)
+
+	ds := NewFileDataSource(&config.FileConfig{Directory: dir})
+	ctx := context.Background()
+
+	klines, err := ds.GetKlines(ctx, "AAPL", Timeframe1d, time.Time{}, time.Time{}, 0)
+	if err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if len(klines) != 4 {
+		t.Fatalf("expected 4 klines, got %d", len(klines))
+	}
+	if klines[0].Close != 8.5 || klines[3].Close != 12 {
+		t.Errorf("klines should be sorted by time: first=%.1f last=%.1f", klines[0].Close, klines[3].Close)
+	}
+	if !klines[0].CloseTime.Equal(time.Date(2025, 1, 1, 23, 59, 59, int(999*time.Millisecond), time.UTC)) {
+		t.Errorf("unexpected close time: %v", klines[0].CloseTime)
+	}
+
+	// 时间范围过滤
+	start := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)
+	klines, err = ds.GetKlines(ctx, "AAPL", Timeframe1d, start, end, 0)
+	if err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if len(klines) != 2 || klines[0].Close != 10 || klines[1].Close != 11 {
+		t.Errorf("unexpected range result: %d klines", len(klines))
+	}
+
+	// limit 保留最新的数据
+	klines, err = ds.GetKlines(ctx, "AAPL", Timeframe1d, time.Time{}, time.Time{}, 1)
+	if err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if len(klines) != 1 || klines[0].Close != 12 {
+		t.Errorf("limit should keep the latest kline")
+	}
+}
+
+func TestFileDataSource_TimestampFormats(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "EURUSD", "1h.csv", `open_time,open,high,low,close,volume,close_time
+1735689600000,1.03,1.04,1.02,1.035,0,1735693199999
+1735693200,1.035,1.05,1.03,1.04,,
+`)
+
+	ds := NewFileDataSource(&config.FileConfig{Directory: dir})
+	klines, err := ds.GetKlines(context.Background(), "EURUSD", Timeframe1h, time.Time{}, time.Time{}, 10)
+	if err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if len(klines) != 2 {
+		t.Fatalf("expected 2 klines, got %d", len(klines))
+	}
+	if klines[1].OpenTime.Sub(klines[0].OpenTime) != time.Hour {
+		t.Errorf("millisecond and second timestamps should both be parsed")
+	}
+	if klines[0].CloseTime.UnixMilli() != 1735693199999 {
+		t.Errorf("close_time column should be honored")
+	}
+}
+
+func TestFileDataSource_IsSymbolValid(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "BTCUSDT", "1d.csv", "open_time,open,high,low,close\n")
+	writeTestFile(t, dir, "EMPTY", "notes.txt", "nothing here")
+
+	ds := NewFileDataSource(&config.FileConfig{Directory: dir})
+	ctx := context.Background()
+
+	tests := []struct {
+		symbol   string
+		expected bool
+	}{
+		{"BTCUSDT", true},
+		{"EMPTY", false},
+		{"MISSING", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		valid, err := ds.IsSymbolValid(ctx, tt.symbol)
+		if err != nil {
+			t.Errorf("IsSymbolValid(%s) error = %v", tt.symbol, err)
+		}
+		if valid != tt.expected {
+			t.Errorf("IsSymbolValid(%s) = %v, expected %v", tt.symbol, valid, tt.expected)
+		}
+	}
+}
+
+func TestFileDataSource_Errors(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "BAD", "1d.csv", "date,open,high,low\n2025-01-01,1,2,0.5\n")
+	writeTestFile(t, dir, "PQ", "1d.parquet", "PAR1")
+
+	ds := NewFileDataSource(&config.FileConfig{Directory: dir})
+	ctx := context.Background()
+
+	if _, err := ds.GetKlines(ctx, "BAD", Timeframe1d, time.Time{}, time.Time{}, 10); err == nil {
+		t.Error("expected error for missing close column")
+	}
+	if _, err := ds.GetKlines(ctx, "MISSING", Timeframe1d, time.Time{}, time.Time{}, 10); err == nil {
+		t.Error("expected error for missing file")
+	}
+	if _, err := ds.GetKlines(ctx, "PQ", Timeframe1d, time.Time{}, time.Time{}, 10); err == nil {
+		t.Error("expected error for invalid parquet file")
+	}
+}
+
+// writeTestParquet 在测试目录中写入 Parquet 数据文件
+func writeTestParquet[T any](t *testing.T, dir, symbol, name string, rows []T) {
+	t.Helper()
+	symbolDir := filepath.Join(dir, symbol)
+	if err := os.MkdirAll(symbolDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Create(filepath.Join(symbolDir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if err := parquet.Write(file, rows); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileDataSource_Parquet(t *testing.T) {
+	type timestampRow struct {
+		OpenTime time.Time `parquet:"open_time,timestamp(millisecond)"`
+		Open     float64   `parquet:"open"`
+		High     float64   `parquet:"high"`
+		Low      float64   `parquet:"low"`
+		Close    float64   `parquet:"close"`
+		Volume   int64     `parquet:"volume"`
+	}
+	type epochRow struct {
+		Timestamp int64   `parquet:"timestamp"`
+		Open      float32 `parquet:"Open"`
+		High      float32 `parquet:"High"`
+		Low       float32 `parquet:"Low"`
+		Close     float32 `parquet:"Close"`
+	}
+
+	dir := t.TempDir()
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	writeTestParquet(t, dir, "BTCUSDT", "1d.parquet", []timestampRow{
+		{day.AddDate(0, 0, 1), 101, 103, 100, 102, 20},
+		{day, 100, 102, 99, 101, 10},
+	})
+	writeTestParquet(t, dir, "ETHUSDT", "1h.parquet", []epochRow{
+		{day.UnixMilli(), 3.5, 4, 3, 3.75},
+		{day.Add(time.Hour).Unix(), 3.75, 4.5, 3.5, 4.25},
+	})
+
+	ds := NewFileDataSource(&config.FileConfig{Directory: dir})
+	ctx := context.Background()
+
+	valid, err := ds.IsSymbolValid(ctx, "BTCUSDT")
+	if err != nil || !valid {
+		t.Fatalf("parquet file should make symbol valid: valid=%v err=%v", valid, err)
+	}
+
+	klines, err := ds.GetKlines(ctx, "BTCUSDT", Timeframe1d, time.Time{}, time.Time{}, 10)
+	if err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if len(klines) != 2 {
+		t.Fatalf("expected 2 klines, got %d", len(klines))
+	}
+	if !klines[0].OpenTime.Equal(day) || klines[0].Close != 101 || klines[1].Volume != 20 {
+		t.Errorf("unexpected parquet klines: %+v %+v", klines[0], klines[1])
+	}
+	if !klines[0].CloseTime.Equal(day.Add(24*time.Hour - time.Millisecond)) {
+		t.Errorf("unexpected close time: %v", klines[0].CloseTime)
+	}
+
+	klines, err = ds.GetKlines(ctx, "ETHUSDT", Timeframe1h, time.Time{}, time.Time{}, 10)
+	if err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if len(klines) != 2 {
+		t.Fatalf("expected 2 klines, got %d", len(klines))
+	}
+	if klines[1].OpenTime.Sub(klines[0].OpenTime) != time.Hour {
+		t.Errorf("integer timestamps should be parsed by magnitude: %v %v", klines[0].OpenTime, klines[1].OpenTime)
+	}
+	if klines[1].Close != 4.25 || klines[0].Volume != 0 {
+		t.Errorf("unexpected parquet klines: %+v %+v", klines[0], klines[1])
+	}
+
+	// 缺少必需列时报错
+	type noCloseRow struct {
+		Date string  `parquet:"date"`
+		Open float64 `parquet:"open"`
+		High float64 `parquet:"high"`
+		Low  float64 `parquet:"low"`
+	}
+	writeTestParquet(t, dir, "BAD", "1d.parquet", []noCloseRow{{"2025-01-01", 1, 2, 0.5}})
+	if _, err := ds.GetKlines(ctx, "BAD", Timeframe1d, time.Time{}, time.Time{}, 10); err == nil {
+		t.Error("expected error for missing close column")
+	}
+}
+
+func TestFileDataSource_MonthlyFileName(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "BTCUSDT", "1m.csv", "open_time,open,high,low,close\n1735689600,1,2,0.5,1.5\n")
+
+	ds := NewFileDataSource(&config.FileConfig{Directory: dir})
+	ctx := context.Background()
+
+	// 1M 不能匹配到分钟线文件（大小写不敏感的文件系统上 os.Stat("1M.csv") 会命中 1m.csv）
+	if _, err := ds.findFile("BTCUSDT", Timeframe1M); err == nil {
+		t.Error("1M should not match 1m.csv")
+	}
+
+	writeTestFile(t, dir, "BTCUSDT", "1mo.csv", "open_time,open,high,low,close\n2025-01-01,10,20,5,15\n")
+	klines, err := ds.GetKlines(ctx, "BTCUSDT", Timeframe1M, time.Time{}, time.Time{}, 10)
+	if err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if len(klines) != 1 || klines[0].Close != 15 {
+		t.Errorf("1M should be read from 1mo.csv, got %+v", klines)
+	}
+}
@@ -0,0 +1,457 @@
+package datasource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"ta-watcher/internal/config"
+	"ta-watcher/internal/instrument"
+)
+
+// krakenMaxCandles Kraken OHLC 接口最多返回的K线数量
+const krakenMaxCandles = 720
+
+// KrakenClient Kraken数据源实现
+type KrakenClient struct {
+	baseURL   string
+	client    *http.Client
+	rateLimit *config.RateLimitConfig
+	resampling
+}
+
+// krakenResponse Kraken API 通用响应结构
+type krakenResponse struct {
+	Error  []string                   `json:"error"`
+	Result map[string]json.RawMessage `json:"result"`
+}
+
+// krakenAPIError Kraken 业务错误
+type krakenAPIError struct {
+	Messages []string
+}
+
+func (e *krakenAPIError) Error() string {
+	return fmt.Sprintf("kraken API error: %s", strings.Join(e.Messages, ", "))
+}
+
+// Unwrap 将 Kraken 错误消息映射为数据源错误类型
+func (e *krakenAPIError) Unwrap() error {
+	for _, msg := range e.Messages {
+		switch {
+		case strings.HasPrefix(msg, "EQuery:Unknown asset pair"):
+			return ErrSymbolNotFound
+		case strings.HasPrefix(msg, "EAPI:Rate limit exceeded"), strings.HasPrefix(msg, "EGeneral:Too many requests"):
+			return ErrRateLimited
+		case strings.HasPrefix(msg, "EService:"):
+			return ErrUnavailable
+		}
+	}
+	return nil
+}
+
+// NewKrakenClient 创建Kraken客户端（建议使用NewKrakenClientWithConfig）
+func NewKrakenClient() *KrakenClient {
+	return NewKrakenClientWithConfig(nil)
+}
+
+// NewKrakenClientWithConfig 使用配置创建Kraken客户端
+func NewKrakenClientWithConfig(cfg *config.KrakenConfig) *KrakenClient {
+	log.Printf("🔗 初始化 Kraken 数据源")
+	client := &KrakenClient{
+		baseURL: "https://api.kraken.com",
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+
+	if cfg != nil {
+		client.rateLimit = &cfg.RateLimit
+		fmt.Printf("🔧 [Kraken] 使用配置限流: 每分钟%d请求, 延迟%v, 重试%d次\n",
+			cfg.RateLimit.RequestsPerMinute, cfg.RateLimit.RetryDelay, cfg.RateLimit.MaxRetries)
+	} else {
+		// 默认配置（公共接口约每秒1次）
+		client.rateLimit = &config.RateLimitConfig{
+			RequestsPerMinute: 60,
+			RetryDelay:        5 * time.Second,
+			MaxRetries:        3,
+		}
+		fmt.Printf("⚠️  [Kraken] 使用默认限流配置: 每分钟%d请求, 延迟%v, 重试%d次\n",
+			60, 5*time.Second, 3)
+	}
+
+	return client
+}
+
+// Name 返回数据源名称
+func (k *KrakenClient) Name() string {
+	return "kraken"
+}
+
+// IsSymbolValid 检查交易对是否有效
+func (k *KrakenClient) IsSymbolValid(ctx context.Context, symbol string) (bool, error) {
+	url := fmt.Sprintf("%s/0/public/AssetPairs", k.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return false, err
+	}
+
+	q := req.URL.Query()
+	q.Add("pair", k.convertToKrakenSymbol(symbol))
+	req.URL.RawQuery = q.Encode()
+
+	result, err := k.doRequest(req)
+	if err != nil {
+		var apiErr *krakenAPIError
+		if errors.As(err, &apiErr) {
+			log.Printf("❌ [Kraken] %s 不存在: %v", symbol, err)
+			return false, nil
+		}
+		return false, err
+	}
+
+	return len(result) > 0, nil
+}
+
+// GetKlines 获取K线数据（按 since 正向分页）
+// 注意：Kraken 仅提供最近720根K线，更早的数据无法获取
+func (k *KrakenClient) GetKlines(ctx context.Context, symbol string, timeframe Timeframe, startTime, endTime time.Time, limit int) ([]*Kline, error) {
+	if limit <= 0 {
+		limit = 300
+	}
+
+	interval := k.convertTimeframeToInterval(timeframe)
+	if interval == 0 {
+		// Kraken 没有该周期（如2h、1M），由更小的原生周期重采样
+		return getResampledKlines(ctx, k.GetKlines, k.supportsTimeframe, k.resampleOptions(), symbol, timeframe, startTime, endTime, limit)
+	}
+
+	if endTime.IsZero() {
+		endTime = time.Now()
+	}
+	if startTime.IsZero() {
+		startTime = endTime.Add(-time.Duration(limit) * timeframe.Duration())
+	}
+
+	pair := k.convertToKrakenSymbol(symbol)
+	seen := make(map[int64]bool)
+	var allKlines []*Kline
+
+	since := startTime.Unix() - 1
+	for {
+		rows, last, err := k.fetchOHLCPage(ctx, pair, interval, since)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch page: %w", err)
+		}
+
+		for _, raw := range rows {
+			kline, err := k.parseCandle(symbol, timeframe, raw)
+			if err != nil {
+				continue // 跳过无法解析的数据
+			}
+			ts := kline.OpenTime.Unix()
+			if seen[ts] || kline.OpenTime.Before(startTime) || kline.OpenTime.After(endTime) {
+				continue
+			}
+			seen[ts] = true
+			allKlines = append(allKlines, kline)
+		}
+
+		// 没有新数据、已到达结束时间或已返回全部可用数据
+		if len(rows) == 0 || last <= since || time.Unix(last, 0).After(endTime) || len(rows) < krakenMaxCandles {
+			break
+		}
+		since = last
+	}
+
+	sortKlinesByTime(allKlines)
+
+	if len(allKlines) > limit {
+		allKlines = allKlines[len(allKlines)-limit:]
+	}
+	markClosed(allKlines, time.Now())
+
+	return allKlines, nil
+}
+
+// fetchOHLCPage 获取单页K线数据，返回原始数据和下一页游标
+func (k *KrakenClient) fetchOHLCPage(ctx context.Context, pair string, interval int, since int64) ([][]interface{}, int64, error) {
+	url := fmt.Sprintf("%s/0/public/OHLC", k.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	q := req.URL.Query()
+	q.Add("pair", pair)
+	q.Add("interval", strconv.Itoa(interval))
+	q.Add("since", strconv.FormatInt(since, 10))
+	req.URL.RawQuery = q.Encode()
+
+	result, err := k.doRequest(req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var rows [][]interface{}
+	var last int64
+	for key, raw := range result {
+		if key == "last" {
+			if err := json.Unmarshal(raw, &last); err != nil {
+				return nil, 0, fmt.Errorf("invalid last cursor: %w", err)
+			}
+			continue
+		}
+		// 结果中的交易对键名为 Kraken 内部名称（如 XXBTZUSD），只会有一个
+		if err := json.Unmarshal(raw, &rows); err != nil {
+			return nil, 0, fmt.Errorf("invalid OHLC data: %w", err)
+		}
+	}
+
+	return rows, last, nil
+}
+
+// doRequest 执行请求并解析Kraken通用响应
+func (k *KrakenClient) doRequest(req *http.Request) (map[string]json.RawMessage, error) {
+	resp, err := k.executeWithRateLimit(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("kraken", resp)
+	}
+
+	var body krakenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	if len(body.Error) > 0 {
+		return nil, &krakenAPIError{Messages: body.Error}
+	}
+
+	return body.Result, nil
+}
+
+// parseCandle 解析Kraken蜡烛图数据
+// Kraken格式: [time, "open", "high", "low", "close", "vwap", "volume", count]
+func (k *KrakenClient) parseCandle(symbol string, timeframe Timeframe, raw []interface{}) (*Kline, error) {
+	if len(raw) < 7 {
+		return nil, fmt.Errorf("invalid candle data length: %d", len(raw))
+	}
+
+	ts, err := parseFloat64(raw[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid time: %w", err)
+	}
+
+	open, err := parseFloat64FromString(raw[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid open price: %w", err)
+	}
+
+	high, err := parseFloat64FromString(raw[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid high price: %w", err)
+	}
+
+	low, err := parseFloat64FromString(raw[3])
+	if err != nil {
+		return nil, fmt.Errorf("invalid low price: %w", err)
+	}
+
+	close, err := parseFloat64FromString(raw[4])
+	if err != nil {
+		return nil, fmt.Errorf("invalid close price: %w", err)
+	}
+
+	volume, err := parseFloat64FromString(raw[6])
+	if err != nil {
+		return nil, fmt.Errorf("invalid volume: %w", err)
+	}
+
+	openTime := time.Unix(int64(ts), 0)
+
+	return &Kline{
+		Symbol:    symbol,
+		OpenTime:  openTime,
+		CloseTime: closeTimeFor(openTime, timeframe),
+		Open:      open,
+		High:      high,
+		Low:       low,
+		Close:     close,
+		Volume:    volume,
+	}, nil
+}
+
+// convertToKrakenSymbol 转换为Kraken交易对格式
+// BTCUSDT -> XBTUSDT
+func (k *KrakenClient) convertToKrakenSymbol(symbol string) string {
+	return nativeSymbol(instrument.Kraken, symbol)
+}
+
+// supportsTimeframe 判断Kraken是否原生支持该时间框架
+func (k *KrakenClient) supportsTimeframe(tf Timeframe) bool {
+	return k.convertTimeframeToInterval(tf) != 0
+}
+
+// convertTimeframeToInterval 转换时间框架为Kraken interval参数（分钟）
+func (k *KrakenClient) convertTimeframeToInterval(tf Timeframe) int {
+	switch tf {
+	case Timeframe1m:
+		return 1
+	case Timeframe5m:
+		return 5
+	case Timeframe15m:
+		return 15
+	case Timeframe30m:
+		return 30
+	case Timeframe1h:
+		return 60
+	case Timeframe4h:
+		return 240
+	case Timeframe1d:
+		return 1440
+	case Timeframe1w:
+		return 10080
+	default:
+		return 0 // 不支持的时间框架
+	}
+}
+
+// krakenDepthMaxCount /0/public/Depth 单次最多返回的档位数
+const krakenDepthMaxCount = 500
+
+// GetOrderBook 获取订单簿快照
+func (k *KrakenClient) GetOrderBook(ctx context.Context, symbol string, depth int) (*OrderBook, error) {
+	count := depth
+	if count <= 0 || count > krakenDepthMaxCount {
+		count = krakenDepthMaxCount
+	}
+
+	url := fmt.Sprintf("%s/0/public/Depth", k.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	q := req.URL.Query()
+	q.Add("pair", k.convertToKrakenSymbol(symbol))
+	q.Add("count", strconv.Itoa(count))
+	req.URL.RawQuery = q.Encode()
+
+	result, err := k.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 结果以Kraken内部交易对名为键，格式: [price, volume, timestamp]
+	for _, raw := range result {
+		var book struct {
+			Bids [][]json.RawMessage `json:"bids"`
+			Asks [][]json.RawMessage `json:"asks"`
+		}
+		if err := json.Unmarshal(raw, &book); err != nil {
+			return nil, fmt.Errorf("invalid order book: %w", err)
+		}
+
+		bids, err := parseBookLevels(book.Bids)
+		if err != nil {
+			return nil, err
+		}
+		asks, err := parseBookLevels(book.Asks)
+		if err != nil {
+			return nil, err
+		}
+		return NewOrderBook(symbol, time.Now(), bids, asks, depth), nil
+	}
+
+	return nil, fmt.Errorf("%w: empty order book for %s", ErrSymbolNotFound, symbol)
+}
+
+// krakenTradesMaxCount /0/public/Trades 单次最大返回数量
+const krakenTradesMaxCount = 1000
+
+// GetRecentTrades 获取最近的成交
+func (k *KrakenClient) GetRecentTrades(ctx context.Context, symbol string, limit int) ([]*Trade, error) {
+	if limit <= 0 || limit > krakenTradesMaxCount {
+		limit = krakenTradesMaxCount
+	}
+
+	url := fmt.Sprintf("%s/0/public/Trades", k.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	q := req.URL.Query()
+	q.Add("pair", k.convertToKrakenSymbol(symbol))
+	q.Add("count", strconv.Itoa(limit))
+	req.URL.RawQuery = q.Encode()
+
+	result, err := k.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 格式: [price, volume, time, buy/sell, market/limit, misc, trade_id]，side 为吃单方方向
+	var rows [][]json.RawMessage
+	for key, raw := range result {
+		if key == "last" {
+			continue
+		}
+		if err := json.Unmarshal(raw, &rows); err != nil {
+			return nil, fmt.Errorf("invalid trades: %w", err)
+		}
+	}
+
+	trades := make([]*Trade, 0, len(rows))
+	for _, row := range rows {
+		if len(row) < 4 {
+			return nil, fmt.Errorf("invalid trade data length: %d", len(row))
+		}
+		price, err := parseJSONNumber(row[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid trade price: %w", err)
+		}
+		volume, err := parseJSONNumber(row[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid trade volume: %w", err)
+		}
+		ts, err := parseJSONNumber(row[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid trade time: %w", err)
+		}
+		var side string
+		if err := json.Unmarshal(row[3], &side); err != nil {
+			return nil, fmt.Errorf("invalid trade side: %w", err)
+		}
+
+		trade := &Trade{
+			Symbol:     symbol,
+			Time:       time.UnixMilli(int64(ts * 1000)),
+			Price:      price,
+			Quantity:   volume,
+			BuyerMaker: side == "s",
+		}
+		if len(row) >= 7 {
+			trade.ID = strings.Trim(string(row[6]), `"`)
+		}
+		trades = append(trades, trade)
+	}
+
+	sortTradesByTime(trades)
+	return trades, nil
+}
+
+// executeWithRateLimit 执行带限流的HTTP请求
+func (k *KrakenClient) executeWithRateLimit(req *http.Request) (*http.Response, error) {
+	return executeWithRetry(k.client, req, k.rateLimit, func(resp *http.Response) bool {
+		return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	})
+}
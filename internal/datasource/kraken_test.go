@@ -0,0 +1,154 @@
+package datasource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// newKrakenTestServer 模拟Kraken公共接口，按请求的 interval 生成K线，每页最多返回pageSize根K线
+func newKrakenTestServer(t *testing.T, base time.Time, count, pageSize int) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/0/public/AssetPairs":
+			if q.Get("pair") == "XBTUSDT" {
+				fmt.Fprint(w, `{"error":[],"result":{"XBTUSDT":{"altname":"XBTUSDT"}}}`)
+				return
+			}
+			fmt.Fprint(w, `{"error":["EQuery:Unknown asset pair"]}`)
+		case "/0/public/OHLC":
+			if q.Get("pair") != "XBTUSDT" {
+				t.Errorf("unexpected pair: %s", q.Get("pair"))
+			}
+			since, _ := strconv.ParseInt(q.Get("since"), 10, 64)
+			interval, _ := strconv.Atoi(q.Get("interval"))
+			step := time.Duration(interval) * time.Minute
+
+			var rows [][]interface{}
+			var last int64
+			for i := 0; i < count && len(rows) < pageSize; i++ {
+				ts := base.Add(time.Duration(i) * step).Unix()
+				if ts <= since {
+					continue
+				}
+				price := strconv.Itoa(100 + i)
+				rows = append(rows, []interface{}{ts, price, price, price, price, price, "2", 5})
+				last = ts
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error":  []string{},
+				"result": map[string]interface{}{"XXBTZUSD": rows, "last": last},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestKrakenClient_New(t *testing.T) {
+	client := NewKrakenClient()
+
+	if client.Name() != "kraken" {
+		t.Errorf("Expected name 'kraken', got '%s'", client.Name())
+	}
+}
+
+func TestKrakenClient_GetKlines(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	server := newKrakenTestServer(t, base, 50, krakenMaxCandles)
+	defer server.Close()
+
+	client := NewKrakenClient()
+	client.baseURL = server.URL
+	client.rateLimit.RequestsPerMinute = 6000
+
+	klines, err := client.GetKlines(context.Background(), "BTCUSDT", Timeframe1h, base.Add(10*time.Hour), base.Add(29*time.Hour), 100)
+	if err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+
+	if len(klines) != 20 {
+		t.Fatalf("expected 20 klines in range, got %d", len(klines))
+	}
+	if klines[0].Close != 110 || klines[19].Close != 129 {
+		t.Errorf("unexpected range: first=%.0f last=%.0f", klines[0].Close, klines[19].Close)
+	}
+	if klines[0].Volume != 2 {
+		t.Errorf("Volume = %.0f, expected 2", klines[0].Volume)
+	}
+}
+
+func TestKrakenClient_IsSymbolValid(t *testing.T) {
+	server := newKrakenTestServer(t, time.Now(), 0, krakenMaxCandles)
+	defer server.Close()
+
+	client := NewKrakenClient()
+	client.baseURL = server.URL
+	client.rateLimit.RequestsPerMinute = 6000
+
+	valid, err := client.IsSymbolValid(context.Background(), "BTCUSDT")
+	if err != nil || !valid {
+		t.Errorf("IsSymbolValid(BTCUSDT) = %v, %v", valid, err)
+	}
+
+	valid, err = client.IsSymbolValid(context.Background(), "FOOUSDT")
+	if err != nil || valid {
+		t.Errorf("IsSymbolValid(FOOUSDT) = %v, %v", valid, err)
+	}
+}
+
+func TestKrakenClient_Conversions(t *testing.T) {
+	client := NewKrakenClient()
+
+	symbols := map[string]string{
+		"BTCUSDT": "XBTUSDT",
+		"DOGEUSD": "XDGUSD",
+		"ETHBTC":  "ETHXBT",
+		"SOLUSDC": "SOLUSDC",
+	}
+	for in, want := range symbols {
+		if got := client.convertToKrakenSymbol(in); got != want {
+			t.Errorf("convertToKrakenSymbol(%s) = %s, expected %s", in, got, want)
+		}
+	}
+
+}
+
+func TestKrakenClient_ResamplesUnsupportedTimeframe(t *testing.T) {
+	// 2024-12-31 是按Unix纪元对齐的3日周期起点
+	base := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	server := newKrakenTestServer(t, base, 9, krakenMaxCandles)
+	defer server.Close()
+
+	client := NewKrakenClient()
+	client.baseURL = server.URL
+	client.rateLimit.RequestsPerMinute = 6000
+
+	// Kraken 没有原生3日周期，由日线重采样
+	klines, err := client.GetKlines(context.Background(), "BTCUSDT", Timeframe3d, base, base.AddDate(0, 0, 8), 10)
+	if err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if len(klines) != 3 {
+		t.Fatalf("expected 3 resampled klines, got %d", len(klines))
+	}
+	for i, k := range klines {
+		if !k.OpenTime.Equal(base.AddDate(0, 0, 3*i)) {
+			t.Errorf("kline %d: open time %v not aligned to 3d period", i, k.OpenTime)
+		}
+		if k.Volume != 6 || k.Partial {
+			t.Errorf("kline %d: expected 3 full days (volume 6), got volume %.0f partial %v", i, k.Volume, k.Partial)
+		}
+	}
+	if klines[0].Open != 100 || klines[0].Close != 102 || klines[2].Close != 108 {
+		t.Errorf("unexpected OHLC: first open=%.0f close=%.0f, last close=%.0f", klines[0].Open, klines[0].Close, klines[2].Close)
+	}
+}
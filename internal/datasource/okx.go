@@ -0,0 +1,445 @@
+package datasource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ta-watcher/internal/config"
+	"ta-watcher/internal/instrument"
+)
+
+// okxPageSize OKX history-candles 单次最大返回数量
+const okxPageSize = 100
+
+// OKXClient OKX数据源实现
+type OKXClient struct {
+	baseURL   string
+	client    *http.Client
+	rateLimit *config.RateLimitConfig
+	resampling
+}
+
+// okxResponse OKX API 通用响应结构
+type okxResponse struct {
+	Code string            `json:"code"`
+	Msg  string            `json:"msg"`
+	Data []json.RawMessage `json:"data"`
+}
+
+// okxAPIError OKX 业务错误
+type okxAPIError struct {
+	Code string
+	Msg  string
+}
+
+func (e *okxAPIError) Error() string {
+	return fmt.Sprintf("okx API error %s: %s", e.Code, e.Msg)
+}
+
+// Unwrap 将常见错误码映射为数据源错误类型
+func (e *okxAPIError) Unwrap() error {
+	switch e.Code {
+	case "51001": // Instrument ID does not exist
+		return ErrSymbolNotFound
+	case "50011", "50061": // 请求过于频繁
+		return ErrRateLimited
+	case "50001", "50013": // 服务暂时不可用 / 系统繁忙
+		return ErrUnavailable
+	default:
+		return nil
+	}
+}
+
+// NewOKXClient 创建OKX客户端（建议使用NewOKXClientWithConfig）
+func NewOKXClient() *OKXClient {
+	return NewOKXClientWithConfig(nil)
+}
+
+// NewOKXClientWithConfig 使用配置创建OKX客户端
+func NewOKXClientWithConfig(cfg *config.OKXConfig) *OKXClient {
+	log.Printf("🔗 初始化 OKX 数据源")
+	client := &OKXClient{
+		baseURL: "https://www.okx.com",
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+
+	if cfg != nil {
+		client.rateLimit = &cfg.RateLimit
+		fmt.Printf("🔧 [OKX] 使用配置限流: 每分钟%d请求, 延迟%v, 重试%d次\n",
+			cfg.RateLimit.RequestsPerMinute, cfg.RateLimit.RetryDelay, cfg.RateLimit.MaxRetries)
+	} else {
+		// 默认配置（history-candles 限制为 20次/2秒）
+		client.rateLimit = &config.RateLimitConfig{
+			RequestsPerMinute: 300,
+			RetryDelay:        2 * time.Second,
+			MaxRetries:        3,
+		}
+		fmt.Printf("⚠️  [OKX] 使用默认限流配置: 每分钟%d请求, 延迟%v, 重试%d次\n",
+			300, 2*time.Second, 3)
+	}
+
+	return client
+}
+
+// Name 返回数据源名称
+func (o *OKXClient) Name() string {
+	return "okx"
+}
+
+// IsSymbolValid 检查交易对是否有效
+func (o *OKXClient) IsSymbolValid(ctx context.Context, symbol string) (bool, error) {
+	url := fmt.Sprintf("%s/api/v5/public/instruments", o.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return false, err
+	}
+
+	q := req.URL.Query()
+	q.Add("instType", "SPOT")
+	q.Add("instId", o.convertToOKXSymbol(symbol))
+	req.URL.RawQuery = q.Encode()
+
+	data, err := o.doRequest(req)
+	if err != nil {
+		// OKX 对不存在的交易对返回业务错误码 51001，限流和系统繁忙等错误码继续上抛
+		if errors.Is(err, ErrSymbolNotFound) {
+			log.Printf("❌ [OKX] %s 不存在: %v", symbol, err)
+			return false, nil
+		}
+		return false, err
+	}
+
+	return len(data) > 0, nil
+}
+
+// GetKlines 获取K线数据（按时间倒序分页）
+func (o *OKXClient) GetKlines(ctx context.Context, symbol string, timeframe Timeframe, startTime, endTime time.Time, limit int) ([]*Kline, error) {
+	if limit <= 0 {
+		limit = 300
+	}
+
+	bar := o.convertTimeframeToBar(timeframe)
+	if bar == "" {
+		// OKX 没有该周期（如8h），由更小的原生周期重采样
+		return getResampledKlines(ctx, o.GetKlines, o.supportsTimeframe, o.resampleOptions(), symbol, timeframe, startTime, endTime, limit)
+	}
+
+	if endTime.IsZero() {
+		endTime = time.Now()
+	}
+	if startTime.IsZero() {
+		startTime = endTime.Add(-time.Duration(limit) * timeframe.Duration())
+	}
+
+	instID := o.convertToOKXSymbol(symbol)
+	seen := make(map[int64]bool)
+	var allKlines []*Kline
+
+	// after 参数返回早于该时间戳的数据
+	after := endTime.UnixMilli() + 1
+	for len(allKlines) < limit {
+		rows, err := o.fetchCandlesPage(ctx, instID, bar, after)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch page: %w", err)
+		}
+		if len(rows) == 0 {
+			break
+		}
+
+		oldest := after
+		for i, raw := range rows {
+			kline, err := o.parseCandle(symbol, timeframe, raw)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse candle %d: %w", i, err)
+			}
+			ts := kline.OpenTime.UnixMilli()
+			if ts < oldest {
+				oldest = ts
+			}
+			if seen[ts] || kline.OpenTime.Before(startTime) || kline.OpenTime.After(endTime) {
+				continue
+			}
+			seen[ts] = true
+			allKlines = append(allKlines, kline)
+		}
+
+		// 已越过开始时间或没有更早的数据
+		if oldest >= after || oldest <= startTime.UnixMilli() {
+			break
+		}
+		after = oldest
+	}
+
+	sortKlinesByTime(allKlines)
+
+	if len(allKlines) > limit {
+		allKlines = allKlines[len(allKlines)-limit:]
+	}
+	markClosed(allKlines, time.Now())
+
+	return allKlines, nil
+}
+
+// fetchCandlesPage 获取单页K线数据
+func (o *OKXClient) fetchCandlesPage(ctx context.Context, instID, bar string, after int64) ([][]string, error) {
+	url := fmt.Sprintf("%s/api/v5/market/history-candles", o.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	q := req.URL.Query()
+	q.Add("instId", instID)
+	q.Add("bar", bar)
+	q.Add("after", strconv.FormatInt(after, 10))
+	q.Add("limit", strconv.Itoa(okxPageSize))
+	req.URL.RawQuery = q.Encode()
+
+	data, err := o.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([][]string, 0, len(data))
+	for _, item := range data {
+		var row []string
+		if err := json.Unmarshal(item, &row); err != nil {
+			return nil, fmt.Errorf("invalid candle row: %w", err)
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// doRequest 执行请求并解析OKX通用响应
+func (o *OKXClient) doRequest(req *http.Request) ([]json.RawMessage, error) {
+	resp, err := o.executeWithRateLimit(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("okx", resp)
+	}
+
+	var body okxResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	if body.Code != "0" {
+		return nil, &okxAPIError{Code: body.Code, Msg: body.Msg}
+	}
+
+	return body.Data, nil
+}
+
+// parseCandle 解析OKX蜡烛图数据
+// OKX格式: [ts, open, high, low, close, vol, volCcy, volCcyQuote, confirm]
+func (o *OKXClient) parseCandle(symbol string, timeframe Timeframe, raw []string) (*Kline, error) {
+	if len(raw) < 6 {
+		return nil, fmt.Errorf("invalid candle data length: %d", len(raw))
+	}
+
+	values := make([]float64, 6)
+	for i := 0; i < 6; i++ {
+		v, err := strconv.ParseFloat(raw[i], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid candle field %d: %w", i, err)
+		}
+		values[i] = v
+	}
+
+	openTime := time.UnixMilli(int64(values[0]))
+
+	return &Kline{
+		Symbol:    symbol,
+		OpenTime:  openTime,
+		CloseTime: closeTimeFor(openTime, timeframe),
+		Open:      values[1],
+		High:      values[2],
+		Low:       values[3],
+		Close:     values[4],
+		Volume:    values[5],
+	}, nil
+}
+
+// convertToOKXSymbol 转换为OKX交易对格式
+// BTCUSDT -> BTC-USDT
+func (o *OKXClient) convertToOKXSymbol(symbol string) string {
+	return nativeSymbol(instrument.OKX, symbol)
+}
+
+// supportsTimeframe 判断OKX是否原生支持该时间框架
+func (o *OKXClient) supportsTimeframe(tf Timeframe) bool {
+	return o.convertTimeframeToBar(tf) != ""
+}
+
+// convertTimeframeToBar 转换时间框架为OKX bar参数（6小时以上使用UTC对齐）
+func (o *OKXClient) convertTimeframeToBar(tf Timeframe) string {
+	switch tf {
+	case Timeframe1m:
+		return "1m"
+	case Timeframe3m:
+		return "3m"
+	case Timeframe5m:
+		return "5m"
+	case Timeframe15m:
+		return "15m"
+	case Timeframe30m:
+		return "30m"
+	case Timeframe1h:
+		return "1H"
+	case Timeframe2h:
+		return "2H"
+	case Timeframe4h:
+		return "4H"
+	case Timeframe6h:
+		return "6Hutc"
+	case Timeframe12h:
+		return "12Hutc"
+	case Timeframe1d:
+		return "1Dutc"
+	case Timeframe3d:
+		return "3Dutc"
+	case Timeframe1w:
+		return "1Wutc"
+	case Timeframe1M:
+		return "1Mutc"
+	default:
+		return "" // 不支持的时间框架
+	}
+}
+
+// okxBookMaxDepth /api/v5/market/books 单次最多返回的档位数
+const okxBookMaxDepth = 400
+
+// GetOrderBook 获取订单簿快照
+func (o *OKXClient) GetOrderBook(ctx context.Context, symbol string, depth int) (*OrderBook, error) {
+	size := depth
+	if size <= 0 || size > okxBookMaxDepth {
+		size = okxBookMaxDepth
+	}
+
+	url := fmt.Sprintf("%s/api/v5/market/books", o.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	q := req.URL.Query()
+	q.Add("instId", o.convertToOKXSymbol(symbol))
+	q.Add("sz", strconv.Itoa(size))
+	req.URL.RawQuery = q.Encode()
+
+	data, err := o.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: empty order book for %s", ErrSymbolNotFound, symbol)
+	}
+
+	// 格式: [price, size, 0, orders]
+	var book struct {
+		Bids [][]json.RawMessage `json:"bids"`
+		Asks [][]json.RawMessage `json:"asks"`
+		Ts   string              `json:"ts"`
+	}
+	if err := json.Unmarshal(data[0], &book); err != nil {
+		return nil, fmt.Errorf("invalid order book: %w", err)
+	}
+
+	bids, err := parseBookLevels(book.Bids)
+	if err != nil {
+		return nil, err
+	}
+	asks, err := parseBookLevels(book.Asks)
+	if err != nil {
+		return nil, err
+	}
+
+	t := time.Now()
+	if ts, err := strconv.ParseInt(book.Ts, 10, 64); err == nil {
+		t = time.UnixMilli(ts)
+	}
+	return NewOrderBook(symbol, t, bids, asks, depth), nil
+}
+
+// okxTradesMaxLimit /api/v5/market/trades 单次最大返回数量
+const okxTradesMaxLimit = 500
+
+// GetRecentTrades 获取最近的成交
+func (o *OKXClient) GetRecentTrades(ctx context.Context, symbol string, limit int) ([]*Trade, error) {
+	if limit <= 0 || limit > okxTradesMaxLimit {
+		limit = okxTradesMaxLimit
+	}
+
+	url := fmt.Sprintf("%s/api/v5/market/trades", o.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	q := req.URL.Query()
+	q.Add("instId", o.convertToOKXSymbol(symbol))
+	q.Add("limit", strconv.Itoa(limit))
+	req.URL.RawQuery = q.Encode()
+
+	data, err := o.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// side 为吃单方方向
+	trades := make([]*Trade, 0, len(data))
+	for _, raw := range data {
+		var row struct {
+			TradeID string `json:"tradeId"`
+			Px      string `json:"px"`
+			Sz      string `json:"sz"`
+			Side    string `json:"side"`
+			Ts      string `json:"ts"`
+		}
+		if err := json.Unmarshal(raw, &row); err != nil {
+			return nil, fmt.Errorf("invalid trade: %w", err)
+		}
+		price, err := strconv.ParseFloat(row.Px, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trade price: %w", err)
+		}
+		size, err := strconv.ParseFloat(row.Sz, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trade size: %w", err)
+		}
+		ts, err := strconv.ParseInt(row.Ts, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trade time: %w", err)
+		}
+		trades = append(trades, &Trade{
+			Symbol:     symbol,
+			ID:         row.TradeID,
+			Time:       time.UnixMilli(ts),
+			Price:      price,
+			Quantity:   size,
+			BuyerMaker: row.Side == "sell",
+		})
+	}
+
+	sortTradesByTime(trades)
+	return trades, nil
+}
+
+// executeWithRateLimit 执行带限流的HTTP请求
+func (o *OKXClient) executeWithRateLimit(req *http.Request) (*http.Response, error) {
+	return executeWithRetry(o.client, req, o.rateLimit, func(resp *http.Response) bool {
+		return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	})
+}
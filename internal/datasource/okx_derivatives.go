@@ -0,0 +1,215 @@
+package datasource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"ta-watcher/internal/instrument"
+)
+
+// okxStatsPageSize OKX 资金费率/持仓量接口单次最大返回数量
+const okxStatsPageSize = 100
+
+// okxOpenInterestPeriods 持仓量历史接口支持的周期
+var okxOpenInterestPeriods = []Timeframe{
+	Timeframe5m, Timeframe15m, Timeframe30m, Timeframe1h, Timeframe2h,
+	Timeframe4h, Timeframe6h, Timeframe12h, Timeframe1d, Timeframe1w,
+}
+
+// okxLongShortPeriods 多空账户比接口支持的周期
+var okxLongShortPeriods = []Timeframe{Timeframe5m, Timeframe1h, Timeframe1d}
+
+// GetFundingRates 获取永续合约资金费率历史
+func (o *OKXClient) GetFundingRates(ctx context.Context, symbol string, startTime, endTime time.Time, limit int) ([]*FundingRate, error) {
+	if limit <= 0 || limit > okxStatsPageSize {
+		limit = okxStatsPageSize
+	}
+
+	url := fmt.Sprintf("%s/api/v5/public/funding-rate-history", o.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	q := req.URL.Query()
+	q.Add("instId", o.convertToOKXSwap(symbol))
+	q.Add("limit", strconv.Itoa(limit))
+	if !endTime.IsZero() {
+		// after 参数返回早于该时间戳的数据
+		q.Add("after", strconv.FormatInt(endTime.UnixMilli()+1, 10))
+	}
+	req.URL.RawQuery = q.Encode()
+
+	data, err := o.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	rates := make([]*FundingRate, 0, len(data))
+	for _, item := range data {
+		var row struct {
+			FundingRate string `json:"fundingRate"`
+			FundingTime string `json:"fundingTime"`
+		}
+		if err := json.Unmarshal(item, &row); err != nil {
+			return nil, fmt.Errorf("invalid funding rate row: %w", err)
+		}
+		rate, err1 := strconv.ParseFloat(row.FundingRate, 64)
+		ts, err2 := strconv.ParseInt(row.FundingTime, 10, 64)
+		if err1 != nil || err2 != nil {
+			continue // 跳过无法解析的数据
+		}
+		t := time.UnixMilli(ts)
+		if t.Before(startTime) {
+			continue
+		}
+		rates = append(rates, &FundingRate{Symbol: symbol, Time: t, Rate: rate})
+	}
+
+	// OKX 按时间倒序返回
+	sort.Slice(rates, func(i, j int) bool { return rates[i].Time.Before(rates[j].Time) })
+	return rates, nil
+}
+
+// GetOpenInterest 获取永续合约持仓量历史
+func (o *OKXClient) GetOpenInterest(ctx context.Context, symbol string, period Timeframe, startTime, endTime time.Time, limit int) ([]*OpenInterest, error) {
+	if limit <= 0 || limit > okxStatsPageSize {
+		limit = okxStatsPageSize
+	}
+
+	params := map[string]string{
+		"instId": o.convertToOKXSwap(symbol),
+		"period": o.convertTimeframeToBar(statPeriod(period, okxOpenInterestPeriods)),
+		"limit":  strconv.Itoa(limit),
+	}
+	// 格式: [ts, oi(合约张数), oiCcy(币), oiUsd]
+	rows, err := o.fetchStatRows(ctx, "/api/v5/rubik/stat/contracts/open-interest-history", params, startTime, endTime, 4)
+	if err != nil {
+		return nil, err
+	}
+
+	series := make([]*OpenInterest, 0, len(rows))
+	for _, row := range rows {
+		series = append(series, &OpenInterest{
+			Symbol:    symbol,
+			Time:      time.UnixMilli(int64(row[0])),
+			Contracts: row[2],
+			Value:     row[3],
+		})
+	}
+
+	sort.Slice(series, func(i, j int) bool { return series[i].Time.Before(series[j].Time) })
+	return series, nil
+}
+
+// GetLongShortRatio 获取多空账户比（按币种统计所有合约）
+func (o *OKXClient) GetLongShortRatio(ctx context.Context, symbol string, period Timeframe, startTime, endTime time.Time, limit int) ([]*LongShortRatio, error) {
+	base, _ := instrument.Split(symbol)
+	params := map[string]string{
+		"ccy":    base,
+		"period": o.convertLongShortPeriod(statPeriod(period, okxLongShortPeriods)),
+	}
+	// 格式: [ts, longShortRatio]
+	rows, err := o.fetchStatRows(ctx, "/api/v5/rubik/stat/contracts/long-short-account-ratio", params, startTime, endTime, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	series := make([]*LongShortRatio, 0, len(rows))
+	for _, row := range rows {
+		ratio := row[1]
+		series = append(series, &LongShortRatio{
+			Symbol:       symbol,
+			Time:         time.UnixMilli(int64(row[0])),
+			Ratio:        ratio,
+			LongAccount:  ratio / (1 + ratio),
+			ShortAccount: 1 / (1 + ratio),
+		})
+	}
+
+	sort.Slice(series, func(i, j int) bool { return series[i].Time.Before(series[j].Time) })
+	if limit > 0 && len(series) > limit {
+		series = series[len(series)-limit:]
+	}
+	return series, nil
+}
+
+// fetchStatRows 请求 rubik 统计接口，解析为数值行（至少 fields 列）
+func (o *OKXClient) fetchStatRows(ctx context.Context, path string, params map[string]string, startTime, endTime time.Time, fields int) ([][]float64, error) {
+	url := fmt.Sprintf("%s%s", o.baseURL, path)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	q := req.URL.Query()
+	for k, v := range params {
+		q.Add(k, v)
+	}
+	if !startTime.IsZero() {
+		q.Add("begin", strconv.FormatInt(startTime.UnixMilli(), 10))
+	}
+	if !endTime.IsZero() {
+		q.Add("end", strconv.FormatInt(endTime.UnixMilli(), 10))
+	}
+	req.URL.RawQuery = q.Encode()
+
+	data, err := o.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([][]float64, 0, len(data))
+	for _, item := range data {
+		var raw []string
+		if err := json.Unmarshal(item, &raw); err != nil {
+			return nil, fmt.Errorf("invalid stat row: %w", err)
+		}
+		if len(raw) < fields {
+			continue
+		}
+		row := make([]float64, fields)
+		valid := true
+		for i := 0; i < fields; i++ {
+			v, err := strconv.ParseFloat(raw[i], 64)
+			if err != nil {
+				valid = false
+				break
+			}
+			row[i] = v
+		}
+		if valid {
+			rows = append(rows, row)
+		}
+	}
+
+	return rows, nil
+}
+
+// convertToOKXSwap 转换为OKX永续合约ID
+// BTCUSDT -> BTC-USDT-SWAP, BTCUSD -> BTC-USD-SWAP（币本位）
+// 永续合约同时有 USDT 本位和币本位，不做现货的报价货币映射
+func (o *OKXClient) convertToOKXSwap(symbol string) string {
+	inst, err := instrument.Parse(symbol)
+	if err != nil {
+		return symbol + "-SWAP"
+	}
+	return inst.Base + "-" + inst.Quote + "-SWAP"
+}
+
+// convertLongShortPeriod 转换多空比统计周期（该接口不使用 UTC 后缀）
+func (o *OKXClient) convertLongShortPeriod(tf Timeframe) string {
+	switch tf {
+	case Timeframe1h:
+		return "1H"
+	case Timeframe1d:
+		return "1D"
+	default:
+		return "5m"
+	}
+}
@@ -0,0 +1,161 @@
+package datasource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// newOKXTestServer 模拟OKX公共接口，提供count根小时K线
+func newOKXTestServer(t *testing.T, base time.Time, count int) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/api/v5/public/instruments":
+			switch q.Get("instId") {
+			case "BTC-USDT":
+				fmt.Fprint(w, `{"code":"0","msg":"","data":[{"instId":"BTC-USDT"}]}`)
+				return
+			case "ETH-USDT":
+				fmt.Fprint(w, `{"code":"50011","msg":"Too Many Requests","data":[]}`)
+				return
+			}
+			fmt.Fprint(w, `{"code":"51001","msg":"Instrument ID does not exist","data":[]}`)
+		case "/api/v5/market/history-candles":
+			if q.Get("bar") != "1H" {
+				t.Errorf("unexpected bar: %s", q.Get("bar"))
+			}
+			after, _ := strconv.ParseInt(q.Get("after"), 10, 64)
+			limit, _ := strconv.Atoi(q.Get("limit"))
+
+			var data [][]string
+			for i := count - 1; i >= 0 && len(data) < limit; i-- {
+				ts := base.Add(time.Duration(i) * time.Hour).UnixMilli()
+				if ts >= after {
+					continue
+				}
+				price := strconv.Itoa(100 + i)
+				data = append(data, []string{strconv.FormatInt(ts, 10), price, price, price, price, "1", "1", "1", "1"})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": "0", "msg": "", "data": data})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestOKXClient_New(t *testing.T) {
+	client := NewOKXClient()
+
+	if client.Name() != "okx" {
+		t.Errorf("Expected name 'okx', got '%s'", client.Name())
+	}
+}
+
+func TestOKXClient_GetKlinesPagination(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	server := newOKXTestServer(t, base, 250)
+	defer server.Close()
+
+	client := NewOKXClient()
+	client.baseURL = server.URL
+
+	endTime := base.Add(249 * time.Hour)
+	klines, err := client.GetKlines(context.Background(), "BTCUSDT", Timeframe1h, base, endTime, 250)
+	if err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+
+	if len(klines) != 250 {
+		t.Fatalf("expected 250 klines across pages, got %d", len(klines))
+	}
+	for i := 1; i < len(klines); i++ {
+		if klines[i].OpenTime.Sub(klines[i-1].OpenTime) != time.Hour {
+			t.Fatalf("klines not contiguous at %d", i)
+		}
+	}
+	if klines[0].Symbol != "BTCUSDT" {
+		t.Errorf("Symbol = %s, expected BTCUSDT", klines[0].Symbol)
+	}
+	if !klines[0].CloseTime.Equal(base.Add(time.Hour - time.Millisecond)) {
+		t.Errorf("unexpected close time: %v", klines[0].CloseTime)
+	}
+
+	// limit 小于可用数量时返回最新的数据
+	latest, err := client.GetKlines(context.Background(), "BTCUSDT", Timeframe1h, time.Time{}, endTime, 10)
+	if err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if len(latest) != 10 || latest[9].Close != 349 {
+		t.Errorf("unexpected latest klines: len=%d", len(latest))
+	}
+}
+
+func TestOKXClient_IsSymbolValid(t *testing.T) {
+	server := newOKXTestServer(t, time.Now(), 0)
+	defer server.Close()
+
+	client := NewOKXClient()
+	client.baseURL = server.URL
+
+	valid, err := client.IsSymbolValid(context.Background(), "BTCUSDT")
+	if err != nil || !valid {
+		t.Errorf("IsSymbolValid(BTCUSDT) = %v, %v", valid, err)
+	}
+
+	valid, err = client.IsSymbolValid(context.Background(), "FOOUSDT")
+	if err != nil || valid {
+		t.Errorf("IsSymbolValid(FOOUSDT) = %v, %v", valid, err)
+	}
+
+	// 限流不能被当作交易对不存在
+	valid, err = client.IsSymbolValid(context.Background(), "ETHUSDT")
+	if !errors.Is(err, ErrRateLimited) || valid {
+		t.Errorf("IsSymbolValid(ETHUSDT) = %v, %v, want ErrRateLimited", valid, err)
+	}
+}
+
+func TestOKXClient_GetKlinesInvalidCandle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"code":"0","msg":"","data":[["1735689600000","100","bad","99","100","1","1","1","1"]]}`)
+	}))
+	defer server.Close()
+
+	client := NewOKXClient()
+	client.baseURL = server.URL
+
+	end := time.Date(2025, 1, 1, 1, 0, 0, 0, time.UTC)
+	if _, err := client.GetKlines(context.Background(), "BTCUSDT", Timeframe1h, end.Add(-time.Hour), end, 1); err == nil {
+		t.Error("expected parse error for invalid candle")
+	}
+}
+
+func TestOKXClient_Conversions(t *testing.T) {
+	client := NewOKXClient()
+
+	symbols := map[string]string{
+		"BTCUSDT": "BTC-USDT",
+		"ETHBTC":  "ETH-BTC",
+		"SOLUSD":  "SOL-USDT", // OKX 现货以 USDT 计价
+	}
+	for in, want := range symbols {
+		if got := client.convertToOKXSymbol(in); got != want {
+			t.Errorf("convertToOKXSymbol(%s) = %s, expected %s", in, got, want)
+		}
+	}
+
+	if client.convertTimeframeToBar(Timeframe1d) != "1Dutc" {
+		t.Error("1d should map to UTC aligned bar")
+	}
+	if client.convertTimeframeToBar(Timeframe8h) != "" {
+		t.Error("8h is not a native OKX bar")
+	}
+}
@@ -0,0 +1,215 @@
+package datasource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// PriceLevel 订单簿价位
+type PriceLevel struct {
+	Price      float64 `json:"price"`
+	Quantity   float64 `json:"quantity"`
+	Cumulative float64 `json:"cumulative"` // 从最优价到该价位的累计数量
+}
+
+// OrderBook 订单簿快照，买盘按价格降序、卖盘按价格升序
+type OrderBook struct {
+	Symbol string       `json:"symbol"`
+	Time   time.Time    `json:"time"`
+	Bids   []PriceLevel `json:"bids"`
+	Asks   []PriceLevel `json:"asks"`
+}
+
+// OrderBookProvider 可获取订单簿快照的数据源
+type OrderBookProvider interface {
+	// GetOrderBook 获取订单簿快照，depth 为每侧最多返回的价位数
+	GetOrderBook(ctx context.Context, symbol string, depth int) (*OrderBook, error)
+}
+
+// AsOrderBookProvider 查找数据源（或其包装的数据源）中支持订单簿的实现
+func AsOrderBookProvider(ds DataSource) (OrderBookProvider, bool) {
+	if provider, ok := ds.(OrderBookProvider); ok {
+		return provider, true
+	}
+	if wrapper, ok := ds.(Unwrapper); ok {
+		for _, inner := range wrapper.Unwrap() {
+			if provider, ok := AsOrderBookProvider(inner); ok {
+				return provider, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// NewOrderBook 整理交易所返回的价位：排序、去掉零数量价位、截取 depth 档并计算累计数量
+func NewOrderBook(symbol string, t time.Time, bids, asks []PriceLevel, depth int) *OrderBook {
+	return &OrderBook{
+		Symbol: symbol,
+		Time:   t,
+		Bids:   normalizeLevels(bids, depth, true),
+		Asks:   normalizeLevels(asks, depth, false),
+	}
+}
+
+// normalizeLevels 排序并截取价位，descending 为 true 时按价格降序
+func normalizeLevels(levels []PriceLevel, depth int, descending bool) []PriceLevel {
+	result := make([]PriceLevel, 0, len(levels))
+	for _, l := range levels {
+		if l.Price > 0 && l.Quantity > 0 {
+			result = append(result, l)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if descending {
+			return result[i].Price > result[j].Price
+		}
+		return result[i].Price < result[j].Price
+	})
+	if depth > 0 && len(result) > depth {
+		result = result[:depth]
+	}
+
+	cumulative := 0.0
+	for i := range result {
+		cumulative += result[i].Quantity
+		result[i].Cumulative = cumulative
+	}
+	return result
+}
+
+// BestBid 返回最高买价，没有买盘时返回0
+func (b *OrderBook) BestBid() float64 {
+	if len(b.Bids) == 0 {
+		return 0
+	}
+	return b.Bids[0].Price
+}
+
+// BestAsk 返回最低卖价，没有卖盘时返回0
+func (b *OrderBook) BestAsk() float64 {
+	if len(b.Asks) == 0 {
+		return 0
+	}
+	return b.Asks[0].Price
+}
+
+// MidPrice 返回买一卖一的中间价，任一侧为空时返回0
+func (b *OrderBook) MidPrice() float64 {
+	if len(b.Bids) == 0 || len(b.Asks) == 0 {
+		return 0
+	}
+	return (b.BestBid() + b.BestAsk()) / 2
+}
+
+// Spread 返回买卖价差
+func (b *OrderBook) Spread() float64 {
+	if len(b.Bids) == 0 || len(b.Asks) == 0 {
+		return 0
+	}
+	return b.BestAsk() - b.BestBid()
+}
+
+// SpreadBps 返回相对中间价的价差（基点）
+func (b *OrderBook) SpreadBps() float64 {
+	mid := b.MidPrice()
+	if mid == 0 {
+		return 0
+	}
+	return b.Spread() / mid * 10000
+}
+
+// DepthWithin 返回中间价 ±percent% 范围内买卖双方的挂单金额（以报价货币计）
+func (b *OrderBook) DepthWithin(percent float64) (bidDepth, askDepth float64) {
+	mid := b.MidPrice()
+	if mid == 0 {
+		return 0, 0
+	}
+	lower := mid * (1 - percent/100)
+	upper := mid * (1 + percent/100)
+
+	for _, l := range b.Bids {
+		if l.Price < lower {
+			break
+		}
+		bidDepth += l.Price * l.Quantity
+	}
+	for _, l := range b.Asks {
+		if l.Price > upper {
+			break
+		}
+		askDepth += l.Price * l.Quantity
+	}
+	return bidDepth, askDepth
+}
+
+// Imbalance 返回中间价 ±percent% 范围内的深度失衡度，范围 [-1, 1]
+// 正值表示买盘更厚，负值表示卖盘更厚
+func (b *OrderBook) Imbalance(percent float64) float64 {
+	bid, ask := b.DepthWithin(percent)
+	if bid+ask == 0 {
+		return 0
+	}
+	return (bid - ask) / (bid + ask)
+}
+
+// OrderBookSummary 订单簿流动性摘要
+type OrderBookSummary struct {
+	MidPrice     float64 // 中间价
+	Spread       float64 // 买卖价差
+	SpreadBps    float64 // 价差（基点）
+	RangePercent float64 // 统计深度的价格范围（±%）
+	BidDepth     float64 // 范围内买盘金额
+	AskDepth     float64 // 范围内卖盘金额
+	Imbalance    float64 // 深度失衡度 [-1, 1]
+}
+
+// Summary 汇总价差和中间价 ±percent% 范围内的深度
+func (b *OrderBook) Summary(percent float64) OrderBookSummary {
+	bid, ask := b.DepthWithin(percent)
+	return OrderBookSummary{
+		MidPrice:     b.MidPrice(),
+		Spread:       b.Spread(),
+		SpreadBps:    b.SpreadBps(),
+		RangePercent: percent,
+		BidDepth:     bid,
+		AskDepth:     ask,
+		Imbalance:    b.Imbalance(percent),
+	}
+}
+
+// parseBookLevels 解析交易所返回的价位数组，每项前两列为价格和数量（字符串或数字）
+func parseBookLevels(rows [][]json.RawMessage) ([]PriceLevel, error) {
+	levels := make([]PriceLevel, 0, len(rows))
+	for _, row := range rows {
+		if len(row) < 2 {
+			return nil, fmt.Errorf("invalid order book level length: %d", len(row))
+		}
+		price, err := parseJSONNumber(row[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid order book price: %w", err)
+		}
+		quantity, err := parseJSONNumber(row[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid order book quantity: %w", err)
+		}
+		levels = append(levels, PriceLevel{Price: price, Quantity: quantity})
+	}
+	return levels, nil
+}
+
+// parseJSONNumber 解析 JSON 字符串或数字形式的数值
+func parseJSONNumber(raw json.RawMessage) (float64, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return strconv.ParseFloat(s, 64)
+	}
+	var f float64
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return 0, err
+	}
+	return f, nil
+}
@@ -0,0 +1,127 @@
+package datasource
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewOrderBook(t *testing.T) {
+	bids := []PriceLevel{{Price: 99, Quantity: 2}, {Price: 100, Quantity: 1}, {Price: 98, Quantity: 0}, {Price: 97, Quantity: 4}}
+	asks := []PriceLevel{{Price: 103, Quantity: 5}, {Price: 101, Quantity: 1}, {Price: 102, Quantity: 2}}
+	book := NewOrderBook("BTCUSDT", time.Now(), bids, asks, 2)
+
+	if len(book.Bids) != 2 || book.Bids[0].Price != 100 || book.Bids[1].Price != 99 {
+		t.Fatalf("bids should be sorted descending and trimmed, got %+v", book.Bids)
+	}
+	if len(book.Asks) != 2 || book.Asks[0].Price != 101 || book.Asks[1].Price != 102 {
+		t.Fatalf("asks should be sorted ascending and trimmed, got %+v", book.Asks)
+	}
+	if book.Bids[1].Cumulative != 3 || book.Asks[1].Cumulative != 3 {
+		t.Errorf("unexpected cumulative depth: bids %+v asks %+v", book.Bids, book.Asks)
+	}
+	if book.BestBid() != 100 || book.BestAsk() != 101 || book.MidPrice() != 100.5 || book.Spread() != 1 {
+		t.Errorf("unexpected top of book: bid %v ask %v mid %v spread %v", book.BestBid(), book.BestAsk(), book.MidPrice(), book.Spread())
+	}
+	if bps := book.SpreadBps(); math.Abs(bps-99.5025) > 0.001 {
+		t.Errorf("SpreadBps() = %v", bps)
+	}
+
+	empty := NewOrderBook("BTCUSDT", time.Now(), nil, asks, 0)
+	if empty.MidPrice() != 0 || empty.SpreadBps() != 0 || empty.Imbalance(1) != 0 {
+		t.Error("one-sided book should report zero mid, spread and imbalance")
+	}
+}
+
+func TestOrderBook_DepthImbalance(t *testing.T) {
+	bids := []PriceLevel{{Price: 100, Quantity: 3}, {Price: 99.5, Quantity: 2}, {Price: 95, Quantity: 100}}
+	asks := []PriceLevel{{Price: 101, Quantity: 1}, {Price: 106, Quantity: 100}}
+	book := NewOrderBook("BTCUSDT", time.Now(), bids, asks, 0)
+
+	// 中间价 100.5，±1% 范围为 [99.495, 101.505]，远端挂单不计入
+	bid, ask := book.DepthWithin(1)
+	if bid != 100*3+99.5*2 || ask != 101 {
+		t.Fatalf("DepthWithin(1) = %v, %v", bid, ask)
+	}
+
+	want := (499.0 - 101.0) / (499.0 + 101.0)
+	if got := book.Imbalance(1); math.Abs(got-want) > 1e-9 {
+		t.Errorf("Imbalance(1) = %v, want %v", got, want)
+	}
+
+	summary := book.Summary(1)
+	if summary.BidDepth != 499 || summary.AskDepth != 101 || summary.RangePercent != 1 || summary.MidPrice != 100.5 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+
+	// 范围扩大到 ±10% 后远端卖单占优
+	if got := book.Imbalance(10); got >= 0 {
+		t.Errorf("Imbalance(10) = %v, want negative", got)
+	}
+}
+
+func TestGetOrderBook(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/api/v3/depth":
+			if q.Get("symbol") != "BTCUSDT" || q.Get("limit") != "5" {
+				t.Errorf("unexpected binance query: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"lastUpdateId":1,"bids":[["100.00","1.5"],["99.00","2"]],"asks":[["101.00","1"],["102.00","3"]]}`))
+		case "/products/BTC-USD/book":
+			if q.Get("level") != "2" {
+				t.Errorf("unexpected coinbase query: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"bids":[["100.00","1.5",3],["99.00","2",1]],"asks":[["101.00","1",2],["102.00","3",1]],"sequence":1,"time":"2025-10-16T00:00:00Z"}`))
+		case "/api/v5/market/books":
+			if q.Get("instId") != "BTC-USDT" || q.Get("sz") != "5" {
+				t.Errorf("unexpected okx query: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"code":"0","msg":"","data":[{"asks":[["101.00","1","0","2"],["102.00","3","0","1"]],"bids":[["100.00","1.5","0","3"],["99.00","2","0","1"]],"ts":"1760572800000"}]}`))
+		case "/0/public/Depth":
+			if q.Get("pair") != "XBTUSDT" || q.Get("count") != "5" {
+				t.Errorf("unexpected kraken query: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"error":[],"result":{"XBTUSDT":{"asks":[["101.00","1",1760572800],["102.00","3",1760572800]],"bids":[["100.00","1.5",1760572800],["99.00","2",1760572800]]}}}`))
+		case "/v5/market/orderbook":
+			if q.Get("category") != "spot" || q.Get("symbol") != "BTCUSDT" || q.Get("limit") != "5" {
+				t.Errorf("unexpected bybit query: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"retCode":0,"retMsg":"OK","result":{"s":"BTCUSDT","b":[["100.00","1.5"],["99.00","2"]],"a":[["101.00","1"],["102.00","3"]],"ts":1760572800000,"u":1}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	// 每个交易所使用独立的服务器，避免共享主机限流
+	tests := []struct {
+		name   string
+		symbol string
+		client func(baseURL string) OrderBookProvider
+	}{
+		{"binance", "BTCUSDT", func(u string) OrderBookProvider { c := NewBinanceClient(); c.baseURL = u; return c }},
+		{"coinbase", "BTCUSD", func(u string) OrderBookProvider { c := NewCoinbaseClient(); c.baseURL = u; return c }},
+		{"okx", "BTCUSDT", func(u string) OrderBookProvider { c := NewOKXClient(); c.baseURL = u; return c }},
+		{"kraken", "BTCUSDT", func(u string) OrderBookProvider { c := NewKrakenClient(); c.baseURL = u; return c }},
+		{"bybit", "BTCUSDT", func(u string) OrderBookProvider { c := NewBybitClient(); c.baseURL = u; return c }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			book, err := tt.client(server.URL).GetOrderBook(context.Background(), tt.symbol, 5)
+			if err != nil {
+				t.Fatalf("GetOrderBook() error = %v", err)
+			}
+			if book.BestBid() != 100 || book.BestAsk() != 101 || len(book.Bids) != 2 || book.Asks[1].Cumulative != 4 {
+				t.Errorf("unexpected order book %+v", book)
+			}
+		})
+	}
+}
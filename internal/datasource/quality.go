@@ -0,0 +1,209 @@
+package datasource
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// IssueType K线数据质量问题类型
+type IssueType string
+
+const (
+	IssueGap        IssueType = "gap"         // 缺失的K线周期
+	IssueDuplicate  IssueType = "duplicate"   // 重复的开盘时间
+	IssueZeroVolume IssueType = "zero_volume" // 成交量为0
+	IssueInvalid    IssueType = "invalid"     // 价格为 NaN/Inf/非正数或 OHLC 不一致
+)
+
+// QualityIssue 一处数据质量问题
+type QualityIssue struct {
+	Type  IssueType
+	Time  time.Time // 问题所在K线（或缺口起始）的开盘时间
+	Count int       // 涉及的K线数量（缺口为缺失的周期数）
+}
+
+// String 返回问题描述
+func (i QualityIssue) String() string {
+	return fmt.Sprintf("%s x%d @ %s", i.Type, i.Count, i.Time.UTC().Format(time.RFC3339))
+}
+
+// RepairPolicy 数据修复策略
+type RepairPolicy string
+
+const (
+	RepairForwardFill RepairPolicy = "forward_fill" // 用前一根收盘价补齐缺口和无效K线
+	RepairDrop        RepairPolicy = "drop"         // 丢弃重复、无效和零成交量K线（计算得到的K线除外），缺口保持原样
+	RepairFail        RepairPolicy = "fail"         // 发现任何问题即返回错误
+)
+
+// maxGapFill 单个缺口最多补齐的K线数，避免长期停牌时生成大量伪造数据
+const maxGapFill = 1000
+
+// CheckKlines 检查K线序列的数据质量，不修改输入
+func CheckKlines(klines []*Kline, timeframe Timeframe) []QualityIssue {
+	_, issues := normalizeKlines(klines, timeframe)
+	return issues
+}
+
+// RepairKlines 按策略修复K线序列，返回修复后的新切片和发现的问题
+// 重复的开盘时间始终只保留最后一条（通常是最新更新的数据）
+func RepairKlines(klines []*Kline, timeframe Timeframe, policy RepairPolicy) ([]*Kline, []QualityIssue, error) {
+	sorted, issues := normalizeKlines(klines, timeframe)
+	if len(issues) == 0 {
+		return sorted, nil, nil
+	}
+
+	switch policy {
+	case RepairFail:
+		return nil, issues, fmt.Errorf("kline data quality check failed: %d issues, first: %s", len(issues), issues[0])
+	case RepairDrop:
+		result := make([]*Kline, 0, len(sorted))
+		for _, k := range sorted {
+			if !klineValid(k) || zeroVolume(k) {
+				continue
+			}
+			result = append(result, k)
+		}
+		return result, issues, nil
+	case RepairForwardFill, "":
+		return forwardFill(sorted, timeframe), issues, nil
+	default:
+		return nil, issues, fmt.Errorf("unsupported repair policy: %s", policy)
+	}
+}
+
+// normalizeKlines 排序并去重，同时收集所有问题
+func normalizeKlines(klines []*Kline, timeframe Timeframe) ([]*Kline, []QualityIssue) {
+	// 稳定排序，保证重复数据中后出现的一条被保留
+	sorted := make([]*Kline, len(klines))
+	copy(sorted, klines)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].OpenTime.Before(sorted[j].OpenTime)
+	})
+
+	var issues []QualityIssue
+	result := make([]*Kline, 0, len(sorted))
+	for _, k := range sorted {
+		if n := len(result); n > 0 && result[n-1].OpenTime.Equal(k.OpenTime) {
+			issues = appendIssue(issues, IssueDuplicate, k.OpenTime, 1)
+			result[n-1] = k
+			continue
+		}
+		result = append(result, k)
+	}
+
+	for i, k := range result {
+		if i > 0 {
+			if missing := missingPeriods(result[i-1].OpenTime, k.OpenTime, timeframe); missing > 0 {
+				issues = append(issues, QualityIssue{Type: IssueGap, Time: nextOpenTime(result[i-1].OpenTime, timeframe), Count: missing})
+			}
+		}
+		if !klineValid(k) {
+			issues = append(issues, QualityIssue{Type: IssueInvalid, Time: k.OpenTime, Count: 1})
+		} else if zeroVolume(k) {
+			issues = append(issues, QualityIssue{Type: IssueZeroVolume, Time: k.OpenTime, Count: 1})
+		}
+	}
+
+	return result, issues
+}
+
+// appendIssue 追加问题，同一时间的同类问题合并计数
+func appendIssue(issues []QualityIssue, issueType IssueType, t time.Time, count int) []QualityIssue {
+	if n := len(issues); n > 0 && issues[n-1].Type == issueType && issues[n-1].Time.Equal(t) {
+		issues[n-1].Count += count
+		return issues
+	}
+	return append(issues, QualityIssue{Type: issueType, Time: t, Count: count})
+}
+
+// klineValid 判断K线价格和成交量是否有效
+func klineValid(k *Kline) bool {
+	for _, v := range []float64{k.Open, k.High, k.Low, k.Close, k.Volume} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+	if k.Open <= 0 || k.High <= 0 || k.Low <= 0 || k.Close <= 0 || k.Volume < 0 {
+		return false
+	}
+	return k.High >= k.Low && k.High >= math.Max(k.Open, k.Close) && k.Low <= math.Min(k.Open, k.Close)
+}
+
+// zeroVolume 判断K线是否为零成交量，计算得到的K线（如交叉汇率）本来就没有成交量，不算问题
+func zeroVolume(k *Kline) bool {
+	return k.Volume == 0 && !k.Synthetic
+}
+
+// nextOpenTime 返回下一根K线的开盘时间
+func nextOpenTime(openTime time.Time, timeframe Timeframe) time.Time {
+	if timeframe == Timeframe1M {
+		return openTime.AddDate(0, 1, 0)
+	}
+	return openTime.Add(timeframe.Duration())
+}
+
+// missingPeriods 计算两根相邻K线之间缺失的周期数
+func missingPeriods(prev, next time.Time, timeframe Timeframe) int {
+	if timeframe.Duration() == 0 {
+		return 0
+	}
+	missing := 0
+	for t := nextOpenTime(prev, timeframe); t.Before(next); t = nextOpenTime(t, timeframe) {
+		missing++
+		if missing > maxGapFill {
+			// 超长缺口不再逐个计数
+			if timeframe != Timeframe1M {
+				missing = int(next.Sub(nextOpenTime(prev, timeframe)) / timeframe.Duration())
+			}
+			break
+		}
+	}
+	return missing
+}
+
+// forwardFill 用前一根有效K线的收盘价替换无效K线并补齐缺口
+func forwardFill(klines []*Kline, timeframe Timeframe) []*Kline {
+	result := make([]*Kline, 0, len(klines))
+	var prev *Kline
+	for _, k := range klines {
+		if prev != nil && timeframe.Duration() > 0 {
+			filled := 0
+			for t := nextOpenTime(prev.OpenTime, timeframe); t.Before(k.OpenTime) && filled < maxGapFill; t = nextOpenTime(t, timeframe) {
+				result = append(result, flatKline(prev, t, timeframe, true))
+				filled++
+			}
+		}
+
+		if !klineValid(k) {
+			if prev == nil {
+				continue // 序列开头没有可参考的价格，只能丢弃
+			}
+			filled := flatKline(prev, k.OpenTime, timeframe, k.IsClosed)
+			filled.CloseTime = k.CloseTime
+			k = filled
+		}
+
+		result = append(result, k)
+		prev = k
+	}
+	return result
+}
+
+// flatKline 以参考K线收盘价生成零成交量的平K线
+func flatKline(ref *Kline, openTime time.Time, timeframe Timeframe, closed bool) *Kline {
+	return &Kline{
+		Symbol:    ref.Symbol,
+		OpenTime:  openTime,
+		CloseTime: closeTimeFor(openTime, timeframe),
+		Open:      ref.Close,
+		High:      ref.Close,
+		Low:       ref.Close,
+		Close:     ref.Close,
+		Volume:    0,
+		IsClosed:  closed,
+		Synthetic: ref.Synthetic,
+	}
+}
@@ -0,0 +1,141 @@
+package datasource
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// qualityTestKlines 生成带有缺口、重复、零成交量和NaN的1h K线
+func qualityTestKlines(base time.Time) []*Kline {
+	klines := makeTestKlines("BTCUSDT", base, time.Hour, 10, 11, 12, 13, 14, 15)
+	for _, k := range klines {
+		k.IsClosed = true
+	}
+	// 去掉第3、4根形成缺口（02:00、03:00）
+	klines = append(klines[:2], klines[4:]...)
+	// 重复最后一根，新数据覆盖旧数据
+	dup := *klines[3]
+	dup.Close, dup.High = 16, 16
+	klines = append(klines, &dup)
+	// 零成交量和NaN
+	klines[1].Volume = 0
+	klines[2].Close = math.NaN()
+	return klines
+}
+
+func TestCheckKlines(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	issues := CheckKlines(qualityTestKlines(base), Timeframe1h)
+
+	counts := make(map[IssueType]int)
+	for _, issue := range issues {
+		counts[issue.Type] += issue.Count
+	}
+	if counts[IssueGap] != 2 || counts[IssueDuplicate] != 1 || counts[IssueZeroVolume] != 1 || counts[IssueInvalid] != 1 {
+		t.Errorf("unexpected issue counts: %v", counts)
+	}
+	for _, issue := range issues {
+		if issue.Type == IssueGap && !issue.Time.Equal(base.Add(2*time.Hour)) {
+			t.Errorf("gap should start at 02:00, got %v", issue.Time)
+		}
+	}
+
+	if issues := CheckKlines(makeTestKlines("BTCUSDT", base, time.Hour, 1, 2, 3), Timeframe1h); len(issues) != 0 {
+		t.Errorf("clean klines should have no issues, got %v", issues)
+	}
+}
+
+func TestRepairKlines_ForwardFill(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	repaired, issues, err := RepairKlines(qualityTestKlines(base), Timeframe1h, RepairForwardFill)
+	if err != nil {
+		t.Fatalf("RepairKlines() error = %v", err)
+	}
+	if len(issues) == 0 {
+		t.Error("issues should be reported")
+	}
+	if len(repaired) != 6 {
+		t.Fatalf("expected 6 contiguous klines, got %d", len(repaired))
+	}
+	for i, k := range repaired {
+		if !k.OpenTime.Equal(base.Add(time.Duration(i) * time.Hour)) {
+			t.Errorf("kline %d open time = %v", i, k.OpenTime)
+		}
+	}
+	// 缺口用前一根收盘价补齐
+	if repaired[2].Close != 11 || repaired[2].Volume != 0 || !repaired[2].IsClosed {
+		t.Errorf("gap should be filled with previous close: %+v", repaired[2])
+	}
+	// NaN K线(04:00)用前值替换，重复K线保留最新
+	if repaired[4].Close != 11 || math.IsNaN(repaired[4].Close) {
+		t.Errorf("invalid kline should be forward filled: %+v", repaired[4])
+	}
+	if repaired[5].Close != 16 {
+		t.Errorf("duplicate should keep the latest kline, got close %.0f", repaired[5].Close)
+	}
+}
+
+func TestRepairKlines_DropAndFail(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	dropped, _, err := RepairKlines(qualityTestKlines(base), Timeframe1h, RepairDrop)
+	if err != nil {
+		t.Fatalf("RepairKlines(drop) error = %v", err)
+	}
+	// 剩余 00:00 和 05:00，零成交量和NaN被丢弃
+	if len(dropped) != 2 || dropped[1].Close != 16 {
+		t.Errorf("unexpected drop result: %d klines", len(dropped))
+	}
+
+	if _, issues, err := RepairKlines(qualityTestKlines(base), Timeframe1h, RepairFail); err == nil || len(issues) == 0 {
+		t.Error("fail policy should return error with issues")
+	}
+
+	clean := makeTestKlines("BTCUSDT", base, time.Hour, 1, 2, 3)
+	if result, issues, err := RepairKlines(clean, Timeframe1h, RepairFail); err != nil || len(issues) != 0 || len(result) != 3 {
+		t.Errorf("clean klines should pass fail policy: %v", err)
+	}
+}
+
+func TestRepairKlines_SyntheticZeroVolume(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	// 交叉汇率等计算得到的K线成交量恒为0
+	klines := makeTestKlines("ETHBTC", base, time.Hour, 0.04, 0.041, 0.042)
+	for _, k := range klines {
+		k.Volume = 0
+		k.Synthetic = true
+	}
+
+	for _, policy := range []RepairPolicy{RepairForwardFill, RepairDrop, RepairFail} {
+		result, issues, err := RepairKlines(klines, Timeframe1h, policy)
+		if err != nil || len(issues) != 0 || len(result) != 3 {
+			t.Errorf("%s: synthetic klines should not be flagged as zero volume: %d klines, issues %v, err %v", policy, len(result), issues, err)
+		}
+	}
+
+	// 补齐的缺口K线沿用计算标记
+	gapped := []*Kline{klines[0], klines[2]}
+	filled, _, _ := RepairKlines(gapped, Timeframe1h, RepairForwardFill)
+	if len(filled) != 3 || !filled[1].Synthetic {
+		t.Errorf("filled kline should stay synthetic: %+v", filled)
+	}
+}
+
+func TestRepairKlines_MonthlyGap(t *testing.T) {
+	klines := []*Kline{
+		{OpenTime: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Open: 1, High: 1, Low: 1, Close: 1, Volume: 1},
+		{OpenTime: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), Open: 2, High: 2, Low: 2, Close: 2, Volume: 1},
+	}
+
+	repaired, issues, err := RepairKlines(klines, Timeframe1M, RepairForwardFill)
+	if err != nil {
+		t.Fatalf("RepairKlines() error = %v", err)
+	}
+	if len(issues) != 1 || issues[0].Count != 2 {
+		t.Errorf("expected one gap of 2 months, got %v", issues)
+	}
+	if len(repaired) != 4 || !repaired[2].OpenTime.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected monthly fill: %d klines", len(repaired))
+	}
+}
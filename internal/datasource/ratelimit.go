@@ -0,0 +1,242 @@
+package datasource
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"ta-watcher/internal/config"
+)
+
+// binanceWeightLimit Binance 现货接口每分钟的请求权重上限
+const binanceWeightLimit = 6000
+
+// weightThreshold 已用权重超过上限的该比例时，暂停到下一分钟
+const weightThreshold = 0.9
+
+// RateLimiter 令牌桶限流器，同一主机的所有客户端共享一个实例
+// 除按配置的速率发放令牌外，还会根据响应头（Retry-After、Binance 的 X-MBX-USED-WEIGHT）暂停请求
+type RateLimiter struct {
+	mu           sync.Mutex
+	rate         float64 // 每秒发放的令牌数，0 表示不限流
+	burst        float64 // 令牌桶容量
+	tokens       float64
+	last         time.Time
+	blockedUntil time.Time // 交易所要求暂停的截止时间
+	now          func() time.Time
+}
+
+// NewRateLimiter 创建令牌桶限流器，burst <= 0 时取每秒请求数（至少为1）
+func NewRateLimiter(requestsPerMinute, burst int) *RateLimiter {
+	l := &RateLimiter{now: time.Now}
+	l.setRate(requestsPerMinute, burst)
+	l.tokens = l.burst
+	return l
+}
+
+// setRate 设置发放速率和桶容量
+func (l *RateLimiter) setRate(requestsPerMinute, burst int) {
+	l.rate = float64(requestsPerMinute) / 60
+	if burst <= 0 {
+		burst = requestsPerMinute / 60
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	l.burst = float64(burst)
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+}
+
+// Wait 等待获取一个令牌，上下文取消时立即返回
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		wait := l.reserve()
+		if wait <= 0 {
+			return nil
+		}
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve 尝试取出令牌，返回需要等待的时间（0 表示已取得）
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Before(l.blockedUntil) {
+		return l.blockedUntil.Sub(now)
+	}
+	if l.rate <= 0 {
+		return 0
+	}
+
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// Observe 根据响应头调整限流状态
+func (l *RateLimiter) Observe(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusTeapot || resp.Header.Get("Retry-After") != "" {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), now); ok {
+			l.block(now.Add(d))
+		} else if resp.StatusCode == http.StatusTooManyRequests {
+			// 未给出等待时间时清空令牌桶，按正常速率恢复
+			l.tokens = 0
+			l.last = now
+		}
+	}
+
+	used := resp.Header.Get("X-MBX-USED-WEIGHT-1M")
+	if used == "" {
+		used = resp.Header.Get("X-MBX-USED-WEIGHT")
+	}
+	if weight, err := strconv.Atoi(used); err == nil && float64(weight) >= binanceWeightLimit*weightThreshold {
+		// 权重按自然分钟重置
+		l.block(now.Truncate(time.Minute).Add(time.Minute))
+	}
+}
+
+// block 暂停请求直到指定时间
+func (l *RateLimiter) block(until time.Time) {
+	if until.After(l.blockedUntil) {
+		l.blockedUntil = until
+	}
+}
+
+// parseRetryAfter 解析 Retry-After 头（秒数或 HTTP 日期）
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// hostLimiters 按主机共享的限流器
+var hostLimiters = struct {
+	sync.Mutex
+	m map[string]*RateLimiter
+}{m: make(map[string]*RateLimiter)}
+
+// limiterForHost 返回主机对应的共享限流器，多个配置并存时采用更严格的速率
+func limiterForHost(host string, rl *config.RateLimitConfig) *RateLimiter {
+	hostLimiters.Lock()
+	defer hostLimiters.Unlock()
+
+	limiter, ok := hostLimiters.m[host]
+	if !ok {
+		limiter = NewRateLimiter(rl.RequestsPerMinute, rl.Burst)
+		hostLimiters.m[host] = limiter
+		return limiter
+	}
+
+	limiter.mu.Lock()
+	if rl.RequestsPerMinute > 0 && (limiter.rate <= 0 || float64(rl.RequestsPerMinute)/60 < limiter.rate) {
+		limiter.setRate(rl.RequestsPerMinute, rl.Burst)
+	}
+	limiter.mu.Unlock()
+	return limiter
+}
+
+// executeWithRetry 经主机限流器和熔断器发送请求，retryable 返回 true 的响应按重试延迟重试
+// 所有等待都会响应请求上下文的取消；网络错误包装为 ErrUnavailable
+func executeWithRetry(client *http.Client, req *http.Request, rl *config.RateLimitConfig, retryable func(*http.Response) bool) (*http.Response, error) {
+	ctx := req.Context()
+	limiter := limiterForHost(req.URL.Host, rl)
+	breaker := breakerForHost(req.URL.Host)
+
+	var resp *http.Response
+	var err error
+	for attempt := 0; attempt <= rl.MaxRetries; attempt++ {
+		if err := limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+		if err := breaker.Allow(); err != nil {
+			return nil, err
+		}
+
+		resp, err = client.Do(req)
+		limiter.Observe(resp)
+		switch {
+		case ctx.Err() != nil:
+			breaker.Release()
+			if resp != nil {
+				resp.Body.Close()
+			}
+			return nil, ctx.Err()
+		case err != nil:
+			breaker.RecordFailure()
+			err = fmt.Errorf("%w: %v", ErrUnavailable, err)
+		case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusTeapot || resp.StatusCode >= 500:
+			// 418 为 Binance 的IP封禁，继续请求会延长封禁时间
+			breaker.RecordFailure()
+		default:
+			breaker.RecordSuccess()
+		}
+
+		if err == nil && !retryable(resp) {
+			return resp, nil
+		}
+
+		if attempt < rl.MaxRetries {
+			if resp != nil {
+				resp.Body.Close()
+			}
+			if err := sleepContext(ctx, rl.RetryDelay); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return resp, err
+}
+
+// sleepContext 休眠指定时间，上下文取消时提前返回
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
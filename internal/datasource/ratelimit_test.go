@@ -0,0 +1,143 @@
+package datasource
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"ta-watcher/internal/config"
+)
+
+// newTestLimiter 创建使用可控时钟的限流器
+func newTestLimiter(requestsPerMinute, burst int) (*RateLimiter, *time.Time) {
+	now := time.Date(2025, 1, 1, 0, 0, 30, 0, time.UTC)
+	l := NewRateLimiter(requestsPerMinute, burst)
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+// resetHostState 清除主机共享的限流器和熔断器，避免其他测试的状态影响回放测试
+func resetHostState(host string) {
+	hostLimiters.Lock()
+	delete(hostLimiters.m, host)
+	hostLimiters.Unlock()
+
+	hostBreakers.Lock()
+	delete(hostBreakers.m, host)
+	hostBreakers.Unlock()
+}
+
+func TestRateLimiter_Burst(t *testing.T) {
+	l, now := newTestLimiter(60, 3)
+
+	for i := 0; i < 3; i++ {
+		if wait := l.reserve(); wait != 0 {
+			t.Fatalf("request %d within burst should not wait, got %v", i, wait)
+		}
+	}
+	if wait := l.reserve(); wait <= 0 || wait > time.Second {
+		t.Errorf("request beyond burst should wait about 1s, got %v", wait)
+	}
+
+	*now = now.Add(2 * time.Second)
+	if wait := l.reserve(); wait != 0 {
+		t.Errorf("tokens should refill over time, got wait %v", wait)
+	}
+}
+
+func TestRateLimiter_ObserveHeaders(t *testing.T) {
+	l, now := newTestLimiter(6000, 10)
+
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	resp.Header.Set("Retry-After", "5")
+	l.Observe(resp)
+	if wait := l.reserve(); wait != 5*time.Second {
+		t.Errorf("Retry-After should block for 5s, got %v", wait)
+	}
+
+	*now = now.Add(5 * time.Second)
+	resp = &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+	resp.Header.Set("X-MBX-USED-WEIGHT-1M", "5800")
+	l.Observe(resp)
+	// 00:00:35 时权重接近上限，应等到下一分钟
+	if wait := l.reserve(); wait != 25*time.Second {
+		t.Errorf("used weight near limit should block until next minute, got %v", wait)
+	}
+
+	resp.Header.Set("X-MBX-USED-WEIGHT-1M", "100")
+	*now = now.Add(25 * time.Second)
+	l.Observe(resp)
+	if wait := l.reserve(); wait != 0 {
+		t.Errorf("low used weight should not block, got %v", wait)
+	}
+}
+
+func TestRateLimiter_WaitHonorsContext(t *testing.T) {
+	l := NewRateLimiter(1, 1)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("first Wait() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := l.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Wait() should return context error, got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("Wait() should return as soon as the context is done")
+	}
+}
+
+func TestLimiterForHost_Shared(t *testing.T) {
+	fast := &config.RateLimitConfig{RequestsPerMinute: 1200}
+	slow := &config.RateLimitConfig{RequestsPerMinute: 60}
+
+	a := limiterForHost("shared.example.com", fast)
+	b := limiterForHost("shared.example.com", slow)
+	if a != b {
+		t.Fatal("clients of the same host should share a limiter")
+	}
+	if a.rate != 1 {
+		t.Errorf("shared limiter should use the stricter rate, got %.1f/s", a.rate)
+	}
+	if limiterForHost("other.example.com", fast) == a {
+		t.Error("different hosts should not share a limiter")
+	}
+}
+
+func TestExecuteWithRetry_RateLimited(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	rl := &config.RateLimitConfig{RequestsPerMinute: 6000, RetryDelay: 10 * time.Millisecond, MaxRetries: 2}
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := executeWithRetry(server.Client(), req, rl, func(resp *http.Response) bool {
+		return resp.StatusCode == http.StatusTooManyRequests
+	})
+	if err != nil {
+		t.Fatalf("executeWithRetry() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || atomic.LoadInt32(&calls) != 2 {
+		t.Errorf("expected retry after 429, status %d calls %d", resp.StatusCode, calls)
+	}
+
+	// 上下文取消时不再等待重试
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ = http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if _, err := executeWithRetry(server.Client(), req, rl, func(*http.Response) bool { return true }); err == nil {
+		t.Error("cancelled context should abort the request")
+	}
+}
@@ -0,0 +1,84 @@
+package datasource
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ta-watcher/internal/resample"
+)
+
+// ResampleConfigurable 可配置重采样选项的数据源
+type ResampleConfigurable interface {
+	SetResampleOptions(opts resample.Options)
+}
+
+// resampling 为数据源提供重采样回退所需的选项，嵌入到各客户端中
+type resampling struct {
+	resampleOpts *resample.Options
+}
+
+// SetResampleOptions 设置重采样选项
+func (r *resampling) SetResampleOptions(opts resample.Options) {
+	r.resampleOpts = &opts
+}
+
+// resampleOptions 返回重采样选项，未设置时使用默认值
+func (r *resampling) resampleOptions() resample.Options {
+	if r.resampleOpts == nil {
+		return resample.DefaultOptions()
+	}
+	return *r.resampleOpts
+}
+
+// klineFetcher 按原生时间框架获取K线的函数
+type klineFetcher func(ctx context.Context, symbol string, timeframe Timeframe, startTime, endTime time.Time, limit int) ([]*Kline, error)
+
+// getResampledKlines 交易所不支持目标时间框架时，获取更小的原生时间框架数据并重采样
+func getResampledKlines(ctx context.Context, fetch klineFetcher, native func(Timeframe) bool, opts resample.Options, symbol string, target Timeframe, startTime, endTime time.Time, limit int) ([]*Kline, error) {
+	source, ok := resample.SourceFor(target, native)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedTimeframe, target)
+	}
+
+	if limit <= 0 {
+		limit = 500
+	}
+
+	// 未指定开始时间时多取一个周期，由数据源决定默认范围；
+	// 指定时从目标周期边界开始获取，避免首个周期不完整
+	ratio := int(target.Duration() / source.Duration())
+	sourceLimit := (limit + 1) * ratio
+	var fetchStart time.Time
+	if !startTime.IsZero() {
+		fetchStart = resample.PeriodStart(startTime, target, opts.WeekStart)
+		end := endTime
+		if end.IsZero() {
+			end = time.Now()
+		}
+		sourceLimit = int(end.Sub(fetchStart)/source.Duration()) + 1
+	}
+
+	klines, err := fetch(ctx, symbol, source, fetchStart, endTime, sourceLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	resampled, err := resample.Resample(klines, source, target, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*Kline, 0, len(resampled))
+	for _, k := range resampled {
+		if k.OpenTime.Before(fetchStart) || (!endTime.IsZero() && k.OpenTime.After(endTime)) {
+			continue
+		}
+		result = append(result, k)
+	}
+	if len(result) > limit {
+		result = result[len(result)-limit:]
+	}
+
+	return result, nil
+}
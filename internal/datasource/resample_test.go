@@ -0,0 +1,86 @@
+package datasource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ta-watcher/internal/config"
+	"ta-watcher/internal/resample"
+)
+
+func TestGetResampledKlines(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	hourly := makeTestKlines("BTCUSDT", base, time.Hour, make([]float64, 48)...)
+
+	var requested Timeframe
+	fetch := func(ctx context.Context, symbol string, tf Timeframe, start, end time.Time, limit int) ([]*Kline, error) {
+		requested = tf
+		var result []*Kline
+		for _, k := range hourly {
+			if (start.IsZero() || !k.OpenTime.Before(start)) && (end.IsZero() || !k.OpenTime.After(end)) {
+				result = append(result, k)
+			}
+		}
+		return result, nil
+	}
+	native := func(tf Timeframe) bool { return tf == Timeframe1m || tf == Timeframe1h }
+
+	klines, err := getResampledKlines(context.Background(), fetch, native, resample.DefaultOptions(),
+		"BTCUSDT", Timeframe8h, base.Add(3*time.Hour), base.Add(47*time.Hour), 10)
+	if err != nil {
+		t.Fatalf("getResampledKlines() error = %v", err)
+	}
+	if requested != Timeframe1h {
+		t.Errorf("should resample from the largest native timeframe, got %s", requested)
+	}
+	// 开始时间对齐到 00:00，共 6 根 8h K线
+	if len(klines) != 6 || !klines[0].OpenTime.Equal(base) || klines[0].Volume != 8 {
+		t.Errorf("unexpected resampled klines: %d", len(klines))
+	}
+
+	klines, _ = getResampledKlines(context.Background(), fetch, native, resample.DefaultOptions(),
+		"BTCUSDT", Timeframe8h, time.Time{}, time.Time{}, 2)
+	if len(klines) != 2 || !klines[1].OpenTime.Equal(base.Add(40*time.Hour)) {
+		t.Errorf("limit should keep the latest klines, got %d", len(klines))
+	}
+
+	if _, err := getResampledKlines(context.Background(), fetch, func(Timeframe) bool { return false },
+		resample.DefaultOptions(), "BTCUSDT", Timeframe8h, time.Time{}, time.Time{}, 2); err == nil {
+		t.Error("expected error when no native timeframe can be resampled")
+	}
+}
+
+func TestFileDataSource_ResampleFallback(t *testing.T) {
+	dir := t.TempDir()
+	// 2025-01-01 是周三
+	writeTestFile(t, dir, "AAPL", "1d.csv", `date,open,high,low,close,volume
+2025-01-01,8,9,7,8.5,80
+2025-01-02,8.5,10,8,10,90
+2025-01-03,10,12,9,11,100
+2025-01-06,11,13,10,12,120
+`)
+
+	ds := NewFileDataSource(&config.FileConfig{Directory: dir})
+	klines, err := ds.GetKlines(context.Background(), "AAPL", Timeframe1w, time.Time{}, time.Time{}, 0)
+	if err != nil {
+		t.Fatalf("GetKlines(1w) error = %v", err)
+	}
+	if len(klines) != 2 {
+		t.Fatalf("expected 2 weekly klines, got %d", len(klines))
+	}
+	if !klines[0].OpenTime.Equal(time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC)) || klines[0].High != 12 || klines[0].Volume != 270 {
+		t.Errorf("unexpected first week: %+v", klines[0])
+	}
+
+	// 周线从周日开始时，1月6日(周一)仍属于1月5日开始的周
+	ds.SetResampleOptions(resample.Options{WeekStart: time.Sunday})
+	klines, _ = ds.GetKlines(context.Background(), "AAPL", Timeframe1w, time.Time{}, time.Time{}, 0)
+	if len(klines) != 2 || !klines[1].OpenTime.Equal(time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected weeks with sunday start: %d", len(klines))
+	}
+
+	if _, err := ds.GetKlines(context.Background(), "AAPL", Timeframe1h, time.Time{}, time.Time{}, 0); err == nil {
+		t.Error("expected error when no finer file exists")
+	}
+}
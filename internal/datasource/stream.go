@@ -0,0 +1,203 @@
+package datasource
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// StreamingDataSource 支持实时推送K线的数据源
+type StreamingDataSource interface {
+	DataSource
+
+	// SubscribeKlines 订阅多个交易对的K线推送，通道中只会收到已收盘的K线
+	// 连接断开时自动重连并重新订阅，ctx 取消后通道关闭
+	SubscribeKlines(ctx context.Context, symbols []string, timeframe Timeframe) (<-chan *Kline, error)
+}
+
+// AsStreaming 查找数据源（或其包装的数据源）中支持实时推送的实现
+func AsStreaming(ds DataSource) (StreamingDataSource, bool) {
+	if streaming, ok := ds.(StreamingDataSource); ok {
+		return streaming, true
+	}
+	if wrapper, ok := ds.(Unwrapper); ok {
+		for _, inner := range wrapper.Unwrap() {
+			if streaming, ok := AsStreaming(inner); ok {
+				return streaming, true
+			}
+		}
+	}
+	return nil, false
+}
+
+const (
+	defaultStreamReconnectDelay    = time.Second
+	defaultStreamMaxReconnectDelay = time.Minute
+	defaultStreamReadTimeout       = 5 * time.Minute
+	streamCloseTimeout             = time.Second
+)
+
+// klineStream 通用的 WebSocket K线订阅流程：连接、订阅、读取、断线重连
+type klineStream struct {
+	name      string
+	url       string
+	subscribe []byte                       // 每次连接成功后发送的订阅消息
+	parse     func(msg []byte) []*Kline    // 解析消息，返回已收盘的K线
+	flush     func(now time.Time) []*Kline // 可选：定时检查需要推送的K线
+	connected func(now time.Time)          // 可选：每次（重新）订阅成功后调用
+
+	reconnectDelay    time.Duration
+	maxReconnectDelay time.Duration
+	readTimeout       time.Duration
+	flushInterval     time.Duration
+}
+
+// start 建立首个连接后在后台持续读取，首次连接失败直接返回错误
+func (s *klineStream) start(ctx context.Context) (<-chan *Kline, error) {
+	if s.reconnectDelay <= 0 {
+		s.reconnectDelay = defaultStreamReconnectDelay
+	}
+	if s.maxReconnectDelay < s.reconnectDelay {
+		s.maxReconnectDelay = defaultStreamMaxReconnectDelay
+	}
+	if s.readTimeout <= 0 {
+		s.readTimeout = defaultStreamReadTimeout
+	}
+	if s.flushInterval <= 0 {
+		s.flushInterval = time.Second
+	}
+
+	conn, err := s.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan *Kline, 64)
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.run(ctx, conn, out)
+	}()
+
+	if s.flush != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ticker := time.NewTicker(s.flushInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case now := <-ticker.C:
+					for _, k := range s.flush(now) {
+						if !sendKline(ctx, out, k) {
+							return
+						}
+					}
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out, nil
+}
+
+// connect 建立连接并发送订阅消息
+func (s *klineStream) connect(ctx context.Context) (*websocket.Conn, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.WriteMessage(websocket.TextMessage, s.subscribe); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if s.connected != nil {
+		s.connected(time.Now())
+	}
+	log.Printf("📡 [%s] WebSocket 已连接并订阅", s.name)
+	return conn, nil
+}
+
+// run 读取消息，断线后按指数退避重连
+func (s *klineStream) run(ctx context.Context, conn *websocket.Conn, out chan<- *Kline) {
+	delay := s.reconnectDelay
+	for {
+		err := s.readLoop(ctx, conn, out)
+		conn.Close()
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("⚠️ [%s] WebSocket 连接断开: %v，%v 后重连", s.name, err, delay)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+
+			conn, err = s.connect(ctx)
+			if err == nil {
+				delay = s.reconnectDelay
+				break
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			delay *= 2
+			if delay > s.maxReconnectDelay {
+				delay = s.maxReconnectDelay
+			}
+			log.Printf("❌ [%s] WebSocket 重连失败: %v，%v 后重试", s.name, err, delay)
+		}
+	}
+}
+
+// readLoop 持续读取消息直到出错或 ctx 取消，ctx 取消时发送关闭帧
+// ping 由 gorilla/websocket 默认处理器自动回复 pong
+func (s *klineStream) readLoop(ctx context.Context, conn *websocket.Conn, out chan<- *Kline) error {
+	stop := context.AfterFunc(ctx, func() {
+		closeMsg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+		conn.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(streamCloseTimeout))
+		conn.Close()
+	})
+	defer stop()
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(s.readTimeout))
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway, websocket.CloseServiceRestart) {
+				log.Printf("🔌 [%s] 服务端关闭 WebSocket 连接: %v", s.name, err)
+			}
+			return err
+		}
+		for _, k := range s.parse(msg) {
+			if !sendKline(ctx, out, k) {
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// sendKline 推送K线，ctx 取消时返回 false
+func sendKline(ctx context.Context, out chan<- *Kline, k *Kline) bool {
+	select {
+	case out <- k:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
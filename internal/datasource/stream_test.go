@@ -0,0 +1,366 @@
+package datasource
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"ta-watcher/internal/resample"
+)
+
+// wsTestServer 本地 WebSocket 测试服务器，每个连接交给 handler 处理
+type wsTestServer struct {
+	*httptest.Server
+	mu          sync.Mutex
+	connections int
+}
+
+func newWSTestServer(t *testing.T, handler func(index int, conn *websocket.Conn)) *wsTestServer {
+	s := &wsTestServer{}
+	upgrader := websocket.Upgrader{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		s.mu.Lock()
+		s.connections++
+		index := s.connections
+		s.mu.Unlock()
+
+		handler(index, conn)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *wsTestServer) wsURL() string {
+	return "ws" + strings.TrimPrefix(s.URL, "http")
+}
+
+func (s *wsTestServer) connectionCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connections
+}
+
+// readText 读取客户端发送的文本消息
+func readText(conn *websocket.Conn) (string, error) {
+	_, msg, err := conn.ReadMessage()
+	return string(msg), err
+}
+
+func binanceTestEvent(symbol string, openTime time.Time, close string, closed bool) map[string]interface{} {
+	return map[string]interface{}{
+		"e": "kline",
+		"s": symbol,
+		"k": map[string]interface{}{
+			"t": openTime.UnixMilli(),
+			"T": openTime.Add(time.Hour - time.Millisecond).UnixMilli(),
+			"i": "1h",
+			"o": "100", "h": "110", "l": "90", "c": close, "v": "5",
+			"x": closed,
+		},
+	}
+}
+
+func receiveKline(t *testing.T, ch <-chan *Kline) *Kline {
+	t.Helper()
+	select {
+	case k, ok := <-ch:
+		if !ok {
+			t.Fatal("stream closed unexpectedly")
+		}
+		return k
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for kline")
+	}
+	return nil
+}
+
+func TestKlineStream_PingAndServerClose(t *testing.T) {
+	pong := make(chan string, 1)
+	closed := make(chan struct{})
+	server := newWSTestServer(t, func(index int, conn *websocket.Conn) {
+		if _, err := readText(conn); err != nil {
+			return
+		}
+		if index > 1 {
+			conn.WriteMessage(websocket.TextMessage, []byte("second"))
+			// 等待客户端在 ctx 取消时发送的关闭帧
+			if _, _, err := conn.ReadMessage(); websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				close(closed)
+			}
+			return
+		}
+
+		// 客户端应自动回复 pong（由 ReadMessage 驱动）
+		conn.SetPongHandler(func(data string) error {
+			pong <- data
+			return nil
+		})
+		conn.WriteControl(websocket.PingMessage, []byte("hb"), time.Now().Add(time.Second))
+		conn.WriteMessage(websocket.TextMessage, []byte("first"))
+		// 读取以处理客户端回复的 pong
+		conn.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+		conn.ReadMessage()
+
+		// 服务端主动关闭，客户端应重连
+		conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "restart"), time.Now().Add(time.Second))
+	})
+
+	stream := &klineStream{
+		name:           "test",
+		url:            server.wsURL(),
+		subscribe:      []byte("sub"),
+		reconnectDelay: 10 * time.Millisecond,
+		parse: func(msg []byte) []*Kline {
+			return []*Kline{{Symbol: string(msg)}}
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := stream.start(ctx)
+	if err != nil {
+		t.Fatalf("start() error = %v", err)
+	}
+
+	if k := receiveKline(t, ch); k.Symbol != "first" {
+		t.Errorf("unexpected message: %s", k.Symbol)
+	}
+	select {
+	case got := <-pong:
+		if got != "hb" {
+			t.Errorf("pong payload = %q, expected hb", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for pong")
+	}
+
+	if k := receiveKline(t, ch); k.Symbol != "second" {
+		t.Errorf("unexpected message after reconnect: %s", k.Symbol)
+	}
+
+	cancel()
+	for range ch {
+	}
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Error("client should send a close frame when ctx is cancelled")
+	}
+}
+
+func TestBinance_SubscribeKlines(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	subscriptions := make(chan string, 4)
+
+	server := newWSTestServer(t, func(index int, conn *websocket.Conn) {
+		msg, err := readText(conn)
+		if err != nil {
+			return
+		}
+		subscriptions <- msg
+		conn.WriteJSON(map[string]interface{}{"result": nil, "id": 1})
+
+		openTime := base.Add(time.Duration(index-1) * time.Hour)
+		conn.WriteJSON(binanceTestEvent("BTCUSDT", openTime, "101", false))
+		conn.WriteJSON(binanceTestEvent("BTCUSDT", openTime, "105", true))
+
+		if index == 1 {
+			return // 第一个连接推送后断开，验证重连和重新订阅
+		}
+		time.Sleep(time.Second)
+	})
+
+	client := NewBinanceClient()
+	client.wsURL = server.wsURL()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := client.SubscribeKlines(ctx, []string{"BTCUSDT"}, Timeframe1h)
+	if err != nil {
+		t.Fatalf("SubscribeKlines() error = %v", err)
+	}
+
+	first := receiveKline(t, ch)
+	if !first.OpenTime.Equal(base) || first.Close != 105 || first.Volume != 5 {
+		t.Errorf("unexpected first kline: %+v", first)
+	}
+
+	second := receiveKline(t, ch)
+	if !second.OpenTime.Equal(base.Add(time.Hour)) {
+		t.Errorf("unexpected second kline after reconnect: %+v", second)
+	}
+
+	for i := 0; i < 2; i++ {
+		msg := <-subscriptions
+		if !strings.Contains(msg, `"btcusdt@kline_1h"`) || !strings.Contains(msg, "SUBSCRIBE") {
+			t.Errorf("unexpected subscribe message: %s", msg)
+		}
+	}
+	if server.connectionCount() != 2 {
+		t.Errorf("expected 2 connections, got %d", server.connectionCount())
+	}
+
+	cancel()
+	for range ch {
+	}
+}
+
+func TestCoinbase_SubscribeKlinesAggregatesTrades(t *testing.T) {
+	// 使用未来时间，避免定时 flush 提前推送正在形成的K线
+	base := time.Now().UTC().Truncate(time.Hour).Add(24 * time.Hour)
+	subscribed := make(chan string, 1)
+
+	server := newWSTestServer(t, func(index int, conn *websocket.Conn) {
+		msg, err := readText(conn)
+		if err != nil {
+			return
+		}
+		subscribed <- msg
+
+		trades := []struct {
+			offset time.Duration
+			price  string
+			size   string
+		}{
+			{0, "100", "1"},
+			{10 * time.Minute, "120", "2"},
+			{20 * time.Minute, "90", "1"},
+			{50 * time.Minute, "110", "0.5"},
+			{61 * time.Minute, "111", "1"}, // 进入下一小时，触发上一根收盘
+		}
+		for _, trade := range trades {
+			conn.WriteJSON(map[string]interface{}{
+				"type":       "match",
+				"product_id": "BTC-USD",
+				"price":      trade.price,
+				"size":       trade.size,
+				"time":       base.Add(trade.offset).Format(time.RFC3339Nano),
+			})
+		}
+		time.Sleep(time.Second)
+	})
+
+	client := NewCoinbaseClient()
+	client.wsURL = server.wsURL()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := client.SubscribeKlines(ctx, []string{"BTCUSD"}, Timeframe1h)
+	if err != nil {
+		t.Fatalf("SubscribeKlines() error = %v", err)
+	}
+
+	if msg := <-subscribed; !strings.Contains(msg, `"BTC-USD"`) || !strings.Contains(msg, `"matches"`) {
+		t.Errorf("unexpected subscribe message: %s", msg)
+	}
+
+	k := receiveKline(t, ch)
+	if k.Symbol != "BTCUSD" || !k.OpenTime.Equal(base) {
+		t.Errorf("unexpected kline identity: %+v", k)
+	}
+	if k.Open != 100 || k.High != 120 || k.Low != 90 || k.Close != 110 || k.Volume != 4.5 {
+		t.Errorf("unexpected OHLCV: %+v", k)
+	}
+	if k.Partial {
+		t.Errorf("kline starting after subscription should not be partial: %+v", k)
+	}
+
+	cancel()
+	for range ch {
+	}
+}
+
+func TestTradeKlineBuilder_FlushAndLateTrades(t *testing.T) {
+	base := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	builder := newTradeKlineBuilder(Timeframe1h, time.Monday)
+
+	builder.addTrade("ETH-USD", "ETHUSD", base.Add(5*time.Minute), 10, 1)
+	if closed := builder.flush(base.Add(30 * time.Minute)); len(closed) != 0 {
+		t.Fatalf("forming kline should not be flushed, got %d", len(closed))
+	}
+
+	closed := builder.flush(base.Add(time.Hour + 5*time.Second))
+	if len(closed) != 1 || closed[0].Close != 10 {
+		t.Fatalf("expected flushed kline, got %+v", closed)
+	}
+
+	// 已推送周期的迟到成交应被忽略
+	if closed := builder.addTrade("ETH-USD", "ETHUSD", base.Add(59*time.Minute), 11, 1); len(closed) != 0 {
+		t.Errorf("late trade should be ignored, got %+v", closed)
+	}
+	if closed := builder.flush(base.Add(3 * time.Hour)); len(closed) != 0 {
+		t.Errorf("late trade should not create a new kline, got %+v", closed)
+	}
+}
+
+func TestTradeKlineBuilder_PeriodBoundariesAndPartial(t *testing.T) {
+	// 3日周期按Unix纪元对齐，与 resample.PeriodStart 一致
+	builder := newTradeKlineBuilder(Timeframe3d, time.Monday)
+	ts := time.Date(2024, 1, 5, 12, 0, 0, 0, time.UTC)
+	builder.connected(ts.Add(-time.Hour))
+	builder.addTrade("BTC-USD", "BTCUSD", ts, 100, 1)
+
+	closed := builder.flush(ts.Add(10 * 24 * time.Hour))
+	if len(closed) != 1 {
+		t.Fatalf("expected flushed kline, got %d", len(closed))
+	}
+	if expected := resample.PeriodStart(ts, Timeframe3d, time.Monday); !closed[0].OpenTime.Equal(expected) {
+		t.Errorf("3d open time = %v, expected %v", closed[0].OpenTime, expected)
+	}
+	if !closed[0].Partial {
+		t.Error("kline started before subscription should be marked partial")
+	}
+
+	// 订阅后开始的周期是完整的
+	next := resample.PeriodStart(ts, Timeframe3d, time.Monday).Add(3 * 24 * time.Hour)
+	builder.addTrade("BTC-USD", "BTCUSD", next.Add(time.Minute), 101, 1)
+	closed = builder.flush(next.Add(10 * 24 * time.Hour))
+	if len(closed) != 1 || closed[0].Partial {
+		t.Errorf("kline started after subscription should be complete: %+v", closed)
+	}
+
+	// 周线按配置的起始日对齐
+	weekly := newTradeKlineBuilder(Timeframe1w, time.Sunday)
+	weekly.addTrade("BTC-USD", "BTCUSD", ts, 100, 1)
+	closed = weekly.flush(ts.Add(10 * 24 * time.Hour))
+	if len(closed) != 1 || closed[0].OpenTime.Weekday() != time.Sunday {
+		t.Errorf("weekly kline should start on sunday: %+v", closed)
+	}
+
+	// 重连时正在形成的K线缺少断线期间的成交
+	builder.addTrade("BTC-USD", "BTCUSD", next.Add(3*24*time.Hour+time.Minute), 102, 1)
+	builder.connected(next.Add(3*24*time.Hour + time.Hour))
+	closed = builder.flush(next.Add(20 * 24 * time.Hour))
+	if len(closed) != 1 || !closed[0].Partial {
+		t.Errorf("kline forming across a reconnect should be partial: %+v", closed)
+	}
+}
+
+func TestAsStreaming(t *testing.T) {
+	binance := NewBinanceClient()
+	ds := NewFailoverDataSource(&stubDataSource{name: "file"}, binance, time.Minute)
+
+	streaming, ok := AsStreaming(ds)
+	if !ok || streaming.Name() != "binance" {
+		t.Errorf("AsStreaming() should find wrapped binance client, got %v %v", streaming, ok)
+	}
+
+	if _, ok := AsStreaming(&stubDataSource{name: "stub"}); ok {
+		t.Error("stub data source should not support streaming")
+	}
+}
@@ -0,0 +1,281 @@
+package datasource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"ta-watcher/internal/instrument"
+)
+
+// defaultSymbolInfoTTL 交易对元数据缓存有效期
+const defaultSymbolInfoTTL = time.Hour
+
+// SymbolStatus 交易对交易状态
+type SymbolStatus string
+
+const (
+	SymbolStatusTrading  SymbolStatus = "trading"  // 正常交易
+	SymbolStatusHalted   SymbolStatus = "halted"   // 暂停交易（维护、熔断、仅撤单等）
+	SymbolStatusDelisted SymbolStatus = "delisted" // 已下架
+)
+
+// SymbolInfo 交易对元数据
+type SymbolInfo struct {
+	Symbol         string       // 统一格式的交易对，例如 "BTCUSDT"
+	Base           string       // 基础货币
+	Quote          string       // 报价货币
+	Status         SymbolStatus // 交易状态
+	TickSize       float64      // 最小价格变动单位
+	PricePrecision int          // 价格小数位数
+	LotSize        float64      // 最小数量变动单位
+	MinNotional    float64      // 最小下单金额（报价货币计）
+}
+
+// SymbolInfoProvider 可查询交易对元数据的数据源
+type SymbolInfoProvider interface {
+	// SymbolInfo 返回交易对元数据，交易对不存在时返回 ErrSymbolNotFound
+	SymbolInfo(ctx context.Context, symbol string) (*SymbolInfo, error)
+
+	// Symbols 返回数据源的全部交易对（按交易对排序）
+	Symbols(ctx context.Context) ([]*SymbolInfo, error)
+}
+
+// AsSymbolInfoProvider 查找数据源（或其包装的数据源）中支持元数据查询的实现
+// 包装了多个支持元数据的数据源时（如主备切换），按顺序依次查询
+func AsSymbolInfoProvider(ds DataSource) (SymbolInfoProvider, bool) {
+	providers := collectSymbolInfoProviders(ds)
+	switch len(providers) {
+	case 0:
+		return nil, false
+	case 1:
+		return providers[0], true
+	default:
+		return multiSymbolInfo(providers), true
+	}
+}
+
+// collectSymbolInfoProviders 收集数据源及其包装的数据源中的元数据实现
+func collectSymbolInfoProviders(ds DataSource) []SymbolInfoProvider {
+	if provider, ok := ds.(SymbolInfoProvider); ok {
+		return []SymbolInfoProvider{provider}
+	}
+	var providers []SymbolInfoProvider
+	if wrapper, ok := ds.(Unwrapper); ok {
+		for _, inner := range wrapper.Unwrap() {
+			providers = append(providers, collectSymbolInfoProviders(inner)...)
+		}
+	}
+	return providers
+}
+
+// multiSymbolInfo 依次查询多个数据源的元数据
+type multiSymbolInfo []SymbolInfoProvider
+
+// SymbolInfo 返回第一个包含该交易对的数据源的元数据
+func (m multiSymbolInfo) SymbolInfo(ctx context.Context, symbol string) (*SymbolInfo, error) {
+	var errs sourceErrors
+	for _, provider := range m {
+		info, err := provider.SymbolInfo(ctx, symbol)
+		if err == nil {
+			return info, nil
+		}
+		errs = append(errs, err)
+	}
+
+	// 所有数据源都明确不存在时才返回 ErrSymbolNotFound
+	for _, err := range errs {
+		if !errors.Is(err, ErrSymbolNotFound) {
+			return nil, errs
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrSymbolNotFound, symbol)
+}
+
+// Symbols 合并各数据源的交易对，同名交易对以靠前的数据源为准
+func (m multiSymbolInfo) Symbols(ctx context.Context) ([]*SymbolInfo, error) {
+	merged := make(map[string]*SymbolInfo)
+	var errs sourceErrors
+	for _, provider := range m {
+		infos, err := provider.Symbols(ctx)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, info := range infos {
+			if _, ok := merged[info.Symbol]; !ok {
+				merged[info.Symbol] = info
+			}
+		}
+	}
+	if len(merged) == 0 && len(errs) > 0 {
+		return nil, errs
+	}
+	return sortedSymbolInfos(merged), nil
+}
+
+// ParseSymbol 将交易对拆分为基础货币和报价货币
+// 优先使用数据源的元数据：交易对存在时直接返回其 base/quote；
+// 不存在时（如需要换算的交叉汇率对）在数据源已知的币种中查找拆分方式。
+// 数据源不支持元数据查询时按常见报价货币后缀拆分
+func ParseSymbol(ctx context.Context, ds DataSource, symbol string) (base, quote string, err error) {
+	symbol = normalizeSymbol(symbol)
+
+	provider, ok := AsSymbolInfoProvider(ds)
+	if !ok {
+		base, quote = instrument.Split(symbol)
+		if base == "" || quote == "" {
+			return "", "", fmt.Errorf("unable to parse symbol: %s", symbol)
+		}
+		return base, quote, nil
+	}
+
+	info, err := provider.SymbolInfo(ctx, symbol)
+	if err == nil {
+		return info.Base, info.Quote, nil
+	}
+	if !errors.Is(err, ErrSymbolNotFound) {
+		return "", "", err
+	}
+
+	infos, err := provider.Symbols(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	return splitByAssets(symbol, infos)
+}
+
+// splitByAssets 在已知币种中查找交易对的拆分方式
+// 多种拆分都成立时优先报价货币在交易所中作为报价货币出现过的，其次优先更长的报价货币
+func splitByAssets(symbol string, infos []*SymbolInfo) (base, quote string, err error) {
+	assets := make(map[string]bool)
+	quotes := make(map[string]bool)
+	for _, info := range infos {
+		assets[info.Base] = true
+		assets[info.Quote] = true
+		quotes[info.Quote] = true
+	}
+
+	bestScore := -1
+	for i := 1; i < len(symbol); i++ {
+		b, q := symbol[:i], symbol[i:]
+		if !assets[b] || !assets[q] {
+			continue
+		}
+		score := len(q)
+		if quotes[q] {
+			score += 100
+		}
+		if score > bestScore {
+			base, quote, bestScore = b, q, score
+		}
+	}
+
+	if bestScore < 0 {
+		return "", "", fmt.Errorf("%w: unable to split %s into known assets", ErrSymbolNotFound, symbol)
+	}
+	return base, quote, nil
+}
+
+// symbolCatalog 交易所交易对元数据缓存，过期后整体刷新
+// 刷新失败时继续使用过期数据，避免交易所接口抖动影响交易对解析
+type symbolCatalog struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	loadedAt time.Time
+	symbols  map[string]*SymbolInfo
+}
+
+// get 返回缓存的元数据，缓存为空或过期时调用 load 重新加载
+func (c *symbolCatalog) get(ctx context.Context, source string, load func(context.Context) ([]*SymbolInfo, error)) (map[string]*SymbolInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ttl := c.ttl
+	if ttl <= 0 {
+		ttl = defaultSymbolInfoTTL
+	}
+	if c.symbols != nil && time.Since(c.loadedAt) < ttl {
+		return c.symbols, nil
+	}
+
+	infos, err := load(ctx)
+	if err != nil {
+		if c.symbols != nil {
+			log.Printf("⚠️ [%s] 刷新交易对元数据失败，继续使用缓存: %v", source, err)
+			return c.symbols, nil
+		}
+		return nil, fmt.Errorf("failed to load symbol info: %w", err)
+	}
+
+	symbols := make(map[string]*SymbolInfo, len(infos))
+	for _, info := range infos {
+		symbols[info.Symbol] = info
+	}
+	c.symbols = symbols
+	c.loadedAt = time.Now()
+	log.Printf("📇 [%s] 已加载 %d 个交易对元数据", source, len(symbols))
+
+	return symbols, nil
+}
+
+// lookup 查询单个交易对
+func (c *symbolCatalog) lookup(ctx context.Context, source, symbol string, load func(context.Context) ([]*SymbolInfo, error)) (*SymbolInfo, error) {
+	symbols, err := c.get(ctx, source, load)
+	if err != nil {
+		return nil, err
+	}
+	info, ok := symbols[normalizeSymbol(symbol)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrSymbolNotFound, symbol)
+	}
+	return info, nil
+}
+
+// all 返回全部交易对
+func (c *symbolCatalog) all(ctx context.Context, source string, load func(context.Context) ([]*SymbolInfo, error)) ([]*SymbolInfo, error) {
+	symbols, err := c.get(ctx, source, load)
+	if err != nil {
+		return nil, err
+	}
+	return sortedSymbolInfos(symbols), nil
+}
+
+// sortedSymbolInfos 按交易对排序
+func sortedSymbolInfos(symbols map[string]*SymbolInfo) []*SymbolInfo {
+	infos := make([]*SymbolInfo, 0, len(symbols))
+	for _, info := range symbols {
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Symbol < infos[j].Symbol
+	})
+	return infos
+}
+
+// normalizeSymbol 统一交易对格式：大写并去掉分隔符
+// BTC-USD -> BTCUSD
+func normalizeSymbol(symbol string) string {
+	symbol = strings.ToUpper(symbol)
+	return strings.NewReplacer("-", "", "/", "", "_", "").Replace(symbol)
+}
+
+// parseIncrement 解析交易所返回的步长字符串，返回数值和小数位数
+// "0.01000000" -> 0.01, 2
+func parseIncrement(value string) (float64, int) {
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil || v <= 0 {
+		return 0, 0
+	}
+	precision := 0
+	if dot := strings.IndexByte(value, '.'); dot >= 0 {
+		precision = len(strings.TrimRight(value[dot+1:], "0"))
+	}
+	return v, precision
+}
@@ -0,0 +1,230 @@
+package datasource
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+const testExchangeInfo = `{"timezone":"UTC","symbols":[
+{"symbol":"BTCUSDT","status":"TRADING","baseAsset":"BTC","quoteAsset":"USDT","filters":[
+ {"filterType":"PRICE_FILTER","minPrice":"0.01000000","maxPrice":"1000000.00000000","tickSize":"0.01000000"},
+ {"filterType":"LOT_SIZE","minQty":"0.00001000","maxQty":"9000.00000000","stepSize":"0.00001000"},
+ {"filterType":"NOTIONAL","minNotional":"5.00000000","applyMinToMarket":true}]},
+{"symbol":"ETHBTC","status":"TRADING","baseAsset":"ETH","quoteAsset":"BTC","filters":[
+ {"filterType":"PRICE_FILTER","tickSize":"0.00001000"},
+ {"filterType":"LOT_SIZE","stepSize":"0.00010000"},
+ {"filterType":"NOTIONAL","minNotional":"0.00010000"}]},
+{"symbol":"LINKUSDT","status":"HALT","baseAsset":"LINK","quoteAsset":"USDT","filters":[]},
+{"symbol":"LUNAUSDT","status":"BREAK","baseAsset":"LUNA","quoteAsset":"USDT","filters":[]}
+]}`
+
+const testCoinbaseProducts = `[
+{"id":"BTC-USD","base_currency":"BTC","quote_currency":"USD","quote_increment":"0.01","base_increment":"0.00000001","min_market_funds":"1","status":"online","trading_disabled":false,"cancel_only":false},
+{"id":"ETH-BTC","base_currency":"ETH","quote_currency":"BTC","quote_increment":"0.00001","base_increment":"0.00000001","min_market_funds":"0.000001","status":"online","trading_disabled":false,"cancel_only":false},
+{"id":"RNDR-USD","base_currency":"RNDR","quote_currency":"USD","quote_increment":"0.001","base_increment":"0.01","min_market_funds":"1","status":"delisted","trading_disabled":true,"cancel_only":false}
+]`
+
+// newSymbolInfoTestServer 返回固定响应并统计请求次数
+func newSymbolInfoTestServer(t *testing.T, path, body string, requests *int32) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			http.NotFound(w, r)
+			return
+		}
+		atomic.AddInt32(requests, 1)
+		w.Write([]byte(body))
+	}))
+}
+
+func TestBinanceClient_SymbolInfo(t *testing.T) {
+	var requests int32
+	server := newSymbolInfoTestServer(t, "/api/v3/exchangeInfo", testExchangeInfo, &requests)
+	defer server.Close()
+
+	client := NewBinanceClient()
+	client.baseURL = server.URL
+	ctx := context.Background()
+
+	info, err := client.SymbolInfo(ctx, "BTCUSDT")
+	if err != nil {
+		t.Fatalf("SymbolInfo() error = %v", err)
+	}
+	want := SymbolInfo{Symbol: "BTCUSDT", Base: "BTC", Quote: "USDT", Status: SymbolStatusTrading,
+		TickSize: 0.01, PricePrecision: 2, LotSize: 0.00001, MinNotional: 5}
+	if *info != want {
+		t.Errorf("SymbolInfo() = %+v, want %+v", *info, want)
+	}
+
+	for symbol, status := range map[string]SymbolStatus{"LINKUSDT": SymbolStatusHalted, "LUNAUSDT": SymbolStatusDelisted} {
+		info, err := client.SymbolInfo(ctx, symbol)
+		if err != nil || info.Status != status {
+			t.Errorf("SymbolInfo(%s) status = %v, err %v, want %s", symbol, info, err, status)
+		}
+	}
+
+	if _, err := client.SymbolInfo(ctx, "INVALIDUSDT"); !errors.Is(err, ErrSymbolNotFound) {
+		t.Errorf("expected ErrSymbolNotFound, got %v", err)
+	}
+
+	symbols, err := client.Symbols(ctx)
+	if err != nil || len(symbols) != 4 || symbols[0].Symbol != "BTCUSDT" {
+		t.Errorf("Symbols() = %d symbols, err %v", len(symbols), err)
+	}
+	if requests != 1 {
+		t.Errorf("exchangeInfo should be cached, got %d requests", requests)
+	}
+}
+
+func TestCoinbaseClient_SymbolInfo(t *testing.T) {
+	var requests int32
+	server := newSymbolInfoTestServer(t, "/products", testCoinbaseProducts, &requests)
+	defer server.Close()
+
+	client := NewCoinbaseClient()
+	client.baseURL = server.URL
+	ctx := context.Background()
+
+	// 同时接受统一格式和 Coinbase 格式
+	for _, symbol := range []string{"ETHBTC", "ETH-BTC"} {
+		info, err := client.SymbolInfo(ctx, symbol)
+		if err != nil {
+			t.Fatalf("SymbolInfo(%s) error = %v", symbol, err)
+		}
+		if info.Symbol != "ETHBTC" || info.Base != "ETH" || info.Quote != "BTC" || info.PricePrecision != 5 || info.LotSize != 0.00000001 {
+			t.Errorf("SymbolInfo(%s) = %+v", symbol, *info)
+		}
+	}
+
+	info, err := client.SymbolInfo(ctx, "RNDRUSD")
+	if err != nil || info.Status != SymbolStatusDelisted {
+		t.Errorf("RNDRUSD should be delisted, got %v, err %v", info, err)
+	}
+	if _, err := client.SymbolInfo(ctx, "INVALIDUSD"); !errors.Is(err, ErrSymbolNotFound) {
+		t.Errorf("expected ErrSymbolNotFound, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("products should be cached, got %d requests", requests)
+	}
+}
+
+func TestSymbolCatalog_KeepsStaleOnError(t *testing.T) {
+	var c symbolCatalog
+	load := func(ctx context.Context) ([]*SymbolInfo, error) {
+		return []*SymbolInfo{{Symbol: "BTCUSDT", Base: "BTC", Quote: "USDT"}}, nil
+	}
+	if _, err := c.lookup(context.Background(), "test", "BTCUSDT", load); err != nil {
+		t.Fatalf("lookup() error = %v", err)
+	}
+
+	// 缓存过期且刷新失败时继续使用旧数据
+	c.loadedAt = c.loadedAt.Add(-2 * defaultSymbolInfoTTL)
+	failing := func(ctx context.Context) ([]*SymbolInfo, error) { return nil, ErrUnavailable }
+	if info, err := c.lookup(context.Background(), "test", "BTCUSDT", failing); err != nil || info.Base != "BTC" {
+		t.Errorf("stale catalog should be used on refresh failure, got %v, err %v", info, err)
+	}
+
+	var empty symbolCatalog
+	if _, err := empty.lookup(context.Background(), "test", "BTCUSDT", failing); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("expected ErrUnavailable without cached data, got %v", err)
+	}
+}
+
+// symbolInfoDataSource 带交易对元数据的测试数据源
+type symbolInfoDataSource struct {
+	stubDataSource
+	infos []*SymbolInfo
+}
+
+func (s *symbolInfoDataSource) SymbolInfo(ctx context.Context, symbol string) (*SymbolInfo, error) {
+	for _, info := range s.infos {
+		if info.Symbol == normalizeSymbol(symbol) {
+			return info, nil
+		}
+	}
+	return nil, ErrSymbolNotFound
+}
+
+func (s *symbolInfoDataSource) Symbols(ctx context.Context) ([]*SymbolInfo, error) {
+	return s.infos, nil
+}
+
+func TestParseSymbol(t *testing.T) {
+	ds := &symbolInfoDataSource{
+		stubDataSource: stubDataSource{name: "binance"},
+		infos: []*SymbolInfo{
+			{Symbol: "BTCUSDT", Base: "BTC", Quote: "USDT"},
+			{Symbol: "ETHUSDT", Base: "ETH", Quote: "USDT"},
+			{Symbol: "LINKUSDT", Base: "LINK", Quote: "USDT"},
+			{Symbol: "PEPEUSDT", Base: "PEPE", Quote: "USDT"},
+			{Symbol: "ETHBTC", Base: "ETH", Quote: "BTC"},
+		},
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		symbol    string
+		base      string
+		quote     string
+		wantError bool
+	}{
+		{"BTCUSDT", "BTC", "USDT", false},
+		{"ETHBTC", "ETH", "BTC", false},
+		{"LINKETH", "LINK", "ETH", false},   // 交易所不存在，由已知币种拆分
+		{"PEPEBTC", "PEPE", "BTC", false},   // 原先的 4/3 拆分依赖长度，这里由币种决定
+		{"LINKPEPE", "LINK", "PEPE", false}, // 报价货币不是常见报价货币
+		{"FOOBAR", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.symbol, func(t *testing.T) {
+			base, quote, err := ParseSymbol(ctx, ds, tt.symbol)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ParseSymbol(%s) error = %v, wantError %v", tt.symbol, err, tt.wantError)
+			}
+			if base != tt.base || quote != tt.quote {
+				t.Errorf("ParseSymbol(%s) = %s/%s, want %s/%s", tt.symbol, base, quote, tt.base, tt.quote)
+			}
+		})
+	}
+
+	// 不支持元数据的数据源按报价货币后缀拆分
+	base, quote, err := ParseSymbol(ctx, &stubDataSource{name: "file"}, "SOLUSDT")
+	if err != nil || base != "SOL" || quote != "USDT" {
+		t.Errorf("fallback ParseSymbol() = %s/%s, err %v", base, quote, err)
+	}
+}
+
+func TestAsSymbolInfoProvider_Wrapped(t *testing.T) {
+	primary := &symbolInfoDataSource{
+		stubDataSource: stubDataSource{name: "binance"},
+		infos:          []*SymbolInfo{{Symbol: "BTCUSDT", Base: "BTC", Quote: "USDT"}},
+	}
+	fallback := &symbolInfoDataSource{
+		stubDataSource: stubDataSource{name: "coinbase"},
+		infos:          []*SymbolInfo{{Symbol: "BTCUSD", Base: "BTC", Quote: "USD"}},
+	}
+	ctx := context.Background()
+
+	if _, ok := AsSymbolInfoProvider(&stubDataSource{name: "file"}); ok {
+		t.Error("stub data source should not provide symbol info")
+	}
+
+	provider, ok := AsSymbolInfoProvider(NewFailoverDataSource(primary, fallback, 0))
+	if !ok {
+		t.Fatal("failover should expose wrapped symbol info providers")
+	}
+	if info, err := provider.SymbolInfo(ctx, "BTCUSD"); err != nil || info.Quote != "USD" {
+		t.Errorf("SymbolInfo(BTCUSD) should come from fallback, got %v, err %v", info, err)
+	}
+	if _, err := provider.SymbolInfo(ctx, "ETHBTC"); !errors.Is(err, ErrSymbolNotFound) {
+		t.Errorf("expected ErrSymbolNotFound, got %v", err)
+	}
+	if symbols, err := provider.Symbols(ctx); err != nil || len(symbols) != 2 {
+		t.Errorf("Symbols() should merge both sources, got %d, err %v", len(symbols), err)
+	}
+}
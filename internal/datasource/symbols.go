@@ -0,0 +1,12 @@
+package datasource
+
+import "ta-watcher/internal/instrument"
+
+// nativeSymbol 将规范交易对（如 BTCUSDT）映射为交易所原生格式，无法解析时原样返回
+func nativeSymbol(venue instrument.Venue, symbol string) string {
+	inst, err := instrument.Parse(symbol)
+	if err != nil {
+		return symbol
+	}
+	return venue.NativeSymbol(inst)
+}
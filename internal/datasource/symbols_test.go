@@ -0,0 +1,62 @@
+package datasource
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestNativeSymbol_EveryVenue(t *testing.T) {
+	binance, bybit := NewBinanceClient(), NewBybitClient()
+	coinbase, okx, kraken := NewCoinbaseClient(), NewOKXClient(), NewKrakenClient()
+
+	convert := map[string]func(string) string{
+		"binance":  binance.convertToBinanceSymbol,
+		"bybit":    bybit.convertToBybitSymbol,
+		"coinbase": coinbase.convertToCoinbaseSymbol,
+		"okx":      okx.convertToOKXSymbol,
+		"kraken":   kraken.convertToKrakenSymbol,
+	}
+
+	// 同一个配置交易对（base_currency 为 USD 或 USDT）在每个数据源都映射到实际存在的现货市场
+	tests := map[string]map[string]string{
+		"BTCUSDT": {"binance": "BTCUSDT", "bybit": "BTCUSDT", "coinbase": "BTC-USD", "okx": "BTC-USDT", "kraken": "XBTUSDT"},
+		"BTCUSD":  {"binance": "BTCUSDT", "bybit": "BTCUSDT", "coinbase": "BTC-USD", "okx": "BTC-USDT", "kraken": "XBTUSD"},
+	}
+	for symbol, venues := range tests {
+		for source, want := range venues {
+			if got := convert[source](symbol); got != want {
+				t.Errorf("%s: %s -> %s, want %s", source, symbol, got, want)
+			}
+		}
+	}
+
+	// 永续合约保留币本位报价
+	if got := okx.convertToOKXSwap("BTCUSD"); got != "BTC-USD-SWAP" {
+		t.Errorf("convertToOKXSwap(BTCUSD) = %s, want BTC-USD-SWAP", got)
+	}
+}
+
+func TestBybitClient_MapsUSDQuote(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	server := newBybitTestServer(t, base, 3)
+	defer server.Close()
+
+	client := NewBybitClient()
+	client.baseURL = server.URL
+	ctx := context.Background()
+
+	// 测试服务器只认识 BTCUSDT
+	valid, err := client.IsSymbolValid(ctx, "BTCUSD")
+	if err != nil || !valid {
+		t.Errorf("IsSymbolValid(BTCUSD) = %v, %v", valid, err)
+	}
+
+	klines, err := client.GetKlines(ctx, "BTCUSD", Timeframe1h, base, base.Add(3*time.Hour), 3)
+	if err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if len(klines) == 0 || klines[0].Symbol != "BTCUSD" {
+		t.Errorf("klines should keep the requested symbol, got %v", klines)
+	}
+}
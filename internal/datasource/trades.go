@@ -0,0 +1,50 @@
+package datasource
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// Trade 逐笔（聚合）成交
+type Trade struct {
+	Symbol     string    `json:"symbol"`
+	ID         string    `json:"id"`
+	Time       time.Time `json:"time"`
+	Price      float64   `json:"price"`
+	Quantity   float64   `json:"quantity"`
+	BuyerMaker bool      `json:"buyer_maker"` // 买方为挂单方，即主动卖出成交
+}
+
+// Notional 成交额（价格 × 数量）
+func (t *Trade) Notional() float64 {
+	return t.Price * t.Quantity
+}
+
+// TradeProvider 可获取近期成交的数据源
+type TradeProvider interface {
+	// GetRecentTrades 获取最近的成交（按时间升序），limit 超过交易所上限时按上限截取
+	GetRecentTrades(ctx context.Context, symbol string, limit int) ([]*Trade, error)
+}
+
+// AsTradeProvider 查找数据源（或其包装的数据源）中支持近期成交的实现
+func AsTradeProvider(ds DataSource) (TradeProvider, bool) {
+	if provider, ok := ds.(TradeProvider); ok {
+		return provider, true
+	}
+	if wrapper, ok := ds.(Unwrapper); ok {
+		for _, inner := range wrapper.Unwrap() {
+			if provider, ok := AsTradeProvider(inner); ok {
+				return provider, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// sortTradesByTime 按成交时间升序排序（多数交易所按时间倒序返回）
+func sortTradesByTime(trades []*Trade) {
+	sort.SliceStable(trades, func(i, j int) bool {
+		return trades[i].Time.Before(trades[j].Time)
+	})
+}
@@ -0,0 +1,97 @@
+package datasource
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetRecentTrades(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/api/v3/aggTrades":
+			if q.Get("symbol") != "BTCUSDT" || q.Get("limit") != "2" {
+				t.Errorf("unexpected binance query: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`[{"a":1,"p":"100.00","q":"1.5","f":1,"l":1,"T":1760572800000,"m":false,"M":true},{"a":2,"p":"101.00","q":"2","f":2,"l":3,"T":1760572801000,"m":true,"M":true}]`))
+		case "/products/BTC-USD/trades":
+			if q.Get("limit") != "2" {
+				t.Errorf("unexpected coinbase query: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`[{"time":"2025-10-16T00:00:01Z","trade_id":2,"price":"101.00","size":"2","side":"buy"},{"time":"2025-10-16T00:00:00Z","trade_id":1,"price":"100.00","size":"1.5","side":"sell"}]`))
+		case "/api/v5/market/trades":
+			if q.Get("instId") != "BTC-USDT" || q.Get("limit") != "2" {
+				t.Errorf("unexpected okx query: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"code":"0","msg":"","data":[{"instId":"BTC-USDT","tradeId":"2","px":"101.00","sz":"2","side":"sell","ts":"1760572801000"},{"instId":"BTC-USDT","tradeId":"1","px":"100.00","sz":"1.5","side":"buy","ts":"1760572800000"}]}`))
+		case "/0/public/Trades":
+			if q.Get("pair") != "XBTUSDT" || q.Get("count") != "2" {
+				t.Errorf("unexpected kraken query: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"error":[],"result":{"XBTUSDT":[["100.00","1.5",1760572800.0,"b","m","",1],["101.00","2",1760572801.0,"s","l","",2]],"last":"1760572801000000000"}}`))
+		case "/v5/market/recent-trade":
+			if q.Get("category") != "spot" || q.Get("symbol") != "BTCUSDT" || q.Get("limit") != "2" {
+				t.Errorf("unexpected bybit query: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"retCode":0,"retMsg":"OK","result":{"category":"spot","list":[{"execId":"2","symbol":"BTCUSDT","price":"101.00","size":"2","side":"Sell","time":"1760572801000"},{"execId":"1","symbol":"BTCUSDT","price":"100.00","size":"1.5","side":"Buy","time":"1760572800000"}]}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	// 每个交易所使用独立的服务器，避免共享主机限流
+	tests := []struct {
+		name   string
+		symbol string
+		client func(baseURL string) TradeProvider
+	}{
+		{"binance", "BTCUSDT", func(u string) TradeProvider { c := NewBinanceClient(); c.baseURL = u; return c }},
+		{"coinbase", "BTCUSD", func(u string) TradeProvider { c := NewCoinbaseClient(); c.baseURL = u; return c }},
+		{"okx", "BTCUSDT", func(u string) TradeProvider { c := NewOKXClient(); c.baseURL = u; return c }},
+		{"kraken", "BTCUSDT", func(u string) TradeProvider { c := NewKrakenClient(); c.baseURL = u; return c }},
+		{"bybit", "BTCUSDT", func(u string) TradeProvider { c := NewBybitClient(); c.baseURL = u; return c }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			trades, err := tt.client(server.URL).GetRecentTrades(context.Background(), tt.symbol, 2)
+			if err != nil {
+				t.Fatalf("GetRecentTrades() error = %v", err)
+			}
+			if len(trades) != 2 {
+				t.Fatalf("expected 2 trades, got %d", len(trades))
+			}
+
+			first, second := trades[0], trades[1]
+			if first.Price != 100 || first.Quantity != 1.5 || first.BuyerMaker || first.ID != "1" {
+				t.Errorf("unexpected first trade %+v", first)
+			}
+			if second.Price != 101 || second.Notional() != 202 || !second.BuyerMaker {
+				t.Errorf("unexpected second trade %+v", second)
+			}
+			if !first.Time.Equal(time.UnixMilli(1760572800000)) || !second.Time.After(first.Time) {
+				t.Errorf("trades should be sorted ascending by time: %v, %v", first.Time, second.Time)
+			}
+		})
+	}
+}
+
+func TestAsTradeProvider(t *testing.T) {
+	binance := NewBinanceClient()
+	failover := NewFailoverDataSource(&stubDataSource{name: "primary"}, binance, time.Minute)
+
+	provider, ok := AsTradeProvider(failover)
+	if !ok || provider != TradeProvider(binance) {
+		t.Errorf("AsTradeProvider() should unwrap the failover source, got %v, %v", provider, ok)
+	}
+
+	if _, ok := AsTradeProvider(&stubDataSource{name: "stub"}); ok {
+		t.Error("stub data source should not provide trades")
+	}
+}
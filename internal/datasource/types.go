@@ -3,40 +3,33 @@ package datasource
 import (
 	"context"
 	"time"
+
+	"ta-watcher/internal/market"
 )
 
 // Timeframe 时间框架
-type Timeframe string
+type Timeframe = market.Timeframe
 
 const (
-	Timeframe1m  Timeframe = "1m"
-	Timeframe3m  Timeframe = "3m"
-	Timeframe5m  Timeframe = "5m"
-	Timeframe15m Timeframe = "15m"
-	Timeframe30m Timeframe = "30m"
-	Timeframe1h  Timeframe = "1h"
-	Timeframe2h  Timeframe = "2h"
-	Timeframe4h  Timeframe = "4h"
-	Timeframe6h  Timeframe = "6h"
-	Timeframe8h  Timeframe = "8h"
-	Timeframe12h Timeframe = "12h"
-	Timeframe1d  Timeframe = "1d"
-	Timeframe3d  Timeframe = "3d"
-	Timeframe1w  Timeframe = "1w"
-	Timeframe1M  Timeframe = "1M"
+	Timeframe1m  = market.Timeframe1m
+	Timeframe3m  = market.Timeframe3m
+	Timeframe5m  = market.Timeframe5m
+	Timeframe15m = market.Timeframe15m
+	Timeframe30m = market.Timeframe30m
+	Timeframe1h  = market.Timeframe1h
+	Timeframe2h  = market.Timeframe2h
+	Timeframe4h  = market.Timeframe4h
+	Timeframe6h  = market.Timeframe6h
+	Timeframe8h  = market.Timeframe8h
+	Timeframe12h = market.Timeframe12h
+	Timeframe1d  = market.Timeframe1d
+	Timeframe3d  = market.Timeframe3d
+	Timeframe1w  = market.Timeframe1w
+	Timeframe1M  = market.Timeframe1M
 )
 
 // Kline K线数据
-type Kline struct {
-	Symbol    string    `json:"symbol"`
-	OpenTime  time.Time `json:"open_time"`
-	CloseTime time.Time `json:"close_time"`
-	Open      float64   `json:"open"`
-	High      float64   `json:"high"`
-	Low       float64   `json:"low"`
-	Close     float64   `json:"close"`
-	Volume    float64   `json:"volume"`
-}
+type Kline = market.Kline
 
 // DataSource 数据源接口
 type DataSource interface {
@@ -54,3 +47,35 @@ type DataSource interface {
 	// Name 返回数据源名称
 	Name() string
 }
+
+// Unwrapper 包装其他数据源的装饰器（缓存、主备切换等）
+type Unwrapper interface {
+	// Unwrap 返回被包装的数据源
+	Unwrap() []DataSource
+}
+
+// markClosed 根据收盘时间标记K线是否已收盘
+func markClosed(klines []*Kline, now time.Time) {
+	for _, k := range klines {
+		k.IsClosed = k.CloseTime.Before(now)
+	}
+}
+
+// ClosedOnly 过滤掉尚未收盘的K线（通常只有最后一根）
+func ClosedOnly(klines []*Kline) []*Kline {
+	closed := make([]*Kline, 0, len(klines))
+	for _, k := range klines {
+		if k.IsClosed {
+			closed = append(closed, k)
+		}
+	}
+	return closed
+}
+
+// closeTimeFor 根据开盘时间和时间框架计算收盘时间（与Binance一致，为下一根开盘前1毫秒）
+func closeTimeFor(openTime time.Time, tf Timeframe) time.Time {
+	if tf == Timeframe1M {
+		return openTime.AddDate(0, 1, 0).Add(-time.Millisecond)
+	}
+	return openTime.Add(tf.Duration() - time.Millisecond)
+}
@@ -0,0 +1,263 @@
+// Package httpreplay 提供录制/回放 HTTP 交互的 http.RoundTripper，
+// 用于在离线环境下确定性地测试交易所和行情接口客户端
+package httpreplay
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// record 为 true 时访问真实接口并覆盖磁带文件：go test ./... -args -record
+var record = flag.Bool("record", false, "re-record HTTP cassettes against live APIs")
+
+// Mode 运行模式
+type Mode int
+
+const (
+	ModeReplay Mode = iota // 只从磁带回放，找不到匹配的交互时报错
+	ModeRecord             // 请求真实接口并录制
+)
+
+// Cassette 磁带文件格式（JSON）
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction 一次请求及其响应
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request 录制的请求
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+// Response 录制的响应
+type Response struct {
+	StatusCode int               `json:"status_code"`
+	Header     map[string]string `json:"header,omitempty"`
+	Body       string            `json:"body"`
+}
+
+// Transport 录制/回放 http.RoundTripper
+type Transport struct {
+	path        string
+	mode        Mode
+	real        http.RoundTripper
+	ignoreQuery map[string]bool
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     map[string]int // 回放时每个请求键已使用的交互数
+}
+
+// New 创建录制/回放传输层
+// 回放模式下 path 必须存在；录制模式下请求经 real（为 nil 时使用 http.DefaultTransport）发出
+func New(path string, mode Mode, real http.RoundTripper) (*Transport, error) {
+	if real == nil {
+		real = http.DefaultTransport
+	}
+	t := &Transport{
+		path:        path,
+		mode:        mode,
+		real:        real,
+		ignoreQuery: make(map[string]bool),
+		used:        make(map[string]int),
+	}
+
+	if mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &t.cassette); err != nil {
+			return nil, fmt.Errorf("corrupted cassette %s: %w", path, err)
+		}
+	}
+
+	return t, nil
+}
+
+// IgnoreQuery 匹配请求时忽略的查询参数（如由当前时间计算的 start/end）
+func (t *Transport) IgnoreQuery(params ...string) *Transport {
+	for _, p := range params {
+		t.ignoreQuery[p] = true
+	}
+	return t
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.mode == ModeRecord {
+		return t.recordRoundTrip(req)
+	}
+	return t.replayRoundTrip(req)
+}
+
+// replayRoundTrip 按请求键顺序返回录制的响应，同一请求被多次调用时重复最后一条
+func (t *Transport) replayRoundTrip(req *http.Request) (*http.Response, error) {
+	key := t.key(req.Method, req.URL)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var matches []Interaction
+	for _, interaction := range t.cassette.Interactions {
+		u, err := url.Parse(interaction.Request.URL)
+		if err != nil {
+			continue
+		}
+		if t.key(interaction.Request.Method, u) == key {
+			matches = append(matches, interaction)
+		}
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("httpreplay: no recorded interaction for %s %s in %s", req.Method, req.URL, t.path)
+	}
+
+	index := t.used[key]
+	if index >= len(matches) {
+		index = len(matches) - 1
+	}
+	t.used[key]++
+
+	return matches[index].Response.toHTTP(req), nil
+}
+
+// recordRoundTrip 请求真实接口并保存交互
+func (t *Transport) recordRoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.real.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	recorded := Response{StatusCode: resp.StatusCode, Body: string(body)}
+	for _, name := range []string{"Content-Type", "Retry-After", "X-Mbx-Used-Weight", "X-Mbx-Used-Weight-1m"} {
+		if value := resp.Header.Get(name); value != "" {
+			if recorded.Header == nil {
+				recorded.Header = make(map[string]string)
+			}
+			recorded.Header[name] = value
+		}
+	}
+
+	t.mu.Lock()
+	t.cassette.Interactions = append(t.cassette.Interactions, Interaction{
+		Request:  Request{Method: req.Method, URL: req.URL.String()},
+		Response: recorded,
+	})
+	t.mu.Unlock()
+
+	return recorded.toHTTP(req), nil
+}
+
+// Save 录制模式下将交互写入磁带文件，回放模式下不做任何事
+func (t *Transport) Save() error {
+	if t.mode != ModeRecord {
+		return nil
+	}
+
+	t.mu.Lock()
+	data, err := json.MarshalIndent(t.cassette, "", "  ")
+	t.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(t.path), 0755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	return os.WriteFile(t.path, append(data, '\n'), 0644)
+}
+
+// key 生成请求匹配键：方法 + 主机 + 路径 + 排序后的查询参数（去掉忽略的参数）
+func (t *Transport) key(method string, u *url.URL) string {
+	query := u.Query()
+	names := make([]string, 0, len(query))
+	for name := range query {
+		if !t.ignoreQuery[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(method + " " + u.Host + u.Path)
+	for i, name := range names {
+		if i == 0 {
+			b.WriteString("?")
+		} else {
+			b.WriteString("&")
+		}
+		b.WriteString(name + "=" + strings.Join(query[name], ","))
+	}
+	return b.String()
+}
+
+// toHTTP 转换为 http.Response
+func (r Response) toHTTP(req *http.Request) *http.Response {
+	header := make(http.Header)
+	for name, value := range r.Header {
+		header.Set(name, value)
+	}
+	return &http.Response{
+		StatusCode:    r.StatusCode,
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewBufferString(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
+
+// NewForTest 为测试创建传输层，磁带位于 testdata/cassettes/<name>.json
+// 带 -record 运行时录制并在测试结束时保存，否则回放；磁带缺失时跳过测试
+func NewForTest(tb testing.TB, name string) *Transport {
+	tb.Helper()
+
+	path := filepath.Join("testdata", "cassettes", name+".json")
+	mode := ModeReplay
+	if *record {
+		mode = ModeRecord
+	}
+
+	t, err := New(path, mode, nil)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			tb.Skipf("cassette %s not found, run with -args -record to create it", path)
+		}
+		tb.Fatalf("httpreplay: %v", err)
+	}
+
+	tb.Cleanup(func() {
+		if err := t.Save(); err != nil {
+			tb.Errorf("httpreplay: failed to save cassette: %v", err)
+		}
+	})
+	return t
+}
@@ -0,0 +1,86 @@
+package httpreplay
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestTransport_RecordAndReplay(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("symbol") == "BAD" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		io.WriteString(w, `{"call":`+strconv.Itoa(calls)+`}`)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassettes", "test.json")
+
+	recorder, err := New(path, ModeRecord, nil)
+	if err != nil {
+		t.Fatalf("New(record) error = %v", err)
+	}
+	client := &http.Client{Transport: recorder}
+	for _, query := range []string{"?symbol=BTC&start=1", "?start=2&symbol=BTC", "?symbol=BAD"} {
+		resp, err := client.Get(server.URL + "/api" + query)
+		if err != nil {
+			t.Fatalf("record request error = %v", err)
+		}
+		resp.Body.Close()
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	player, err := New(path, ModeReplay, nil)
+	if err != nil {
+		t.Fatalf("New(replay) error = %v", err)
+	}
+	player.IgnoreQuery("start")
+	client = &http.Client{Transport: player}
+
+	get := func(query string) (int, string) {
+		t.Helper()
+		resp, err := client.Get(server.URL + "/api" + query)
+		if err != nil {
+			t.Fatalf("replay request error = %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	// 忽略 start 后两次请求匹配同一个键，按录制顺序回放，之后重复最后一条
+	if _, body := get("?symbol=BTC&start=99"); body != `{"call":1}` {
+		t.Errorf("first replay body = %s", body)
+	}
+	if _, body := get("?symbol=BTC&start=100"); body != `{"call":2}` {
+		t.Errorf("second replay body = %s", body)
+	}
+	if _, body := get("?symbol=BTC"); body != `{"call":2}` {
+		t.Errorf("repeated replay body = %s", body)
+	}
+	if status, _ := get("?symbol=BAD"); status != http.StatusBadRequest {
+		t.Errorf("replay status = %d, want 400", status)
+	}
+	if calls != 3 {
+		t.Errorf("replay should not reach the server, got %d calls", calls)
+	}
+
+	if _, err := client.Get(server.URL + "/other"); err == nil {
+		t.Error("expected error for unrecorded request")
+	}
+}
+
+func TestNew_MissingCassette(t *testing.T) {
+	if _, err := New(filepath.Join(t.TempDir(), "missing.json"), ModeReplay, nil); err == nil {
+		t.Error("expected error for missing cassette in replay mode")
+	}
+}
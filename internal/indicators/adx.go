@@ -0,0 +1,175 @@
+package indicators
+
+import (
+	"errors"
+	"math"
+)
+
+// ADXResult 平均趋向指数计算结果
+// 三个序列长度相同，第一个值对应第 2×period 根K线（ADX 需要先累积 period 个 DX）
+type ADXResult struct {
+	ADX     []float64 // 平均趋向指数 (0-100)，衡量趋势强度，不区分方向
+	PlusDI  []float64 // 正向指标 +DI (0-100)
+	MinusDI []float64 // 负向指标 -DI (0-100)
+	Period  int       // 计算周期
+}
+
+// ADX默认参数
+const (
+	DefaultADXPeriod      = 14   // 默认ADX周期
+	DefaultTrendThreshold = 25.0 // 默认趋势强度阈值，ADX 高于该值视为有趋势
+)
+
+// CalculateADX 计算威尔德平均趋向指数及 +DI/-DI
+// high/low/close: 最高价、最低价和收盘价序列（长度必须相同）
+// period: 计算周期，通常为14
+func CalculateADX(high, low, close []float64, period int) (*ADXResult, error) {
+	if len(high) != len(close) || len(low) != len(close) {
+		return nil, errors.New("最高价、最低价和收盘价序列长度不一致")
+	}
+
+	if period <= 0 {
+		return nil, errors.New("ADX周期必须大于0")
+	}
+
+	if len(close) < 2*period {
+		return nil, errors.New("价格数据不足，无法计算ADX指标")
+	}
+
+	trueRanges := calculateTrueRanges(high, low, close)
+
+	// 计算趋向变动：只有幅度更大的一方计入
+	plusDM := make([]float64, len(trueRanges))
+	minusDM := make([]float64, len(trueRanges))
+	for i := 1; i < len(close); i++ {
+		up := high[i] - high[i-1]
+		down := low[i-1] - low[i]
+		if up > down && up > 0 {
+			plusDM[i-1] = up
+		}
+		if down > up && down > 0 {
+			minusDM[i-1] = down
+		}
+	}
+
+	// 威尔德平滑：首值为前 period 个值之和，之后 S = S - S/period + 当前值
+	var smoothTR, smoothPlus, smoothMinus float64
+	for i := 0; i < period; i++ {
+		smoothTR += trueRanges[i]
+		smoothPlus += plusDM[i]
+		smoothMinus += minusDM[i]
+	}
+
+	var plusDIs, minusDIs, dxs []float64
+	for i := period - 1; i < len(trueRanges); i++ {
+		if i >= period {
+			smoothTR = smoothTR - smoothTR/float64(period) + trueRanges[i]
+			smoothPlus = smoothPlus - smoothPlus/float64(period) + plusDM[i]
+			smoothMinus = smoothMinus - smoothMinus/float64(period) + minusDM[i]
+		}
+
+		var plusDI, minusDI, dx float64
+		if smoothTR > 0 {
+			plusDI = 100 * smoothPlus / smoothTR
+			minusDI = 100 * smoothMinus / smoothTR
+		}
+		if sum := plusDI + minusDI; sum > 0 {
+			dx = 100 * math.Abs(plusDI-minusDI) / sum
+		}
+		plusDIs = append(plusDIs, plusDI)
+		minusDIs = append(minusDIs, minusDI)
+		dxs = append(dxs, dx)
+	}
+
+	// 第一个ADX为前 period 个 DX 的平均值，之后使用威尔德平滑
+	adx := 0.0
+	for _, dx := range dxs[:period] {
+		adx += dx
+	}
+	adx /= float64(period)
+
+	adxValues := []float64{adx}
+	for _, dx := range dxs[period:] {
+		adx = (adx*float64(period-1) + dx) / float64(period)
+		adxValues = append(adxValues, adx)
+	}
+
+	// 对齐 +DI/-DI 到 ADX 的起始位置
+	offset := len(plusDIs) - len(adxValues)
+	return &ADXResult{
+		ADX:     adxValues,
+		PlusDI:  plusDIs[offset:],
+		MinusDI: minusDIs[offset:],
+		Period:  period,
+	}, nil
+}
+
+// GetLatest 获取最新的ADX、+DI和-DI值
+func (a *ADXResult) GetLatest() (adx, plusDI, minusDI float64) {
+	if len(a.ADX) == 0 {
+		return 0, 0, 0
+	}
+
+	idx := len(a.ADX) - 1
+	return a.ADX[idx], a.PlusDI[idx], a.MinusDI[idx]
+}
+
+// GetLatestN 获取最新的N个ADX、+DI和-DI值
+func (a *ADXResult) GetLatestN(n int) (adx, plusDI, minusDI []float64) {
+	if n <= 0 || len(a.ADX) == 0 {
+		return []float64{}, []float64{}, []float64{}
+	}
+
+	start := int(math.Max(0, float64(len(a.ADX)-n)))
+	return a.ADX[start:], a.PlusDI[start:], a.MinusDI[start:]
+}
+
+// IsTrending 检查ADX是否高于阈值（存在明显趋势）
+func (a *ADXResult) IsTrending(threshold float64) bool {
+	if len(a.ADX) == 0 {
+		return false
+	}
+	return a.ADX[len(a.ADX)-1] >= threshold
+}
+
+// IsBullishCross 检查 +DI 是否上穿 -DI
+func (a *ADXResult) IsBullishCross() bool {
+	if len(a.ADX) < 2 {
+		return false
+	}
+	idx := len(a.ADX) - 1
+	return a.PlusDI[idx] > a.MinusDI[idx] && a.PlusDI[idx-1] <= a.MinusDI[idx-1]
+}
+
+// IsBearishCross 检查 +DI 是否下穿 -DI
+func (a *ADXResult) IsBearishCross() bool {
+	if len(a.ADX) < 2 {
+		return false
+	}
+	idx := len(a.ADX) - 1
+	return a.PlusDI[idx] < a.MinusDI[idx] && a.PlusDI[idx-1] >= a.MinusDI[idx-1]
+}
+
+// GetTrendStrength 获取趋势强度描述
+func (a *ADXResult) GetTrendStrength() string {
+	if len(a.ADX) == 0 {
+		return "无数据"
+	}
+
+	adx, plusDI, minusDI := a.GetLatest()
+	direction := "上涨"
+	if minusDI > plusDI {
+		direction = "下跌"
+	}
+
+	switch {
+	case adx >= 50:
+		return "极强" + direction + "趋势"
+	case adx >= DefaultTrendThreshold:
+		return "强" + direction + "趋势"
+	case adx >= 20:
+		return "趋势形成中"
+	default:
+		return "无明显趋势"
+	}
+}
@@ -0,0 +1,103 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateADX(t *testing.T) {
+	tests := []struct {
+		name    string
+		high    []float64
+		low     []float64
+		close   []float64
+		period  int
+		wantErr bool
+	}{
+		{name: "正常计算ADX-14周期", high: refHigh, low: refLow, close: refClose, period: 14},
+		{name: "序列长度不一致", high: refHigh, low: refLow[:20], close: refClose, period: 14, wantErr: true},
+		{name: "价格数据不足", high: refHigh[:27], low: refLow[:27], close: refClose[:27], period: 14, wantErr: true},
+		{name: "周期为0", high: refHigh, low: refLow, close: refClose, period: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CalculateADX(tt.high, tt.low, tt.close, tt.period)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("CalculateADX() 期望错误，但没有返回错误")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CalculateADX() 意外错误 = %v", err)
+			}
+
+			expectedLength := len(tt.close) - 2*tt.period + 1
+			if len(result.ADX) != expectedLength || len(result.PlusDI) != expectedLength || len(result.MinusDI) != expectedLength {
+				t.Errorf("CalculateADX() 结果长度 = %v/%v/%v, 期望 %v", len(result.ADX), len(result.PlusDI), len(result.MinusDI), expectedLength)
+			}
+		})
+	}
+}
+
+func TestADXAccuracy(t *testing.T) {
+	result, err := CalculateADX(refHigh, refLow, refClose, 14)
+	if err != nil {
+		t.Fatalf("CalculateADX() 意外错误 = %v", err)
+	}
+
+	wantADX := []float64{28.597875, 29.549970, 30.508815}
+	for i, want := range wantADX {
+		if math.Abs(result.ADX[i]-want) > 1e-5 {
+			t.Errorf("ADX[%d] = %v, 期望 %v", i, result.ADX[i], want)
+		}
+	}
+
+	adx, plusDI, minusDI := result.GetLatest()
+	if math.Abs(plusDI-14.687860) > 1e-5 || math.Abs(minusDI-36.824813) > 1e-5 || adx != result.ADX[2] {
+		t.Errorf("GetLatest() = %v, %v, %v", adx, plusDI, minusDI)
+	}
+	if !result.IsTrending(DefaultTrendThreshold) || result.GetTrendStrength() != "强下跌趋势" {
+		t.Errorf("趋势判断错误: ADX %v, %s", adx, result.GetTrendStrength())
+	}
+
+	// 持续上涨时 -DI 为0，DX 恒为100
+	n := 30
+	high, low, close := make([]float64, n), make([]float64, n), make([]float64, n)
+	for i := 0; i < n; i++ {
+		close[i] = 100 + float64(i)
+		high[i] = close[i] + 0.5
+		low[i] = close[i] - 0.5
+	}
+	rising, _ := CalculateADX(high, low, close, 5)
+	adx, plusDI, minusDI = rising.GetLatest()
+	if math.Abs(adx-100) > 1e-9 || minusDI != 0 || plusDI <= 0 {
+		t.Errorf("持续上涨 GetLatest() = %v, %v, %v", adx, plusDI, minusDI)
+	}
+	if rising.GetTrendStrength() != "极强上涨趋势" {
+		t.Errorf("GetTrendStrength() = %s", rising.GetTrendStrength())
+	}
+}
+
+func TestADXResult_Cross(t *testing.T) {
+	bullish := &ADXResult{ADX: []float64{20, 22}, PlusDI: []float64{18, 25}, MinusDI: []float64{20, 19}}
+	if !bullish.IsBullishCross() || bullish.IsBearishCross() {
+		t.Error("+DI 上穿 -DI 应判定为看涨交叉")
+	}
+
+	bearish := &ADXResult{ADX: []float64{20, 22}, PlusDI: []float64{22, 17}, MinusDI: []float64{20, 21}}
+	if !bearish.IsBearishCross() || bearish.IsBullishCross() {
+		t.Error("+DI 下穿 -DI 应判定为看跌交叉")
+	}
+
+	empty := &ADXResult{}
+	if empty.IsBullishCross() || empty.IsTrending(0) || empty.GetTrendStrength() != "无数据" {
+		t.Error("空结果不应产生信号")
+	}
+	if a, p, m := empty.GetLatestN(3); len(a) != 0 || len(p) != 0 || len(m) != 0 {
+		t.Error("空结果 GetLatestN() 应返回空切片")
+	}
+}
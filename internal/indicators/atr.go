@@ -0,0 +1,90 @@
+package indicators
+
+import (
+	"errors"
+	"math"
+)
+
+// ATRResult 平均真实波幅计算结果
+type ATRResult struct {
+	Values []float64 // ATR 值序列，第一个值对应第 period 根K线（真实波幅需要前一根收盘价）
+	Period int       // 计算周期
+}
+
+// DefaultATRPeriod 默认ATR周期
+const DefaultATRPeriod = 14
+
+// CalculateATR 计算威尔德平均真实波幅
+// high/low/close: 最高价、最低价和收盘价序列（长度必须相同）
+// period: ATR周期，通常为14
+func CalculateATR(high, low, close []float64, period int) (*ATRResult, error) {
+	if len(high) != len(close) || len(low) != len(close) {
+		return nil, errors.New("最高价、最低价和收盘价序列长度不一致")
+	}
+
+	if period <= 0 {
+		return nil, errors.New("ATR周期必须大于0")
+	}
+
+	if len(close) < period+1 {
+		return nil, errors.New("价格数据不足，无法计算ATR指标")
+	}
+
+	trueRanges := calculateTrueRanges(high, low, close)
+
+	// 第一个ATR使用简单平均
+	atr := 0.0
+	for _, tr := range trueRanges[:period] {
+		atr += tr
+	}
+	atr /= float64(period)
+
+	// 后续使用威尔德平滑
+	values := []float64{atr}
+	for _, tr := range trueRanges[period:] {
+		atr = (atr*float64(period-1) + tr) / float64(period)
+		values = append(values, atr)
+	}
+
+	return &ATRResult{
+		Values: values,
+		Period: period,
+	}, nil
+}
+
+// calculateTrueRanges 计算真实波幅序列，第 i 个值对应第 i+1 根K线
+// 真实波幅 = max(最高价 - 最低价, |最高价 - 前收盘价|, |最低价 - 前收盘价|)
+func calculateTrueRanges(high, low, close []float64) []float64 {
+	trueRanges := make([]float64, 0, len(close)-1)
+	for i := 1; i < len(close); i++ {
+		tr := math.Max(high[i]-low[i], math.Max(math.Abs(high[i]-close[i-1]), math.Abs(low[i]-close[i-1])))
+		trueRanges = append(trueRanges, tr)
+	}
+	return trueRanges
+}
+
+// GetLatest 获取最新的ATR值
+func (a *ATRResult) GetLatest() float64 {
+	if len(a.Values) == 0 {
+		return 0
+	}
+	return a.Values[len(a.Values)-1]
+}
+
+// GetLatestN 获取最新的N个ATR值
+func (a *ATRResult) GetLatestN(n int) []float64 {
+	if n <= 0 || len(a.Values) == 0 {
+		return []float64{}
+	}
+
+	start := int(math.Max(0, float64(len(a.Values)-n)))
+	return a.Values[start:]
+}
+
+// GetPercent 获取最新ATR占价格的百分比，用于比较不同价位资产的波动
+func (a *ATRResult) GetPercent(price float64) float64 {
+	if price == 0 {
+		return 0
+	}
+	return a.GetLatest() / price * 100
+}
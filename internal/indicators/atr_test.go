@@ -0,0 +1,109 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+// 参考数据：30根K线的最高价、最低价和收盘价
+var (
+	refHigh = []float64{
+		48.70, 48.72, 48.90, 48.87, 48.82, 49.05, 49.20, 49.35, 49.92, 50.19,
+		50.12, 49.66, 49.88, 50.19, 50.36, 50.57, 50.65, 50.43, 49.63, 50.33,
+		50.29, 50.17, 49.32, 48.50, 48.32, 46.80, 47.80, 48.39, 48.66, 48.79,
+	}
+	refLow = []float64{
+		47.79, 48.14, 48.39, 48.37, 48.24, 48.64, 48.94, 48.86, 49.50, 49.87,
+		49.20, 48.90, 49.43, 49.73, 49.26, 50.09, 50.30, 49.21, 48.98, 49.61,
+		49.20, 49.43, 48.08, 47.64, 41.55, 44.28, 47.31, 47.20, 47.90, 47.73,
+	}
+	refClose = []float64{
+		48.16, 48.61, 48.75, 48.63, 48.74, 49.03, 49.07, 49.32, 49.91, 50.13,
+		49.53, 49.50, 49.75, 50.03, 50.31, 50.52, 50.41, 49.34, 49.37, 50.23,
+		49.24, 49.93, 48.43, 48.18, 46.57, 45.41, 47.77, 47.72, 48.62, 47.85,
+	}
+)
+
+func TestCalculateATR(t *testing.T) {
+	tests := []struct {
+		name    string
+		high    []float64
+		low     []float64
+		close   []float64
+		period  int
+		wantErr bool
+	}{
+		{name: "正常计算ATR-14周期", high: refHigh, low: refLow, close: refClose, period: 14},
+		{name: "序列长度不一致", high: refHigh[:10], low: refLow, close: refClose, period: 14, wantErr: true},
+		{name: "价格数据不足", high: refHigh[:14], low: refLow[:14], close: refClose[:14], period: 14, wantErr: true},
+		{name: "周期为0", high: refHigh, low: refLow, close: refClose, period: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CalculateATR(tt.high, tt.low, tt.close, tt.period)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("CalculateATR() 期望错误，但没有返回错误")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CalculateATR() 意外错误 = %v", err)
+			}
+
+			expectedLength := len(tt.close) - tt.period
+			if len(result.Values) != expectedLength {
+				t.Errorf("CalculateATR() 结果长度 = %v, 期望 %v", len(result.Values), expectedLength)
+			}
+		})
+	}
+}
+
+func TestATRAccuracy(t *testing.T) {
+	result, err := CalculateATR(refHigh, refLow, refClose, 14)
+	if err != nil {
+		t.Fatalf("CalculateATR() 意外错误 = %v", err)
+	}
+
+	// 参考值：首值为前14个真实波幅的平均值，之后按威尔德平滑
+	if math.Abs(result.Values[0]-0.567857) > 1e-6 {
+		t.Errorf("第一个ATR = %v, 期望 0.567857", result.Values[0])
+	}
+	if math.Abs(result.GetLatest()-1.307983) > 1e-6 {
+		t.Errorf("最新ATR = %v, 期望 1.307983", result.GetLatest())
+	}
+	if math.Abs(result.GetPercent(refClose[len(refClose)-1])-1.307983/47.85*100) > 1e-4 {
+		t.Errorf("GetPercent() = %v", result.GetPercent(47.85))
+	}
+
+	// 没有跳空时ATR等于固定的K线振幅
+	high := []float64{11, 12, 13, 14, 15}
+	low := []float64{9, 10, 11, 12, 13}
+	close := []float64{10, 11, 12, 13, 14}
+	constant, _ := CalculateATR(high, low, close, 2)
+	for i, v := range constant.Values {
+		if math.Abs(v-2) > 1e-9 {
+			t.Errorf("第%d个ATR = %v, 期望 2", i, v)
+		}
+	}
+}
+
+func TestATRResult_GetLatestN(t *testing.T) {
+	result := &ATRResult{Values: []float64{1, 2, 3}, Period: 2}
+
+	if got := result.GetLatestN(2); len(got) != 2 || got[0] != 2 {
+		t.Errorf("GetLatestN(2) = %v", got)
+	}
+	if got := result.GetLatestN(5); len(got) != 3 {
+		t.Errorf("GetLatestN(5) = %v", got)
+	}
+	if got := (&ATRResult{}).GetLatestN(2); len(got) != 0 {
+		t.Errorf("空结果 GetLatestN() = %v", got)
+	}
+	if (&ATRResult{}).GetLatest() != 0 || result.GetPercent(0) != 0 {
+		t.Error("空结果或零价格应返回0")
+	}
+}
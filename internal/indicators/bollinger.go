@@ -0,0 +1,153 @@
+package indicators
+
+import (
+	"errors"
+	"math"
+)
+
+// BollingerResult 布林带计算结果
+type BollingerResult struct {
+	Upper     []float64 // 上轨：中轨 + 倍数 × 标准差
+	Middle    []float64 // 中轨：简单移动平均
+	Lower     []float64 // 下轨：中轨 - 倍数 × 标准差
+	PercentB  []float64 // %B：价格在上下轨之间的位置，0 为下轨，1 为上轨
+	Bandwidth []float64 // 带宽：(上轨 - 下轨) / 中轨
+	Period    int       // 计算周期
+	StdDev    float64   // 标准差倍数
+}
+
+// 布林带默认参数
+const (
+	DefaultBollingerPeriod = 20  // 默认布林带周期
+	DefaultBollingerStdDev = 2.0 // 默认标准差倍数
+)
+
+// CalculateBollingerBands 计算布林带
+// prices: 价格序列（通常是收盘价）
+// period: 移动平均周期，通常为20
+// stdDev: 标准差倍数，通常为2
+func CalculateBollingerBands(prices []float64, period int, stdDev float64) (*BollingerResult, error) {
+	if period <= 0 {
+		return nil, errors.New("布林带周期必须大于0")
+	}
+
+	if len(prices) < period {
+		return nil, errors.New("价格数据不足，无法计算布林带")
+	}
+
+	if stdDev <= 0 {
+		return nil, errors.New("标准差倍数必须大于0")
+	}
+
+	sma, err := CalculateSMA(prices, period)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(sma.Values)
+	result := &BollingerResult{
+		Upper:     make([]float64, n),
+		Middle:    sma.Values,
+		Lower:     make([]float64, n),
+		PercentB:  make([]float64, n),
+		Bandwidth: make([]float64, n),
+		Period:    period,
+		StdDev:    stdDev,
+	}
+
+	for i, middle := range sma.Values {
+		// 使用总体标准差（与主流行情软件一致）
+		window := prices[i : i+period]
+		variance := 0.0
+		for _, p := range window {
+			variance += (p - middle) * (p - middle)
+		}
+		deviation := math.Sqrt(variance / float64(period))
+
+		upper := middle + stdDev*deviation
+		lower := middle - stdDev*deviation
+		result.Upper[i] = upper
+		result.Lower[i] = lower
+
+		// 价格没有波动时上下轨重合，%B 取中间位置
+		price := prices[i+period-1]
+		if width := upper - lower; width > 0 {
+			result.PercentB[i] = (price - lower) / width
+		} else {
+			result.PercentB[i] = 0.5
+		}
+		if middle != 0 {
+			result.Bandwidth[i] = (upper - lower) / middle
+		}
+	}
+
+	return result, nil
+}
+
+// CalculateDefaultBollingerBands 使用默认参数计算布林带
+func CalculateDefaultBollingerBands(prices []float64) (*BollingerResult, error) {
+	return CalculateBollingerBands(prices, DefaultBollingerPeriod, DefaultBollingerStdDev)
+}
+
+// GetLatest 获取最新的上轨、中轨和下轨
+func (b *BollingerResult) GetLatest() (upper, middle, lower float64) {
+	if len(b.Middle) == 0 {
+		return 0, 0, 0
+	}
+
+	idx := len(b.Middle) - 1
+	return b.Upper[idx], b.Middle[idx], b.Lower[idx]
+}
+
+// GetLatestN 获取最新的N个上轨、中轨和下轨值
+func (b *BollingerResult) GetLatestN(n int) (upper, middle, lower []float64) {
+	if n <= 0 || len(b.Middle) == 0 {
+		return []float64{}, []float64{}, []float64{}
+	}
+
+	start := int(math.Max(0, float64(len(b.Middle)-n)))
+	return b.Upper[start:], b.Middle[start:], b.Lower[start:]
+}
+
+// GetLatestPercentB 获取最新的 %B 值
+func (b *BollingerResult) GetLatestPercentB() float64 {
+	if len(b.PercentB) == 0 {
+		return 0
+	}
+	return b.PercentB[len(b.PercentB)-1]
+}
+
+// GetLatestBandwidth 获取最新的带宽值
+func (b *BollingerResult) GetLatestBandwidth() float64 {
+	if len(b.Bandwidth) == 0 {
+		return 0
+	}
+	return b.Bandwidth[len(b.Bandwidth)-1]
+}
+
+// IsBandwidthSqueeze 检查带宽是否处于最近 lookback 个周期的最低值（波动收缩，常出现在突破之前）
+func (b *BollingerResult) IsBandwidthSqueeze(lookback int) bool {
+	if lookback < 2 || len(b.Bandwidth) < lookback {
+		return false
+	}
+
+	recent := b.Bandwidth[len(b.Bandwidth)-lookback:]
+	latest := recent[len(recent)-1]
+	for _, bw := range recent[:len(recent)-1] {
+		if bw < latest {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSqueeze 检查布林带是否收缩到肯特纳通道之内（TTM Squeeze）
+func IsSqueeze(bollinger *BollingerResult, keltner *KeltnerResult) bool {
+	if len(bollinger.Middle) == 0 || len(keltner.Middle) == 0 {
+		return false
+	}
+
+	bbUpper, _, bbLower := bollinger.GetLatest()
+	kcUpper, _, kcLower := keltner.GetLatest()
+	return bbUpper < kcUpper && bbLower > kcLower
+}
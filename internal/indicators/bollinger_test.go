@@ -0,0 +1,127 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateBollingerBands(t *testing.T) {
+	tests := []struct {
+		name    string
+		prices  []float64
+		period  int
+		stdDev  float64
+		wantErr bool
+	}{
+		{name: "正常计算布林带-20周期", prices: testPrices, period: 20, stdDev: 2},
+		{name: "价格数据不足", prices: []float64{1.0, 2.0}, period: 5, stdDev: 2, wantErr: true},
+		{name: "周期为0", prices: testPrices, period: 0, stdDev: 2, wantErr: true},
+		{name: "标准差倍数为0", prices: testPrices, period: 20, stdDev: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CalculateBollingerBands(tt.prices, tt.period, tt.stdDev)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("CalculateBollingerBands() 期望错误，但没有返回错误")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CalculateBollingerBands() 意外错误 = %v", err)
+			}
+
+			expectedLength := len(tt.prices) - tt.period + 1
+			if len(result.Upper) != expectedLength || len(result.PercentB) != expectedLength || len(result.Bandwidth) != expectedLength {
+				t.Errorf("CalculateBollingerBands() 结果长度 = %v, 期望 %v", len(result.Upper), expectedLength)
+			}
+
+			for i := range result.Middle {
+				if result.Upper[i] < result.Middle[i] || result.Lower[i] > result.Middle[i] {
+					t.Errorf("第%d个值上轨/中轨/下轨顺序错误: %v %v %v", i, result.Upper[i], result.Middle[i], result.Lower[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBollingerAccuracy(t *testing.T) {
+	// 周期3：第一个窗口 [1,2,3]，均值2，总体标准差 sqrt(2/3)
+	result, err := CalculateBollingerBands([]float64{1, 2, 3, 4, 5}, 3, 2)
+	if err != nil {
+		t.Fatalf("CalculateBollingerBands() 意外错误 = %v", err)
+	}
+
+	sd := math.Sqrt(2.0 / 3.0)
+	if math.Abs(result.Middle[0]-2) > 1e-9 || math.Abs(result.Upper[0]-(2+2*sd)) > 1e-9 || math.Abs(result.Lower[0]-(2-2*sd)) > 1e-9 {
+		t.Errorf("第一个布林带 = %v/%v/%v", result.Upper[0], result.Middle[0], result.Lower[0])
+	}
+
+	// %B = (3 - 下轨) / (上轨 - 下轨)，带宽 = 4σ / 2
+	if want := (3 - (2 - 2*sd)) / (4 * sd); math.Abs(result.PercentB[0]-want) > 1e-9 {
+		t.Errorf("PercentB[0] = %v, 期望 %v", result.PercentB[0], want)
+	}
+	if want := 4 * sd / 2; math.Abs(result.Bandwidth[0]-want) > 1e-9 {
+		t.Errorf("Bandwidth[0] = %v, 期望 %v", result.Bandwidth[0], want)
+	}
+
+	// 价格不变时上下轨重合
+	flat, _ := CalculateBollingerBands([]float64{5, 5, 5}, 3, 2)
+	if flat.GetLatestPercentB() != 0.5 || flat.GetLatestBandwidth() != 0 {
+		t.Errorf("价格不变时 %%B = %v, 带宽 = %v", flat.GetLatestPercentB(), flat.GetLatestBandwidth())
+	}
+}
+
+func TestBollingerResult_GetLatest(t *testing.T) {
+	result, _ := CalculateBollingerBands([]float64{1, 2, 3, 4, 5}, 3, 2)
+
+	upper, middle, lower := result.GetLatest()
+	if middle != 4 || upper != result.Upper[2] || lower != result.Lower[2] {
+		t.Errorf("GetLatest() = %v, %v, %v", upper, middle, lower)
+	}
+
+	u, m, l := result.GetLatestN(2)
+	if len(u) != 2 || len(m) != 2 || len(l) != 2 || m[0] != 3 {
+		t.Errorf("GetLatestN(2) = %v, %v, %v", u, m, l)
+	}
+
+	empty := &BollingerResult{}
+	if u, m, l := empty.GetLatest(); u != 0 || m != 0 || l != 0 {
+		t.Error("空结果 GetLatest() 应返回0")
+	}
+	if u, _, _ := empty.GetLatestN(3); len(u) != 0 {
+		t.Error("空结果 GetLatestN() 应返回空切片")
+	}
+}
+
+func TestBollingerSqueeze(t *testing.T) {
+	// 波动逐渐收缩
+	prices := []float64{100, 110, 95, 108, 97, 105, 99, 103, 100, 102, 101, 101.5}
+	bollinger, err := CalculateBollingerBands(prices, 4, 2)
+	if err != nil {
+		t.Fatalf("CalculateBollingerBands() 意外错误 = %v", err)
+	}
+	if !bollinger.IsBandwidthSqueeze(5) {
+		t.Errorf("带宽应处于最近5个周期最低值: %v", bollinger.Bandwidth)
+	}
+
+	expanding := append(append([]float64{}, prices...), 120)
+	bollinger, _ = CalculateBollingerBands(expanding, 4, 2)
+	if bollinger.IsBandwidthSqueeze(5) {
+		t.Error("放量突破后带宽不应处于低位")
+	}
+
+	// 布林带收缩到肯特纳通道之内
+	bb := &BollingerResult{Upper: []float64{102}, Middle: []float64{100}, Lower: []float64{98}}
+	kc := &KeltnerResult{Upper: []float64{103}, Middle: []float64{100}, Lower: []float64{97}}
+	if !IsSqueeze(bb, kc) {
+		t.Error("布林带位于肯特纳通道之内时应判定为收缩")
+	}
+	kc.Upper[0] = 101
+	if IsSqueeze(bb, kc) {
+		t.Error("布林带上轨超出肯特纳通道时不应判定为收缩")
+	}
+}
@@ -0,0 +1,139 @@
+package indicators
+
+import (
+	"errors"
+	"math"
+)
+
+// CCIResult 顺势指标计算结果
+type CCIResult struct {
+	Values []float64 // CCI 值序列（无固定区间），第一个值对应第 period 根K线
+	Period int       // 计算周期
+}
+
+// CCISignal CCI信号类型
+type CCISignal int
+
+const (
+	CCINeutral CCISignal = iota // 中性
+	CCIBuy                      // 买入信号（超卖）
+	CCISell                     // 卖出信号（超买）
+)
+
+// CCI默认参数
+const (
+	DefaultCCIPeriod     = 20     // 默认CCI周期
+	DefaultCCIOverbought = 100.0  // 默认超买水平
+	DefaultCCIOversold   = -100.0 // 默认超卖水平
+
+	cciConstant = 0.015 // 兰伯特常数，使约70%-80%的值落在 ±100 之间
+)
+
+// CalculateCCI 计算顺势指标
+// CCI = (典型价格 - 典型价格SMA) / (0.015 × 平均绝对偏差)，典型价格 = (最高价 + 最低价 + 收盘价) / 3
+// high/low/close: 最高价、最低价和收盘价序列（长度必须相同）
+// period: 计算周期，通常为20
+func CalculateCCI(high, low, close []float64, period int) (*CCIResult, error) {
+	if len(high) != len(close) || len(low) != len(close) {
+		return nil, errors.New("最高价、最低价和收盘价序列长度不一致")
+	}
+
+	if period <= 0 {
+		return nil, errors.New("CCI周期必须大于0")
+	}
+
+	if len(close) < period {
+		return nil, errors.New("价格数据不足，无法计算CCI指标")
+	}
+
+	typical := make([]float64, len(close))
+	for i := range close {
+		typical[i] = (high[i] + low[i] + close[i]) / 3
+	}
+
+	values := make([]float64, 0, len(close)-period+1)
+	for i := period - 1; i < len(typical); i++ {
+		window := typical[i-period+1 : i+1]
+
+		mean := 0.0
+		for _, tp := range window {
+			mean += tp
+		}
+		mean /= float64(period)
+
+		deviation := 0.0
+		for _, tp := range window {
+			deviation += math.Abs(tp - mean)
+		}
+		deviation /= float64(period)
+
+		// 周期内价格完全不变时偏差为零，CCI 取0
+		if deviation == 0 {
+			values = append(values, 0)
+			continue
+		}
+		values = append(values, (typical[i]-mean)/(cciConstant*deviation))
+	}
+
+	return &CCIResult{
+		Values: values,
+		Period: period,
+	}, nil
+}
+
+// CalculateDefaultCCI 使用默认参数计算CCI
+func CalculateDefaultCCI(high, low, close []float64) (*CCIResult, error) {
+	return CalculateCCI(high, low, close, DefaultCCIPeriod)
+}
+
+// GetLatest 获取最新的CCI值
+func (c *CCIResult) GetLatest() float64 {
+	if len(c.Values) == 0 {
+		return 0
+	}
+	return c.Values[len(c.Values)-1]
+}
+
+// GetLatestN 获取最新的N个CCI值
+func (c *CCIResult) GetLatestN(n int) []float64 {
+	if n <= 0 || len(c.Values) == 0 {
+		return []float64{}
+	}
+
+	start := int(math.Max(0, float64(len(c.Values)-n)))
+	return c.Values[start:]
+}
+
+// GetSignal 根据CCI值获取交易信号
+func (c *CCIResult) GetSignal(overboughtLevel, oversoldLevel float64) CCISignal {
+	if len(c.Values) == 0 {
+		return CCINeutral
+	}
+
+	latest := c.GetLatest()
+
+	if latest >= overboughtLevel {
+		return CCISell // 超买，考虑卖出
+	} else if latest <= oversoldLevel {
+		return CCIBuy // 超卖，考虑买入
+	}
+
+	return CCINeutral
+}
+
+// GetDefaultSignal 使用默认阈值获取交易信号
+func (c *CCIResult) GetDefaultSignal() CCISignal {
+	return c.GetSignal(DefaultCCIOverbought, DefaultCCIOversold)
+}
+
+// CCISignalToString 将CCI信号转换为字符串
+func CCISignalToString(signal CCISignal) string {
+	switch signal {
+	case CCIBuy:
+		return "买入信号"
+	case CCISell:
+		return "卖出信号"
+	default:
+		return "中性"
+	}
+}
@@ -0,0 +1,90 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateCCI(t *testing.T) {
+	tests := []struct {
+		name    string
+		high    []float64
+		low     []float64
+		close   []float64
+		period  int
+		wantErr bool
+	}{
+		{name: "正常计算CCI-20周期", high: refHigh, low: refLow, close: refClose, period: 20},
+		{name: "序列长度不一致", high: refHigh, low: refLow, close: refClose[:10], period: 20, wantErr: true},
+		{name: "价格数据不足", high: refHigh[:19], low: refLow[:19], close: refClose[:19], period: 20, wantErr: true},
+		{name: "周期为0", high: refHigh, low: refLow, close: refClose, period: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CalculateCCI(tt.high, tt.low, tt.close, tt.period)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("CalculateCCI() 期望错误，但没有返回错误")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CalculateCCI() 意外错误 = %v", err)
+			}
+
+			expectedLength := len(tt.close) - tt.period + 1
+			if len(result.Values) != expectedLength {
+				t.Errorf("CalculateCCI() 结果长度 = %v, 期望 %v", len(result.Values), expectedLength)
+			}
+		})
+	}
+}
+
+func TestCCIAccuracy(t *testing.T) {
+	result, err := CalculateDefaultCCI(refHigh, refLow, refClose)
+	if err != nil {
+		t.Fatalf("CalculateDefaultCCI() 意外错误 = %v", err)
+	}
+
+	if math.Abs(result.Values[0]-77.358677) > 1e-5 {
+		t.Errorf("第一个CCI = %v, 期望 77.358677", result.Values[0])
+	}
+	if math.Abs(result.GetLatest()-(-44.028833)) > 1e-5 {
+		t.Errorf("最新CCI = %v, 期望 -44.028833", result.GetLatest())
+	}
+
+	// 价格不变时平均偏差为零，CCI 取0
+	flat := []float64{10, 10, 10}
+	flatResult, _ := CalculateCCI(flat, flat, flat, 3)
+	if flatResult.GetLatest() != 0 {
+		t.Errorf("价格不变时CCI = %v, 期望 0", flatResult.GetLatest())
+	}
+}
+
+func TestCCIResult_GetSignal(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    float64
+		expected CCISignal
+	}{
+		{name: "超买", value: 150, expected: CCISell},
+		{name: "超卖", value: -180, expected: CCIBuy},
+		{name: "中性", value: 20, expected: CCINeutral},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &CCIResult{Values: []float64{tt.value}, Period: 20}
+			if got := result.GetDefaultSignal(); got != tt.expected {
+				t.Errorf("GetDefaultSignal() = %v, 期望 %v", CCISignalToString(got), CCISignalToString(tt.expected))
+			}
+		})
+	}
+
+	if got := (&CCIResult{Values: []float64{1, 2, 3}}).GetLatestN(2); len(got) != 2 || got[0] != 2 {
+		t.Errorf("GetLatestN(2) = %v", got)
+	}
+}
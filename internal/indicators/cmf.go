@@ -0,0 +1,90 @@
+package indicators
+
+import (
+	"errors"
+	"math"
+)
+
+// CMFResult 蔡金资金流计算结果
+type CMFResult struct {
+	Values []float64 // CMF 值序列 (-1 到 1)，第一个值对应第 period 根K线
+	Period int       // 计算周期
+}
+
+// DefaultCMFPeriod 默认CMF周期
+const DefaultCMFPeriod = 20
+
+// CalculateCMF 计算蔡金资金流
+// 资金流乘数 = ((收盘价 - 最低价) - (最高价 - 收盘价)) / (最高价 - 最低价)
+// CMF = Σ(资金流乘数 × 成交量) / Σ成交量
+// high/low/close/volume: 价格和成交量序列（长度必须相同）
+// period: 计算周期，通常为20
+func CalculateCMF(high, low, close, volume []float64, period int) (*CMFResult, error) {
+	if len(high) != len(close) || len(low) != len(close) || len(volume) != len(close) {
+		return nil, errors.New("价格和成交量序列长度不一致")
+	}
+
+	if period <= 0 {
+		return nil, errors.New("CMF周期必须大于0")
+	}
+
+	if len(close) < period {
+		return nil, errors.New("价格数据不足，无法计算CMF指标")
+	}
+
+	// 资金流量：最高价等于最低价时乘数取0
+	flows := make([]float64, len(close))
+	for i := range close {
+		if spread := high[i] - low[i]; spread > 0 {
+			flows[i] = ((close[i] - low[i]) - (high[i] - close[i])) / spread * volume[i]
+		}
+	}
+
+	values := make([]float64, 0, len(close)-period+1)
+	for i := period - 1; i < len(close); i++ {
+		var flow, totalVolume float64
+		for j := i - period + 1; j <= i; j++ {
+			flow += flows[j]
+			totalVolume += volume[j]
+		}
+
+		if totalVolume == 0 {
+			values = append(values, 0)
+			continue
+		}
+		values = append(values, flow/totalVolume)
+	}
+
+	return &CMFResult{
+		Values: values,
+		Period: period,
+	}, nil
+}
+
+// CalculateDefaultCMF 使用默认参数计算CMF
+func CalculateDefaultCMF(high, low, close, volume []float64) (*CMFResult, error) {
+	return CalculateCMF(high, low, close, volume, DefaultCMFPeriod)
+}
+
+// GetLatest 获取最新的CMF值
+func (c *CMFResult) GetLatest() float64 {
+	if len(c.Values) == 0 {
+		return 0
+	}
+	return c.Values[len(c.Values)-1]
+}
+
+// GetLatestN 获取最新的N个CMF值
+func (c *CMFResult) GetLatestN(n int) []float64 {
+	if n <= 0 || len(c.Values) == 0 {
+		return []float64{}
+	}
+
+	start := int(math.Max(0, float64(len(c.Values)-n)))
+	return c.Values[start:]
+}
+
+// IsAccumulation 检查最新CMF是否为正（买方资金占优）
+func (c *CMFResult) IsAccumulation() bool {
+	return c.GetLatest() > 0
+}
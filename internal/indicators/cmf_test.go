@@ -0,0 +1,74 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateCMF(t *testing.T) {
+	tests := []struct {
+		name    string
+		volume  []float64
+		period  int
+		wantErr bool
+	}{
+		{name: "正常计算CMF-20周期", volume: refVolume, period: 20},
+		{name: "序列长度不一致", volume: refVolume[:10], period: 20, wantErr: true},
+		{name: "周期为负数", volume: refVolume, period: -1, wantErr: true},
+		{name: "价格数据不足", volume: refVolume, period: 31, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CalculateCMF(refHigh, refLow, refClose, tt.volume, tt.period)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("CalculateCMF() 期望错误，但没有返回错误")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CalculateCMF() 意外错误 = %v", err)
+			}
+
+			expectedLength := len(refClose) - tt.period + 1
+			if len(result.Values) != expectedLength {
+				t.Errorf("CalculateCMF() 结果长度 = %v, 期望 %v", len(result.Values), expectedLength)
+			}
+		})
+	}
+}
+
+func TestCMFAccuracy(t *testing.T) {
+	result, err := CalculateDefaultCMF(refHigh, refLow, refClose, refVolume)
+	if err != nil {
+		t.Fatalf("CalculateDefaultCMF() 意外错误 = %v", err)
+	}
+
+	if math.Abs(result.Values[0]-0.359584) > 1e-5 {
+		t.Errorf("第一个CMF = %v, 期望 0.359584", result.Values[0])
+	}
+	if math.Abs(result.GetLatest()-0.117954) > 1e-5 {
+		t.Errorf("最新CMF = %v, 期望 0.117954", result.GetLatest())
+	}
+	if !result.IsAccumulation() {
+		t.Error("CMF为正时应识别为资金流入")
+	}
+
+	// 收盘于最低价时资金流乘数为-1；无振幅或无成交量时为0
+	high := []float64{11, 10, 10}
+	low := []float64{9, 10, 10}
+	close := []float64{9, 10, 10}
+	bearish, _ := CalculateCMF(high, low, close, []float64{5, 5, 0}, 1)
+	expected := []float64{-1, 0, 0}
+	for i, v := range bearish.Values {
+		if v != expected[i] {
+			t.Errorf("第%d个CMF = %v, 期望 %v", i, v, expected[i])
+		}
+	}
+	if got := bearish.GetLatestN(2); len(got) != 2 {
+		t.Errorf("GetLatestN(2) = %v", got)
+	}
+}
@@ -0,0 +1,193 @@
+package indicators
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// DivergenceType 背离类型
+type DivergenceType int
+
+const (
+	RegularBullish DivergenceType = iota // 常规看涨背离：价格更低的低点，指标更高的低点（反转）
+	RegularBearish                       // 常规看跌背离：价格更高的高点，指标更低的高点（反转）
+	HiddenBullish                        // 隐藏看涨背离：价格更高的低点，指标更低的低点（上升趋势延续）
+	HiddenBearish                        // 隐藏看跌背离：价格更低的高点，指标更高的高点（下降趋势延续）
+)
+
+// Divergence 一次价格与振荡指标之间的背离
+type Divergence struct {
+	Type            DivergenceType // 背离类型
+	Start           Pivot          // 较早的价格摆动点
+	End             Pivot          // 较近的价格摆动点
+	StartOscillator float64        // 较早摆动点处的指标值
+	EndOscillator   float64        // 较近摆动点处的指标值
+	ConfirmIndex    int            // 背离被确认的K线索引（较近摆动点右侧K线走完）
+}
+
+// DivergenceResult 背离检测结果
+type DivergenceResult struct {
+	Divergences []Divergence // 按确认位置升序排列的背离
+	Bars        int          // 参与检测的K线数量
+	LeftBars    int          // 摆动点左侧K线数
+	RightBars   int          // 摆动点右侧K线数
+	MaxLookback int          // 两个摆动点之间允许的最大K线间隔
+}
+
+// 背离检测默认参数
+const (
+	DefaultDivergenceLookback = 60 // 默认两个摆动点间最大间隔
+)
+
+// CalculateDivergence 检测价格与任意振荡指标之间的常规/隐藏背离
+// 先在价格上寻找摆动点，再比较相邻两个同类摆动点处的价格与指标走势
+// high/low: 最高价和最低价序列（长度必须相同），只有收盘价时可以传入同一序列
+// oscillator: 振荡指标序列，与价格序列末端对齐，可以比价格序列短（如RSI、MACD），NaN 位置不参与比较
+// leftBars/rightBars: 摆动点左右两侧比较的K线数
+// maxLookback: 两个摆动点之间允许的最大K线间隔，超过则不视为背离
+func CalculateDivergence(high, low, oscillator []float64, leftBars, rightBars, maxLookback int) (*DivergenceResult, error) {
+	if len(high) != len(low) {
+		return nil, errors.New("最高价和最低价序列长度不一致")
+	}
+
+	if len(oscillator) > len(high) {
+		return nil, errors.New("振荡指标序列长度不能超过价格序列")
+	}
+
+	if maxLookback <= 0 {
+		return nil, errors.New("背离回看K线数必须大于0")
+	}
+
+	pivots, err := CalculatePivots(high, low, leftBars, rightBars)
+	if err != nil {
+		return nil, err
+	}
+
+	offset := len(high) - len(oscillator)
+	oscillatorAt := func(index int) float64 {
+		if index < offset {
+			return math.NaN()
+		}
+		return oscillator[index-offset]
+	}
+
+	result := &DivergenceResult{
+		Bars:        len(high),
+		LeftBars:    leftBars,
+		RightBars:   rightBars,
+		MaxLookback: maxLookback,
+	}
+
+	compare := func(list []Pivot, classify func(prev, curr Pivot, prevOsc, currOsc float64) (DivergenceType, bool)) {
+		for i := 1; i < len(list); i++ {
+			prev, curr := list[i-1], list[i]
+			if curr.Index-prev.Index > maxLookback {
+				continue
+			}
+
+			prevOsc, currOsc := oscillatorAt(prev.Index), oscillatorAt(curr.Index)
+			if math.IsNaN(prevOsc) || math.IsNaN(currOsc) {
+				continue
+			}
+
+			if divType, ok := classify(prev, curr, prevOsc, currOsc); ok {
+				result.Divergences = append(result.Divergences, Divergence{
+					Type:            divType,
+					Start:           prev,
+					End:             curr,
+					StartOscillator: prevOsc,
+					EndOscillator:   currOsc,
+					ConfirmIndex:    pivots.ConfirmIndex(curr),
+				})
+			}
+		}
+	}
+
+	// 低点比较：看涨背离
+	compare(pivots.Lows, func(prev, curr Pivot, prevOsc, currOsc float64) (DivergenceType, bool) {
+		switch {
+		case curr.Value < prev.Value && currOsc > prevOsc:
+			return RegularBullish, true
+		case curr.Value > prev.Value && currOsc < prevOsc:
+			return HiddenBullish, true
+		}
+		return 0, false
+	})
+
+	// 高点比较：看跌背离
+	compare(pivots.Highs, func(prev, curr Pivot, prevOsc, currOsc float64) (DivergenceType, bool) {
+		switch {
+		case curr.Value > prev.Value && currOsc < prevOsc:
+			return RegularBearish, true
+		case curr.Value < prev.Value && currOsc > prevOsc:
+			return HiddenBearish, true
+		}
+		return 0, false
+	})
+
+	sort.SliceStable(result.Divergences, func(i, j int) bool {
+		return result.Divergences[i].ConfirmIndex < result.Divergences[j].ConfirmIndex
+	})
+
+	return result, nil
+}
+
+// IsBullish 检查是否为看涨背离（常规或隐藏）
+func (d Divergence) IsBullish() bool {
+	return d.Type == RegularBullish || d.Type == HiddenBullish
+}
+
+// IsRegular 检查是否为常规（反转型）背离
+func (d Divergence) IsRegular() bool {
+	return d.Type == RegularBullish || d.Type == RegularBearish
+}
+
+// GetLatest 获取最近确认的背离
+func (r *DivergenceResult) GetLatest() (Divergence, bool) {
+	if len(r.Divergences) == 0 {
+		return Divergence{}, false
+	}
+	return r.Divergences[len(r.Divergences)-1], true
+}
+
+// GetNew 获取在最新K线上刚刚确认的背离
+func (r *DivergenceResult) GetNew() []Divergence {
+	var divergences []Divergence
+	for _, d := range r.Divergences {
+		if d.ConfirmIndex == r.Bars-1 {
+			divergences = append(divergences, d)
+		}
+	}
+	return divergences
+}
+
+// Filter 按类型筛选背离
+func (r *DivergenceResult) Filter(types ...DivergenceType) []Divergence {
+	var divergences []Divergence
+	for _, d := range r.Divergences {
+		for _, t := range types {
+			if d.Type == t {
+				divergences = append(divergences, d)
+				break
+			}
+		}
+	}
+	return divergences
+}
+
+// DivergenceTypeToString 将背离类型转换为字符串
+func DivergenceTypeToString(divType DivergenceType) string {
+	switch divType {
+	case RegularBullish:
+		return "常规看涨背离"
+	case RegularBearish:
+		return "常规看跌背离"
+	case HiddenBullish:
+		return "隐藏看涨背离"
+	case HiddenBearish:
+		return "隐藏看跌背离"
+	default:
+		return "未知"
+	}
+}
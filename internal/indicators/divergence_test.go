@@ -0,0 +1,142 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateDivergence_Regular(t *testing.T) {
+	// 低点 2→6 价格创新低，高点 4→8 价格创新高
+	prices := []float64{10, 8, 5, 8, 10, 9, 4, 9, 12, 10, 11, 9, 10}
+	oscillator := make([]float64, len(prices))
+	for i := range oscillator {
+		oscillator[i] = 50
+	}
+	oscillator[2], oscillator[6] = 20, 30 // 指标低点抬高
+	oscillator[4], oscillator[8] = 70, 60 // 指标高点降低
+
+	result, err := CalculateDivergence(prices, prices, oscillator, 2, 2, DefaultDivergenceLookback)
+	if err != nil {
+		t.Fatalf("CalculateDivergence() 意外错误 = %v", err)
+	}
+
+	if len(result.Divergences) != 2 {
+		t.Fatalf("背离数量 = %v, 期望 2: %+v", len(result.Divergences), result.Divergences)
+	}
+
+	bullish := result.Divergences[0]
+	if bullish.Type != RegularBullish || bullish.Start.Index != 2 || bullish.End.Index != 6 || bullish.ConfirmIndex != 8 {
+		t.Errorf("看涨背离 = %+v", bullish)
+	}
+	if !bullish.IsBullish() || !bullish.IsRegular() {
+		t.Error("RegularBullish 应为常规看涨背离")
+	}
+	if bullish.StartOscillator != 20 || bullish.EndOscillator != 30 {
+		t.Errorf("指标值 = %v/%v, 期望 20/30", bullish.StartOscillator, bullish.EndOscillator)
+	}
+
+	bearish, ok := result.GetLatest()
+	if !ok || bearish.Type != RegularBearish || bearish.Start.Index != 4 || bearish.End.Index != 8 {
+		t.Errorf("GetLatest() = %+v, %v", bearish, ok)
+	}
+	if bearish.IsBullish() {
+		t.Error("RegularBearish 不应为看涨背离")
+	}
+
+	if got := result.Filter(RegularBullish, HiddenBullish); len(got) != 1 || got[0].Type != RegularBullish {
+		t.Errorf("Filter() = %+v", got)
+	}
+
+	// 最新K线之前确认的背离不算新背离
+	if got := result.GetNew(); len(got) != 0 {
+		t.Errorf("GetNew() = %+v, 期望为空", got)
+	}
+
+	// 截断到看跌背离刚被确认的K线
+	result, _ = CalculateDivergence(prices[:11], prices[:11], oscillator[:11], 2, 2, DefaultDivergenceLookback)
+	if got := result.GetNew(); len(got) != 1 || got[0].Type != RegularBearish {
+		t.Errorf("GetNew() = %+v, 期望一个常规看跌背离", got)
+	}
+}
+
+func TestCalculateDivergence_Hidden(t *testing.T) {
+	// 低点 2→6→10 逐步抬高，高点 4→8 降低
+	prices := []float64{10, 8, 5, 8, 10, 9, 6, 9, 9.5, 9, 8, 9, 10}
+	oscillator := []float64{50, 50, 30, 50, 60, 50, 20, 50, 70, 50, 25, 50, 50}
+
+	result, err := CalculateDivergence(prices, prices, oscillator, 2, 2, DefaultDivergenceLookback)
+	if err != nil {
+		t.Fatalf("CalculateDivergence() 意外错误 = %v", err)
+	}
+
+	want := []DivergenceType{HiddenBullish, HiddenBearish}
+	if len(result.Divergences) != len(want) {
+		t.Fatalf("背离 = %+v, 期望类型 %v", result.Divergences, want)
+	}
+	for i, divType := range want {
+		if result.Divergences[i].Type != divType {
+			t.Errorf("第%d个背离 = %v, 期望 %v", i,
+				DivergenceTypeToString(result.Divergences[i].Type), DivergenceTypeToString(divType))
+		}
+	}
+	if result.Divergences[0].IsRegular() {
+		t.Error("HiddenBullish 不应为常规背离")
+	}
+}
+
+func TestCalculateDivergence_Alignment(t *testing.T) {
+	prices := []float64{10, 8, 5, 8, 10, 9, 4, 9, 12, 10, 11, 9, 10}
+	oscillator := []float64{50, 50, 20, 50, 70, 50, 30, 50, 60, 50, 50, 50, 50}
+
+	// 指标序列与价格末端对齐，截掉开头不影响结果
+	result, err := CalculateDivergence(prices, prices, oscillator[1:], 2, 2, DefaultDivergenceLookback)
+	if err != nil {
+		t.Fatalf("CalculateDivergence() 意外错误 = %v", err)
+	}
+	if len(result.Divergences) != 2 {
+		t.Errorf("截掉开头后背离数量 = %v, 期望 2", len(result.Divergences))
+	}
+
+	// 较早摆动点处没有指标值时不比较
+	result, _ = CalculateDivergence(prices, prices, oscillator[5:], 2, 2, DefaultDivergenceLookback)
+	if len(result.Divergences) != 0 {
+		t.Errorf("指标值缺失时背离 = %+v, 期望为空", result.Divergences)
+	}
+
+	nanOscillator := append([]float64(nil), oscillator...)
+	nanOscillator[6] = math.NaN()
+	result, _ = CalculateDivergence(prices, prices, nanOscillator, 2, 2, DefaultDivergenceLookback)
+	if got := result.Filter(RegularBullish); len(got) != 0 {
+		t.Errorf("NaN 指标值不应参与比较: %+v", got)
+	}
+
+	// 摆动点间隔超过回看范围
+	result, _ = CalculateDivergence(prices, prices, oscillator, 2, 2, 3)
+	if len(result.Divergences) != 0 {
+		t.Errorf("超出回看范围时背离 = %+v, 期望为空", result.Divergences)
+	}
+}
+
+func TestCalculateDivergence_Errors(t *testing.T) {
+	prices := []float64{10, 8, 5, 8, 10, 9, 4}
+
+	tests := []struct {
+		name       string
+		high       []float64
+		low        []float64
+		oscillator []float64
+		lookback   int
+	}{
+		{"价格序列长度不一致", prices, prices[:6], prices, 10},
+		{"指标序列过长", prices[:6], prices[:6], prices, 10},
+		{"回看K线数为0", prices, prices, prices, 0},
+		{"数据不足", prices[:3], prices[:3], prices[:3], 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := CalculateDivergence(tt.high, tt.low, tt.oscillator, 2, 2, tt.lookback); err == nil {
+				t.Error("CalculateDivergence() 期望错误，但没有返回错误")
+			}
+		})
+	}
+}
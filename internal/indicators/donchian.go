@@ -0,0 +1,92 @@
+package indicators
+
+import (
+	"errors"
+	"math"
+)
+
+// DonchianResult 唐奇安通道计算结果
+type DonchianResult struct {
+	Upper  []float64 // 上轨：周期内最高价
+	Middle []float64 // 中轨：上下轨平均值
+	Lower  []float64 // 下轨：周期内最低价
+	Period int       // 计算周期
+}
+
+// DefaultDonchianPeriod 默认唐奇安通道周期
+const DefaultDonchianPeriod = 20
+
+// CalculateDonchianChannels 计算唐奇安通道
+// high/low: 最高价和最低价序列（长度必须相同）
+// period: 通道周期，通常为20
+func CalculateDonchianChannels(high, low []float64, period int) (*DonchianResult, error) {
+	if len(high) != len(low) {
+		return nil, errors.New("最高价和最低价序列长度不一致")
+	}
+
+	if period <= 0 {
+		return nil, errors.New("唐奇安通道周期必须大于0")
+	}
+
+	if len(high) < period {
+		return nil, errors.New("价格数据不足，无法计算唐奇安通道")
+	}
+
+	n := len(high) - period + 1
+	result := &DonchianResult{
+		Upper:  make([]float64, n),
+		Middle: make([]float64, n),
+		Lower:  make([]float64, n),
+		Period: period,
+	}
+
+	for i := 0; i < n; i++ {
+		upper, lower := high[i], low[i]
+		for j := i + 1; j < i+period; j++ {
+			upper = math.Max(upper, high[j])
+			lower = math.Min(lower, low[j])
+		}
+		result.Upper[i] = upper
+		result.Lower[i] = lower
+		result.Middle[i] = (upper + lower) / 2
+	}
+
+	return result, nil
+}
+
+// GetLatest 获取最新的上轨、中轨和下轨
+func (d *DonchianResult) GetLatest() (upper, middle, lower float64) {
+	if len(d.Middle) == 0 {
+		return 0, 0, 0
+	}
+
+	idx := len(d.Middle) - 1
+	return d.Upper[idx], d.Middle[idx], d.Lower[idx]
+}
+
+// GetLatestN 获取最新的N个上轨、中轨和下轨值
+func (d *DonchianResult) GetLatestN(n int) (upper, middle, lower []float64) {
+	if n <= 0 || len(d.Middle) == 0 {
+		return []float64{}, []float64{}, []float64{}
+	}
+
+	start := int(math.Max(0, float64(len(d.Middle)-n)))
+	return d.Upper[start:], d.Middle[start:], d.Lower[start:]
+}
+
+// IsBreakout 检测价格是否突破前一根K线的通道（通道包含当前K线时价格不可能超出）
+// 返回：是否突破，突破方向（true 为向上突破）
+func (d *DonchianResult) IsBreakout(price float64) (bool, bool) {
+	if len(d.Middle) < 2 {
+		return false, false
+	}
+
+	prev := len(d.Middle) - 2
+	if price > d.Upper[prev] {
+		return true, true
+	}
+	if price < d.Lower[prev] {
+		return true, false
+	}
+	return false, false
+}
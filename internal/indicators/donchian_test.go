@@ -0,0 +1,67 @@
+package indicators
+
+import "testing"
+
+func TestCalculateDonchianChannels(t *testing.T) {
+	high := []float64{3, 5, 4, 6, 2}
+	low := []float64{1, 2, 0, 3, 1}
+
+	result, err := CalculateDonchianChannels(high, low, 3)
+	if err != nil {
+		t.Fatalf("CalculateDonchianChannels() 意外错误 = %v", err)
+	}
+
+	wantUpper := []float64{5, 6, 6}
+	wantLower := []float64{0, 0, 0}
+	wantMiddle := []float64{2.5, 3, 3}
+	for i := range wantUpper {
+		if result.Upper[i] != wantUpper[i] || result.Lower[i] != wantLower[i] || result.Middle[i] != wantMiddle[i] {
+			t.Errorf("第%d个值 = %v/%v/%v, 期望 %v/%v/%v", i,
+				result.Upper[i], result.Middle[i], result.Lower[i], wantUpper[i], wantMiddle[i], wantLower[i])
+		}
+	}
+
+	if upper, middle, lower := result.GetLatest(); upper != 6 || middle != 3 || lower != 0 {
+		t.Errorf("GetLatest() = %v, %v, %v", upper, middle, lower)
+	}
+	if u, _, _ := result.GetLatestN(5); len(u) != 3 {
+		t.Errorf("GetLatestN(5) 长度 = %v, 期望 3", len(u))
+	}
+
+	if _, err := CalculateDonchianChannels(high, low[:4], 3); err == nil {
+		t.Error("序列长度不一致时应返回错误")
+	}
+	if _, err := CalculateDonchianChannels(high, low, 6); err == nil {
+		t.Error("价格数据不足时应返回错误")
+	}
+	if _, err := CalculateDonchianChannels(high, low, 0); err == nil {
+		t.Error("周期为0时应返回错误")
+	}
+}
+
+func TestDonchianResult_IsBreakout(t *testing.T) {
+	result, _ := CalculateDonchianChannels([]float64{3, 5, 4, 6, 2}, []float64{1, 2, 0, 3, 1}, 3)
+
+	tests := []struct {
+		name     string
+		price    float64
+		breakout bool
+		up       bool
+	}{
+		{"向上突破", 6.5, true, true},
+		{"向下突破", -1, true, false},
+		{"通道内", 3, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breakout, up := result.IsBreakout(tt.price)
+			if breakout != tt.breakout || up != tt.up {
+				t.Errorf("IsBreakout(%v) = %v, %v, 期望 %v, %v", tt.price, breakout, up, tt.breakout, tt.up)
+			}
+		})
+	}
+
+	if breakout, _ := (&DonchianResult{}).IsBreakout(100); breakout {
+		t.Error("数据不足时不应判定为突破")
+	}
+}
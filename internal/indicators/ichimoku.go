@@ -0,0 +1,213 @@
+package indicators
+
+import (
+	"errors"
+	"math"
+)
+
+// IchimokuResult 一目均衡表计算结果
+// 所有序列按K线索引对齐，尚无法计算的位置为 NaN；
+// 先行带 A/B 向未来平移 Displacement 根K线，长度为K线数 + Displacement
+type IchimokuResult struct {
+	Tenkan        []float64 // 转换线：TenkanPeriod 周期最高最低价中点
+	Kijun         []float64 // 基准线：KijunPeriod 周期最高最低价中点
+	SenkouA       []float64 // 先行带A：(转换线 + 基准线) / 2，向前平移
+	SenkouB       []float64 // 先行带B：SenkouBPeriod 周期最高最低价中点，向前平移
+	Chikou        []float64 // 迟行带：收盘价向后平移，Chikou[i] 为第 i+Displacement 根K线收盘价
+	TenkanPeriod  int       // 转换线周期
+	KijunPeriod   int       // 基准线周期
+	SenkouBPeriod int       // 先行带B周期
+	Displacement  int       // 平移周期
+}
+
+// CloudPosition 价格相对云层的位置
+type CloudPosition int
+
+const (
+	CloudInside CloudPosition = iota // 位于云层内（或云层尚未形成）
+	CloudAbove                       // 位于云层上方
+	CloudBelow                       // 位于云层下方
+)
+
+// 一目均衡表默认参数
+const (
+	DefaultTenkanPeriod  = 9  // 默认转换线周期
+	DefaultKijunPeriod   = 26 // 默认基准线周期
+	DefaultSenkouBPeriod = 52 // 默认先行带B周期
+	DefaultDisplacement  = 26 // 默认平移周期
+)
+
+// CalculateIchimoku 计算一目均衡表
+// high/low/close: 最高价、最低价和收盘价序列（长度必须相同）
+// tenkanPeriod/kijunPeriod/senkouBPeriod: 通常为 9/26/52
+// displacement: 先行带向前、迟行带向后平移的K线数，通常为26
+func CalculateIchimoku(high, low, close []float64, tenkanPeriod, kijunPeriod, senkouBPeriod, displacement int) (*IchimokuResult, error) {
+	if len(high) != len(close) || len(low) != len(close) {
+		return nil, errors.New("最高价、最低价和收盘价序列长度不一致")
+	}
+
+	if tenkanPeriod <= 0 || kijunPeriod <= 0 || senkouBPeriod <= 0 || displacement <= 0 {
+		return nil, errors.New("一目均衡表周期参数必须大于0")
+	}
+
+	if len(close) < max(tenkanPeriod, kijunPeriod, senkouBPeriod) {
+		return nil, errors.New("价格数据不足，无法计算一目均衡表")
+	}
+
+	n := len(close)
+	result := &IchimokuResult{
+		Tenkan:        midpointSeries(high, low, tenkanPeriod),
+		Kijun:         midpointSeries(high, low, kijunPeriod),
+		SenkouA:       nanSeries(n + displacement),
+		SenkouB:       nanSeries(n + displacement),
+		Chikou:        nanSeries(n),
+		TenkanPeriod:  tenkanPeriod,
+		KijunPeriod:   kijunPeriod,
+		SenkouBPeriod: senkouBPeriod,
+		Displacement:  displacement,
+	}
+
+	spanB := midpointSeries(high, low, senkouBPeriod)
+	for i := 0; i < n; i++ {
+		result.SenkouA[i+displacement] = (result.Tenkan[i] + result.Kijun[i]) / 2
+		result.SenkouB[i+displacement] = spanB[i]
+		if i >= displacement {
+			result.Chikou[i-displacement] = close[i]
+		}
+	}
+
+	return result, nil
+}
+
+// CalculateDefaultIchimoku 使用默认参数计算一目均衡表
+func CalculateDefaultIchimoku(high, low, close []float64) (*IchimokuResult, error) {
+	return CalculateIchimoku(high, low, close, DefaultTenkanPeriod, DefaultKijunPeriod, DefaultSenkouBPeriod, DefaultDisplacement)
+}
+
+// midpointSeries 计算按K线对齐的周期最高最低价中点，前 period-1 个位置为 NaN
+func midpointSeries(high, low []float64, period int) []float64 {
+	values := nanSeries(len(high))
+	for i := period - 1; i < len(high); i++ {
+		highest, lowest := high[i], low[i]
+		for j := i - period + 1; j < i; j++ {
+			highest = math.Max(highest, high[j])
+			lowest = math.Min(lowest, low[j])
+		}
+		values[i] = (highest + lowest) / 2
+	}
+	return values
+}
+
+// nanSeries 创建全部为 NaN 的序列
+func nanSeries(n int) []float64 {
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = math.NaN()
+	}
+	return values
+}
+
+// bars 返回参与计算的K线数量
+func (r *IchimokuResult) bars() int {
+	return len(r.Tenkan)
+}
+
+// GetLatest 获取最新的转换线和基准线
+func (r *IchimokuResult) GetLatest() (tenkan, kijun float64) {
+	if r.bars() == 0 {
+		return 0, 0
+	}
+
+	idx := r.bars() - 1
+	return r.Tenkan[idx], r.Kijun[idx]
+}
+
+// CloudAt 获取相对最新K线偏移 offset 根处的云层（先行带A、B）
+// offset 为0时是当前价格对应的云层，为 Displacement 时是投射到最远未来的云层，负数表示历史云层
+func (r *IchimokuResult) CloudAt(offset int) (spanA, spanB float64) {
+	idx := r.bars() - 1 + offset
+	if idx < 0 || idx >= len(r.SenkouA) {
+		return math.NaN(), math.NaN()
+	}
+	return r.SenkouA[idx], r.SenkouB[idx]
+}
+
+// GetFutureCloud 获取投射到未来最远处的云层
+func (r *IchimokuResult) GetFutureCloud() (spanA, spanB float64) {
+	return r.CloudAt(r.Displacement)
+}
+
+// IsFutureCloudBullish 检查未来云层是否为多头云（先行带A高于先行带B）
+func (r *IchimokuResult) IsFutureCloudBullish() bool {
+	spanA, spanB := r.GetFutureCloud()
+	return spanA > spanB
+}
+
+// GetCloudPosition 获取价格相对当前云层的位置
+func (r *IchimokuResult) GetCloudPosition(price float64) CloudPosition {
+	return r.positionAt(price, 0)
+}
+
+// positionAt 获取价格相对偏移 offset 处云层的位置，云层未形成时视为位于云层内
+func (r *IchimokuResult) positionAt(price float64, offset int) CloudPosition {
+	spanA, spanB := r.CloudAt(offset)
+	if math.IsNaN(spanA) || math.IsNaN(spanB) {
+		return CloudInside
+	}
+
+	switch {
+	case price > math.Max(spanA, spanB):
+		return CloudAbove
+	case price < math.Min(spanA, spanB):
+		return CloudBelow
+	default:
+		return CloudInside
+	}
+}
+
+// IsCloudBreakout 检查最新收盘价是否刚突破云层
+// prevClose/close: 前一根和最新K线的收盘价
+// 返回：是否突破，突破方向（true 为向上突破）
+func (r *IchimokuResult) IsCloudBreakout(prevClose, close float64) (bool, bool) {
+	prev := r.positionAt(prevClose, -1)
+	current := r.GetCloudPosition(close)
+	if current == prev || current == CloudInside {
+		return false, false
+	}
+	return true, current == CloudAbove
+}
+
+// IsTKBullishCross 检查转换线是否上穿基准线
+func (r *IchimokuResult) IsTKBullishCross() bool {
+	return isCrossAbove(r.Tenkan, r.Kijun)
+}
+
+// IsTKBearishCross 检查转换线是否下穿基准线
+func (r *IchimokuResult) IsTKBearishCross() bool {
+	return isCrossAbove(r.Kijun, r.Tenkan)
+}
+
+// ChikouConfirmation 检查迟行带是否确认趋势：最新收盘价与 Displacement 根K线前的价格区间比较
+// high/low: 计算时使用的最高价和最低价序列
+// 返回：看涨确认（高于当时最高价），看跌确认（低于当时最低价）
+func (r *IchimokuResult) ChikouConfirmation(high, low []float64) (bullish, bearish bool) {
+	idx := r.bars() - 1 - r.Displacement
+	if idx < 0 || idx >= len(high) || idx >= len(low) {
+		return false, false
+	}
+
+	latest := r.Chikou[idx]
+	return latest > high[idx], latest < low[idx]
+}
+
+// CloudPositionToString 将云层位置转换为字符串
+func CloudPositionToString(position CloudPosition) string {
+	switch position {
+	case CloudAbove:
+		return "云层上方"
+	case CloudBelow:
+		return "云层下方"
+	default:
+		return "云层内"
+	}
+}
@@ -0,0 +1,114 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateIchimoku(t *testing.T) {
+	tests := []struct {
+		name    string
+		high    []float64
+		low     []float64
+		close   []float64
+		senkouB int
+		wantErr bool
+	}{
+		{name: "正常计算一目均衡表(3,5,10,4)", high: refHigh, low: refLow, close: refClose, senkouB: 10},
+		{name: "序列长度不一致", high: refHigh[:10], low: refLow, close: refClose, senkouB: 10, wantErr: true},
+		{name: "价格数据不足", high: refHigh, low: refLow, close: refClose, senkouB: 31, wantErr: true},
+		{name: "周期为0", high: refHigh, low: refLow, close: refClose, senkouB: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CalculateIchimoku(tt.high, tt.low, tt.close, 3, 5, tt.senkouB, 4)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("CalculateIchimoku() 期望错误，但没有返回错误")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CalculateIchimoku() 意外错误 = %v", err)
+			}
+
+			// 先行带向未来投射 displacement 根K线
+			if len(result.Tenkan) != len(tt.close) || len(result.SenkouA) != len(tt.close)+4 || len(result.SenkouB) != len(tt.close)+4 {
+				t.Errorf("CalculateIchimoku() 结果长度 = %v/%v, 期望 %v/%v", len(result.Tenkan), len(result.SenkouA), len(tt.close), len(tt.close)+4)
+			}
+			if !math.IsNaN(result.Tenkan[1]) || !math.IsNaN(result.SenkouB[12]) || math.IsNaN(result.SenkouB[13]) {
+				t.Error("尚无法计算的位置应为 NaN")
+			}
+		})
+	}
+}
+
+func TestIchimokuAccuracy(t *testing.T) {
+	result, err := CalculateIchimoku(refHigh, refLow, refClose, 3, 5, 10, 4)
+	if err != nil {
+		t.Fatalf("CalculateIchimoku() 意外错误 = %v", err)
+	}
+
+	tenkan, kijun := result.GetLatest()
+	if math.Abs(tenkan-47.995) > 1e-9 || math.Abs(kijun-46.535) > 1e-9 {
+		t.Errorf("最新转换线/基准线 = %v/%v, 期望 47.995/46.535", tenkan, kijun)
+	}
+
+	// 投射到未来的云层由最新K线计算
+	spanA, spanB := result.GetFutureCloud()
+	if math.Abs(spanA-47.265) > 1e-9 || math.Abs(spanB-45.92) > 1e-9 {
+		t.Errorf("未来云层 = %v/%v, 期望 47.265/45.92", spanA, spanB)
+	}
+	if !result.IsFutureCloudBullish() {
+		t.Error("先行带A高于先行带B应为多头云")
+	}
+
+	// 当前云层由 displacement 根K线前的数据计算
+	spanA, spanB = result.CloudAt(0)
+	if math.Abs(spanA-45.4425) > 1e-9 || math.Abs(spanB-46.1) > 1e-9 {
+		t.Errorf("当前云层 = %v/%v, 期望 45.4425/46.1", spanA, spanB)
+	}
+	if got := result.GetCloudPosition(47.85); got != CloudAbove {
+		t.Errorf("GetCloudPosition() = %v, 期望云层上方", CloudPositionToString(got))
+	}
+	if got := result.GetCloudPosition(45.8); got != CloudInside {
+		t.Errorf("GetCloudPosition() = %v, 期望云层内", CloudPositionToString(got))
+	}
+	if a, b := result.CloudAt(10); !math.IsNaN(a) || !math.IsNaN(b) {
+		t.Error("超出投射范围的云层应为 NaN")
+	}
+
+	bullish, bearish := result.ChikouConfirmation(refHigh, refLow)
+	if !bullish || bearish {
+		t.Errorf("ChikouConfirmation() = %v/%v, 期望看涨确认", bullish, bearish)
+	}
+}
+
+func TestIchimokuResult_Signals(t *testing.T) {
+	result := &IchimokuResult{
+		Tenkan:       []float64{9, 11},
+		Kijun:        []float64{10, 10},
+		SenkouA:      []float64{8, 8, 9, 9},
+		SenkouB:      []float64{10, 10, 10, 10},
+		Chikou:       []float64{12, math.NaN()},
+		Displacement: 1,
+	}
+
+	if !result.IsTKBullishCross() || result.IsTKBearishCross() {
+		t.Error("转换线上穿基准线应识别为看涨交叉")
+	}
+
+	// 前收盘价位于云层内，最新收盘价突破云层
+	if breakout, up := result.IsCloudBreakout(9, 10.5); !breakout || !up {
+		t.Errorf("IsCloudBreakout() = %v/%v, 期望向上突破", breakout, up)
+	}
+	if breakout, _ := result.IsCloudBreakout(11, 10.5); breakout {
+		t.Error("持续位于云层上方不应视为突破")
+	}
+	if bullish, _ := result.ChikouConfirmation([]float64{11, 12}, []float64{9, 10}); !bullish {
+		t.Error("迟行带高于当时最高价应为看涨确认")
+	}
+}
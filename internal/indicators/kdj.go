@@ -0,0 +1,147 @@
+package indicators
+
+import (
+	"errors"
+	"math"
+)
+
+// KDJResult KDJ随机指标计算结果
+// 三个序列长度相同，第一个值对应第 period 根K线
+type KDJResult struct {
+	K       []float64 // K 值：RSV 的移动平滑
+	D       []float64 // D 值：K 的移动平滑
+	J       []float64 // J 值：3K - 2D，可超出 0-100 区间
+	Period  int       // RSV 回看周期
+	KSmooth int       // K 平滑系数
+	DSmooth int       // D 平滑系数
+}
+
+// KDJSignal KDJ信号类型
+type KDJSignal int
+
+const (
+	KDJNeutral KDJSignal = iota // 中性
+	KDJBuy                      // 买入信号（超卖）
+	KDJSell                     // 卖出信号（超买）
+)
+
+// KDJ默认参数
+const (
+	DefaultKDJPeriod     = 9    // 默认RSV周期
+	DefaultKDJKSmooth    = 3    // 默认K平滑系数
+	DefaultKDJDSmooth    = 3    // 默认D平滑系数
+	DefaultKDJOverbought = 80.0 // 默认超买水平
+	DefaultKDJOversold   = 20.0 // 默认超卖水平
+
+	kdjInitialValue = 50.0 // K、D 的初始值
+)
+
+// CalculateKDJ 计算KDJ随机指标（国内行情软件的常用算法）
+// RSV = (收盘价 - 周期最低价) / (周期最高价 - 周期最低价) × 100
+// K = (kSmooth-1)/kSmooth × 前K + 1/kSmooth × RSV，D 同理平滑 K，J = 3K - 2D
+// high/low/close: 最高价、最低价和收盘价序列（长度必须相同）
+// period: RSV 回看周期，通常为9
+// kSmooth/dSmooth: K、D 平滑系数，通常为3
+func CalculateKDJ(high, low, close []float64, period, kSmooth, dSmooth int) (*KDJResult, error) {
+	if len(high) != len(close) || len(low) != len(close) {
+		return nil, errors.New("最高价、最低价和收盘价序列长度不一致")
+	}
+
+	if period <= 0 || kSmooth <= 0 || dSmooth <= 0 {
+		return nil, errors.New("KDJ周期参数必须大于0")
+	}
+
+	if len(close) < period {
+		return nil, errors.New("价格数据不足，无法计算KDJ指标")
+	}
+
+	rsv := calculateRawStochastic(high, low, close, period)
+	result := &KDJResult{
+		K:       make([]float64, len(rsv)),
+		D:       make([]float64, len(rsv)),
+		J:       make([]float64, len(rsv)),
+		Period:  period,
+		KSmooth: kSmooth,
+		DSmooth: dSmooth,
+	}
+
+	k, d := kdjInitialValue, kdjInitialValue
+	for i, v := range rsv {
+		k = (k*float64(kSmooth-1) + v) / float64(kSmooth)
+		d = (d*float64(dSmooth-1) + k) / float64(dSmooth)
+		result.K[i] = k
+		result.D[i] = d
+		result.J[i] = 3*k - 2*d
+	}
+
+	return result, nil
+}
+
+// CalculateDefaultKDJ 使用默认参数计算KDJ
+func CalculateDefaultKDJ(high, low, close []float64) (*KDJResult, error) {
+	return CalculateKDJ(high, low, close, DefaultKDJPeriod, DefaultKDJKSmooth, DefaultKDJDSmooth)
+}
+
+// GetLatest 获取最新的K、D、J值
+func (r *KDJResult) GetLatest() (k, d, j float64) {
+	if len(r.K) == 0 {
+		return 0, 0, 0
+	}
+
+	idx := len(r.K) - 1
+	return r.K[idx], r.D[idx], r.J[idx]
+}
+
+// GetLatestN 获取最新的N个K、D、J值
+func (r *KDJResult) GetLatestN(n int) (k, d, j []float64) {
+	if n <= 0 || len(r.K) == 0 {
+		return []float64{}, []float64{}, []float64{}
+	}
+
+	start := int(math.Max(0, float64(len(r.K)-n)))
+	return r.K[start:], r.D[start:], r.J[start:]
+}
+
+// GetSignal 根据K、D值获取交易信号，两者同时进入超买/超卖区才视为有效
+func (r *KDJResult) GetSignal(overboughtLevel, oversoldLevel float64) KDJSignal {
+	if len(r.K) == 0 {
+		return KDJNeutral
+	}
+
+	k, d, _ := r.GetLatest()
+
+	if k >= overboughtLevel && d >= overboughtLevel {
+		return KDJSell // 超买，考虑卖出
+	} else if k <= oversoldLevel && d <= oversoldLevel {
+		return KDJBuy // 超卖，考虑买入
+	}
+
+	return KDJNeutral
+}
+
+// GetDefaultSignal 使用默认阈值获取交易信号
+func (r *KDJResult) GetDefaultSignal() KDJSignal {
+	return r.GetSignal(DefaultKDJOverbought, DefaultKDJOversold)
+}
+
+// IsGoldenCross 检查K是否上穿D（金叉）
+func (r *KDJResult) IsGoldenCross() bool {
+	return isCrossAbove(r.K, r.D)
+}
+
+// IsDeathCross 检查K是否下穿D（死叉）
+func (r *KDJResult) IsDeathCross() bool {
+	return isCrossAbove(r.D, r.K)
+}
+
+// KDJSignalToString 将KDJ信号转换为字符串
+func KDJSignalToString(signal KDJSignal) string {
+	switch signal {
+	case KDJBuy:
+		return "买入信号"
+	case KDJSell:
+		return "卖出信号"
+	default:
+		return "中性"
+	}
+}
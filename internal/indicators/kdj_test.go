@@ -0,0 +1,100 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateKDJ(t *testing.T) {
+	tests := []struct {
+		name    string
+		high    []float64
+		low     []float64
+		close   []float64
+		period  int
+		wantErr bool
+	}{
+		{name: "正常计算KDJ(9,3,3)", high: refHigh, low: refLow, close: refClose, period: 9},
+		{name: "序列长度不一致", high: refHigh[:10], low: refLow, close: refClose, period: 9, wantErr: true},
+		{name: "价格数据不足", high: refHigh[:8], low: refLow[:8], close: refClose[:8], period: 9, wantErr: true},
+		{name: "周期为0", high: refHigh, low: refLow, close: refClose, period: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CalculateKDJ(tt.high, tt.low, tt.close, tt.period, 3, 3)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("CalculateKDJ() 期望错误，但没有返回错误")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CalculateKDJ() 意外错误 = %v", err)
+			}
+
+			expectedLength := len(tt.close) - tt.period + 1
+			if len(result.K) != expectedLength || len(result.J) != expectedLength {
+				t.Errorf("CalculateKDJ() 结果长度 = %v, 期望 %v", len(result.K), expectedLength)
+			}
+		})
+	}
+}
+
+func TestKDJAccuracy(t *testing.T) {
+	result, err := CalculateDefaultKDJ(refHigh, refLow, refClose)
+	if err != nil {
+		t.Fatalf("CalculateDefaultKDJ() 意外错误 = %v", err)
+	}
+
+	k, d, j := result.GetLatest()
+	if math.Abs(k-67.753834) > 1e-5 || math.Abs(d-58.748333) > 1e-5 || math.Abs(j-85.764835) > 1e-5 {
+		t.Errorf("最新 K/D/J = %v/%v/%v, 期望 67.753834/58.748333/85.764835", k, d, j)
+	}
+
+	for i := range result.J {
+		if math.Abs(result.J[i]-(3*result.K[i]-2*result.D[i])) > 1e-9 {
+			t.Errorf("第%d个J值不等于3K-2D", i)
+		}
+	}
+}
+
+func TestKDJResult_GetSignal(t *testing.T) {
+	tests := []struct {
+		name     string
+		k        float64
+		d        float64
+		expected KDJSignal
+	}{
+		{name: "K和D同时超买", k: 88, d: 82, expected: KDJSell},
+		{name: "K和D同时超卖", k: 12, d: 18, expected: KDJBuy},
+		{name: "只有K超买", k: 85, d: 70, expected: KDJNeutral},
+		{name: "中性", k: 50, d: 50, expected: KDJNeutral},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &KDJResult{K: []float64{tt.k}, D: []float64{tt.d}, J: []float64{3*tt.k - 2*tt.d}}
+			if got := result.GetDefaultSignal(); got != tt.expected {
+				t.Errorf("GetDefaultSignal() = %v, 期望 %v", KDJSignalToString(got), KDJSignalToString(tt.expected))
+			}
+		})
+	}
+}
+
+func TestKDJResult_Cross(t *testing.T) {
+	golden := &KDJResult{K: []float64{15, 22}, D: []float64{18, 20}, J: []float64{9, 26}}
+	if !golden.IsGoldenCross() || golden.IsDeathCross() {
+		t.Error("K上穿D应识别为金叉")
+	}
+
+	k, d, j := golden.GetLatestN(5)
+	if len(k) != 2 || len(d) != 2 || len(j) != 2 {
+		t.Errorf("GetLatestN(5) 长度 = %v/%v/%v, 期望 2", len(k), len(d), len(j))
+	}
+	if k, _, _ := (&KDJResult{}).GetLatest(); k != 0 {
+		t.Error("空结果应返回0")
+	}
+}
@@ -0,0 +1,104 @@
+package indicators
+
+import (
+	"errors"
+	"math"
+)
+
+// KeltnerResult 肯特纳通道计算结果
+type KeltnerResult struct {
+	Upper      []float64 // 上轨：中轨 + 倍数 × ATR
+	Middle     []float64 // 中轨：收盘价EMA
+	Lower      []float64 // 下轨：中轨 - 倍数 × ATR
+	EMAPeriod  int       // 中轨EMA周期
+	ATRPeriod  int       // ATR周期
+	Multiplier float64   // ATR倍数
+}
+
+// 肯特纳通道默认参数
+const (
+	DefaultKeltnerEMAPeriod  = 20  // 默认中轨EMA周期
+	DefaultKeltnerATRPeriod  = 10  // 默认ATR周期
+	DefaultKeltnerMultiplier = 2.0 // 默认ATR倍数
+)
+
+// CalculateKeltnerChannels 计算肯特纳通道
+// high/low/close: 最高价、最低价和收盘价序列（长度必须相同）
+// emaPeriod: 中轨EMA周期，通常为20
+// atrPeriod: ATR周期，通常为10
+// multiplier: ATR倍数，通常为2
+func CalculateKeltnerChannels(high, low, close []float64, emaPeriod, atrPeriod int, multiplier float64) (*KeltnerResult, error) {
+	if len(high) != len(close) || len(low) != len(close) {
+		return nil, errors.New("最高价、最低价和收盘价序列长度不一致")
+	}
+
+	if emaPeriod <= 0 || atrPeriod <= 0 {
+		return nil, errors.New("肯特纳通道周期参数必须大于0")
+	}
+
+	if len(close) < emaPeriod || len(close) < atrPeriod+1 {
+		return nil, errors.New("价格数据不足，无法计算肯特纳通道")
+	}
+
+	if multiplier <= 0 {
+		return nil, errors.New("ATR倍数必须大于0")
+	}
+
+	ema, err := CalculateEMA(close, emaPeriod)
+	if err != nil {
+		return nil, err
+	}
+	atrResult, err := CalculateATR(high, low, close, atrPeriod)
+	if err != nil {
+		return nil, err
+	}
+	atr := atrResult.Values
+
+	// EMA 和 ATR 起始位置不同，按最新值对齐到较短的序列
+	n := len(ema.Values)
+	if len(atr) < n {
+		n = len(atr)
+	}
+	middle := ema.Values[len(ema.Values)-n:]
+	atr = atr[len(atr)-n:]
+
+	result := &KeltnerResult{
+		Upper:      make([]float64, n),
+		Middle:     middle,
+		Lower:      make([]float64, n),
+		EMAPeriod:  emaPeriod,
+		ATRPeriod:  atrPeriod,
+		Multiplier: multiplier,
+	}
+	for i := range middle {
+		result.Upper[i] = middle[i] + multiplier*atr[i]
+		result.Lower[i] = middle[i] - multiplier*atr[i]
+	}
+
+	return result, nil
+}
+
+// CalculateDefaultKeltnerChannels 使用默认参数计算肯特纳通道
+func CalculateDefaultKeltnerChannels(high, low, close []float64) (*KeltnerResult, error) {
+	return CalculateKeltnerChannels(high, low, close, DefaultKeltnerEMAPeriod, DefaultKeltnerATRPeriod, DefaultKeltnerMultiplier)
+}
+
+// GetLatest 获取最新的上轨、中轨和下轨
+func (k *KeltnerResult) GetLatest() (upper, middle, lower float64) {
+	if len(k.Middle) == 0 {
+		return 0, 0, 0
+	}
+
+	idx := len(k.Middle) - 1
+	return k.Upper[idx], k.Middle[idx], k.Lower[idx]
+}
+
+// GetLatestN 获取最新的N个上轨、中轨和下轨值
+func (k *KeltnerResult) GetLatestN(n int) (upper, middle, lower []float64) {
+	if n <= 0 || len(k.Middle) == 0 {
+		return []float64{}, []float64{}, []float64{}
+	}
+
+	start := int(math.Max(0, float64(len(k.Middle)-n)))
+	return k.Upper[start:], k.Middle[start:], k.Lower[start:]
+}
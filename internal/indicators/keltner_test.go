@@ -0,0 +1,70 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+// 肯特纳通道测试数据
+var (
+	keltnerHigh  = []float64{10.5, 11.5, 12.5, 12, 13.5}
+	keltnerLow   = []float64{9.5, 10.5, 11, 10.5, 12}
+	keltnerClose = []float64{10, 11, 12, 11, 13}
+)
+
+func TestCalculateKeltnerChannels(t *testing.T) {
+	tests := []struct {
+		name       string
+		high       []float64
+		low        []float64
+		close      []float64
+		emaPeriod  int
+		atrPeriod  int
+		multiplier float64
+		wantErr    bool
+	}{
+		{name: "正常计算肯特纳通道", high: keltnerHigh, low: keltnerLow, close: keltnerClose, emaPeriod: 3, atrPeriod: 2, multiplier: 1},
+		{name: "序列长度不一致", high: keltnerHigh[:4], low: keltnerLow, close: keltnerClose, emaPeriod: 3, atrPeriod: 2, multiplier: 1, wantErr: true},
+		{name: "价格数据不足", high: keltnerHigh, low: keltnerLow, close: keltnerClose, emaPeriod: 3, atrPeriod: 5, multiplier: 1, wantErr: true},
+		{name: "周期为0", high: keltnerHigh, low: keltnerLow, close: keltnerClose, emaPeriod: 0, atrPeriod: 2, multiplier: 1, wantErr: true},
+		{name: "倍数为0", high: keltnerHigh, low: keltnerLow, close: keltnerClose, emaPeriod: 3, atrPeriod: 2, multiplier: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CalculateKeltnerChannels(tt.high, tt.low, tt.close, tt.emaPeriod, tt.atrPeriod, tt.multiplier)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CalculateKeltnerChannels() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestKeltnerAccuracy(t *testing.T) {
+	// 真实波幅: 1.5, 1.5, 1.5, 2.5；ATR(2): 1.5, 1.5, 2.0；EMA(3): 11, 11, 12
+	result, err := CalculateKeltnerChannels(keltnerHigh, keltnerLow, keltnerClose, 3, 2, 1)
+	if err != nil {
+		t.Fatalf("CalculateKeltnerChannels() 意外错误 = %v", err)
+	}
+
+	wantUpper := []float64{12.5, 12.5, 14}
+	wantMiddle := []float64{11, 11, 12}
+	wantLower := []float64{9.5, 9.5, 10}
+	if len(result.Middle) != len(wantMiddle) {
+		t.Fatalf("结果长度 = %v, 期望 %v", len(result.Middle), len(wantMiddle))
+	}
+	for i := range wantMiddle {
+		if math.Abs(result.Upper[i]-wantUpper[i]) > 1e-9 || math.Abs(result.Middle[i]-wantMiddle[i]) > 1e-9 || math.Abs(result.Lower[i]-wantLower[i]) > 1e-9 {
+			t.Errorf("第%d个值 = %v/%v/%v, 期望 %v/%v/%v", i,
+				result.Upper[i], result.Middle[i], result.Lower[i], wantUpper[i], wantMiddle[i], wantLower[i])
+		}
+	}
+
+	upper, middle, lower := result.GetLatest()
+	if upper != 14 || middle != 12 || lower != 10 {
+		t.Errorf("GetLatest() = %v, %v, %v", upper, middle, lower)
+	}
+	if u, _, _ := result.GetLatestN(2); len(u) != 2 || u[0] != 12.5 {
+		t.Errorf("GetLatestN(2) 上轨 = %v", u)
+	}
+}
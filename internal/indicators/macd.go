@@ -195,6 +195,8 @@ func (m *MACDResult) GetTrend() string {
 // IsDivergence 检测MACD背离
 // prices: 对应的价格序列
 // 返回：是否存在背离，背离类型（看涨/看跌）
+//
+// Deprecated: use CalculateDivergence
 func (m *MACDResult) IsDivergence(prices []float64) (bool, bool) {
 	if len(m.MACD) < 4 || len(prices) < len(m.MACD)+m.SlowPeriod {
 		return false, false
@@ -0,0 +1,141 @@
+package indicators
+
+import (
+	"errors"
+	"math"
+)
+
+// MFIResult 资金流量指标计算结果
+type MFIResult struct {
+	Values []float64 // MFI 值序列 (0-100)，第一个值对应第 period+1 根K线
+	Period int       // 计算周期
+}
+
+// MFISignal MFI信号类型
+type MFISignal int
+
+const (
+	MFINeutral MFISignal = iota // 中性
+	MFIBuy                      // 买入信号（超卖）
+	MFISell                     // 卖出信号（超买）
+)
+
+// MFI默认参数
+const (
+	DefaultMFIPeriod     = 14   // 默认MFI周期
+	DefaultMFIOverbought = 80.0 // 默认超买水平
+	DefaultMFIOversold   = 20.0 // 默认超卖水平
+)
+
+// CalculateMFI 计算资金流量指标（成交量加权的RSI）
+// 典型价格上涨时的资金流计入正向，下跌时计入负向，MFI = 100 - 100 / (1 + 正向资金流 / 负向资金流)
+// high/low/close/volume: 价格和成交量序列（长度必须相同）
+// period: 计算周期，通常为14
+func CalculateMFI(high, low, close, volume []float64, period int) (*MFIResult, error) {
+	if len(high) != len(close) || len(low) != len(close) || len(volume) != len(close) {
+		return nil, errors.New("价格和成交量序列长度不一致")
+	}
+
+	if period <= 0 {
+		return nil, errors.New("MFI周期必须大于0")
+	}
+
+	if len(close) < period+1 {
+		return nil, errors.New("价格数据不足，无法计算MFI指标")
+	}
+
+	// 第 i 个资金流对应第 i+1 根K线
+	positive := make([]float64, len(close)-1)
+	negative := make([]float64, len(close)-1)
+	prevTypical := (high[0] + low[0] + close[0]) / 3
+	for i := 1; i < len(close); i++ {
+		typical := (high[i] + low[i] + close[i]) / 3
+		flow := typical * volume[i]
+		if typical > prevTypical {
+			positive[i-1] = flow
+		} else if typical < prevTypical {
+			negative[i-1] = flow
+		}
+		prevTypical = typical
+	}
+
+	values := make([]float64, 0, len(positive)-period+1)
+	for i := period - 1; i < len(positive); i++ {
+		var pos, neg float64
+		for j := i - period + 1; j <= i; j++ {
+			pos += positive[j]
+			neg += negative[j]
+		}
+
+		switch {
+		case neg == 0 && pos == 0:
+			values = append(values, 50)
+		case neg == 0:
+			values = append(values, 100)
+		default:
+			values = append(values, 100-100/(1+pos/neg))
+		}
+	}
+
+	return &MFIResult{
+		Values: values,
+		Period: period,
+	}, nil
+}
+
+// CalculateDefaultMFI 使用默认参数计算MFI
+func CalculateDefaultMFI(high, low, close, volume []float64) (*MFIResult, error) {
+	return CalculateMFI(high, low, close, volume, DefaultMFIPeriod)
+}
+
+// GetLatest 获取最新的MFI值
+func (m *MFIResult) GetLatest() float64 {
+	if len(m.Values) == 0 {
+		return 0
+	}
+	return m.Values[len(m.Values)-1]
+}
+
+// GetLatestN 获取最新的N个MFI值
+func (m *MFIResult) GetLatestN(n int) []float64 {
+	if n <= 0 || len(m.Values) == 0 {
+		return []float64{}
+	}
+
+	start := int(math.Max(0, float64(len(m.Values)-n)))
+	return m.Values[start:]
+}
+
+// GetSignal 根据MFI值获取交易信号
+func (m *MFIResult) GetSignal(overboughtLevel, oversoldLevel float64) MFISignal {
+	if len(m.Values) == 0 {
+		return MFINeutral
+	}
+
+	latest := m.GetLatest()
+
+	if latest >= overboughtLevel {
+		return MFISell // 超买，考虑卖出
+	} else if latest <= oversoldLevel {
+		return MFIBuy // 超卖，考虑买入
+	}
+
+	return MFINeutral
+}
+
+// GetDefaultSignal 使用默认阈值获取交易信号
+func (m *MFIResult) GetDefaultSignal() MFISignal {
+	return m.GetSignal(DefaultMFIOverbought, DefaultMFIOversold)
+}
+
+// MFISignalToString 将MFI信号转换为字符串
+func MFISignalToString(signal MFISignal) string {
+	switch signal {
+	case MFIBuy:
+		return "买入信号"
+	case MFISell:
+		return "卖出信号"
+	default:
+		return "中性"
+	}
+}
@@ -0,0 +1,79 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateMFI(t *testing.T) {
+	tests := []struct {
+		name    string
+		volume  []float64
+		period  int
+		wantErr bool
+	}{
+		{name: "正常计算MFI-14周期", volume: refVolume, period: 14},
+		{name: "序列长度不一致", volume: refVolume[:10], period: 14, wantErr: true},
+		{name: "周期为0", volume: refVolume, period: 0, wantErr: true},
+		{name: "价格数据不足", volume: refVolume, period: 30, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CalculateMFI(refHigh, refLow, refClose, tt.volume, tt.period)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("CalculateMFI() 期望错误，但没有返回错误")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CalculateMFI() 意外错误 = %v", err)
+			}
+
+			expectedLength := len(refClose) - tt.period
+			if len(result.Values) != expectedLength {
+				t.Errorf("CalculateMFI() 结果长度 = %v, 期望 %v", len(result.Values), expectedLength)
+			}
+		})
+	}
+}
+
+func TestMFIAccuracy(t *testing.T) {
+	result, err := CalculateDefaultMFI(refHigh, refLow, refClose, refVolume)
+	if err != nil {
+		t.Fatalf("CalculateDefaultMFI() 意外错误 = %v", err)
+	}
+
+	if math.Abs(result.Values[0]-64.760769) > 1e-5 {
+		t.Errorf("第一个MFI = %v, 期望 64.760769", result.Values[0])
+	}
+	if math.Abs(result.GetLatest()-44.611336) > 1e-5 {
+		t.Errorf("最新MFI = %v, 期望 44.611336", result.GetLatest())
+	}
+	if result.GetDefaultSignal() != MFINeutral {
+		t.Errorf("GetDefaultSignal() = %v, 期望中性", MFISignalToString(result.GetDefaultSignal()))
+	}
+
+	// 典型价格持续上涨时没有负向资金流，MFI为100
+	rising := []float64{1, 2, 3, 4}
+	up, _ := CalculateMFI(rising, rising, rising, []float64{1, 1, 1, 1}, 3)
+	if up.GetLatest() != 100 || up.GetDefaultSignal() != MFISell {
+		t.Errorf("持续上涨时 MFI = %v, 期望 100 且为卖出信号", up.GetLatest())
+	}
+}
+
+func TestMFIResult_GetSignal(t *testing.T) {
+	oversold := &MFIResult{Values: []float64{30, 15}, Period: 14}
+	if oversold.GetDefaultSignal() != MFIBuy {
+		t.Error("MFI低于超卖线应返回买入信号")
+	}
+	if got := oversold.GetLatestN(1); len(got) != 1 || got[0] != 15 {
+		t.Errorf("GetLatestN(1) = %v", got)
+	}
+	if (&MFIResult{}).GetDefaultSignal() != MFINeutral {
+		t.Error("空结果应返回中性信号")
+	}
+}
@@ -0,0 +1,64 @@
+package indicators
+
+import (
+	"errors"
+	"math"
+)
+
+// OBVResult 能量潮计算结果
+type OBVResult struct {
+	Values []float64 // OBV 累计值序列，与输入等长，第一个值为0
+}
+
+// CalculateOBV 计算能量潮指标
+// 收盘价上涨时累加当根成交量，下跌时减去，持平时不变
+// close/volume: 收盘价和成交量序列（长度必须相同）
+func CalculateOBV(close, volume []float64) (*OBVResult, error) {
+	if len(close) != len(volume) {
+		return nil, errors.New("收盘价和成交量序列长度不一致")
+	}
+
+	if len(close) < 2 {
+		return nil, errors.New("价格数据不足，无法计算OBV指标")
+	}
+
+	values := make([]float64, len(close))
+	for i := 1; i < len(close); i++ {
+		switch {
+		case close[i] > close[i-1]:
+			values[i] = values[i-1] + volume[i]
+		case close[i] < close[i-1]:
+			values[i] = values[i-1] - volume[i]
+		default:
+			values[i] = values[i-1]
+		}
+	}
+
+	return &OBVResult{Values: values}, nil
+}
+
+// GetLatest 获取最新的OBV值
+func (o *OBVResult) GetLatest() float64 {
+	if len(o.Values) == 0 {
+		return 0
+	}
+	return o.Values[len(o.Values)-1]
+}
+
+// GetLatestN 获取最新的N个OBV值
+func (o *OBVResult) GetLatestN(n int) []float64 {
+	if n <= 0 || len(o.Values) == 0 {
+		return []float64{}
+	}
+
+	start := int(math.Max(0, float64(len(o.Values)-n)))
+	return o.Values[start:]
+}
+
+// GetChange 获取最近 n 根K线的OBV变化量，正值表示资金净流入
+func (o *OBVResult) GetChange(n int) float64 {
+	if n <= 0 || len(o.Values) <= n {
+		return 0
+	}
+	return o.GetLatest() - o.Values[len(o.Values)-1-n]
+}
@@ -0,0 +1,75 @@
+package indicators
+
+import (
+	"testing"
+)
+
+// 参考数据：与 refClose 对应的30根K线成交量，第25根为明显放量
+var refVolume = []float64{
+	1200, 1350, 980, 1100, 1420, 1600, 1250, 1380, 2100, 1900,
+	1750, 1300, 1150, 1480, 1620, 1700, 1550, 2400, 1800, 1650,
+	2200, 1400, 2600, 1950, 4800, 3900, 2100, 1700, 1600, 1500,
+}
+
+func TestCalculateOBV(t *testing.T) {
+	tests := []struct {
+		name    string
+		close   []float64
+		volume  []float64
+		wantErr bool
+	}{
+		{name: "正常计算OBV", close: refClose, volume: refVolume},
+		{name: "序列长度不一致", close: refClose, volume: refVolume[:10], wantErr: true},
+		{name: "价格数据不足", close: refClose[:1], volume: refVolume[:1], wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CalculateOBV(tt.close, tt.volume)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("CalculateOBV() 期望错误，但没有返回错误")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CalculateOBV() 意外错误 = %v", err)
+			}
+
+			if len(result.Values) != len(tt.close) {
+				t.Errorf("CalculateOBV() 结果长度 = %v, 期望 %v", len(result.Values), len(tt.close))
+			}
+		})
+	}
+}
+
+func TestOBVAccuracy(t *testing.T) {
+	result, err := CalculateOBV(refClose, refVolume)
+	if err != nil {
+		t.Fatalf("CalculateOBV() 意外错误 = %v", err)
+	}
+
+	if result.GetLatest() != -270 {
+		t.Errorf("最新OBV = %v, 期望 -270", result.GetLatest())
+	}
+	if got := result.GetChange(5); got != -3400 {
+		t.Errorf("GetChange(5) = %v, 期望 -3400", got)
+	}
+
+	// 收盘价持平时OBV不变
+	flat, _ := CalculateOBV([]float64{10, 11, 11, 10}, []float64{100, 200, 300, 400})
+	expected := []float64{0, 200, 200, -200}
+	for i, v := range flat.Values {
+		if v != expected[i] {
+			t.Errorf("第%d个OBV = %v, 期望 %v", i, v, expected[i])
+		}
+	}
+	if got := flat.GetLatestN(2); len(got) != 2 || got[0] != 200 {
+		t.Errorf("GetLatestN(2) = %v", got)
+	}
+	if flat.GetChange(10) != 0 {
+		t.Error("回看周期超过数据长度时 GetChange() 应返回0")
+	}
+}
@@ -0,0 +1,136 @@
+package indicators
+
+import (
+	"errors"
+	"math"
+)
+
+// PivotType 摆动点类型
+type PivotType int
+
+const (
+	PivotHigh PivotType = iota // 摆动高点
+	PivotLow                   // 摆动低点
+)
+
+// Pivot 摆动点（分形）
+type Pivot struct {
+	Index int       // 摆动点所在K线索引
+	Value float64   // 摆动点价格（高点取最高价，低点取最低价）
+	Type  PivotType // 摆动点类型
+}
+
+// PivotResult 摆动点检测结果
+// 摆动点需要其右侧 RightBars 根K线走完才能确认，因此最新的 RightBars 根K线上不会出现摆动点
+type PivotResult struct {
+	Highs     []Pivot // 摆动高点，按索引升序
+	Lows      []Pivot // 摆动低点，按索引升序
+	LeftBars  int     // 左侧比较K线数
+	RightBars int     // 右侧比较K线数
+}
+
+// 摆动点默认参数
+const (
+	DefaultPivotLeftBars  = 5 // 默认左侧K线数
+	DefaultPivotRightBars = 5 // 默认右侧K线数
+)
+
+// CalculatePivots 检测摆动高点和低点（分形）
+// 某根K线的最高价严格高于左侧 leftBars 根、且不低于右侧 rightBars 根K线的最高价时为摆动高点，低点同理；
+// 连续相等的极值只取第一根
+// high/low: 最高价和最低价序列（长度必须相同），只有单一序列时可以传入同一序列
+// leftBars/rightBars: 左右两侧比较的K线数，经典威廉分形为 2/2
+func CalculatePivots(high, low []float64, leftBars, rightBars int) (*PivotResult, error) {
+	if len(high) != len(low) {
+		return nil, errors.New("最高价和最低价序列长度不一致")
+	}
+
+	if leftBars <= 0 || rightBars <= 0 {
+		return nil, errors.New("摆动点左右K线数必须大于0")
+	}
+
+	if len(high) < leftBars+rightBars+1 {
+		return nil, errors.New("价格数据不足，无法检测摆动点")
+	}
+
+	return &PivotResult{
+		Highs:     findPivots(high, leftBars, rightBars, PivotHigh),
+		Lows:      findPivots(low, leftBars, rightBars, PivotLow),
+		LeftBars:  leftBars,
+		RightBars: rightBars,
+	}, nil
+}
+
+// CalculateDefaultPivots 使用默认参数检测摆动点
+func CalculateDefaultPivots(high, low []float64) (*PivotResult, error) {
+	return CalculatePivots(high, low, DefaultPivotLeftBars, DefaultPivotRightBars)
+}
+
+// findPivots 在单一序列中查找指定类型的摆动点，NaN 位置不参与比较
+func findPivots(values []float64, leftBars, rightBars int, pivotType PivotType) []Pivot {
+	// beats 判断 a 是否比 b 更极端
+	beats := func(a, b float64) bool {
+		if pivotType == PivotHigh {
+			return a > b
+		}
+		return a < b
+	}
+
+	var pivots []Pivot
+	for i := leftBars; i < len(values)-rightBars; i++ {
+		value := values[i]
+		if math.IsNaN(value) {
+			continue
+		}
+
+		isPivot := true
+		for j := i - leftBars; j < i && isPivot; j++ {
+			if math.IsNaN(values[j]) || !beats(value, values[j]) {
+				isPivot = false
+			}
+		}
+		for j := i + 1; j <= i+rightBars && isPivot; j++ {
+			if math.IsNaN(values[j]) || beats(values[j], value) {
+				isPivot = false
+			}
+		}
+
+		if isPivot {
+			pivots = append(pivots, Pivot{Index: i, Value: value, Type: pivotType})
+		}
+	}
+	return pivots
+}
+
+// GetLatestHigh 获取最近的摆动高点
+func (r *PivotResult) GetLatestHigh() (Pivot, bool) {
+	if len(r.Highs) == 0 {
+		return Pivot{}, false
+	}
+	return r.Highs[len(r.Highs)-1], true
+}
+
+// GetLatestLow 获取最近的摆动低点
+func (r *PivotResult) GetLatestLow() (Pivot, bool) {
+	if len(r.Lows) == 0 {
+		return Pivot{}, false
+	}
+	return r.Lows[len(r.Lows)-1], true
+}
+
+// ConfirmIndex 返回摆动点被确认的K线索引（右侧K线全部走完的位置）
+func (r *PivotResult) ConfirmIndex(p Pivot) int {
+	return p.Index + r.RightBars
+}
+
+// PivotTypeToString 将摆动点类型转换为字符串
+func PivotTypeToString(pivotType PivotType) string {
+	switch pivotType {
+	case PivotHigh:
+		return "摆动高点"
+	case PivotLow:
+		return "摆动低点"
+	default:
+		return "未知"
+	}
+}
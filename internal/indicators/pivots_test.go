@@ -0,0 +1,83 @@
+package indicators
+
+import "testing"
+
+func TestCalculatePivots(t *testing.T) {
+	high := []float64{1, 3, 2, 5, 4, 4, 6, 2, 1, 3, 2}
+	low := []float64{0, 2, 1, 4, 3, 3, 5, 1, 0, 2, 1}
+
+	result, err := CalculatePivots(high, low, 2, 2)
+	if err != nil {
+		t.Fatalf("CalculatePivots() 意外错误 = %v", err)
+	}
+
+	wantHighs := []int{3, 6}
+	if len(result.Highs) != len(wantHighs) {
+		t.Fatalf("摆动高点数量 = %v, 期望 %v", len(result.Highs), len(wantHighs))
+	}
+	for i, idx := range wantHighs {
+		if result.Highs[i].Index != idx || result.Highs[i].Value != high[idx] || result.Highs[i].Type != PivotHigh {
+			t.Errorf("第%d个摆动高点 = %+v, 期望索引 %v", i, result.Highs[i], idx)
+		}
+	}
+
+	wantLows := []int{8}
+	if len(result.Lows) != len(wantLows) {
+		t.Fatalf("摆动低点数量 = %v, 期望 %v", len(result.Lows), len(wantLows))
+	}
+	for i, idx := range wantLows {
+		if result.Lows[i].Index != idx || result.Lows[i].Type != PivotLow {
+			t.Errorf("第%d个摆动低点 = %+v, 期望索引 %v", i, result.Lows[i], idx)
+		}
+	}
+
+	if latest, ok := result.GetLatestHigh(); !ok || latest.Index != 6 {
+		t.Errorf("GetLatestHigh() = %+v, %v", latest, ok)
+	}
+	if latest, ok := result.GetLatestLow(); !ok || latest.Index != 8 {
+		t.Errorf("GetLatestLow() = %+v, %v", latest, ok)
+	}
+	if idx := result.ConfirmIndex(result.Lows[0]); idx != 10 {
+		t.Errorf("ConfirmIndex() = %v, 期望 10", idx)
+	}
+}
+
+func TestCalculatePivots_EqualValues(t *testing.T) {
+	// 平顶只取第一根，平顶右侧的相等K线不影响确认
+	values := []float64{1, 2, 5, 5, 3, 2, 1}
+	result, err := CalculatePivots(values, values, 2, 2)
+	if err != nil {
+		t.Fatalf("CalculatePivots() 意外错误 = %v", err)
+	}
+	if len(result.Highs) != 1 || result.Highs[0].Index != 2 {
+		t.Errorf("平顶摆动高点 = %+v, 期望只有索引 2", result.Highs)
+	}
+}
+
+func TestCalculatePivots_Errors(t *testing.T) {
+	values := []float64{1, 2, 3, 2, 1}
+
+	tests := []struct {
+		name  string
+		high  []float64
+		low   []float64
+		left  int
+		right int
+	}{
+		{"序列长度不一致", values, values[:4], 2, 2},
+		{"左侧K线数为0", values, values, 0, 2},
+		{"右侧K线数为负", values, values, 2, -1},
+		{"数据不足", values, values, 3, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := CalculatePivots(tt.high, tt.low, tt.left, tt.right); err == nil {
+				t.Error("CalculatePivots() 期望错误，但没有返回错误")
+			}
+		})
+	}
+
+	if _, ok := (&PivotResult{}).GetLatestHigh(); ok {
+		t.Error("没有摆动点时 GetLatestHigh() 不应返回结果")
+	}
+}
@@ -145,6 +145,8 @@ func (r *RSIResult) GetDefaultSignal() RSISignal {
 // IsDivergence 检测RSI背离
 // prices: 对应的价格序列
 // 返回：是否存在背离，背离类型（看涨/看跌）
+//
+// Deprecated: use CalculateDivergence
 func (r *RSIResult) IsDivergence(prices []float64) (bool, bool) {
 	if len(r.Values) < 4 || len(prices) < len(r.Values)+r.Period {
 		return false, false
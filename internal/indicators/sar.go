@@ -0,0 +1,140 @@
+package indicators
+
+import (
+	"errors"
+	"math"
+)
+
+// SARResult 抛物线转向指标计算结果
+// 序列第一个值对应第2根K线
+type SARResult struct {
+	Values  []float64 // SAR 值序列（止损/反转价位）
+	Uptrend []bool    // 对应位置是否处于上升趋势（SAR 位于价格下方）
+	Step    float64   // 加速因子步长
+	Max     float64   // 加速因子上限
+}
+
+// 抛物线转向默认参数
+const (
+	DefaultSARStep = 0.02 // 默认加速因子步长
+	DefaultSARMax  = 0.2  // 默认加速因子上限
+)
+
+// CalculateParabolicSAR 计算威尔德抛物线转向指标
+// high/low: 最高价和最低价序列（长度必须相同）
+// step: 加速因子初始值和步长，通常为0.02
+// max: 加速因子上限，通常为0.2
+func CalculateParabolicSAR(high, low []float64, step, max float64) (*SARResult, error) {
+	if len(high) != len(low) {
+		return nil, errors.New("最高价和最低价序列长度不一致")
+	}
+
+	if step <= 0 || max < step {
+		return nil, errors.New("加速因子步长必须大于0且不超过上限")
+	}
+
+	if len(high) < 2 {
+		return nil, errors.New("价格数据不足，无法计算抛物线转向指标")
+	}
+
+	// 根据前两根K线的趋向变动确定初始方向：下跌幅度更大时为下降趋势
+	uptrend := low[0]-low[1] <= high[1]-high[0]
+	var sar, extreme float64
+	if uptrend {
+		sar, extreme = low[0], high[1]
+	} else {
+		sar, extreme = high[0], low[1]
+	}
+	af := step
+
+	values := []float64{sar}
+	trends := []bool{uptrend}
+
+	for i := 2; i < len(high); i++ {
+		next := sar + af*(extreme-sar)
+
+		if uptrend {
+			// SAR 不能高于前两根K线的最低价
+			next = math.Min(next, math.Min(low[i-1], low[i-2]))
+			if low[i] < next {
+				// 跌破 SAR，反转为下降趋势，新 SAR 为上升趋势中的极值
+				uptrend = false
+				next = extreme
+				extreme = low[i]
+				af = step
+			} else if high[i] > extreme {
+				extreme = high[i]
+				af = math.Min(af+step, max)
+			}
+		} else {
+			// SAR 不能低于前两根K线的最高价
+			next = math.Max(next, math.Max(high[i-1], high[i-2]))
+			if high[i] > next {
+				// 突破 SAR，反转为上升趋势
+				uptrend = true
+				next = extreme
+				extreme = high[i]
+				af = step
+			} else if low[i] < extreme {
+				extreme = low[i]
+				af = math.Min(af+step, max)
+			}
+		}
+
+		sar = next
+		values = append(values, sar)
+		trends = append(trends, uptrend)
+	}
+
+	return &SARResult{
+		Values:  values,
+		Uptrend: trends,
+		Step:    step,
+		Max:     max,
+	}, nil
+}
+
+// CalculateDefaultParabolicSAR 使用默认参数计算抛物线转向指标
+func CalculateDefaultParabolicSAR(high, low []float64) (*SARResult, error) {
+	return CalculateParabolicSAR(high, low, DefaultSARStep, DefaultSARMax)
+}
+
+// GetLatest 获取最新的SAR值
+func (s *SARResult) GetLatest() float64 {
+	if len(s.Values) == 0 {
+		return 0
+	}
+	return s.Values[len(s.Values)-1]
+}
+
+// GetLatestN 获取最新的N个SAR值
+func (s *SARResult) GetLatestN(n int) []float64 {
+	if n <= 0 || len(s.Values) == 0 {
+		return []float64{}
+	}
+
+	start := int(math.Max(0, float64(len(s.Values)-n)))
+	return s.Values[start:]
+}
+
+// IsUptrend 检查最新位置是否处于上升趋势
+func (s *SARResult) IsUptrend() bool {
+	if len(s.Uptrend) == 0 {
+		return false
+	}
+	return s.Uptrend[len(s.Uptrend)-1]
+}
+
+// IsReversal 检查最新K线是否发生趋势反转
+// 返回：是否反转，反转后的方向（true 为转为上升趋势）
+func (s *SARResult) IsReversal() (bool, bool) {
+	if len(s.Uptrend) < 2 {
+		return false, false
+	}
+
+	latest := s.Uptrend[len(s.Uptrend)-1]
+	if latest != s.Uptrend[len(s.Uptrend)-2] {
+		return true, latest
+	}
+	return false, false
+}
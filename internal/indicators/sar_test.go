@@ -0,0 +1,110 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateParabolicSAR(t *testing.T) {
+	tests := []struct {
+		name    string
+		high    []float64
+		low     []float64
+		step    float64
+		max     float64
+		wantErr bool
+	}{
+		{name: "正常计算SAR", high: refHigh, low: refLow, step: DefaultSARStep, max: DefaultSARMax},
+		{name: "序列长度不一致", high: refHigh, low: refLow[:10], step: 0.02, max: 0.2, wantErr: true},
+		{name: "价格数据不足", high: refHigh[:1], low: refLow[:1], step: 0.02, max: 0.2, wantErr: true},
+		{name: "步长为0", high: refHigh, low: refLow, step: 0, max: 0.2, wantErr: true},
+		{name: "上限小于步长", high: refHigh, low: refLow, step: 0.02, max: 0.01, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CalculateParabolicSAR(tt.high, tt.low, tt.step, tt.max)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("CalculateParabolicSAR() 期望错误，但没有返回错误")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CalculateParabolicSAR() 意外错误 = %v", err)
+			}
+
+			if len(result.Values) != len(tt.high)-1 || len(result.Uptrend) != len(result.Values) {
+				t.Errorf("CalculateParabolicSAR() 结果长度 = %v, 期望 %v", len(result.Values), len(tt.high)-1)
+			}
+
+			// 上升趋势中 SAR 位于最低价下方，下降趋势中位于最高价上方
+			for i, sar := range result.Values {
+				bar := i + 1
+				if result.Uptrend[i] && sar > tt.low[bar] || !result.Uptrend[i] && sar < tt.high[bar] {
+					t.Errorf("第%d根K线 SAR %v 位于价格错误一侧（上升趋势 %v）", bar, sar, result.Uptrend[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParabolicSARAccuracy(t *testing.T) {
+	// 逐步推演：上升趋势中加速因子随新高递增，最后一根跌破 SAR 后反转，新 SAR 为此前最高价
+	high := []float64{10, 11, 12, 13, 12, 10}
+	low := []float64{9, 10, 11, 12, 10, 8}
+
+	result, err := CalculateParabolicSAR(high, low, 0.02, 0.2)
+	if err != nil {
+		t.Fatalf("CalculateParabolicSAR() 意外错误 = %v", err)
+	}
+
+	want := []float64{9, 9, 9.12, 9.3528, 13}
+	wantTrend := []bool{true, true, true, true, false}
+	for i := range want {
+		if math.Abs(result.Values[i]-want[i]) > 1e-9 || result.Uptrend[i] != wantTrend[i] {
+			t.Errorf("第%d个SAR = %v (上升 %v), 期望 %v (上升 %v)", i, result.Values[i], result.Uptrend[i], want[i], wantTrend[i])
+		}
+	}
+
+	if result.GetLatest() != 13 || result.IsUptrend() {
+		t.Errorf("GetLatest() = %v, IsUptrend() = %v", result.GetLatest(), result.IsUptrend())
+	}
+	if reversal, up := result.IsReversal(); !reversal || up {
+		t.Errorf("IsReversal() = %v, %v, 期望转为下降趋势", reversal, up)
+	}
+	if got := result.GetLatestN(2); len(got) != 2 || math.Abs(got[0]-9.3528) > 1e-9 {
+		t.Errorf("GetLatestN(2) = %v", got)
+	}
+}
+
+func TestParabolicSARMaxAcceleration(t *testing.T) {
+	// 持续创新高时加速因子不超过上限
+	n := 40
+	high, low := make([]float64, n), make([]float64, n)
+	for i := 0; i < n; i++ {
+		high[i] = 100 + float64(i)
+		low[i] = 99 + float64(i)
+	}
+
+	result, err := CalculateDefaultParabolicSAR(high, low)
+	if err != nil {
+		t.Fatalf("CalculateDefaultParabolicSAR() 意外错误 = %v", err)
+	}
+	if !result.IsUptrend() {
+		t.Fatal("持续上涨应保持上升趋势")
+	}
+
+	// 加速因子达到上限后 SAR 与极值的距离按 (1-0.2) 收敛
+	last := len(result.Values) - 1
+	prevGap := high[last] - result.Values[last-1]
+	gap := high[last+1] - result.Values[last]
+	if math.Abs(gap-(prevGap*0.8+1)) > 1e-9 {
+		t.Errorf("SAR 间距 = %v, 期望 %v", gap, prevGap*0.8+1)
+	}
+	if reversal, _ := result.IsReversal(); reversal {
+		t.Error("持续上涨不应发生反转")
+	}
+}
@@ -0,0 +1,183 @@
+package indicators
+
+import (
+	"errors"
+	"math"
+)
+
+// StochasticResult 随机指标计算结果
+// K 和 D 序列长度相同，第一个值对应第 kPeriod+smoothK+dPeriod-2 根K线
+type StochasticResult struct {
+	K       []float64 // 慢速 %K：原始 %K 的 smoothK 周期简单平均 (0-100)
+	D       []float64 // %D：%K 的 dPeriod 周期简单平均 (0-100)
+	KPeriod int       // 最高/最低价回看周期
+	SmoothK int       // %K 平滑周期
+	DPeriod int       // %D 周期
+}
+
+// StochasticSignal 随机指标信号类型
+type StochasticSignal int
+
+const (
+	StochasticNeutral StochasticSignal = iota // 中性
+	StochasticBuy                             // 买入信号（超卖）
+	StochasticSell                            // 卖出信号（超买）
+)
+
+// 随机指标默认参数
+const (
+	DefaultStochasticKPeriod    = 14   // 默认回看周期
+	DefaultStochasticSmoothK    = 3    // 默认 %K 平滑周期
+	DefaultStochasticDPeriod    = 3    // 默认 %D 周期
+	DefaultStochasticOverbought = 80.0 // 默认超买水平
+	DefaultStochasticOversold   = 20.0 // 默认超卖水平
+)
+
+// CalculateStochastic 计算慢速随机指标
+// high/low/close: 最高价、最低价和收盘价序列（长度必须相同）
+// kPeriod: 最高/最低价回看周期，通常为14
+// smoothK: %K 平滑周期，通常为3（为1时即快速随机指标）
+// dPeriod: %D 周期，通常为3
+func CalculateStochastic(high, low, close []float64, kPeriod, smoothK, dPeriod int) (*StochasticResult, error) {
+	if len(high) != len(close) || len(low) != len(close) {
+		return nil, errors.New("最高价、最低价和收盘价序列长度不一致")
+	}
+
+	if kPeriod <= 0 || smoothK <= 0 || dPeriod <= 0 {
+		return nil, errors.New("随机指标周期参数必须大于0")
+	}
+
+	if len(close) < kPeriod+smoothK+dPeriod-2 {
+		return nil, errors.New("价格数据不足，无法计算随机指标")
+	}
+
+	k, d := smoothStochastic(calculateRawStochastic(high, low, close, kPeriod), smoothK, dPeriod)
+	return &StochasticResult{
+		K:       k,
+		D:       d,
+		KPeriod: kPeriod,
+		SmoothK: smoothK,
+		DPeriod: dPeriod,
+	}, nil
+}
+
+// CalculateDefaultStochastic 使用默认参数计算随机指标
+func CalculateDefaultStochastic(high, low, close []float64) (*StochasticResult, error) {
+	return CalculateStochastic(high, low, close, DefaultStochasticKPeriod, DefaultStochasticSmoothK, DefaultStochasticDPeriod)
+}
+
+// calculateRawStochastic 计算原始 %K（即 RSV），第一个值对应第 period 根K线
+// %K = (收盘价 - 周期最低价) / (周期最高价 - 周期最低价) × 100，区间为零时取50
+func calculateRawStochastic(high, low, close []float64, period int) []float64 {
+	values := make([]float64, 0, len(close)-period+1)
+	for i := period - 1; i < len(close); i++ {
+		highest, lowest := high[i], low[i]
+		for j := i - period + 1; j < i; j++ {
+			highest = math.Max(highest, high[j])
+			lowest = math.Min(lowest, low[j])
+		}
+
+		if highest == lowest {
+			values = append(values, 50)
+			continue
+		}
+		values = append(values, (close[i]-lowest)/(highest-lowest)*100)
+	}
+	return values
+}
+
+// smoothStochastic 对原始 %K 做两次简单平均得到 %K 和 %D，并按 %D 的起始位置对齐
+func smoothStochastic(raw []float64, smoothK, dPeriod int) (k, d []float64) {
+	k = simpleMovingAverage(raw, smoothK)
+	d = simpleMovingAverage(k, dPeriod)
+	return k[len(k)-len(d):], d
+}
+
+// simpleMovingAverage 计算简单移动平均，调用方需保证数据长度不小于周期
+func simpleMovingAverage(values []float64, period int) []float64 {
+	result := make([]float64, 0, len(values)-period+1)
+	sum := 0.0
+	for i, v := range values {
+		sum += v
+		if i >= period {
+			sum -= values[i-period]
+		}
+		if i >= period-1 {
+			result = append(result, sum/float64(period))
+		}
+	}
+	return result
+}
+
+// isCrossAbove 检查序列 a 是否在最新位置上穿序列 b（两个序列按末尾对齐）
+func isCrossAbove(a, b []float64) bool {
+	if len(a) < 2 || len(b) < 2 {
+		return false
+	}
+	i, j := len(a)-1, len(b)-1
+	return a[i] > b[j] && a[i-1] <= b[j-1]
+}
+
+// GetLatest 获取最新的 %K 和 %D 值
+func (s *StochasticResult) GetLatest() (k, d float64) {
+	if len(s.K) == 0 {
+		return 0, 0
+	}
+
+	idx := len(s.K) - 1
+	return s.K[idx], s.D[idx]
+}
+
+// GetLatestN 获取最新的N个 %K 和 %D 值
+func (s *StochasticResult) GetLatestN(n int) (k, d []float64) {
+	if n <= 0 || len(s.K) == 0 {
+		return []float64{}, []float64{}
+	}
+
+	start := int(math.Max(0, float64(len(s.K)-n)))
+	return s.K[start:], s.D[start:]
+}
+
+// GetSignal 根据 %K 值获取交易信号
+func (s *StochasticResult) GetSignal(overboughtLevel, oversoldLevel float64) StochasticSignal {
+	if len(s.K) == 0 {
+		return StochasticNeutral
+	}
+
+	k, _ := s.GetLatest()
+
+	if k >= overboughtLevel {
+		return StochasticSell // 超买，考虑卖出
+	} else if k <= oversoldLevel {
+		return StochasticBuy // 超卖，考虑买入
+	}
+
+	return StochasticNeutral
+}
+
+// GetDefaultSignal 使用默认阈值获取交易信号
+func (s *StochasticResult) GetDefaultSignal() StochasticSignal {
+	return s.GetSignal(DefaultStochasticOverbought, DefaultStochasticOversold)
+}
+
+// IsBullishCross 检查 %K 是否上穿 %D
+func (s *StochasticResult) IsBullishCross() bool {
+	return isCrossAbove(s.K, s.D)
+}
+
+// IsBearishCross 检查 %K 是否下穿 %D
+func (s *StochasticResult) IsBearishCross() bool {
+	return isCrossAbove(s.D, s.K)
+}
+
+// StochasticSignalToString 将随机指标信号转换为字符串
+func StochasticSignalToString(signal StochasticSignal) string {
+	switch signal {
+	case StochasticBuy:
+		return "买入信号"
+	case StochasticSell:
+		return "卖出信号"
+	default:
+		return "中性"
+	}
+}
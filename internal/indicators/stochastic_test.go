@@ -0,0 +1,115 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateStochastic(t *testing.T) {
+	tests := []struct {
+		name    string
+		high    []float64
+		low     []float64
+		close   []float64
+		kPeriod int
+		wantErr bool
+	}{
+		{name: "正常计算Stoch(14,3,3)", high: refHigh, low: refLow, close: refClose, kPeriod: 14},
+		{name: "序列长度不一致", high: refHigh[:10], low: refLow, close: refClose, kPeriod: 14, wantErr: true},
+		{name: "价格数据不足", high: refHigh[:17], low: refLow[:17], close: refClose[:17], kPeriod: 14, wantErr: true},
+		{name: "周期为0", high: refHigh, low: refLow, close: refClose, kPeriod: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CalculateStochastic(tt.high, tt.low, tt.close, tt.kPeriod, 3, 3)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("CalculateStochastic() 期望错误，但没有返回错误")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CalculateStochastic() 意外错误 = %v", err)
+			}
+
+			expectedLength := len(tt.close) - tt.kPeriod - 3 - 3 + 3
+			if len(result.K) != expectedLength || len(result.D) != expectedLength {
+				t.Errorf("CalculateStochastic() 结果长度 = %v/%v, 期望 %v", len(result.K), len(result.D), expectedLength)
+			}
+		})
+	}
+}
+
+func TestStochasticAccuracy(t *testing.T) {
+	result, err := CalculateDefaultStochastic(refHigh, refLow, refClose)
+	if err != nil {
+		t.Fatalf("CalculateDefaultStochastic() 意外错误 = %v", err)
+	}
+
+	if math.Abs(result.D[0]-89.790800) > 1e-5 || math.Abs(result.K[0]-77.846242) > 1e-5 {
+		t.Errorf("第一个 %%K/%%D = %v/%v, 期望 77.846242/89.790800", result.K[0], result.D[0])
+	}
+
+	k, d := result.GetLatest()
+	if math.Abs(k-71.575092) > 1e-5 || math.Abs(d-67.460317) > 1e-5 {
+		t.Errorf("最新 %%K/%%D = %v/%v, 期望 71.575092/67.460317", k, d)
+	}
+
+	for i := range result.K {
+		if result.K[i] < 0 || result.K[i] > 100 || result.D[i] < 0 || result.D[i] > 100 {
+			t.Errorf("第%d个值超出0-100区间: %v/%v", i, result.K[i], result.D[i])
+		}
+	}
+
+	// 价格完全不变时区间为零，%K 取50
+	flat := []float64{10, 10, 10, 10, 10}
+	flatResult, _ := CalculateStochastic(flat, flat, flat, 3, 1, 1)
+	if k, _ := flatResult.GetLatest(); k != 50 {
+		t.Errorf("价格不变时 %%K = %v, 期望 50", k)
+	}
+}
+
+func TestStochasticResult_GetSignal(t *testing.T) {
+	tests := []struct {
+		name     string
+		k        float64
+		expected StochasticSignal
+	}{
+		{name: "超买", k: 85, expected: StochasticSell},
+		{name: "超卖", k: 15, expected: StochasticBuy},
+		{name: "中性", k: 50, expected: StochasticNeutral},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &StochasticResult{K: []float64{tt.k}, D: []float64{50}}
+			if got := result.GetDefaultSignal(); got != tt.expected {
+				t.Errorf("GetDefaultSignal() = %v, 期望 %v", StochasticSignalToString(got), StochasticSignalToString(tt.expected))
+			}
+		})
+	}
+
+	if (&StochasticResult{}).GetDefaultSignal() != StochasticNeutral {
+		t.Error("空结果应返回中性信号")
+	}
+}
+
+func TestStochasticResult_Cross(t *testing.T) {
+	bullish := &StochasticResult{K: []float64{18, 25}, D: []float64{20, 21}}
+	if !bullish.IsBullishCross() || bullish.IsBearishCross() {
+		t.Error("%K 上穿 %D 应识别为看涨交叉")
+	}
+
+	bearish := &StochasticResult{K: []float64{82, 75}, D: []float64{80, 79}}
+	if !bearish.IsBearishCross() || bearish.IsBullishCross() {
+		t.Error("%K 下穿 %D 应识别为看跌交叉")
+	}
+
+	k, d := bearish.GetLatestN(1)
+	if len(k) != 1 || k[0] != 75 || d[0] != 79 {
+		t.Errorf("GetLatestN(1) = %v/%v", k, d)
+	}
+}
@@ -0,0 +1,135 @@
+package indicators
+
+import (
+	"errors"
+	"math"
+)
+
+// StochRSIResult 随机相对强弱指标计算结果
+// K 和 D 序列长度相同，数值区间为 0-100
+type StochRSIResult struct {
+	K           []float64 // StochRSI 的 kPeriod 周期简单平均
+	D           []float64 // K 的 dPeriod 周期简单平均
+	RSIPeriod   int       // RSI 周期
+	StochPeriod int       // RSI 最高/最低值回看周期
+	KPeriod     int       // K 平滑周期
+	DPeriod     int       // D 周期
+}
+
+// StochRSISignal StochRSI信号类型
+type StochRSISignal int
+
+const (
+	StochRSINeutral StochRSISignal = iota // 中性
+	StochRSIBuy                           // 买入信号（超卖）
+	StochRSISell                          // 卖出信号（超买）
+)
+
+// StochRSI默认参数
+const (
+	DefaultStochRSIPeriod     = 14   // 默认RSI周期及回看周期
+	DefaultStochRSIKPeriod    = 3    // 默认K平滑周期
+	DefaultStochRSIDPeriod    = 3    // 默认D周期
+	DefaultStochRSIOverbought = 80.0 // 默认超买水平
+	DefaultStochRSIOversold   = 20.0 // 默认超卖水平
+)
+
+// CalculateStochRSI 计算随机相对强弱指标
+// prices: 价格序列（通常是收盘价）
+// rsiPeriod: RSI 周期，通常为14
+// stochPeriod: 在RSI序列上取最高/最低值的回看周期，通常为14
+// kPeriod/dPeriod: K 和 D 的平滑周期，通常为3
+func CalculateStochRSI(prices []float64, rsiPeriod, stochPeriod, kPeriod, dPeriod int) (*StochRSIResult, error) {
+	if rsiPeriod <= 0 || stochPeriod <= 0 || kPeriod <= 0 || dPeriod <= 0 {
+		return nil, errors.New("StochRSI周期参数必须大于0")
+	}
+
+	if len(prices) < rsiPeriod+stochPeriod+kPeriod+dPeriod-2 {
+		return nil, errors.New("价格数据不足，无法计算StochRSI指标")
+	}
+
+	rsi, err := CalculateRSI(prices, rsiPeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	// 把RSI序列同时当作最高价、最低价和收盘价，套用随机指标公式
+	k, d := smoothStochastic(calculateRawStochastic(rsi.Values, rsi.Values, rsi.Values, stochPeriod), kPeriod, dPeriod)
+	return &StochRSIResult{
+		K:           k,
+		D:           d,
+		RSIPeriod:   rsiPeriod,
+		StochPeriod: stochPeriod,
+		KPeriod:     kPeriod,
+		DPeriod:     dPeriod,
+	}, nil
+}
+
+// CalculateDefaultStochRSI 使用默认参数计算StochRSI
+func CalculateDefaultStochRSI(prices []float64) (*StochRSIResult, error) {
+	return CalculateStochRSI(prices, DefaultStochRSIPeriod, DefaultStochRSIPeriod, DefaultStochRSIKPeriod, DefaultStochRSIDPeriod)
+}
+
+// GetLatest 获取最新的K和D值
+func (s *StochRSIResult) GetLatest() (k, d float64) {
+	if len(s.K) == 0 {
+		return 0, 0
+	}
+
+	idx := len(s.K) - 1
+	return s.K[idx], s.D[idx]
+}
+
+// GetLatestN 获取最新的N个K和D值
+func (s *StochRSIResult) GetLatestN(n int) (k, d []float64) {
+	if n <= 0 || len(s.K) == 0 {
+		return []float64{}, []float64{}
+	}
+
+	start := int(math.Max(0, float64(len(s.K)-n)))
+	return s.K[start:], s.D[start:]
+}
+
+// GetSignal 根据K值获取交易信号
+func (s *StochRSIResult) GetSignal(overboughtLevel, oversoldLevel float64) StochRSISignal {
+	if len(s.K) == 0 {
+		return StochRSINeutral
+	}
+
+	k, _ := s.GetLatest()
+
+	if k >= overboughtLevel {
+		return StochRSISell // 超买，考虑卖出
+	} else if k <= oversoldLevel {
+		return StochRSIBuy // 超卖，考虑买入
+	}
+
+	return StochRSINeutral
+}
+
+// GetDefaultSignal 使用默认阈值获取交易信号
+func (s *StochRSIResult) GetDefaultSignal() StochRSISignal {
+	return s.GetSignal(DefaultStochRSIOverbought, DefaultStochRSIOversold)
+}
+
+// IsBullishCross 检查K是否上穿D
+func (s *StochRSIResult) IsBullishCross() bool {
+	return isCrossAbove(s.K, s.D)
+}
+
+// IsBearishCross 检查K是否下穿D
+func (s *StochRSIResult) IsBearishCross() bool {
+	return isCrossAbove(s.D, s.K)
+}
+
+// StochRSISignalToString 将StochRSI信号转换为字符串
+func StochRSISignalToString(signal StochRSISignal) string {
+	switch signal {
+	case StochRSIBuy:
+		return "买入信号"
+	case StochRSISell:
+		return "卖出信号"
+	default:
+		return "中性"
+	}
+}
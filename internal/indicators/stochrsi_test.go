@@ -0,0 +1,77 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateStochRSI(t *testing.T) {
+	tests := []struct {
+		name      string
+		prices    []float64
+		rsiPeriod int
+		wantErr   bool
+	}{
+		{name: "正常计算StochRSI(5,5,3,3)", prices: refClose, rsiPeriod: 5},
+		{name: "价格数据不足", prices: refClose[:10], rsiPeriod: 5, wantErr: true},
+		{name: "周期为0", prices: refClose, rsiPeriod: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CalculateStochRSI(tt.prices, tt.rsiPeriod, 5, 3, 3)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("CalculateStochRSI() 期望错误，但没有返回错误")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CalculateStochRSI() 意外错误 = %v", err)
+			}
+
+			expectedLength := len(tt.prices) - tt.rsiPeriod - 5 - 3 - 3 + 3
+			if len(result.K) != expectedLength || len(result.D) != expectedLength {
+				t.Errorf("CalculateStochRSI() 结果长度 = %v/%v, 期望 %v", len(result.K), len(result.D), expectedLength)
+			}
+		})
+	}
+}
+
+func TestStochRSIAccuracy(t *testing.T) {
+	result, err := CalculateStochRSI(refClose, 5, 5, 3, 3)
+	if err != nil {
+		t.Fatalf("CalculateStochRSI() 意外错误 = %v", err)
+	}
+
+	k, d := result.GetLatest()
+	if math.Abs(k-92.142268) > 1e-5 || math.Abs(d-85.946871) > 1e-5 {
+		t.Errorf("最新 K/D = %v/%v, 期望 92.142268/85.946871", k, d)
+	}
+	if result.GetDefaultSignal() != StochRSISell {
+		t.Errorf("GetDefaultSignal() = %v, 期望卖出信号", StochRSISignalToString(result.GetDefaultSignal()))
+	}
+
+	// 默认参数需要 14+14+3+3-2 = 32 个数据点
+	if _, err := CalculateDefaultStochRSI(refClose); err == nil {
+		t.Error("30个数据点不足以计算默认StochRSI")
+	}
+}
+
+func TestStochRSIResult_GetSignal(t *testing.T) {
+	oversold := &StochRSIResult{K: []float64{30, 10}, D: []float64{20, 15}}
+	if oversold.GetDefaultSignal() != StochRSIBuy {
+		t.Error("K低于超卖线应返回买入信号")
+	}
+	if oversold.IsBullishCross() || !oversold.IsBearishCross() {
+		t.Error("K下穿D应识别为看跌交叉")
+	}
+	if (&StochRSIResult{}).GetDefaultSignal() != StochRSINeutral {
+		t.Error("空结果应返回中性信号")
+	}
+	if StochRSISignalToString(StochRSINeutral) != "中性" {
+		t.Error("StochRSISignalToString() 中性信号描述错误")
+	}
+}
@@ -0,0 +1,134 @@
+package indicators
+
+import (
+	"errors"
+	"math"
+)
+
+// SuperTrendResult 超级趋势指标计算结果
+// 序列第一个值对应第 period+1 根K线（与ATR起始位置相同）
+type SuperTrendResult struct {
+	Values     []float64 // SuperTrend 值序列：上升趋势时为下轨，下降趋势时为上轨
+	Uptrend    []bool    // 对应位置是否处于上升趋势
+	Period     int       // ATR周期
+	Multiplier float64   // ATR倍数
+}
+
+// 超级趋势默认参数
+const (
+	DefaultSuperTrendPeriod     = 10  // 默认ATR周期
+	DefaultSuperTrendMultiplier = 3.0 // 默认ATR倍数
+)
+
+// CalculateSuperTrend 计算超级趋势指标
+// 基础上下轨 = (最高价 + 最低价) / 2 ± 倍数 × ATR；下轨只升不降、上轨只降不升，收盘价穿越当前轨道时趋势翻转
+// high/low/close: 最高价、最低价和收盘价序列（长度必须相同）
+// period: ATR周期，通常为10
+// multiplier: ATR倍数，通常为3
+func CalculateSuperTrend(high, low, close []float64, period int, multiplier float64) (*SuperTrendResult, error) {
+	if len(high) != len(close) || len(low) != len(close) {
+		return nil, errors.New("最高价、最低价和收盘价序列长度不一致")
+	}
+
+	if period <= 0 {
+		return nil, errors.New("SuperTrend周期必须大于0")
+	}
+
+	if multiplier <= 0 {
+		return nil, errors.New("ATR倍数必须大于0")
+	}
+
+	atr, err := CalculateATR(high, low, close, period)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SuperTrendResult{
+		Values:     make([]float64, len(atr.Values)),
+		Uptrend:    make([]bool, len(atr.Values)),
+		Period:     period,
+		Multiplier: multiplier,
+	}
+
+	var upper, lower float64
+	uptrend := true
+	for k, atrValue := range atr.Values {
+		i := k + period // 对应的K线索引
+		mid := (high[i] + low[i]) / 2
+		basicUpper := mid + multiplier*atrValue
+		basicLower := mid - multiplier*atrValue
+
+		if k == 0 {
+			upper, lower = basicUpper, basicLower
+			uptrend = close[i] >= mid
+		} else {
+			// 前收盘价未突破时轨道只向价格方向收紧
+			if basicUpper < upper || close[i-1] > upper {
+				upper = basicUpper
+			}
+			if basicLower > lower || close[i-1] < lower {
+				lower = basicLower
+			}
+
+			if uptrend && close[i] < lower {
+				uptrend = false
+			} else if !uptrend && close[i] > upper {
+				uptrend = true
+			}
+		}
+
+		result.Uptrend[k] = uptrend
+		if uptrend {
+			result.Values[k] = lower
+		} else {
+			result.Values[k] = upper
+		}
+	}
+
+	return result, nil
+}
+
+// CalculateDefaultSuperTrend 使用默认参数计算超级趋势指标
+func CalculateDefaultSuperTrend(high, low, close []float64) (*SuperTrendResult, error) {
+	return CalculateSuperTrend(high, low, close, DefaultSuperTrendPeriod, DefaultSuperTrendMultiplier)
+}
+
+// GetLatest 获取最新的SuperTrend值
+func (s *SuperTrendResult) GetLatest() float64 {
+	if len(s.Values) == 0 {
+		return 0
+	}
+	return s.Values[len(s.Values)-1]
+}
+
+// GetLatestN 获取最新的N个SuperTrend值
+func (s *SuperTrendResult) GetLatestN(n int) []float64 {
+	if n <= 0 || len(s.Values) == 0 {
+		return []float64{}
+	}
+
+	start := int(math.Max(0, float64(len(s.Values)-n)))
+	return s.Values[start:]
+}
+
+// IsUptrend 检查最新位置是否处于上升趋势
+func (s *SuperTrendResult) IsUptrend() bool {
+	if len(s.Uptrend) == 0 {
+		return false
+	}
+	return s.Uptrend[len(s.Uptrend)-1]
+}
+
+// IsReversal 检查最新K线是否发生趋势翻转
+// 返回：是否翻转，翻转后的方向（true 为转为上升趋势）
+func (s *SuperTrendResult) IsReversal() (bool, bool) {
+	if len(s.Uptrend) < 2 {
+		return false, false
+	}
+
+	latest := s.Uptrend[len(s.Uptrend)-1]
+	if latest != s.Uptrend[len(s.Uptrend)-2] {
+		return true, latest
+	}
+	return false, false
+}
@@ -0,0 +1,88 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateSuperTrend(t *testing.T) {
+	tests := []struct {
+		name       string
+		high       []float64
+		low        []float64
+		close      []float64
+		period     int
+		multiplier float64
+		wantErr    bool
+	}{
+		{name: "正常计算SuperTrend(10,3)", high: refHigh, low: refLow, close: refClose, period: 10, multiplier: 3},
+		{name: "序列长度不一致", high: refHigh[:10], low: refLow, close: refClose, period: 10, multiplier: 3, wantErr: true},
+		{name: "价格数据不足", high: refHigh[:10], low: refLow[:10], close: refClose[:10], period: 10, multiplier: 3, wantErr: true},
+		{name: "周期为0", high: refHigh, low: refLow, close: refClose, period: 0, multiplier: 3, wantErr: true},
+		{name: "倍数为0", high: refHigh, low: refLow, close: refClose, period: 10, multiplier: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CalculateSuperTrend(tt.high, tt.low, tt.close, tt.period, tt.multiplier)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("CalculateSuperTrend() 期望错误，但没有返回错误")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CalculateSuperTrend() 意外错误 = %v", err)
+			}
+
+			expectedLength := len(tt.close) - tt.period
+			if len(result.Values) != expectedLength || len(result.Uptrend) != expectedLength {
+				t.Errorf("CalculateSuperTrend() 结果长度 = %v, 期望 %v", len(result.Values), expectedLength)
+			}
+		})
+	}
+}
+
+func TestSuperTrendAccuracy(t *testing.T) {
+	result, err := CalculateDefaultSuperTrend(refHigh, refLow, refClose)
+	if err != nil {
+		t.Fatalf("CalculateDefaultSuperTrend() 意外错误 = %v", err)
+	}
+
+	// 首根收盘价低于中点，整段保持下降趋势，SuperTrend 为上轨
+	if math.Abs(result.Values[0]-51.214) > 1e-6 || math.Abs(result.GetLatest()-49.225380) > 1e-5 {
+		t.Errorf("SuperTrend 首值/最新值 = %v/%v, 期望 51.214/49.225380", result.Values[0], result.GetLatest())
+	}
+	if result.IsUptrend() {
+		t.Error("默认参数下应处于下降趋势")
+	}
+
+	fast, _ := CalculateSuperTrend(refHigh, refLow, refClose, 5, 1)
+	if math.Abs(fast.GetLatest()-46.587571) > 1e-5 || !fast.IsUptrend() {
+		t.Errorf("SuperTrend(5,1) 最新值 = %v, 期望 46.587571 且为上升趋势", fast.GetLatest())
+	}
+	// 第22个值由下降趋势翻转为上升趋势
+	flipped := &SuperTrendResult{Values: fast.Values[:22], Uptrend: fast.Uptrend[:22]}
+	if reversal, up := flipped.IsReversal(); !reversal || !up {
+		t.Errorf("IsReversal() = %v/%v, 期望翻转为上升趋势", reversal, up)
+	}
+	if reversal, _ := fast.IsReversal(); reversal {
+		t.Error("最新K线未翻转")
+	}
+}
+
+func TestSuperTrendResult_GetLatestN(t *testing.T) {
+	result := &SuperTrendResult{Values: []float64{1, 2, 3}, Uptrend: []bool{true, true, false}}
+
+	if got := result.GetLatestN(2); len(got) != 2 || got[0] != 2 {
+		t.Errorf("GetLatestN(2) = %v", got)
+	}
+	if got := (&SuperTrendResult{}).GetLatestN(2); len(got) != 0 {
+		t.Errorf("空结果 GetLatestN() = %v", got)
+	}
+	if (&SuperTrendResult{}).IsUptrend() || (&SuperTrendResult{}).GetLatest() != 0 {
+		t.Error("空结果应返回默认值")
+	}
+}
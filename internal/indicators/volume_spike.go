@@ -0,0 +1,97 @@
+package indicators
+
+import (
+	"errors"
+	"math"
+)
+
+// VolumeSpikeResult 成交量异动检测结果
+type VolumeSpikeResult struct {
+	ZScores []float64 // 成交量相对前 period 根K线的 z 分数，第一个值对应第 period+1 根K线
+	Period  int       // 回看周期
+}
+
+// 成交量异动默认参数
+const (
+	DefaultVolumeSpikePeriod    = 20  // 默认回看周期
+	DefaultVolumeSpikeThreshold = 2.0 // 默认放量阈值（标准差倍数）
+
+	maxVolumeZScore = 10.0 // 历史成交量完全相同时的 z 分数上限
+)
+
+// CalculateVolumeSpike 计算成交量 z 分数，用于检测放量/缩量
+// 每根K线与其之前 period 根K线的均值和总体标准差比较，不包含自身，避免放量稀释基准
+// volume: 成交量序列
+// period: 回看周期，通常为20
+func CalculateVolumeSpike(volume []float64, period int) (*VolumeSpikeResult, error) {
+	if period <= 1 {
+		return nil, errors.New("成交量回看周期必须大于1")
+	}
+
+	if len(volume) < period+1 {
+		return nil, errors.New("成交量数据不足，无法检测成交量异动")
+	}
+
+	zScores := make([]float64, 0, len(volume)-period)
+	for i := period; i < len(volume); i++ {
+		window := volume[i-period : i]
+
+		mean := 0.0
+		for _, v := range window {
+			mean += v
+		}
+		mean /= float64(period)
+
+		variance := 0.0
+		for _, v := range window {
+			variance += (v - mean) * (v - mean)
+		}
+		stdDev := math.Sqrt(variance / float64(period))
+
+		switch {
+		case stdDev > 0:
+			zScores = append(zScores, (volume[i]-mean)/stdDev)
+		case volume[i] == mean:
+			zScores = append(zScores, 0)
+		default:
+			// 历史成交量完全不变时任何偏离都视为极端值
+			zScores = append(zScores, math.Copysign(maxVolumeZScore, volume[i]-mean))
+		}
+	}
+
+	return &VolumeSpikeResult{
+		ZScores: zScores,
+		Period:  period,
+	}, nil
+}
+
+// CalculateDefaultVolumeSpike 使用默认参数检测成交量异动
+func CalculateDefaultVolumeSpike(volume []float64) (*VolumeSpikeResult, error) {
+	return CalculateVolumeSpike(volume, DefaultVolumeSpikePeriod)
+}
+
+// GetLatest 获取最新的成交量 z 分数
+func (v *VolumeSpikeResult) GetLatest() float64 {
+	if len(v.ZScores) == 0 {
+		return 0
+	}
+	return v.ZScores[len(v.ZScores)-1]
+}
+
+// GetLatestN 获取最新的N个成交量 z 分数
+func (v *VolumeSpikeResult) GetLatestN(n int) []float64 {
+	if n <= 0 || len(v.ZScores) == 0 {
+		return []float64{}
+	}
+
+	start := int(math.Max(0, float64(len(v.ZScores)-n)))
+	return v.ZScores[start:]
+}
+
+// IsSpike 检查最新K线是否放量（z 分数不低于阈值）
+func (v *VolumeSpikeResult) IsSpike(threshold float64) bool {
+	if len(v.ZScores) == 0 {
+		return false
+	}
+	return v.GetLatest() >= threshold
+}
@@ -0,0 +1,79 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateVolumeSpike(t *testing.T) {
+	tests := []struct {
+		name    string
+		volume  []float64
+		period  int
+		wantErr bool
+	}{
+		{name: "正常计算20周期z分数", volume: refVolume, period: 20},
+		{name: "成交量数据不足", volume: refVolume[:20], period: 20, wantErr: true},
+		{name: "周期为1", volume: refVolume, period: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CalculateVolumeSpike(tt.volume, tt.period)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("CalculateVolumeSpike() 期望错误，但没有返回错误")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CalculateVolumeSpike() 意外错误 = %v", err)
+			}
+
+			expectedLength := len(tt.volume) - tt.period
+			if len(result.ZScores) != expectedLength {
+				t.Errorf("CalculateVolumeSpike() 结果长度 = %v, 期望 %v", len(result.ZScores), expectedLength)
+			}
+		})
+	}
+}
+
+func TestVolumeSpikeAccuracy(t *testing.T) {
+	result, err := CalculateDefaultVolumeSpike(refVolume)
+	if err != nil {
+		t.Fatalf("CalculateDefaultVolumeSpike() 意外错误 = %v", err)
+	}
+
+	// 第25根K线成交量4800，远高于之前20根的均值
+	if math.Abs(result.ZScores[4]-8.187621) > 1e-5 {
+		t.Errorf("放量K线z分数 = %v, 期望 8.187621", result.ZScores[4])
+	}
+	if math.Abs(result.GetLatest()-(-0.613784)) > 1e-5 {
+		t.Errorf("最新z分数 = %v, 期望 -0.613784", result.GetLatest())
+	}
+	if result.IsSpike(DefaultVolumeSpikeThreshold) {
+		t.Error("最新K线不应识别为放量")
+	}
+
+	spike := &VolumeSpikeResult{ZScores: result.GetLatestN(6)[:1]}
+	if !spike.IsSpike(DefaultVolumeSpikeThreshold) {
+		t.Error("z分数超过阈值应识别为放量")
+	}
+}
+
+func TestVolumeSpike_ConstantHistory(t *testing.T) {
+	// 历史成交量完全不变时，相同成交量为0，任何偏离取上限
+	result, err := CalculateVolumeSpike([]float64{100, 100, 100, 100, 150, 100}, 3)
+	if err != nil {
+		t.Fatalf("CalculateVolumeSpike() 意外错误 = %v", err)
+	}
+
+	if result.ZScores[0] != 0 || result.ZScores[1] != maxVolumeZScore {
+		t.Errorf("z分数 = %v, 期望 [0 %v ...]", result.ZScores, maxVolumeZScore)
+	}
+	if (&VolumeSpikeResult{}).IsSpike(0) {
+		t.Error("空结果不应识别为放量")
+	}
+}
@@ -0,0 +1,114 @@
+package indicators
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// VWAPResult 成交量加权平均价计算结果
+type VWAPResult struct {
+	Values []float64 // VWAP 值序列
+	Anchor int       // 锚定VWAP的起始K线索引，Values[0] 对应该K线；时段VWAP为0
+}
+
+// DefaultVWAPSession 默认时段长度：按 UTC 自然日重置
+const DefaultVWAPSession = 24 * time.Hour
+
+// CalculateAnchoredVWAP 计算从指定K线开始累计的锚定VWAP
+// VWAP = Σ(典型价格 × 成交量) / Σ成交量，典型价格 = (最高价 + 最低价 + 收盘价) / 3
+// high/low/close/volume: 价格和成交量序列（长度必须相同）
+// anchor: 锚点K线索引，例如重要高低点或事件发生的K线
+func CalculateAnchoredVWAP(high, low, close, volume []float64, anchor int) (*VWAPResult, error) {
+	if len(high) != len(close) || len(low) != len(close) || len(volume) != len(close) {
+		return nil, errors.New("价格和成交量序列长度不一致")
+	}
+
+	if anchor < 0 || anchor >= len(close) {
+		return nil, errors.New("VWAP锚点超出数据范围")
+	}
+
+	values := make([]float64, 0, len(close)-anchor)
+	var pv, totalVolume float64
+	for i := anchor; i < len(close); i++ {
+		pv, totalVolume = accumulateVWAP(pv, totalVolume, high[i], low[i], close[i], volume[i])
+		values = append(values, vwapValue(pv, totalVolume, high[i], low[i], close[i]))
+	}
+
+	return &VWAPResult{
+		Values: values,
+		Anchor: anchor,
+	}, nil
+}
+
+// CalculateSessionVWAP 计算按时段重置的VWAP
+// openTimes: 每根K线的开盘时间，用于划分时段
+// high/low/close/volume: 价格和成交量序列（长度必须与 openTimes 相同）
+// session: 时段长度，K线开盘时间按该长度截断后变化即开始新时段，通常为24小时
+func CalculateSessionVWAP(openTimes []time.Time, high, low, close, volume []float64, session time.Duration) (*VWAPResult, error) {
+	if len(openTimes) != len(close) || len(high) != len(close) || len(low) != len(close) || len(volume) != len(close) {
+		return nil, errors.New("时间、价格和成交量序列长度不一致")
+	}
+
+	if session <= 0 {
+		return nil, errors.New("VWAP时段长度必须大于0")
+	}
+
+	if len(close) == 0 {
+		return nil, errors.New("价格数据不足，无法计算VWAP")
+	}
+
+	values := make([]float64, len(close))
+	var pv, totalVolume float64
+	current := openTimes[0].Truncate(session)
+	for i := range close {
+		if start := openTimes[i].Truncate(session); !start.Equal(current) {
+			current = start
+			pv, totalVolume = 0, 0
+		}
+		pv, totalVolume = accumulateVWAP(pv, totalVolume, high[i], low[i], close[i], volume[i])
+		values[i] = vwapValue(pv, totalVolume, high[i], low[i], close[i])
+	}
+
+	return &VWAPResult{Values: values}, nil
+}
+
+// accumulateVWAP 累加一根K线的成交额和成交量
+func accumulateVWAP(pv, totalVolume, high, low, close, volume float64) (float64, float64) {
+	return pv + (high+low+close)/3*volume, totalVolume + volume
+}
+
+// vwapValue 计算当前累计的VWAP，尚无成交量时退化为当根典型价格
+func vwapValue(pv, totalVolume, high, low, close float64) float64 {
+	if totalVolume == 0 {
+		return (high + low + close) / 3
+	}
+	return pv / totalVolume
+}
+
+// GetLatest 获取最新的VWAP值
+func (v *VWAPResult) GetLatest() float64 {
+	if len(v.Values) == 0 {
+		return 0
+	}
+	return v.Values[len(v.Values)-1]
+}
+
+// GetLatestN 获取最新的N个VWAP值
+func (v *VWAPResult) GetLatestN(n int) []float64 {
+	if n <= 0 || len(v.Values) == 0 {
+		return []float64{}
+	}
+
+	start := int(math.Max(0, float64(len(v.Values)-n)))
+	return v.Values[start:]
+}
+
+// GetDeviation 获取价格相对最新VWAP的偏离百分比，正值表示价格位于VWAP上方
+func (v *VWAPResult) GetDeviation(price float64) float64 {
+	vwap := v.GetLatest()
+	if vwap == 0 {
+		return 0
+	}
+	return (price - vwap) / vwap * 100
+}
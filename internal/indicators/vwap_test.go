@@ -0,0 +1,100 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCalculateAnchoredVWAP(t *testing.T) {
+	tests := []struct {
+		name    string
+		volume  []float64
+		anchor  int
+		wantErr bool
+	}{
+		{name: "从第一根K线锚定", volume: refVolume, anchor: 0},
+		{name: "从第21根K线锚定", volume: refVolume, anchor: 20},
+		{name: "序列长度不一致", volume: refVolume[:10], anchor: 0, wantErr: true},
+		{name: "锚点为负数", volume: refVolume, anchor: -1, wantErr: true},
+		{name: "锚点超出范围", volume: refVolume, anchor: 30, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CalculateAnchoredVWAP(refHigh, refLow, refClose, tt.volume, tt.anchor)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("CalculateAnchoredVWAP() 期望错误，但没有返回错误")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CalculateAnchoredVWAP() 意外错误 = %v", err)
+			}
+
+			if len(result.Values) != len(refClose)-tt.anchor || result.Anchor != tt.anchor {
+				t.Errorf("CalculateAnchoredVWAP() 结果长度 = %v, 期望 %v", len(result.Values), len(refClose)-tt.anchor)
+			}
+		})
+	}
+}
+
+func TestVWAPAccuracy(t *testing.T) {
+	full, _ := CalculateAnchoredVWAP(refHigh, refLow, refClose, refVolume, 0)
+	if math.Abs(full.GetLatest()-48.571653) > 1e-5 {
+		t.Errorf("全区间VWAP = %v, 期望 48.571653", full.GetLatest())
+	}
+
+	anchored, _ := CalculateAnchoredVWAP(refHigh, refLow, refClose, refVolume, 20)
+	if math.Abs(anchored.GetLatest()-47.394681) > 1e-5 {
+		t.Errorf("锚定VWAP = %v, 期望 47.394681", anchored.GetLatest())
+	}
+	if math.Abs(anchored.GetDeviation(47.85)-(47.85-47.394681)/47.394681*100) > 1e-4 {
+		t.Errorf("GetDeviation() = %v", anchored.GetDeviation(47.85))
+	}
+
+	// 锚点K线的VWAP等于其典型价格
+	if math.Abs(anchored.Values[0]-(refHigh[20]+refLow[20]+refClose[20])/3) > 1e-9 {
+		t.Errorf("锚点VWAP = %v, 期望等于典型价格", anchored.Values[0])
+	}
+}
+
+func TestCalculateSessionVWAP(t *testing.T) {
+	// 6根12小时K线，跨越3个UTC自然日
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	times := make([]time.Time, 6)
+	for i := range times {
+		times[i] = base.Add(time.Duration(i) * 12 * time.Hour)
+	}
+	price := []float64{10, 20, 30, 40, 50, 60}
+	volume := []float64{1, 3, 1, 1, 0, 0}
+
+	result, err := CalculateSessionVWAP(times, price, price, price, volume, DefaultVWAPSession)
+	if err != nil {
+		t.Fatalf("CalculateSessionVWAP() 意外错误 = %v", err)
+	}
+
+	// 每个自然日重新累计；无成交量时取典型价格
+	expected := []float64{10, 17.5, 30, 35, 50, 60}
+	for i, v := range result.Values {
+		if math.Abs(v-expected[i]) > 1e-9 {
+			t.Errorf("第%d个VWAP = %v, 期望 %v", i, v, expected[i])
+		}
+	}
+	if got := result.GetLatestN(2); len(got) != 2 || got[0] != 50 {
+		t.Errorf("GetLatestN(2) = %v", got)
+	}
+
+	if _, err := CalculateSessionVWAP(times[:3], price, price, price, volume, DefaultVWAPSession); err == nil {
+		t.Error("序列长度不一致时应返回错误")
+	}
+	if _, err := CalculateSessionVWAP(times, price, price, price, volume, 0); err == nil {
+		t.Error("时段长度为0时应返回错误")
+	}
+	if _, err := CalculateSessionVWAP(nil, nil, nil, nil, nil, DefaultVWAPSession); err == nil {
+		t.Error("空数据应返回错误")
+	}
+}
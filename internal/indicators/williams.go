@@ -0,0 +1,114 @@
+package indicators
+
+import (
+	"errors"
+	"math"
+)
+
+// WilliamsRResult 威廉指标计算结果
+type WilliamsRResult struct {
+	Values []float64 // %R 值序列 (-100 到 0)，第一个值对应第 period 根K线
+	Period int       // 计算周期
+}
+
+// WilliamsRSignal 威廉指标信号类型
+type WilliamsRSignal int
+
+const (
+	WilliamsRNeutral WilliamsRSignal = iota // 中性
+	WilliamsRBuy                            // 买入信号（超卖）
+	WilliamsRSell                           // 卖出信号（超买）
+)
+
+// 威廉指标默认参数
+const (
+	DefaultWilliamsRPeriod     = 14    // 默认周期
+	DefaultWilliamsROverbought = -20.0 // 默认超买水平
+	DefaultWilliamsROversold   = -80.0 // 默认超卖水平
+)
+
+// CalculateWilliamsR 计算威廉指标
+// %R = (周期最高价 - 收盘价) / (周期最高价 - 周期最低价) × -100
+// high/low/close: 最高价、最低价和收盘价序列（长度必须相同）
+// period: 回看周期，通常为14
+func CalculateWilliamsR(high, low, close []float64, period int) (*WilliamsRResult, error) {
+	if len(high) != len(close) || len(low) != len(close) {
+		return nil, errors.New("最高价、最低价和收盘价序列长度不一致")
+	}
+
+	if period <= 0 {
+		return nil, errors.New("威廉指标周期必须大于0")
+	}
+
+	if len(close) < period {
+		return nil, errors.New("价格数据不足，无法计算威廉指标")
+	}
+
+	// %R 与原始 %K 只差一个平移：%R = %K - 100
+	values := calculateRawStochastic(high, low, close, period)
+	for i := range values {
+		values[i] -= 100
+	}
+
+	return &WilliamsRResult{
+		Values: values,
+		Period: period,
+	}, nil
+}
+
+// CalculateDefaultWilliamsR 使用默认参数计算威廉指标
+func CalculateDefaultWilliamsR(high, low, close []float64) (*WilliamsRResult, error) {
+	return CalculateWilliamsR(high, low, close, DefaultWilliamsRPeriod)
+}
+
+// GetLatest 获取最新的 %R 值
+func (w *WilliamsRResult) GetLatest() float64 {
+	if len(w.Values) == 0 {
+		return 0
+	}
+	return w.Values[len(w.Values)-1]
+}
+
+// GetLatestN 获取最新的N个 %R 值
+func (w *WilliamsRResult) GetLatestN(n int) []float64 {
+	if n <= 0 || len(w.Values) == 0 {
+		return []float64{}
+	}
+
+	start := int(math.Max(0, float64(len(w.Values)-n)))
+	return w.Values[start:]
+}
+
+// GetSignal 根据 %R 值获取交易信号
+func (w *WilliamsRResult) GetSignal(overboughtLevel, oversoldLevel float64) WilliamsRSignal {
+	if len(w.Values) == 0 {
+		return WilliamsRNeutral
+	}
+
+	latest := w.GetLatest()
+
+	if latest >= overboughtLevel {
+		return WilliamsRSell // 超买，考虑卖出
+	} else if latest <= oversoldLevel {
+		return WilliamsRBuy // 超卖，考虑买入
+	}
+
+	return WilliamsRNeutral
+}
+
+// GetDefaultSignal 使用默认阈值获取交易信号
+func (w *WilliamsRResult) GetDefaultSignal() WilliamsRSignal {
+	return w.GetSignal(DefaultWilliamsROverbought, DefaultWilliamsROversold)
+}
+
+// WilliamsRSignalToString 将威廉指标信号转换为字符串
+func WilliamsRSignalToString(signal WilliamsRSignal) string {
+	switch signal {
+	case WilliamsRBuy:
+		return "买入信号"
+	case WilliamsRSell:
+		return "卖出信号"
+	default:
+		return "中性"
+	}
+}
@@ -0,0 +1,87 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateWilliamsR(t *testing.T) {
+	tests := []struct {
+		name    string
+		high    []float64
+		low     []float64
+		close   []float64
+		period  int
+		wantErr bool
+	}{
+		{name: "正常计算%R-14周期", high: refHigh, low: refLow, close: refClose, period: 14},
+		{name: "序列长度不一致", high: refHigh, low: refLow[:10], close: refClose, period: 14, wantErr: true},
+		{name: "价格数据不足", high: refHigh[:13], low: refLow[:13], close: refClose[:13], period: 14, wantErr: true},
+		{name: "周期为负数", high: refHigh, low: refLow, close: refClose, period: -1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CalculateWilliamsR(tt.high, tt.low, tt.close, tt.period)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("CalculateWilliamsR() 期望错误，但没有返回错误")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CalculateWilliamsR() 意外错误 = %v", err)
+			}
+
+			expectedLength := len(tt.close) - tt.period + 1
+			if len(result.Values) != expectedLength {
+				t.Errorf("CalculateWilliamsR() 结果长度 = %v, 期望 %v", len(result.Values), expectedLength)
+			}
+		})
+	}
+}
+
+func TestWilliamsRAccuracy(t *testing.T) {
+	result, err := CalculateDefaultWilliamsR(refHigh, refLow, refClose)
+	if err != nil {
+		t.Fatalf("CalculateDefaultWilliamsR() 意外错误 = %v", err)
+	}
+
+	if math.Abs(result.Values[0]-(-6.666667)) > 1e-5 {
+		t.Errorf("第一个%%R = %v, 期望 -6.666667", result.Values[0])
+	}
+	if math.Abs(result.GetLatest()-(-30.769231)) > 1e-5 {
+		t.Errorf("最新%%R = %v, 期望 -30.769231", result.GetLatest())
+	}
+	if result.GetDefaultSignal() != WilliamsRNeutral {
+		t.Errorf("GetDefaultSignal() = %v, 期望中性", WilliamsRSignalToString(result.GetDefaultSignal()))
+	}
+
+	// 收盘于周期最高价为0，收盘于周期最低价为-100
+	high := []float64{11, 12, 13}
+	low := []float64{9, 10, 11}
+	top, _ := CalculateWilliamsR(high, low, []float64{10, 11, 13}, 3)
+	bottom, _ := CalculateWilliamsR(high, low, []float64{10, 11, 9}, 3)
+	if top.GetLatest() != 0 || top.GetDefaultSignal() != WilliamsRSell {
+		t.Errorf("收盘于最高价 %%R = %v, 期望 0 且为卖出信号", top.GetLatest())
+	}
+	if bottom.GetLatest() != -100 || bottom.GetDefaultSignal() != WilliamsRBuy {
+		t.Errorf("收盘于最低价 %%R = %v, 期望 -100 且为买入信号", bottom.GetLatest())
+	}
+}
+
+func TestWilliamsRResult_GetLatestN(t *testing.T) {
+	result := &WilliamsRResult{Values: []float64{-10, -50, -90}, Period: 14}
+
+	if got := result.GetLatestN(2); len(got) != 2 || got[0] != -50 {
+		t.Errorf("GetLatestN(2) = %v", got)
+	}
+	if got := (&WilliamsRResult{}).GetLatestN(2); len(got) != 0 {
+		t.Errorf("空结果 GetLatestN() = %v", got)
+	}
+	if (&WilliamsRResult{}).GetDefaultSignal() != WilliamsRNeutral {
+		t.Error("空结果应返回中性信号")
+	}
+}
@@ -0,0 +1,151 @@
+// Package instrument 定义与交易所无关的交易品种模型
+// 配置、资产验证、监控器和数据源都使用规范形式（基础货币 + 报价货币），
+// 由各交易所（Venue）映射为自己的原生交易对格式，切换数据源时配置无需修改
+package instrument
+
+import (
+	"fmt"
+	"strings"
+)
+
+// knownQuoteCurrencies 常见报价货币（长后缀优先匹配）
+var knownQuoteCurrencies = []string{
+	"FDUSD", "USDT", "USDC", "BUSD", "TUSD", "DAI", "USD", "EUR", "GBP", "BTC", "ETH", "BNB",
+}
+
+// Instrument 规范化的现货交易品种
+type Instrument struct {
+	Base  string // 基础货币，如 BTC
+	Quote string // 报价货币，如 USDT
+}
+
+// New 创建交易品种，币种代码统一为大写
+func New(base, quote string) Instrument {
+	return Instrument{
+		Base:  strings.ToUpper(strings.TrimSpace(base)),
+		Quote: strings.ToUpper(strings.TrimSpace(quote)),
+	}
+}
+
+// Parse 解析交易品种，支持 "BTC/USDT"、"BTC-USDT"、"BTC_USDT" 和 "BTCUSDT"
+// 无分隔符时按常见报价货币后缀拆分
+func Parse(symbol string) (Instrument, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	for _, sep := range []string{"/", "-", "_"} {
+		if base, quote, ok := strings.Cut(symbol, sep); ok {
+			inst := New(base, quote)
+			if !inst.IsValid() {
+				return Instrument{}, fmt.Errorf("invalid instrument: %s", symbol)
+			}
+			return inst, nil
+		}
+	}
+
+	base, quote := Split(symbol)
+	inst := New(base, quote)
+	if !inst.IsValid() {
+		return Instrument{}, fmt.Errorf("invalid instrument: %s", symbol)
+	}
+	return inst, nil
+}
+
+// Split 将无分隔符的交易对拆分为基础货币和报价货币
+// BTCUSDT -> BTC, USDT；无法识别报价货币时假设最后3个字符是报价货币
+func Split(symbol string) (base, quote string) {
+	symbol = strings.ToUpper(symbol)
+	for _, q := range knownQuoteCurrencies {
+		if strings.HasSuffix(symbol, q) && len(symbol) > len(q) {
+			return symbol[:len(symbol)-len(q)], q
+		}
+	}
+
+	if len(symbol) > 3 {
+		return symbol[:len(symbol)-3], symbol[len(symbol)-3:]
+	}
+	return symbol, ""
+}
+
+// ValidAsset 判断币种代码是否有效（字母和数字组成）
+func ValidAsset(asset string) bool {
+	if asset == "" {
+		return false
+	}
+	for _, r := range asset {
+		if !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// IsValid 判断基础货币和报价货币是否都有效
+func (i Instrument) IsValid() bool {
+	return ValidAsset(i.Base) && ValidAsset(i.Quote)
+}
+
+// Symbol 返回规范交易对标识（基础货币 + 报价货币），如 BTCUSDT
+func (i Instrument) Symbol() string {
+	return i.Base + i.Quote
+}
+
+// String 返回便于阅读的形式，如 BTC/USDT
+func (i Instrument) String() string {
+	return i.Base + "/" + i.Quote
+}
+
+// Venue 交易所
+type Venue string
+
+const (
+	Binance  Venue = "binance"
+	Coinbase Venue = "coinbase"
+	OKX      Venue = "okx"
+	Kraken   Venue = "kraken"
+	Bybit    Venue = "bybit"
+)
+
+// venueAssetAliases 交易所使用的币种代码别名
+var venueAssetAliases = map[Venue]map[string]string{
+	Kraken: {
+		"BTC":  "XBT",
+		"DOGE": "XDG",
+	},
+}
+
+// venueQuoteAliases 交易所没有某种报价货币的现货市场时改用的等价报价货币
+// Binance/Bybit/OKX 现货以 USDT 计价，Coinbase 以 USD 计价（USDC 市场已并入 USD）
+var venueQuoteAliases = map[Venue]map[string]string{
+	Binance:  {"USD": "USDT"},
+	Bybit:    {"USD": "USDT"},
+	OKX:      {"USD": "USDT"},
+	Coinbase: {"USDT": "USD", "USDC": "USD"},
+}
+
+// NativeAsset 返回币种在交易所中的代码
+func (v Venue) NativeAsset(asset string) string {
+	if alias, ok := venueAssetAliases[v][asset]; ok {
+		return alias
+	}
+	return asset
+}
+
+// NativeQuote 返回报价货币在交易所中对应的代码，映射后与基础货币相同时保留原报价货币
+func (v Venue) NativeQuote(i Instrument) string {
+	quote := i.Quote
+	if alias, ok := venueQuoteAliases[v][quote]; ok && alias != i.Base {
+		quote = alias
+	}
+	return v.NativeAsset(quote)
+}
+
+// NativeSymbol 将交易品种映射为交易所原生交易对
+// BTC/USDT -> binance/bybit: BTCUSDT，okx: BTC-USDT，coinbase: BTC-USD，kraken: XBTUSDT
+func (v Venue) NativeSymbol(i Instrument) string {
+	base, quote := v.NativeAsset(i.Base), v.NativeQuote(i)
+	switch v {
+	case Coinbase, OKX:
+		return base + "-" + quote
+	default:
+		return base + quote
+	}
+}
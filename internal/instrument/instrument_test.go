@@ -0,0 +1,78 @@
+package instrument
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		symbol string
+		want   Instrument
+	}{
+		{"BTCUSDT", Instrument{"BTC", "USDT"}},
+		{"btc/usdt", Instrument{"BTC", "USDT"}},
+		{"ETH-BTC", Instrument{"ETH", "BTC"}},
+		{"SOL_USDC", Instrument{"SOL", "USDC"}},
+		{"BTCFDUSD", Instrument{"BTC", "FDUSD"}},
+		{"ADASOL", Instrument{"ADA", "SOL"}}, // 未知报价货币按最后3个字符拆分
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.symbol)
+		if err != nil || got != tt.want {
+			t.Errorf("Parse(%s) = %v, %v, want %v", tt.symbol, got, err, tt.want)
+		}
+	}
+
+	for _, invalid := range []string{"", "BTC", "BTC/", "BTC-US$"} {
+		if _, err := Parse(invalid); err == nil {
+			t.Errorf("Parse(%q) should fail", invalid)
+		}
+	}
+}
+
+func TestVenue_NativeSymbol(t *testing.T) {
+	btc := New("btc", "usdt")
+	if btc.Symbol() != "BTCUSDT" || btc.String() != "BTC/USDT" {
+		t.Errorf("unexpected canonical forms: %s %s", btc.Symbol(), btc.String())
+	}
+
+	tests := []struct {
+		venue Venue
+		inst  Instrument
+		want  string
+	}{
+		{Binance, btc, "BTCUSDT"},
+		{Bybit, btc, "BTCUSDT"},
+		{Coinbase, btc, "BTC-USD"},
+		{Coinbase, New("ETH", "USD"), "ETH-USD"},
+		{OKX, New("ETH", "BTC"), "ETH-BTC"},
+		{Kraken, btc, "XBTUSDT"},
+		{Kraken, New("DOGE", "BTC"), "XDGXBT"},
+		{Coinbase, New("USDT", "USD"), "USDT-USD"},
+		{Binance, New("USDT", "USD"), "USDTUSD"}, // 报价货币映射后与基础货币相同时不映射
+	}
+	for _, tt := range tests {
+		if got := tt.venue.NativeSymbol(tt.inst); got != tt.want {
+			t.Errorf("%s.NativeSymbol(%s) = %s, want %s", tt.venue, tt.inst, got, tt.want)
+		}
+	}
+}
+
+func TestVenue_NativeSymbolOnEveryVenue(t *testing.T) {
+	// 同一个配置交易对在每个交易所都映射到实际存在的现货市场
+	tests := map[string]map[Venue]string{
+		"BTCUSDT": {Binance: "BTCUSDT", Bybit: "BTCUSDT", OKX: "BTC-USDT", Coinbase: "BTC-USD", Kraken: "XBTUSDT"},
+		"BTCUSD":  {Binance: "BTCUSDT", Bybit: "BTCUSDT", OKX: "BTC-USDT", Coinbase: "BTC-USD", Kraken: "XBTUSD"},
+		"ETHUSDC": {Binance: "ETHUSDC", Bybit: "ETHUSDC", OKX: "ETH-USDC", Coinbase: "ETH-USD", Kraken: "ETHUSDC"},
+		"ETHBTC":  {Binance: "ETHBTC", Bybit: "ETHBTC", OKX: "ETH-BTC", Coinbase: "ETH-BTC", Kraken: "ETHXBT"},
+	}
+	for symbol, venues := range tests {
+		inst, err := Parse(symbol)
+		if err != nil {
+			t.Fatalf("Parse(%s) error = %v", symbol, err)
+		}
+		for venue, want := range venues {
+			if got := venue.NativeSymbol(inst); got != want {
+				t.Errorf("%s.NativeSymbol(%s) = %s, want %s", venue, symbol, got, want)
+			}
+		}
+	}
+}
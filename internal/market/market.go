@@ -0,0 +1,79 @@
+// Package market 定义与数据源无关的行情数据类型
+// 数据源、重采样和策略共用同一种K线和时间框架，datasource 包以类型别名导出
+package market
+
+import "time"
+
+// Timeframe 时间框架
+type Timeframe string
+
+const (
+	Timeframe1m  Timeframe = "1m"
+	Timeframe3m  Timeframe = "3m"
+	Timeframe5m  Timeframe = "5m"
+	Timeframe15m Timeframe = "15m"
+	Timeframe30m Timeframe = "30m"
+	Timeframe1h  Timeframe = "1h"
+	Timeframe2h  Timeframe = "2h"
+	Timeframe4h  Timeframe = "4h"
+	Timeframe6h  Timeframe = "6h"
+	Timeframe8h  Timeframe = "8h"
+	Timeframe12h Timeframe = "12h"
+	Timeframe1d  Timeframe = "1d"
+	Timeframe3d  Timeframe = "3d"
+	Timeframe1w  Timeframe = "1w"
+	Timeframe1M  Timeframe = "1M"
+)
+
+// Kline K线数据
+type Kline struct {
+	Symbol    string    `json:"symbol"`
+	OpenTime  time.Time `json:"open_time"`
+	CloseTime time.Time `json:"close_time"`
+	Open      float64   `json:"open"`
+	High      float64   `json:"high"`
+	Low       float64   `json:"low"`
+	Close     float64   `json:"close"`
+	Volume    float64   `json:"volume"`
+	IsClosed  bool      `json:"is_closed"` // 获取时是否已收盘（收盘时间早于当前时间）
+	Partial   bool      `json:"partial"`   // 基础数据不完整（重采样缺少基础K线，或实时聚合时订阅晚于周期开始）
+	Synthetic bool      `json:"synthetic"` // 由其他交易对计算得到（如交叉汇率），没有实际成交量
+}
+
+// Duration 返回时间框架对应的时长（月线按30天近似）
+func (tf Timeframe) Duration() time.Duration {
+	switch tf {
+	case Timeframe1m:
+		return time.Minute
+	case Timeframe3m:
+		return 3 * time.Minute
+	case Timeframe5m:
+		return 5 * time.Minute
+	case Timeframe15m:
+		return 15 * time.Minute
+	case Timeframe30m:
+		return 30 * time.Minute
+	case Timeframe1h:
+		return time.Hour
+	case Timeframe2h:
+		return 2 * time.Hour
+	case Timeframe4h:
+		return 4 * time.Hour
+	case Timeframe6h:
+		return 6 * time.Hour
+	case Timeframe8h:
+		return 8 * time.Hour
+	case Timeframe12h:
+		return 12 * time.Hour
+	case Timeframe1d:
+		return 24 * time.Hour
+	case Timeframe3d:
+		return 3 * 24 * time.Hour
+	case Timeframe1w:
+		return 7 * 24 * time.Hour
+	case Timeframe1M:
+		return 30 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,389 @@
+// Package patterns 识别K线形态
+// 在 []*datasource.Kline 上扫描吞没、锤子线/射击之星、十字星、启明星/黄昏星、红三兵/三只乌鸦、孕线和内包线，
+// 每个匹配给出形态类型、所在K线索引、方向和 0-1 的置信度。实体长短以之前K线的平均实体为参照，
+// 反转形态出现在与其方向相反的趋势之后时置信度更高
+package patterns
+
+import (
+	"math"
+	"sort"
+
+	"ta-watcher/internal/datasource"
+)
+
+// Type K线形态类型
+type Type int
+
+const (
+	Doji               Type = iota // 十字星
+	Hammer                         // 锤子线（下跌后）
+	ShootingStar                   // 射击之星（上涨后）
+	BullishEngulfing               // 看涨吞没
+	BearishEngulfing               // 看跌吞没
+	BullishHarami                  // 看涨孕线
+	BearishHarami                  // 看跌孕线
+	MorningStar                    // 启明星
+	EveningStar                    // 黄昏星
+	ThreeWhiteSoldiers             // 红三兵
+	ThreeBlackCrows                // 三只乌鸦
+	InsideBar                      // 内包线
+)
+
+// String 返回形态的中文名称
+func (t Type) String() string {
+	switch t {
+	case Doji:
+		return "十字星"
+	case Hammer:
+		return "锤子线"
+	case ShootingStar:
+		return "射击之星"
+	case BullishEngulfing:
+		return "看涨吞没"
+	case BearishEngulfing:
+		return "看跌吞没"
+	case BullishHarami:
+		return "看涨孕线"
+	case BearishHarami:
+		return "看跌孕线"
+	case MorningStar:
+		return "启明星"
+	case EveningStar:
+		return "黄昏星"
+	case ThreeWhiteSoldiers:
+		return "红三兵"
+	case ThreeBlackCrows:
+		return "三只乌鸦"
+	case InsideBar:
+		return "内包线"
+	default:
+		return "未知形态"
+	}
+}
+
+// Direction 形态方向
+type Direction int
+
+const (
+	Neutral Direction = iota // 中性（犹豫、盘整）
+	Bullish                  // 看涨
+	Bearish                  // 看跌
+)
+
+// String 返回方向的中文名称
+func (d Direction) String() string {
+	switch d {
+	case Bullish:
+		return "看涨"
+	case Bearish:
+		return "看跌"
+	default:
+		return "中性"
+	}
+}
+
+// Match 一次形态匹配
+type Match struct {
+	Type       Type      // 形态类型
+	Start      int       // 形态第一根K线索引
+	Index      int       // 形态最后一根K线索引（形态在此K线收盘时完成）
+	Direction  Direction // 形态方向
+	Confidence float64   // 置信度 0-1
+}
+
+// 形态识别参数
+const (
+	bodyAveragePeriod = 10  // 计算平均实体长度的参考K线数
+	trendPeriod       = 5   // 判断形态前趋势的K线数
+	dojiBodyRatio     = 0.1 // 十字星实体占整根K线波幅的最大比例
+)
+
+// detector 检测以第 i 根K线结束的某类形态
+type detector func(klines []*datasource.Kline, i int) []Match
+
+var detectors = []detector{
+	detectDoji,
+	detectHammer,
+	detectShootingStar,
+	detectEngulfing,
+	detectHarami,
+	detectStar,
+	detectThreeSoldiersOrCrows,
+	detectInsideBar,
+}
+
+// Scan 扫描全部K线，返回按结束位置排序的所有形态匹配
+func Scan(klines []*datasource.Kline) []Match {
+	return Recent(klines, len(klines))
+}
+
+// Recent 返回在最近 bars 根K线内完成的形态匹配，形态识别仍会参考更早的K线
+func Recent(klines []*datasource.Kline, bars int) []Match {
+	var matches []Match
+	for i := max(0, len(klines)-bars); i < len(klines); i++ {
+		for _, detect := range detectors {
+			matches = append(matches, detect(klines, i)...)
+		}
+	}
+
+	sort.SliceStable(matches, func(a, b int) bool {
+		return matches[a].Index < matches[b].Index
+	})
+	return matches
+}
+
+// Latest 返回在最新一根K线上完成的形态匹配
+func Latest(klines []*datasource.Kline) []Match {
+	return Recent(klines, 1)
+}
+
+// detectDoji 十字星：实体极小，开盘价与收盘价几乎相同
+func detectDoji(klines []*datasource.Kline, i int) []Match {
+	k := klines[i]
+	r := candleRange(k)
+	if r <= 0 || body(k) > dojiBodyRatio*r {
+		return nil
+	}
+
+	confidence := 1 - 0.5*body(k)/(dojiBodyRatio*r)
+	return single(Doji, i, i, Neutral, confidence)
+}
+
+// detectHammer 锤子线：下跌后出现的长下影小实体K线
+func detectHammer(klines []*datasource.Kline, i int) []Match {
+	k := klines[i]
+	r := candleRange(k)
+	if r <= 0 || priorTrend(klines, i) >= 0 {
+		return nil
+	}
+
+	shadow := lowerShadow(k)
+	if shadow < 2*body(k) || shadow < 0.6*r || upperShadow(k) > 0.1*r {
+		return nil
+	}
+
+	confidence := 0.5 + 0.3*(shadow/r-0.6)/0.4
+	if k.Close > k.Open {
+		confidence += 0.2 // 收阳说明买方已夺回主动
+	}
+	return single(Hammer, i, i, Bullish, confidence)
+}
+
+// detectShootingStar 射击之星：上涨后出现的长上影小实体K线
+func detectShootingStar(klines []*datasource.Kline, i int) []Match {
+	k := klines[i]
+	r := candleRange(k)
+	if r <= 0 || priorTrend(klines, i) <= 0 {
+		return nil
+	}
+
+	shadow := upperShadow(k)
+	if shadow < 2*body(k) || shadow < 0.6*r || lowerShadow(k) > 0.1*r {
+		return nil
+	}
+
+	confidence := 0.5 + 0.3*(shadow/r-0.6)/0.4
+	if k.Close < k.Open {
+		confidence += 0.2 // 收阴说明卖方已夺回主动
+	}
+	return single(ShootingStar, i, i, Bearish, confidence)
+}
+
+// detectEngulfing 吞没：后一根K线实体完全覆盖前一根方向相反的实体
+func detectEngulfing(klines []*datasource.Kline, i int) []Match {
+	if i < 1 {
+		return nil
+	}
+	prev, curr := klines[i-1], klines[i]
+	if body(curr) <= body(prev) || bodyTop(curr) < bodyTop(prev) || bodyBottom(curr) > bodyBottom(prev) {
+		return nil
+	}
+
+	var patternType Type
+	var direction Direction
+	switch {
+	case isBearish(prev) && isBullish(curr):
+		patternType, direction = BullishEngulfing, Bullish
+	case isBullish(prev) && isBearish(curr):
+		patternType, direction = BearishEngulfing, Bearish
+	default:
+		return nil
+	}
+
+	confidence := 0.5 + 0.2*math.Min(1, body(curr)/body(prev)-1)
+	confidence += reversalBonus(klines, i-1, direction, 0.2)
+	if body(curr) >= averageBody(klines, i) {
+		confidence += 0.1
+	}
+	return single(patternType, i-1, i, direction, confidence)
+}
+
+// detectHarami 孕线：小实体完全位于前一根方向相反的长实体之内
+func detectHarami(klines []*datasource.Kline, i int) []Match {
+	if i < 1 {
+		return nil
+	}
+	prev, curr := klines[i-1], klines[i]
+	if body(prev) <= 0 || body(prev) < averageBody(klines, i-1) {
+		return nil
+	}
+	if bodyTop(curr) > bodyTop(prev) || bodyBottom(curr) < bodyBottom(prev) || body(curr) > 0.5*body(prev) {
+		return nil
+	}
+
+	var patternType Type
+	var direction Direction
+	switch {
+	case isBearish(prev) && isBullish(curr):
+		patternType, direction = BullishHarami, Bullish
+	case isBullish(prev) && isBearish(curr):
+		patternType, direction = BearishHarami, Bearish
+	default:
+		return nil
+	}
+
+	confidence := 0.4 + 0.2*(1-2*body(curr)/body(prev))
+	confidence += reversalBonus(klines, i-1, direction, 0.2)
+	return single(patternType, i-1, i, direction, confidence)
+}
+
+// detectStar 启明星/黄昏星：长实体、小实体星线、反向长实体收复第一根实体一半以上
+func detectStar(klines []*datasource.Kline, i int) []Match {
+	if i < 2 {
+		return nil
+	}
+	first, star, last := klines[i-2], klines[i-1], klines[i]
+	if body(first) <= 0 || body(first) < averageBody(klines, i-2) || body(star) > 0.3*body(first) {
+		return nil
+	}
+
+	mid := (first.Open + first.Close) / 2
+	switch {
+	case isBearish(first) && bodyTop(star) < mid && isBullish(last) && last.Close > mid:
+		confidence := 0.6 + 0.2*math.Min(1, (last.Close-mid)/(first.Open-mid))
+		confidence += reversalBonus(klines, i-2, Bullish, 0.2)
+		return single(MorningStar, i-2, i, Bullish, confidence)
+	case isBullish(first) && bodyBottom(star) > mid && isBearish(last) && last.Close < mid:
+		confidence := 0.6 + 0.2*math.Min(1, (mid-last.Close)/(mid-first.Open))
+		confidence += reversalBonus(klines, i-2, Bearish, 0.2)
+		return single(EveningStar, i-2, i, Bearish, confidence)
+	}
+	return nil
+}
+
+// detectThreeSoldiersOrCrows 红三兵/三只乌鸦：三根同向实体K线逐级推进，每根开盘于前一根实体内
+func detectThreeSoldiersOrCrows(klines []*datasource.Kline, i int) []Match {
+	if i < 2 {
+		return nil
+	}
+	avg := averageBody(klines, i-2)
+	bullish, bearish, allLong := true, true, true
+	for j := i - 2; j <= i; j++ {
+		k := klines[j]
+		if body(k) < 0.5*avg {
+			return nil
+		}
+		allLong = allLong && body(k) >= avg
+
+		// 影线过长说明推进受阻
+		bullish = bullish && isBullish(k) && upperShadow(k) <= 0.3*candleRange(k)
+		bearish = bearish && isBearish(k) && lowerShadow(k) <= 0.3*candleRange(k)
+		if j > i-2 {
+			prev := klines[j-1]
+			inPrevBody := k.Open >= bodyBottom(prev) && k.Open <= bodyTop(prev)
+			bullish = bullish && inPrevBody && k.Close > prev.Close
+			bearish = bearish && inPrevBody && k.Close < prev.Close
+		}
+	}
+
+	var patternType Type
+	var direction Direction
+	switch {
+	case bullish:
+		patternType, direction = ThreeWhiteSoldiers, Bullish
+	case bearish:
+		patternType, direction = ThreeBlackCrows, Bearish
+	default:
+		return nil
+	}
+
+	confidence := 0.6 + reversalBonus(klines, i-2, direction, 0.2)
+	if allLong {
+		confidence += 0.2
+	}
+	return single(patternType, i-2, i, direction, confidence)
+}
+
+// detectInsideBar 内包线：最高价和最低价都在前一根K线区间之内
+func detectInsideBar(klines []*datasource.Kline, i int) []Match {
+	if i < 1 {
+		return nil
+	}
+	prev, curr := klines[i-1], klines[i]
+	if curr.High > prev.High || curr.Low < prev.Low || candleRange(curr) >= candleRange(prev) {
+		return nil
+	}
+
+	confidence := 0.5 + 0.5*(1-candleRange(curr)/candleRange(prev))
+	return single(InsideBar, i-1, i, Neutral, confidence)
+}
+
+// single 构造单个匹配，置信度限制在 0-1
+func single(patternType Type, start, index int, direction Direction, confidence float64) []Match {
+	return []Match{{
+		Type:       patternType,
+		Start:      start,
+		Index:      index,
+		Direction:  direction,
+		Confidence: math.Max(0, math.Min(1, confidence)),
+	}}
+}
+
+// reversalBonus 形态之前的趋势与反转方向相反时返回 bonus，start 为形态第一根K线索引
+func reversalBonus(klines []*datasource.Kline, start int, direction Direction, bonus float64) float64 {
+	trend := priorTrend(klines, start)
+	if (direction == Bullish && trend < 0) || (direction == Bearish && trend > 0) {
+		return bonus
+	}
+	return 0
+}
+
+// priorTrend 判断第 end 根K线之前的短期趋势：1 上涨，-1 下跌，0 无法判断或持平
+func priorTrend(klines []*datasource.Kline, end int) int {
+	if end-1-trendPeriod < 0 {
+		return 0
+	}
+
+	change := klines[end-1].Close - klines[end-1-trendPeriod].Close
+	switch {
+	case change > 0:
+		return 1
+	case change < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// averageBody 计算第 end 根K线之前最多 bodyAveragePeriod 根K线的平均实体长度，没有历史时为0
+func averageBody(klines []*datasource.Kline, end int) float64 {
+	start := max(0, end-bodyAveragePeriod)
+	if end <= start {
+		return 0
+	}
+
+	sum := 0.0
+	for _, k := range klines[start:end] {
+		sum += body(k)
+	}
+	return sum / float64(end-start)
+}
+
+func body(k *datasource.Kline) float64        { return math.Abs(k.Close - k.Open) }
+func candleRange(k *datasource.Kline) float64 { return k.High - k.Low }
+func bodyTop(k *datasource.Kline) float64     { return math.Max(k.Open, k.Close) }
+func bodyBottom(k *datasource.Kline) float64  { return math.Min(k.Open, k.Close) }
+func upperShadow(k *datasource.Kline) float64 { return k.High - bodyTop(k) }
+func lowerShadow(k *datasource.Kline) float64 { return bodyBottom(k) - k.Low }
+func isBullish(k *datasource.Kline) bool      { return k.Close > k.Open }
+func isBearish(k *datasource.Kline) bool      { return k.Close < k.Open }
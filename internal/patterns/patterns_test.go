@@ -0,0 +1,203 @@
+package patterns
+
+import (
+	"testing"
+
+	"ta-watcher/internal/datasource"
+)
+
+// candle 创建测试K线
+func candle(open, high, low, close float64) *datasource.Kline {
+	return &datasource.Kline{Symbol: "BTCUSDT", Open: open, High: high, Low: low, Close: close}
+}
+
+// downtrend 生成7根实体为2的连续阴线，最后一根为 98→96
+func downtrend() []*datasource.Kline {
+	klines := make([]*datasource.Kline, 0, 7)
+	for j := 0; j < 7; j++ {
+		open := 110 - 2*float64(j)
+		klines = append(klines, candle(open, open+0.5, open-2.5, open-2))
+	}
+	return klines
+}
+
+// mirror 将K线上下翻转，看涨形态变为对应的看跌形态
+func mirror(klines []*datasource.Kline) []*datasource.Kline {
+	mirrored := make([]*datasource.Kline, len(klines))
+	for i, k := range klines {
+		mirrored[i] = candle(200-k.Open, 200-k.Low, 200-k.High, 200-k.Close)
+	}
+	return mirrored
+}
+
+// find 查找指定类型的匹配
+func find(matches []Match, patternType Type) (Match, bool) {
+	for _, m := range matches {
+		if m.Type == patternType {
+			return m, true
+		}
+	}
+	return Match{}, false
+}
+
+func TestLatest(t *testing.T) {
+	tests := []struct {
+		name      string
+		tail      []*datasource.Kline
+		want      Type
+		mirrored  Type
+		direction Direction
+		bars      int
+		minConf   float64
+	}{
+		{
+			name:      "锤子线",
+			tail:      []*datasource.Kline{candle(95, 96, 92, 95.8)},
+			want:      Hammer,
+			mirrored:  ShootingStar,
+			direction: Bullish,
+			bars:      1,
+			minConf:   0.8,
+		},
+		{
+			name:      "吞没",
+			tail:      []*datasource.Kline{candle(95.5, 99.2, 95.3, 99)},
+			want:      BullishEngulfing,
+			mirrored:  BearishEngulfing,
+			direction: Bullish,
+			bars:      2,
+			minConf:   0.9,
+		},
+		{
+			name:      "孕线",
+			tail:      []*datasource.Kline{candle(96.5, 97.8, 96.2, 97.5)},
+			want:      BullishHarami,
+			mirrored:  BearishHarami,
+			direction: Bullish,
+			bars:      2,
+			minConf:   0.6,
+		},
+		{
+			name:      "启明星",
+			tail:      []*datasource.Kline{candle(95, 95.3, 94.5, 94.8), candle(95, 98, 94.9, 97.8)},
+			want:      MorningStar,
+			mirrored:  EveningStar,
+			direction: Bullish,
+			bars:      3,
+			minConf:   0.9,
+		},
+		{
+			name: "红三兵",
+			tail: []*datasource.Kline{
+				candle(96.5, 98.7, 96.3, 98.5),
+				candle(97.5, 100.2, 97.3, 100),
+				candle(99, 102, 98.8, 101.8),
+			},
+			want:      ThreeWhiteSoldiers,
+			mirrored:  ThreeBlackCrows,
+			direction: Bullish,
+			bars:      3,
+			minConf:   1,
+		},
+		{
+			name:      "十字星",
+			tail:      []*datasource.Kline{candle(100, 101, 99, 100.05)},
+			want:      Doji,
+			mirrored:  Doji,
+			direction: Neutral,
+			bars:      1,
+			minConf:   0.7,
+		},
+		{
+			name:      "内包线",
+			tail:      []*datasource.Kline{candle(96.5, 97.8, 96.2, 97.5)},
+			want:      InsideBar,
+			mirrored:  InsideBar,
+			direction: Neutral,
+			bars:      2,
+			minConf:   0.7,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			klines := append(downtrend(), tt.tail...)
+			last := len(klines) - 1
+
+			match, ok := find(Latest(klines), tt.want)
+			if !ok {
+				t.Fatalf("Latest() = %+v, 期望包含%s", Latest(klines), tt.want)
+			}
+			if match.Index != last || match.Start != last-tt.bars+1 {
+				t.Errorf("%s 位置 = %d-%d, 期望 %d-%d", tt.want, match.Start, match.Index, last-tt.bars+1, last)
+			}
+			if match.Direction != tt.direction {
+				t.Errorf("%s 方向 = %s, 期望 %s", tt.want, match.Direction, tt.direction)
+			}
+			if match.Confidence < tt.minConf || match.Confidence > 1 {
+				t.Errorf("%s 置信度 = %.3f, 期望不低于 %.2f", tt.want, match.Confidence, tt.minConf)
+			}
+
+			mirrored, ok := find(Latest(mirror(klines)), tt.mirrored)
+			if !ok {
+				t.Fatalf("翻转后 Latest() = %+v, 期望包含%s", Latest(mirror(klines)), tt.mirrored)
+			}
+			if mirrored.Confidence != match.Confidence {
+				t.Errorf("翻转后置信度 = %.3f, 期望 %.3f", mirrored.Confidence, match.Confidence)
+			}
+		})
+	}
+}
+
+func TestLatest_NoFalsePositives(t *testing.T) {
+	klines := append(downtrend(), candle(95, 96, 92, 95.8))
+
+	// 锤子线只应单独出现，不应同时识别为十字星、吞没或内包线
+	if matches := Latest(klines); len(matches) != 1 {
+		t.Errorf("Latest() = %+v, 期望只有锤子线", matches)
+	}
+
+	// 上涨趋势中的同形K线不是锤子线
+	rising := mirror(downtrend())
+	for i, k := range rising {
+		rising[i] = candle(k.Open-10, k.High-10, k.Low-10, k.Close-10)
+	}
+	rising = append(rising, candle(95, 96, 92, 95.8))
+	if _, ok := find(Latest(rising), Hammer); ok {
+		t.Error("上涨之后不应识别为锤子线")
+	}
+}
+
+func TestScanAndRecent(t *testing.T) {
+	klines := append(downtrend(), candle(95, 96, 92, 95.8), candle(100, 101, 99, 100.05))
+
+	matches := Scan(klines)
+	if len(matches) < 2 {
+		t.Fatalf("Scan() = %+v, 期望至少包含锤子线和十字星", matches)
+	}
+	for i := 1; i < len(matches); i++ {
+		if matches[i].Index < matches[i-1].Index {
+			t.Errorf("Scan() 未按位置排序: %+v", matches)
+		}
+	}
+
+	if _, ok := find(Recent(klines, 2), Hammer); !ok {
+		t.Error("Recent(2) 应包含倒数第二根K线的锤子线")
+	}
+	if _, ok := find(Latest(klines), Hammer); ok {
+		t.Error("Latest() 不应包含之前K线上的形态")
+	}
+
+	if matches := Scan(nil); len(matches) != 0 {
+		t.Errorf("Scan(nil) = %+v, 期望为空", matches)
+	}
+}
+
+func TestTypeString(t *testing.T) {
+	if ThreeBlackCrows.String() != "三只乌鸦" || Type(99).String() != "未知形态" {
+		t.Error("Type.String() 返回值不正确")
+	}
+	if Bullish.String() != "看涨" || Neutral.String() != "中性" {
+		t.Error("Direction.String() 返回值不正确")
+	}
+}
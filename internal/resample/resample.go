@@ -0,0 +1,161 @@
+// Package resample 将K线重采样为更大的时间框架
+// 与数据源无关：交易所不支持的周期、本地文件和实时聚合都使用相同的周期边界
+package resample
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"ta-watcher/internal/market"
+)
+
+// timeframes 按时长升序排列的全部时间框架
+var timeframes = []market.Timeframe{
+	market.Timeframe1m, market.Timeframe3m, market.Timeframe5m, market.Timeframe15m, market.Timeframe30m,
+	market.Timeframe1h, market.Timeframe2h, market.Timeframe4h, market.Timeframe6h, market.Timeframe8h, market.Timeframe12h,
+	market.Timeframe1d, market.Timeframe3d, market.Timeframe1w, market.Timeframe1M,
+}
+
+// Options 重采样选项
+// 注意 time.Weekday 的零值为周日，一般应从 DefaultOptions 开始修改
+type Options struct {
+	WeekStart   time.Weekday // 周线起始日
+	DropPartial bool         // 丢弃基础数据不完整的周期（如区间首尾或存在缺口）
+}
+
+// DefaultOptions 默认重采样选项（周线从周一开始，与Binance一致）
+func DefaultOptions() Options {
+	return Options{WeekStart: time.Monday}
+}
+
+// PeriodStart 返回时间所在周期的开始时间（UTC）
+// 日内及3日周期按Unix纪元对齐，周线按 weekStart 对齐，月线为当月1日
+func PeriodStart(t time.Time, tf market.Timeframe, weekStart time.Weekday) time.Time {
+	t = t.UTC()
+	switch tf {
+	case market.Timeframe1M:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case market.Timeframe1w:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		offset := (int(day.Weekday()) - int(weekStart) + 7) % 7
+		return day.AddDate(0, 0, -offset)
+	}
+
+	step := int64(tf.Duration() / time.Second)
+	if step == 0 {
+		return t
+	}
+	unix := t.Unix()
+	start := unix - ((unix%step)+step)%step
+	return time.Unix(start, 0).UTC()
+}
+
+// PeriodEnd 返回周期的结束时间（下一周期开始时间）
+func PeriodEnd(start time.Time, tf market.Timeframe) time.Time {
+	switch tf {
+	case market.Timeframe1M:
+		return start.AddDate(0, 1, 0)
+	case market.Timeframe1w:
+		return start.AddDate(0, 0, 7)
+	default:
+		return start.Add(tf.Duration())
+	}
+}
+
+// CanResample 判断是否可以由 source 时间框架重采样得到 target
+func CanResample(source, target market.Timeframe) bool {
+	sourceDur, targetDur := source.Duration(), target.Duration()
+	if sourceDur == 0 || targetDur == 0 || sourceDur >= targetDur {
+		return false
+	}
+
+	switch target {
+	case market.Timeframe1w, market.Timeframe1M:
+		// 周线和月线的边界都在UTC零点，基础周期需能整除一天
+		return sourceDur <= 24*time.Hour && (24*time.Hour)%sourceDur == 0
+	default:
+		return targetDur%sourceDur == 0
+	}
+}
+
+// SourceFor 在原生支持的时间框架中找出可重采样到 target 的最大者
+func SourceFor(target market.Timeframe, native func(market.Timeframe) bool) (market.Timeframe, bool) {
+	for i := len(timeframes) - 1; i >= 0; i-- {
+		source := timeframes[i]
+		if native(source) && CanResample(source, target) {
+			return source, true
+		}
+	}
+	return "", false
+}
+
+// Resample 将 source 时间框架的K线重采样为更大的 target 时间框架
+// 输入无需排序；结果按周期开始时间升序，基础K线数量不足的周期标记为 Partial
+func Resample(klines []*market.Kline, source, target market.Timeframe, opts Options) ([]*market.Kline, error) {
+	if !CanResample(source, target) {
+		return nil, fmt.Errorf("cannot resample %s to %s", source, target)
+	}
+	if len(klines) == 0 {
+		return []*market.Kline{}, nil
+	}
+
+	sorted := make([]*market.Kline, len(klines))
+	copy(sorted, klines)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].OpenTime.Before(sorted[j].OpenTime)
+	})
+
+	var result []*market.Kline
+	var current *market.Kline
+	var currentEnd time.Time
+	var count int
+	var lastCloseTime time.Time
+	allClosed := true
+
+	finish := func() {
+		if current == nil {
+			return
+		}
+		expected := int(currentEnd.Sub(current.OpenTime) / source.Duration())
+		current.Partial = count < expected
+		// 周期最后一根基础K线已收盘，整个周期才算收盘
+		current.IsClosed = allClosed && !lastCloseTime.Before(current.CloseTime)
+		if !(opts.DropPartial && current.Partial) {
+			result = append(result, current)
+		}
+	}
+
+	for _, k := range sorted {
+		start := PeriodStart(k.OpenTime, target, opts.WeekStart)
+		if current == nil || !start.Equal(current.OpenTime) {
+			finish()
+			currentEnd = PeriodEnd(start, target)
+			current = &market.Kline{
+				Symbol:    k.Symbol,
+				OpenTime:  start,
+				CloseTime: currentEnd.Add(-time.Millisecond),
+				Open:      k.Open,
+				High:      k.High,
+				Low:       k.Low,
+			}
+			count = 0
+			allClosed = true
+		}
+
+		if k.High > current.High {
+			current.High = k.High
+		}
+		if k.Low < current.Low {
+			current.Low = k.Low
+		}
+		current.Close = k.Close
+		current.Volume += k.Volume
+		count++
+		lastCloseTime = k.CloseTime
+		allClosed = allClosed && k.IsClosed
+	}
+	finish()
+
+	return result, nil
+}
@@ -0,0 +1,464 @@
+package resample
+
+import (
+	"testing"
+	"time"
+
+	"ta-watcher/internal/market"
+)
+
+// makeKlines 按固定间隔创建K线，OHLC 均为 close，成交量为1
+func makeKlines(symbol string, start time.Time, interval time.Duration, closes ...float64) []*market.Kline {
+	klines := make([]*market.Kline, len(closes))
+	for i, c := range closes {
+		open := start.Add(time.Duration(i) * interval)
+		klines[i] = &market.Kline{
+			Symbol:    symbol,
+			OpenTime:  open,
+			CloseTime: open.Add(interval - time.Millisecond),
+			Open:      c,
+			High:      c,
+			Low:       c,
+			Close:     c,
+			Volume:    1,
+		}
+	}
+	return klines
+}
+
+func TestPeriodStart(t *testing.T) {
+	// 2025-01-01 是周三
+	ts := time.Date(2025, 1, 1, 13, 45, 0, 0, time.UTC)
+
+	tests := []struct {
+		tf        market.Timeframe
+		weekStart time.Weekday
+		expected  time.Time
+	}{
+		{market.Timeframe15m, time.Monday, time.Date(2025, 1, 1, 13, 45, 0, 0, time.UTC)},
+		{market.Timeframe4h, time.Monday, time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)},
+		{market.Timeframe8h, time.Monday, time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)},
+		{market.Timeframe1d, time.Monday, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{market.Timeframe1w, time.Monday, time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC)},
+		{market.Timeframe1w, time.Sunday, time.Date(2024, 12, 29, 0, 0, 0, 0, time.UTC)},
+		{market.Timeframe1w, time.Wednesday, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{market.Timeframe1M, time.Monday, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range tests {
+		if got := PeriodStart(ts, tc.tf, tc.weekStart); !got.Equal(tc.expected) {
+			t.Errorf("PeriodStart(%s, %s) = %v, expected %v", tc.tf, tc.weekStart, got, tc.expected)
+		}
+	}
+}
+
+func TestCanResample(t *testing.T) {
+	tests := []struct {
+		source, target market.Timeframe
+		expected       bool
+	}{
+		{market.Timeframe1h, market.Timeframe2h, true},
+		{market.Timeframe4h, market.Timeframe8h, true},
+		{market.Timeframe1d, market.Timeframe3d, true},
+		{market.Timeframe1d, market.Timeframe1w, true},
+		{market.Timeframe1h, market.Timeframe1M, true},
+		{market.Timeframe4h, market.Timeframe6h, false}, // 不能整除
+		{market.Timeframe3d, market.Timeframe1w, false}, // 跨越周边界
+		{market.Timeframe1w, market.Timeframe1M, false},
+		{market.Timeframe1d, market.Timeframe1h, false},
+	}
+
+	for _, tc := range tests {
+		if got := CanResample(tc.source, tc.target); got != tc.expected {
+			t.Errorf("CanResample(%s, %s) = %v, expected %v", tc.source, tc.target, got, tc.expected)
+		}
+	}
+}
+
+func TestResample_OHLCVAndPartial(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	// 10根1h K线 -> 4h: [0-3] [4-7] [8-9]
+	klines := makeKlines("BTCUSDT", base, time.Hour, 1, 5, 3, 4, 6, 2, 8, 7, 9, 10)
+	for _, k := range klines {
+		k.High = k.Close + 1
+		k.Low = k.Close - 1
+		k.IsClosed = true
+	}
+
+	result, err := Resample(klines, market.Timeframe1h, market.Timeframe4h, DefaultOptions())
+	if err != nil {
+		t.Fatalf("Resample() error = %v", err)
+	}
+	if len(result) != 3 {
+		t.Fatalf("expected 3 klines, got %d", len(result))
+	}
+
+	first := result[0]
+	if first.Open != 1 || first.High != 6 || first.Low != 0 || first.Close != 4 || first.Volume != 4 {
+		t.Errorf("unexpected OHLCV: %+v", first)
+	}
+	if !first.OpenTime.Equal(base) || !first.CloseTime.Equal(base.Add(4*time.Hour-time.Millisecond)) {
+		t.Errorf("unexpected period: %v - %v", first.OpenTime, first.CloseTime)
+	}
+	if first.Partial || !first.IsClosed {
+		t.Errorf("complete period should not be partial and should be closed: %+v", first)
+	}
+
+	last := result[2]
+	if !last.Partial || last.IsClosed {
+		t.Errorf("incomplete period should be partial and not closed: %+v", last)
+	}
+
+	opts := DefaultOptions()
+	opts.DropPartial = true
+	result, _ = Resample(klines, market.Timeframe1h, market.Timeframe4h, opts)
+	if len(result) != 2 {
+		t.Errorf("DropPartial should drop incomplete period, got %d klines", len(result))
+	}
+
+	if _, err := Resample(klines, market.Timeframe1h, market.Timeframe1h, opts); err == nil {
+		t.Error("expected error when target is not larger than source")
+	}
+}
+
+// TestResample_DailyToWeekly 测试日线重采样为周线（Coinbase 周线由此得到）
+func TestResample_DailyToWeekly(t *testing.T) {
+	// 创建测试数据：14天的日线数据，应该聚合成2周的周线数据
+	dailyKlines := []*market.Kline{}
+
+	// 第一周：2025-06-23 (周一) 到 2025-06-29 (周日)，7天
+	baseTime := time.Date(2025, 6, 23, 8, 0, 0, 0, time.UTC) // 周一
+	for i := 0; i < 7; i++ {
+		kline := &market.Kline{
+			Symbol:    "BTCUSD",
+			OpenTime:  baseTime.AddDate(0, 0, i),
+			CloseTime: baseTime.AddDate(0, 0, i).Add(24 * time.Hour),
+			Open:      float64(30000 + i*100),
+			High:      float64(30500 + i*100),
+			Low:       float64(29500 + i*100),
+			Close:     float64(30200 + i*100),
+			Volume:    1000.0,
+		}
+		dailyKlines = append(dailyKlines, kline)
+	}
+
+	// 第二周：2025-06-30 (周一) 到 2025-07-06 (周日)，7天
+	baseTime2 := time.Date(2025, 6, 30, 8, 0, 0, 0, time.UTC) // 第二周周一
+	for i := 0; i < 7; i++ {
+		kline := &market.Kline{
+			Symbol:    "BTCUSD",
+			OpenTime:  baseTime2.AddDate(0, 0, i),
+			CloseTime: baseTime2.AddDate(0, 0, i).Add(24 * time.Hour),
+			Open:      float64(31000 + i*100),
+			High:      float64(31500 + i*100),
+			Low:       float64(30500 + i*100),
+			Close:     float64(31200 + i*100),
+			Volume:    1000.0,
+		}
+		dailyKlines = append(dailyKlines, kline)
+	}
+
+	t.Logf("输入数据: %d条日线K线", len(dailyKlines))
+
+	// 执行聚合
+	weeklyKlines, err := Resample(dailyKlines, market.Timeframe1d, market.Timeframe1w, DefaultOptions())
+	if err != nil {
+		t.Fatalf("Resample() error = %v", err)
+	}
+
+	t.Logf("输出数据: %d条周线K线", len(weeklyKlines))
+
+	// 验证结果
+	if len(weeklyKlines) != 2 {
+		t.Errorf("期望2条周线数据，实际得到 %d 条", len(weeklyKlines))
+	}
+
+	if len(weeklyKlines) >= 2 {
+		// 验证第一周数据
+		week1 := weeklyKlines[0]
+		if week1.Open != 30000 {
+			t.Errorf("第一周开盘价错误: 期望 30000, 实际 %.0f", week1.Open)
+		}
+		if week1.Close != 30800 {
+			t.Errorf("第一周收盘价错误: 期望 30800, 实际 %.0f", week1.Close)
+		}
+
+		// 验证第二周数据
+		week2 := weeklyKlines[1]
+		if week2.Open != 31000 {
+			t.Errorf("第二周开盘价错误: 期望 31000, 实际 %.0f", week2.Open)
+		}
+		if week2.Close != 31800 {
+			t.Errorf("第二周收盘价错误: 期望 31800, 实际 %.0f", week2.Close)
+		}
+	}
+}
+
+// TestResample_DailyToMonthly 测试日线重采样为月线
+func TestResample_DailyToMonthly(t *testing.T) {
+	// 创建跨月的测试数据
+	dailyKlines := []*market.Kline{}
+
+	// 2025年5月：1-31日，31天
+	may1 := time.Date(2025, 5, 1, 8, 0, 0, 0, time.UTC)
+	for i := 0; i < 31; i++ {
+		kline := &market.Kline{
+			Symbol:    "BTCUSD",
+			OpenTime:  may1.AddDate(0, 0, i),
+			CloseTime: may1.AddDate(0, 0, i).Add(24 * time.Hour),
+			Open:      float64(2100 + i),
+			High:      float64(2200 + i),
+			Low:       float64(2000 + i),
+			Close:     float64(2150 + i),
+			Volume:    1000.0,
+		}
+		dailyKlines = append(dailyKlines, kline)
+	}
+
+	t.Logf("输入数据: %d条日线K线", len(dailyKlines))
+
+	// 执行聚合
+	monthlyKlines, err := Resample(dailyKlines, market.Timeframe1d, market.Timeframe1M, DefaultOptions())
+	if err != nil {
+		t.Fatalf("Resample() error = %v", err)
+	}
+
+	t.Logf("输出数据: %d条月线K线", len(monthlyKlines))
+
+	// 验证应该有1个月的数据
+	if len(monthlyKlines) != 1 {
+		t.Errorf("期望1条月线数据，实际得到 %d 条", len(monthlyKlines))
+	}
+
+	if len(monthlyKlines) >= 1 {
+		may := monthlyKlines[0]
+		if may.Open != 2100 { // 5月1日的开盘价
+			t.Errorf("5月开盘价错误: 期望 2100, 实际 %.0f", may.Open)
+		}
+	}
+}
+
+// TestPeriodStart_WeekStart 测试周线周期起点计算
+func TestPeriodStart_WeekStart(t *testing.T) {
+	testCases := []struct {
+		name      string
+		input     time.Time
+		weekStart time.Weekday
+		expected  time.Time
+	}{
+		{
+			name:      "周一",
+			input:     time.Date(2025, 6, 23, 15, 30, 45, 0, time.UTC), // 周一
+			weekStart: time.Monday,
+			expected:  time.Date(2025, 6, 23, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "周三",
+			input:     time.Date(2025, 6, 25, 10, 20, 30, 0, time.UTC), // 周三
+			weekStart: time.Monday,
+			expected:  time.Date(2025, 6, 23, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "周日",
+			input:     time.Date(2025, 6, 29, 23, 59, 59, 0, time.UTC), // 周日
+			weekStart: time.Monday,
+			expected:  time.Date(2025, 6, 23, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "周日起始",
+			input:     time.Date(2025, 6, 25, 10, 20, 30, 0, time.UTC), // 周三
+			weekStart: time.Sunday,
+			expected:  time.Date(2025, 6, 22, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := PeriodStart(tc.input, market.Timeframe1w, tc.weekStart)
+			if !result.Equal(tc.expected) {
+				t.Errorf("PeriodStart(%s, 1w, %s) = %s, 期望 %s",
+					tc.input.Format("2006-01-02 15:04:05"), tc.weekStart,
+					result.Format("2006-01-02 15:04:05"),
+					tc.expected.Format("2006-01-02 15:04:05"))
+			}
+		})
+	}
+}
+
+func TestResample_CoinbaseDailyToWeekly(t *testing.T) {
+	// 创建测试数据：14天的日线数据，应该聚合成2周的周线数据
+	dailyKlines := []*market.Kline{}
+
+	// 第一周：2025-06-23 (周一) 到 2025-06-29 (周日)，7天
+	baseTime := time.Date(2025, 6, 23, 8, 0, 0, 0, time.UTC) // 周一
+	for i := 0; i < 7; i++ {
+		kline := &market.Kline{
+			Symbol:    "BTCUSD",
+			OpenTime:  baseTime.AddDate(0, 0, i),
+			CloseTime: baseTime.AddDate(0, 0, i).Add(24 * time.Hour),
+			Open:      float64(30000 + i*100),
+			High:      float64(30500 + i*100),
+			Low:       float64(29500 + i*100),
+			Close:     float64(30200 + i*100),
+			Volume:    1000.0,
+		}
+		dailyKlines = append(dailyKlines, kline)
+	}
+
+	// 第二周：2025-06-30 (周一) 到 2025-07-06 (周日)，7天
+	baseTime2 := time.Date(2025, 6, 30, 8, 0, 0, 0, time.UTC) // 第二周周一
+	for i := 0; i < 7; i++ {
+		kline := &market.Kline{
+			Symbol:    "BTCUSD",
+			OpenTime:  baseTime2.AddDate(0, 0, i),
+			CloseTime: baseTime2.AddDate(0, 0, i).Add(24 * time.Hour),
+			Open:      float64(31000 + i*100),
+			High:      float64(31500 + i*100),
+			Low:       float64(30500 + i*100),
+			Close:     float64(31200 + i*100),
+			Volume:    1000.0,
+		}
+		dailyKlines = append(dailyKlines, kline)
+	}
+
+	// 聚合为周线
+	weeklyKlines, err := Resample(dailyKlines, market.Timeframe1d, market.Timeframe1w, DefaultOptions())
+	if err != nil {
+		t.Fatalf("Resample() error = %v", err)
+	}
+
+	// 验证结果
+	if len(weeklyKlines) != 2 {
+		t.Errorf("期望得到 2 条周线数据，实际得到 %d 条", len(weeklyKlines))
+	}
+
+	if len(weeklyKlines) >= 1 {
+		// 验证第一周数据
+		week1 := weeklyKlines[0]
+		if week1.Open != 30000 {
+			t.Errorf("第一周开盘价期望 30000，实际 %.0f", week1.Open)
+		}
+		if week1.Close != 30800 { // 最后一天收盘价
+			t.Errorf("第一周收盘价期望 30800，实际 %.0f", week1.Close)
+		}
+		if week1.Volume != 7000 { // 7天总量
+			t.Errorf("第一周成交量期望 7000，实际 %.0f", week1.Volume)
+		}
+	}
+}
+
+func TestResample_CoinbaseDailyToMonthly(t *testing.T) {
+	// 创建测试数据：跨越3个月的日线数据
+	dailyKlines := []*market.Kline{}
+
+	// 2025年4月的最后几天
+	for day := 28; day <= 30; day++ {
+		kline := &market.Kline{
+			Symbol:    "ETHUSD",
+			OpenTime:  time.Date(2025, 4, day, 8, 0, 0, 0, time.UTC),
+			CloseTime: time.Date(2025, 4, day, 8, 0, 0, 0, time.UTC).Add(24 * time.Hour),
+			Open:      float64(2000 + day),
+			High:      float64(2100 + day),
+			Low:       float64(1900 + day),
+			Close:     float64(2050 + day),
+			Volume:    500.0,
+		}
+		dailyKlines = append(dailyKlines, kline)
+	}
+
+	// 2025年5月整月
+	for day := 1; day <= 31; day++ {
+		kline := &market.Kline{
+			Symbol:    "ETHUSD",
+			OpenTime:  time.Date(2025, 5, day, 8, 0, 0, 0, time.UTC),
+			CloseTime: time.Date(2025, 5, day, 8, 0, 0, 0, time.UTC).Add(24 * time.Hour),
+			Open:      float64(2100 + day),
+			High:      float64(2200 + day),
+			Low:       float64(2000 + day),
+			Close:     float64(2150 + day),
+			Volume:    500.0,
+		}
+		dailyKlines = append(dailyKlines, kline)
+	}
+
+	// 2025年6月前几天
+	for day := 1; day <= 5; day++ {
+		kline := &market.Kline{
+			Symbol:    "ETHUSD",
+			OpenTime:  time.Date(2025, 6, day, 8, 0, 0, 0, time.UTC),
+			CloseTime: time.Date(2025, 6, day, 8, 0, 0, 0, time.UTC).Add(24 * time.Hour),
+			Open:      float64(2200 + day),
+			High:      float64(2300 + day),
+			Low:       float64(2100 + day),
+			Close:     float64(2250 + day),
+			Volume:    500.0,
+		}
+		dailyKlines = append(dailyKlines, kline)
+	}
+
+	// 聚合为月线
+	monthlyKlines, err := Resample(dailyKlines, market.Timeframe1d, market.Timeframe1M, DefaultOptions())
+	if err != nil {
+		t.Fatalf("Resample() error = %v", err)
+	}
+
+	// 验证结果：应该得到3条月线数据（4月末+5月+6月初）
+	if len(monthlyKlines) != 3 {
+		t.Errorf("期望得到 3 条月线数据，实际得到 %d 条", len(monthlyKlines))
+	}
+
+	if len(monthlyKlines) >= 2 {
+		// 验证5月份数据（完整月份）
+		may := monthlyKlines[1]
+		if may.Open != 2101 { // 5月1日开盘价
+			t.Errorf("5月开盘价期望 2101，实际 %.0f", may.Open)
+		}
+		if may.Close != 2181 { // 5月31日收盘价
+			t.Errorf("5月收盘价期望 2181，实际 %.0f", may.Close)
+		}
+		if may.Volume != 15500 { // 31天总量
+			t.Errorf("5月成交量期望 15500，实际 %.0f", may.Volume)
+		}
+	}
+}
+
+func TestResample_EdgeCases(t *testing.T) {
+	// 测试空数据
+	emptyResult, err := Resample([]*market.Kline{}, market.Timeframe1d, market.Timeframe1w, DefaultOptions())
+	if err != nil {
+		t.Fatalf("Resample() error = %v", err)
+	}
+	if len(emptyResult) != 0 {
+		t.Errorf("空数据聚合应该返回空结果，实际得到 %d 条", len(emptyResult))
+	}
+
+	// 测试单条数据
+	singleKline := []*market.Kline{
+		{
+			Symbol:    "BTCUSD",
+			OpenTime:  time.Date(2025, 6, 23, 8, 0, 0, 0, time.UTC),
+			CloseTime: time.Date(2025, 6, 23, 8, 0, 0, 0, time.UTC).Add(24 * time.Hour),
+			Open:      30000,
+			High:      30500,
+			Low:       29500,
+			Close:     30200,
+			Volume:    1000,
+		},
+	}
+
+	singleResult, err := Resample(singleKline, market.Timeframe1d, market.Timeframe1w, DefaultOptions())
+	if err != nil {
+		t.Fatalf("Resample() error = %v", err)
+	}
+	if len(singleResult) != 1 {
+		t.Errorf("单条数据聚合应该返回1条结果，实际得到 %d 条", len(singleResult))
+	}
+
+	if len(singleResult) == 1 {
+		result := singleResult[0]
+		if result.Open != 30000 || result.Close != 30200 || result.Volume != 1000 {
+			t.Errorf("单条数据聚合结果不正确: Open=%.0f Close=%.0f Volume=%.0f",
+				result.Open, result.Close, result.Volume)
+		}
+	}
+}
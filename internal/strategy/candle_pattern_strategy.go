@@ -0,0 +1,239 @@
+package strategy
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"ta-watcher/internal/datasource"
+	"ta-watcher/internal/patterns"
+)
+
+// 形态确认模式下允许形态出现在基础信号之前的K线数
+const patternConfirmBars = 2
+
+// CandlePatternStrategy K线形态策略
+// 单独使用时由最新K线完成的形态触发信号；包装基础策略时只在最近出现同向形态时放行基础策略的信号
+type CandlePatternStrategy struct {
+	name                string
+	minConfidence       float64
+	base                Strategy // 被确认的基础策略，为 nil 时形态直接触发信号
+	supportedTimeframes []datasource.Timeframe
+}
+
+// NewCandlePatternStrategy 创建K线形态策略
+// minConfidence: 参与判断的形态最低置信度 (0-1)
+func NewCandlePatternStrategy(minConfidence float64) *CandlePatternStrategy {
+	if minConfidence <= 0 || minConfidence > 1 {
+		minConfidence = 0.6
+	}
+
+	return &CandlePatternStrategy{
+		name:                fmt.Sprintf("CandlePattern_%.0f", minConfidence*100),
+		minConfidence:       minConfidence,
+		supportedTimeframes: oscillatorTimeframes(),
+	}
+}
+
+// NewCandlePatternConfirmStrategy 创建以K线形态确认基础策略信号的策略
+// base: 基础策略，其信号只有在最近 patternConfirmBars 根K线内出现同向形态时才会保留
+func NewCandlePatternConfirmStrategy(base Strategy, minConfidence float64) *CandlePatternStrategy {
+	s := NewCandlePatternStrategy(minConfidence)
+	s.base = base
+	s.name = fmt.Sprintf("%s+CandlePattern_%.0f", base.Name(), s.minConfidence*100)
+	s.supportedTimeframes = base.SupportedTimeframes()
+	return s
+}
+
+// Name 返回策略名称
+func (s *CandlePatternStrategy) Name() string {
+	return s.name
+}
+
+// Description 返回策略描述
+func (s *CandlePatternStrategy) Description() string {
+	if s.base != nil {
+		return fmt.Sprintf("K线形态确认策略\n• 基础策略: %s\n• 最低置信度: %.0f%%\n• 说明: 基础策略触发后，最近%d根K线内出现同向K线形态才发出信号，形态置信度高时提升信号强度",
+			s.base.Name(), s.minConfidence*100, patternConfirmBars)
+	}
+	return fmt.Sprintf("K线形态策略\n• 最低置信度: %.0f%%\n• 形态: 吞没、锤子线/射击之星、十字星、启明星/黄昏星、红三兵/三只乌鸦、孕线、内包线\n• 说明: 最新K线完成看涨形态时买入，看跌形态时卖出，多空形态并存时按置信度之和取舍",
+		s.minConfidence*100)
+}
+
+// RequiredDataPoints 返回所需数据点
+func (s *CandlePatternStrategy) RequiredDataPoints() int {
+	// 平均实体和前期趋势需要约十几根K线作为参照
+	required := 15
+	if s.base != nil && s.base.RequiredDataPoints() > required {
+		required = s.base.RequiredDataPoints()
+	}
+	return required
+}
+
+// SupportedTimeframes 返回支持的时间框架
+func (s *CandlePatternStrategy) SupportedTimeframes() []datasource.Timeframe {
+	return s.supportedTimeframes
+}
+
+// Evaluate 评估策略
+func (s *CandlePatternStrategy) Evaluate(data *MarketData) (*StrategyResult, error) {
+	if len(data.Klines) < 3 {
+		return nil, fmt.Errorf("insufficient data for candle patterns: need at least 3 klines, got %d", len(data.Klines))
+	}
+
+	if s.base != nil {
+		return s.confirm(data)
+	}
+
+	ctx := NewIndicatorContext(data)
+	matches := s.filter(ctx.CandlePatterns(1))
+
+	result := &StrategyResult{
+		Signal:    SignalNone,
+		Strength:  StrengthNormal,
+		Timestamp: time.Now(),
+		Metadata:  make(map[string]interface{}),
+		Indicators: map[string]interface{}{
+			"price":         ctx.LatestPrice(),
+			"pattern_count": len(matches),
+		},
+		Thresholds: map[string]interface{}{
+			"min_confidence": s.minConfidence,
+		},
+	}
+	result.Metadata["patterns"] = describePatterns(matches)
+	result.IndicatorSummary = fmt.Sprintf("K线形态: %s", describePatterns(matches))
+
+	var bullScore, bearScore float64
+	for _, m := range matches {
+		switch m.Direction {
+		case patterns.Bullish:
+			bullScore += m.Confidence
+		case patterns.Bearish:
+			bearScore += m.Confidence
+		}
+	}
+	result.Metadata["bullish_score"] = bullScore
+	result.Metadata["bearish_score"] = bearScore
+
+	if bullScore == bearScore {
+		result.Message = "⚪ 无明确方向的K线形态"
+		if len(matches) > 0 {
+			result.DetailedAnalysis = fmt.Sprintf("最新K线出现%s，多空方向不明确，建议等待后续K线确认。", describePatterns(matches))
+		} else {
+			result.DetailedAnalysis = "最新K线未形成达到置信度要求的K线形态。"
+		}
+		return result, nil
+	}
+
+	// 多空形态并存时取置信度之和较大的一方
+	direction := patterns.Bullish
+	if bearScore > bullScore {
+		direction = patterns.Bearish
+	}
+	best := strongest(matches, direction)
+
+	if direction == patterns.Bullish {
+		result.Signal = SignalBuy
+		result.Message = fmt.Sprintf("🟢 %s形态", best.Type)
+	} else {
+		result.Signal = SignalSell
+		result.Message = fmt.Sprintf("🔴 %s形态", best.Type)
+	}
+	result.Strength = patternStrength(best.Confidence)
+	result.DetailedAnalysis = fmt.Sprintf("最新K线完成%s（%d根K线，置信度 %.0f%%），%s信号。",
+		best.Type, best.Index-best.Start+1, best.Confidence*100, direction)
+	if len(matches) > 1 {
+		result.DetailedAnalysis += fmt.Sprintf("<br/>同时识别到: %s", describePatterns(matches))
+	}
+	result.DetailedAnalysis += fmt.Sprintf("<br/>信号强度: %s", result.Strength)
+
+	return result, nil
+}
+
+// confirm 运行基础策略，并要求最近K线出现同向形态
+func (s *CandlePatternStrategy) confirm(data *MarketData) (*StrategyResult, error) {
+	result, err := s.base.Evaluate(data)
+	if err != nil {
+		return nil, err
+	}
+	if result.Metadata == nil {
+		result.Metadata = make(map[string]interface{})
+	}
+
+	matches := s.filter(NewIndicatorContext(data).CandlePatterns(patternConfirmBars))
+	result.Metadata["patterns"] = describePatterns(matches)
+	if !result.ShouldNotify() {
+		return result, nil
+	}
+
+	direction := patterns.Bullish
+	if result.Signal == SignalSell {
+		direction = patterns.Bearish
+	}
+
+	best := strongest(matches, direction)
+	if best == nil {
+		result.Metadata["suppressed_signal"] = result.Signal.String()
+		result.Signal = SignalNone
+		result.Message = fmt.Sprintf("⚪ 等待K线形态确认（%s）", result.Message)
+		result.DetailedAnalysis += fmt.Sprintf("<br/>🕯️ 最近%d根K线未出现%s形态，信号暂不发出", patternConfirmBars, direction)
+		return result, nil
+	}
+
+	result.Metadata["confirm_pattern"] = best.Type.String()
+	result.IndicatorSummary += fmt.Sprintf(" | %s", best.Type)
+	result.DetailedAnalysis += fmt.Sprintf("<br/>🕯️ %s形态确认（置信度 %.0f%%）", best.Type, best.Confidence*100)
+	if patternStrength(best.Confidence) == StrengthStrong && result.Strength < StrengthStrong {
+		result.Strength++
+		result.DetailedAnalysis += "，信号强度提升一级"
+	}
+	return result, nil
+}
+
+// filter 去掉置信度不足的形态
+func (s *CandlePatternStrategy) filter(matches []patterns.Match) []patterns.Match {
+	var filtered []patterns.Match
+	for _, m := range matches {
+		if m.Confidence >= s.minConfidence {
+			filtered = append(filtered, m)
+		}
+	}
+	return filtered
+}
+
+// strongest 返回指定方向置信度最高的形态，没有时返回 nil
+func strongest(matches []patterns.Match, direction patterns.Direction) *patterns.Match {
+	var best *patterns.Match
+	for i, m := range matches {
+		if m.Direction == direction && (best == nil || m.Confidence > best.Confidence) {
+			best = &matches[i]
+		}
+	}
+	return best
+}
+
+// patternStrength 按置信度划分信号强度
+func patternStrength(confidence float64) Strength {
+	switch {
+	case confidence >= 0.85:
+		return StrengthStrong
+	case confidence >= 0.65:
+		return StrengthNormal
+	default:
+		return StrengthWeak
+	}
+}
+
+// describePatterns 以 "名称 置信度" 列出形态
+func describePatterns(matches []patterns.Match) string {
+	if len(matches) == 0 {
+		return "无"
+	}
+
+	parts := make([]string, len(matches))
+	for i, m := range matches {
+		parts[i] = fmt.Sprintf("%s %.0f%%", m.Type, m.Confidence*100)
+	}
+	return strings.Join(parts, "、")
+}
@@ -0,0 +1,114 @@
+package strategy
+
+import (
+	"fmt"
+	"time"
+
+	"ta-watcher/internal/datasource"
+)
+
+// CCIStrategy 顺势指标策略
+type CCIStrategy struct {
+	name                string
+	period              int
+	overboughtLevel     float64
+	oversoldLevel       float64
+	supportedTimeframes []datasource.Timeframe
+}
+
+// NewCCIStrategy 创建CCI策略
+// 超买阈值须为正数、超卖阈值须为负数，例如 100/-100
+func NewCCIStrategy(period int, overboughtLevel, oversoldLevel float64) *CCIStrategy {
+	if period <= 0 {
+		period = 20 // 默认周期
+	}
+	if overboughtLevel <= 0 {
+		overboughtLevel = 100
+	}
+	if oversoldLevel >= 0 {
+		oversoldLevel = -100
+	}
+
+	return &CCIStrategy{
+		name:                fmt.Sprintf("CCI_%d_%.0f_%.0f", period, overboughtLevel, oversoldLevel),
+		period:              period,
+		overboughtLevel:     overboughtLevel,
+		oversoldLevel:       oversoldLevel,
+		supportedTimeframes: oscillatorTimeframes(),
+	}
+}
+
+// Name 返回策略名称
+func (s *CCIStrategy) Name() string {
+	return s.name
+}
+
+// Description 返回策略描述
+func (s *CCIStrategy) Description() string {
+	return fmt.Sprintf("CCI顺势指标策略\n• 指标: CCI-%d\n• 超买阈值: %.0f\n• 超卖阈值: %.0f\n• 说明: CCI > %.0f 为超买区域(卖出信号), CCI < %.0f 为超卖区域(买入信号)",
+		s.period, s.overboughtLevel, s.oversoldLevel, s.overboughtLevel, s.oversoldLevel)
+}
+
+// RequiredDataPoints 返回所需数据点
+func (s *CCIStrategy) RequiredDataPoints() int {
+	return s.period + 10 // 额外缓冲
+}
+
+// SupportedTimeframes 返回支持的时间框架
+func (s *CCIStrategy) SupportedTimeframes() []datasource.Timeframe {
+	return s.supportedTimeframes
+}
+
+// Evaluate 评估策略
+func (s *CCIStrategy) Evaluate(data *MarketData) (*StrategyResult, error) {
+	ctx := NewIndicatorContext(data)
+
+	// 计算CCI
+	cci, err := ctx.CCI(s.period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate CCI: %w", err)
+	}
+
+	if len(cci.Values) == 0 {
+		return nil, fmt.Errorf("no CCI values calculated")
+	}
+
+	latest := cci.GetLatest()
+
+	result := &StrategyResult{
+		Signal:    SignalNone,
+		Strength:  StrengthNormal,
+		Timestamp: time.Now(),
+		Metadata:  make(map[string]interface{}),
+		Indicators: map[string]interface{}{
+			"cci":        latest,
+			"cci_period": s.period,
+			"price":      ctx.LatestPrice(),
+		},
+		Thresholds: map[string]interface{}{
+			"overbought_level": s.overboughtLevel,
+			"oversold_level":   s.oversoldLevel,
+		},
+	}
+
+	result.IndicatorSummary = fmt.Sprintf("CCI-%d: %.1f (超买>%.0f, 超卖<%.0f)",
+		s.period, latest, s.overboughtLevel, s.oversoldLevel)
+
+	// CCI 没有固定区间，按阈值的一半分级：默认参数下超出 ±150 为中等、±200 为强
+	applyOscillatorZone(result, oscillatorZone{
+		name:       "CCI",
+		value:      latest,
+		overbought: s.overboughtLevel,
+		oversold:   s.oversoldLevel,
+		step:       s.overboughtLevel / 2,
+	})
+
+	// 添加趋势信息
+	if len(cci.Values) >= 2 {
+		previous := cci.Values[len(cci.Values)-2]
+		result.Metadata["cci_previous"] = previous
+		result.Metadata["cci_trend"] = latest - previous
+	}
+
+	return result, nil
+}
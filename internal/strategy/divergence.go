@@ -0,0 +1,81 @@
+package strategy
+
+import (
+	"fmt"
+
+	"ta-watcher/internal/indicators"
+)
+
+// divergenceCheck 背离类策略的判定参数
+type divergenceCheck struct {
+	name      string                             // 指标名称，用于消息和分析描述
+	result    *indicators.DivergenceResult       // 背离检测结果
+	extreme   func(d indicators.Divergence) bool // 摆动点处指标是否位于极端区域，满足时提升一级强度
+	extremeOK string                             // 满足极端区域条件时的说明
+}
+
+// applyDivergence 根据最新K线上刚确认的背离填充信号、强度、消息和详细分析
+// 常规背离为中等强度，隐藏背离为弱，指标位于极端区域时提升一级
+func applyDivergence(result *StrategyResult, check divergenceCheck) {
+	divergences := check.result
+	result.Metadata["divergence_count"] = len(divergences.Divergences)
+	result.Thresholds["pivot_left_bars"] = divergences.LeftBars
+	result.Thresholds["pivot_right_bars"] = divergences.RightBars
+	result.Thresholds["max_lookback"] = divergences.MaxLookback
+
+	found := divergences.GetNew()
+	if len(found) == 0 {
+		result.Signal = SignalNone
+		result.Message = fmt.Sprintf("⚪ %s无新背离", check.name)
+		result.DetailedAnalysis = fmt.Sprintf("最新K线上没有新确认的价格与%s背离。", check.name)
+		if latest, ok := divergences.GetLatest(); ok {
+			result.DetailedAnalysis += fmt.Sprintf("<br/>最近一次为%s，确认于 %d 根K线前。",
+				indicators.DivergenceTypeToString(latest.Type), divergences.Bars-1-latest.ConfirmIndex)
+		}
+		return
+	}
+
+	// 同时出现多个背离时优先采用常规背离
+	divergence := found[0]
+	for _, d := range found {
+		if d.IsRegular() {
+			divergence = d
+			break
+		}
+	}
+
+	typeName := indicators.DivergenceTypeToString(divergence.Type)
+	result.Metadata["divergence_type"] = typeName
+	result.Metadata["divergence_start_index"] = divergence.Start.Index
+	result.Metadata["divergence_end_index"] = divergence.End.Index
+
+	pivotName := "低点"
+	if divergence.IsBullish() {
+		result.Signal = SignalBuy
+		result.Message = fmt.Sprintf("🟢 %s%s", check.name, typeName)
+	} else {
+		result.Signal = SignalSell
+		result.Message = fmt.Sprintf("🔴 %s%s", check.name, typeName)
+		pivotName = "高点"
+	}
+
+	result.DetailedAnalysis = fmt.Sprintf("价格摆动%s %.4f → %.4f（相隔 %d 根K线），%s %.2f → %.2f，形成%s。",
+		pivotName, divergence.Start.Value, divergence.End.Value, divergence.End.Index-divergence.Start.Index,
+		check.name, divergence.StartOscillator, divergence.EndOscillator, typeName)
+	result.DetailedAnalysis += fmt.Sprintf("<br/>摆动点经右侧 %d 根K线确认", divergences.RightBars)
+
+	if divergence.IsRegular() {
+		result.Strength = StrengthNormal
+		result.DetailedAnalysis += "<br/>🔄 常规背离提示趋势可能反转"
+	} else {
+		result.Strength = StrengthWeak
+		result.DetailedAnalysis += "<br/>➡️ 隐藏背离提示原趋势可能延续"
+	}
+
+	if check.extreme != nil && check.extreme(divergence) {
+		if result.Strength < StrengthStrong {
+			result.Strength++
+		}
+		result.DetailedAnalysis += "<br/>✨ " + check.extremeOK
+	}
+}
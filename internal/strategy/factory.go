@@ -72,6 +72,59 @@ func (f *Factory) registerDefaultPresets() {
 		return NewMACDStrategy(60, 120, 36) // 月线参数
 	}
 
+	// 超买超卖振荡指标策略预设
+	f.presets["stoch_standard"] = func() Strategy {
+		return NewStochasticStrategy(14, 3, 3, 80, 20) // 标准慢速随机指标
+	}
+	f.presets["stochrsi_standard"] = func() Strategy {
+		return NewStochRSIStrategy(14, 14, 3, 3, 80, 20) // 标准StochRSI
+	}
+	f.presets["williams_r_standard"] = func() Strategy {
+		return NewWilliamsRStrategy(14, -20, -80) // 标准威廉指标
+	}
+	f.presets["cci_standard"] = func() Strategy {
+		return NewCCIStrategy(20, 100, -100) // 标准CCI
+	}
+	f.presets["kdj_standard"] = func() Strategy {
+		return NewKDJStrategy(9, 3, 3, 80, 20) // 标准KDJ
+	}
+
+	// 趋势跟踪指标策略预设
+	f.presets["ichimoku_standard"] = func() Strategy {
+		return NewIchimokuStrategy(9, 26, 52, 26) // 经典一目均衡表
+	}
+	f.presets["ichimoku_crypto"] = func() Strategy {
+		return NewIchimokuStrategy(20, 60, 120, 30) // 适配7×24小时加密市场
+	}
+	f.presets["supertrend_standard"] = func() Strategy {
+		return NewSuperTrendStrategy(10, 3) // 标准SuperTrend
+	}
+	f.presets["supertrend_fast"] = func() Strategy {
+		return NewSuperTrendStrategy(7, 2) // 快速SuperTrend
+	}
+
+	// 背离策略预设
+	f.presets["rsi_divergence"] = func() Strategy {
+		return NewRSIDivergenceStrategy(14, 5, 5, 60) // 标准RSI背离
+	}
+	f.presets["rsi_divergence_fast"] = func() Strategy {
+		return NewRSIDivergenceStrategy(14, 3, 2, 40) // 确认更快的RSI背离
+	}
+	f.presets["macd_divergence"] = func() Strategy {
+		return NewMACDDivergenceStrategy(12, 26, 9, 5, 5, 60) // 标准MACD背离
+	}
+
+	// K线形态策略预设
+	f.presets["candle_patterns"] = func() Strategy {
+		return NewCandlePatternStrategy(0.6) // 形态直接触发信号
+	}
+	f.presets["candle_patterns_strict"] = func() Strategy {
+		return NewCandlePatternStrategy(0.8) // 只采用高置信度形态
+	}
+	f.presets["rsi_pattern_confirm"] = func() Strategy {
+		return NewCandlePatternConfirmStrategy(NewRSIStrategy(14, 70, 30), 0.6) // RSI超买超卖需形态确认
+	}
+
 	// 组合策略预设
 	f.presets["balanced_combo"] = func() Strategy {
 		combo := NewMultiStrategy("平衡组合", "RSI+MA+MACD平衡组合策略")
@@ -266,18 +319,33 @@ func (f *Factory) ListPresets() []string {
 // GetPresetDescription 获取预设策略描述
 func (f *Factory) GetPresetDescription(name string) string {
 	descriptions := map[string]string{
-		"rsi_conservative": "保守RSI策略 (14, 75/25) - 适合稳健投资",
-		"rsi_aggressive":   "激进RSI策略 (14, 65/35) - 适合活跃交易",
-		"rsi_scalping":     "短线RSI策略 (7, 70/30) - 适合快速进出",
-		"ma_golden_cross":  "黄金交叉策略 (SMA 5/20) - 经典趋势跟踪",
-		"ma_ema_cross":     "EMA交叉策略 (EMA 12/26) - 快速趋势响应",
-		"ma_long_term":     "长期MA策略 (SMA 20/50) - 适合长期持有",
-		"macd_standard":    "标准MACD策略 (12/26/9) - 经典动量指标",
-		"macd_fast":        "快速MACD策略 (6/13/5) - 敏感信号捕捉",
-		"macd_slow":        "慢速MACD策略 (26/52/18) - 过滤噪音",
-		"balanced_combo":   "平衡组合策略 - RSI+MA+MACD均衡组合",
-		"consensus_combo":  "共识组合策略 - 多策略投票决策",
-		"scalping_combo":   "短线组合策略 - 快速交易优化组合",
+		"rsi_conservative":       "保守RSI策略 (14, 75/25) - 适合稳健投资",
+		"rsi_aggressive":         "激进RSI策略 (14, 65/35) - 适合活跃交易",
+		"rsi_scalping":           "短线RSI策略 (7, 70/30) - 适合快速进出",
+		"ma_golden_cross":        "黄金交叉策略 (SMA 5/20) - 经典趋势跟踪",
+		"ma_ema_cross":           "EMA交叉策略 (EMA 12/26) - 快速趋势响应",
+		"ma_long_term":           "长期MA策略 (SMA 20/50) - 适合长期持有",
+		"macd_standard":          "标准MACD策略 (12/26/9) - 经典动量指标",
+		"macd_fast":              "快速MACD策略 (6/13/5) - 敏感信号捕捉",
+		"macd_slow":              "慢速MACD策略 (26/52/18) - 过滤噪音",
+		"stoch_standard":         "随机指标策略 (14/3/3, 80/20) - 短线超买超卖",
+		"stochrsi_standard":      "StochRSI策略 (14/14/3/3, 80/20) - 灵敏的RSI超买超卖",
+		"williams_r_standard":    "威廉指标策略 (14, -20/-80) - 快速反转捕捉",
+		"cci_standard":           "CCI策略 (20, ±100) - 偏离均值程度",
+		"kdj_standard":           "KDJ策略 (9/3/3, 80/20) - 国内常用随机指标",
+		"ichimoku_standard":      "一目均衡表策略 (9/26/52/26) - 云层趋势与TK交叉",
+		"ichimoku_crypto":        "加密市场一目均衡表 (20/60/120/30) - 适合周线/月线复盘",
+		"supertrend_standard":    "SuperTrend策略 (10, 3.0) - ATR趋势翻转",
+		"supertrend_fast":        "快速SuperTrend策略 (7, 2.0) - 更早捕捉翻转",
+		"rsi_divergence":         "RSI背离策略 (14, 分形5/5, 回看60) - 摆动点常规/隐藏背离",
+		"rsi_divergence_fast":    "快速RSI背离策略 (14, 分形3/2, 回看40) - 更早确认背离",
+		"macd_divergence":        "MACD背离策略 (12/26/9, 分形5/5, 回看60) - 动能衰竭识别",
+		"candle_patterns":        "K线形态策略 (置信度≥60%) - 吞没/锤子线/启明星等形态触发",
+		"candle_patterns_strict": "严格K线形态策略 (置信度≥80%) - 只采用高置信度形态",
+		"rsi_pattern_confirm":    "形态确认RSI策略 (14, 70/30) - RSI信号需K线形态确认",
+		"balanced_combo":         "平衡组合策略 - RSI+MA+MACD均衡组合",
+		"consensus_combo":        "共识组合策略 - 多策略投票决策",
+		"scalping_combo":         "短线组合策略 - 快速交易优化组合",
 	}
 
 	if desc, exists := descriptions[name]; exists {
@@ -0,0 +1,207 @@
+package strategy
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"ta-watcher/internal/datasource"
+	"ta-watcher/internal/indicators"
+)
+
+// IchimokuStrategy 一目均衡表策略
+type IchimokuStrategy struct {
+	name                string
+	tenkanPeriod        int
+	kijunPeriod         int
+	senkouBPeriod       int
+	displacement        int
+	supportedTimeframes []datasource.Timeframe
+}
+
+// NewIchimokuStrategy 创建一目均衡表策略
+func NewIchimokuStrategy(tenkanPeriod, kijunPeriod, senkouBPeriod, displacement int) *IchimokuStrategy {
+	if tenkanPeriod <= 0 {
+		tenkanPeriod = 9
+	}
+	if kijunPeriod <= 0 {
+		kijunPeriod = 26
+	}
+	if senkouBPeriod <= 0 {
+		senkouBPeriod = 52
+	}
+	if displacement <= 0 {
+		displacement = 26
+	}
+
+	// 确保周期递增
+	if tenkanPeriod >= kijunPeriod || kijunPeriod >= senkouBPeriod {
+		tenkanPeriod, kijunPeriod, senkouBPeriod = 9, 26, 52
+	}
+
+	return &IchimokuStrategy{
+		name:          fmt.Sprintf("Ichimoku_%d_%d_%d_%d", tenkanPeriod, kijunPeriod, senkouBPeriod, displacement),
+		tenkanPeriod:  tenkanPeriod,
+		kijunPeriod:   kijunPeriod,
+		senkouBPeriod: senkouBPeriod,
+		displacement:  displacement,
+		supportedTimeframes: []datasource.Timeframe{
+			datasource.Timeframe1h, datasource.Timeframe2h, datasource.Timeframe4h,
+			datasource.Timeframe6h, datasource.Timeframe12h,
+			datasource.Timeframe1d, datasource.Timeframe3d, datasource.Timeframe1w, datasource.Timeframe1M,
+		},
+	}
+}
+
+// Name 返回策略名称
+func (s *IchimokuStrategy) Name() string {
+	return s.name
+}
+
+// Description 返回策略描述
+func (s *IchimokuStrategy) Description() string {
+	return fmt.Sprintf("一目均衡表(Ichimoku)策略\n• 转换线: %d\n• 基准线: %d\n• 先行带B: %d\n• 平移: %d\n• 说明: 转换线/基准线交叉或价格突破云层触发信号，价格相对云层位置、迟行带和未来云层颜色决定信号强度",
+		s.tenkanPeriod, s.kijunPeriod, s.senkouBPeriod, s.displacement)
+}
+
+// RequiredDataPoints 返回所需数据点
+func (s *IchimokuStrategy) RequiredDataPoints() int {
+	// 当前云层需要平移前已有完整的先行带B
+	return s.senkouBPeriod + s.displacement + 10 // 额外缓冲
+}
+
+// SupportedTimeframes 返回支持的时间框架
+func (s *IchimokuStrategy) SupportedTimeframes() []datasource.Timeframe {
+	return s.supportedTimeframes
+}
+
+// Evaluate 评估策略
+func (s *IchimokuStrategy) Evaluate(data *MarketData) (*StrategyResult, error) {
+	ctx := NewIndicatorContext(data)
+
+	// 计算一目均衡表
+	ichimoku, err := ctx.Ichimoku(s.tenkanPeriod, s.kijunPeriod, s.senkouBPeriod, s.displacement)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate Ichimoku: %w", err)
+	}
+
+	spanA, spanB := ichimoku.CloudAt(0)
+	if math.IsNaN(spanA) || math.IsNaN(spanB) {
+		return nil, fmt.Errorf("insufficient data for Ichimoku cloud")
+	}
+
+	closes := ctx.ClosePrices()
+	currentPrice := closes[len(closes)-1]
+	prevPrice := closes[len(closes)-2]
+	tenkan, kijun := ichimoku.GetLatest()
+	futureA, futureB := ichimoku.GetFutureCloud()
+	position := ichimoku.GetCloudPosition(currentPrice)
+	chikouBullish, chikouBearish := ichimoku.ChikouConfirmation(ctx.HighPrices(), ctx.LowPrices())
+
+	result := &StrategyResult{
+		Signal:    SignalNone,
+		Strength:  StrengthNormal,
+		Timestamp: time.Now(),
+		Metadata:  make(map[string]interface{}),
+		Indicators: map[string]interface{}{
+			"tenkan":          tenkan,
+			"kijun":           kijun,
+			"senkou_a":        spanA,
+			"senkou_b":        spanB,
+			"future_senkou_a": futureA,
+			"future_senkou_b": futureB,
+			"price":           currentPrice,
+		},
+		Thresholds: map[string]interface{}{
+			"tenkan_period":   s.tenkanPeriod,
+			"kijun_period":    s.kijunPeriod,
+			"senkou_b_period": s.senkouBPeriod,
+			"displacement":    s.displacement,
+		},
+	}
+	result.Metadata["cloud_position"] = indicators.CloudPositionToString(position)
+	result.Metadata["chikou_bullish"] = chikouBullish
+	result.Metadata["chikou_bearish"] = chikouBearish
+	result.Metadata["future_cloud_bullish"] = ichimoku.IsFutureCloudBullish()
+
+	// 生成指标摘要
+	result.IndicatorSummary = fmt.Sprintf("Ichimoku(%d,%d,%d): 转换线 %.4f / 基准线 %.4f, %s",
+		s.tenkanPeriod, s.kijunPeriod, s.senkouBPeriod, tenkan, kijun, indicators.CloudPositionToString(position))
+
+	// 触发条件：TK交叉或云层突破
+	breakout, breakoutUp := ichimoku.IsCloudBreakout(prevPrice, currentPrice)
+	var trigger string
+	switch {
+	case ichimoku.IsTKBullishCross():
+		result.Signal = SignalBuy
+		trigger = fmt.Sprintf("转换线 %.4f 上穿基准线 %.4f，形成TK金叉。", tenkan, kijun)
+	case ichimoku.IsTKBearishCross():
+		result.Signal = SignalSell
+		trigger = fmt.Sprintf("转换线 %.4f 下穿基准线 %.4f，形成TK死叉。", tenkan, kijun)
+	case breakout && breakoutUp && tenkan >= kijun:
+		result.Signal = SignalBuy
+		trigger = fmt.Sprintf("价格 %.4f 向上突破云层 (%.4f-%.4f)。", currentPrice, math.Min(spanA, spanB), math.Max(spanA, spanB))
+	case breakout && !breakoutUp && tenkan <= kijun:
+		result.Signal = SignalSell
+		trigger = fmt.Sprintf("价格 %.4f 向下跌破云层 (%.4f-%.4f)。", currentPrice, math.Min(spanA, spanB), math.Max(spanA, spanB))
+	}
+
+	if result.Signal == SignalNone {
+		result.Message = "⚪ Ichimoku无交叉信号"
+		result.DetailedAnalysis = fmt.Sprintf("价格 %.4f 位于%s，转换线 %.4f，基准线 %.4f，暂无TK交叉或云层突破。",
+			currentPrice, indicators.CloudPositionToString(position), tenkan, kijun)
+		result.DetailedAnalysis += s.cloudOutlook(ichimoku)
+		return result, nil
+	}
+
+	// 确认条件：价格相对云层位置、迟行带、未来云层颜色
+	bullish := result.Signal == SignalBuy
+	confirmations := 0
+	result.DetailedAnalysis = trigger
+	if (bullish && position == indicators.CloudAbove) || (!bullish && position == indicators.CloudBelow) {
+		confirmations++
+		result.DetailedAnalysis += fmt.Sprintf("<br/>✅ 价格位于%s，与信号方向一致", indicators.CloudPositionToString(position))
+	} else {
+		result.DetailedAnalysis += fmt.Sprintf("<br/>⚠️ 价格位于%s，趋势尚未确认", indicators.CloudPositionToString(position))
+	}
+	if (bullish && chikouBullish) || (!bullish && chikouBearish) {
+		confirmations++
+		result.DetailedAnalysis += fmt.Sprintf("<br/>✅ 迟行带确认：最新收盘价相对 %d 根K线前的价格区间突破", s.displacement)
+	} else {
+		result.DetailedAnalysis += "<br/>⚠️ 迟行带未确认"
+	}
+	if ichimoku.IsFutureCloudBullish() == bullish {
+		confirmations++
+	}
+	result.DetailedAnalysis += s.cloudOutlook(ichimoku)
+
+	if bullish {
+		result.Message = "🟢 Ichimoku看涨信号"
+	} else {
+		result.Message = "🔴 Ichimoku看跌信号"
+	}
+
+	switch confirmations {
+	case 3:
+		result.Strength = StrengthStrong
+		result.DetailedAnalysis += "<br/>📈 三项确认全部满足，信号强度: 强"
+	case 2:
+		result.Strength = StrengthNormal
+		result.DetailedAnalysis += "<br/>📊 部分条件确认，信号强度: 中等"
+	default:
+		result.Strength = StrengthWeak
+		result.DetailedAnalysis += "<br/>📉 缺少确认条件，信号强度: 弱"
+	}
+	result.Metadata["confirmations"] = confirmations
+
+	return result, nil
+}
+
+// cloudOutlook 描述投射到未来的云层
+func (s *IchimokuStrategy) cloudOutlook(ichimoku *indicators.IchimokuResult) string {
+	futureA, futureB := ichimoku.GetFutureCloud()
+	if ichimoku.IsFutureCloudBullish() {
+		return fmt.Sprintf("<br/>☁️ 未来 %d 根K线为多头云 (先行带A %.4f > 先行带B %.4f)", s.displacement, futureA, futureB)
+	}
+	return fmt.Sprintf("<br/>☁️ 未来 %d 根K线为空头云 (先行带A %.4f ≤ 先行带B %.4f)", s.displacement, futureA, futureB)
+}
@@ -0,0 +1,140 @@
+package strategy
+
+import (
+	"fmt"
+	"time"
+
+	"ta-watcher/internal/datasource"
+	"ta-watcher/internal/indicators"
+)
+
+// KDJStrategy KDJ策略
+type KDJStrategy struct {
+	name                string
+	period              int
+	kSmooth             int
+	dSmooth             int
+	overboughtLevel     float64
+	oversoldLevel       float64
+	supportedTimeframes []datasource.Timeframe
+}
+
+// NewKDJStrategy 创建KDJ策略
+func NewKDJStrategy(period, kSmooth, dSmooth int, overboughtLevel, oversoldLevel float64) *KDJStrategy {
+	if period <= 0 {
+		period = 9 // 默认周期
+	}
+	if kSmooth <= 0 {
+		kSmooth = 3
+	}
+	if dSmooth <= 0 {
+		dSmooth = 3
+	}
+	if overboughtLevel <= 0 || overboughtLevel >= 100 {
+		overboughtLevel = 80
+	}
+	if oversoldLevel <= 0 || oversoldLevel >= overboughtLevel {
+		oversoldLevel = 20
+	}
+
+	return &KDJStrategy{
+		name:                fmt.Sprintf("KDJ_%d_%d_%d_%.0f_%.0f", period, kSmooth, dSmooth, overboughtLevel, oversoldLevel),
+		period:              period,
+		kSmooth:             kSmooth,
+		dSmooth:             dSmooth,
+		overboughtLevel:     overboughtLevel,
+		oversoldLevel:       oversoldLevel,
+		supportedTimeframes: oscillatorTimeframes(),
+	}
+}
+
+// Name 返回策略名称
+func (s *KDJStrategy) Name() string {
+	return s.name
+}
+
+// Description 返回策略描述
+func (s *KDJStrategy) Description() string {
+	return fmt.Sprintf("KDJ随机指标策略\n• 指标: KDJ(%d,%d,%d)\n• 超买阈值: %.0f\n• 超卖阈值: %.0f\n• 说明: K、D 同时高于 %.0f 为超买区域(卖出信号), 同时低于 %.0f 为超卖区域(买入信号)，J 值突破 100/0 增强信号",
+		s.period, s.kSmooth, s.dSmooth, s.overboughtLevel, s.oversoldLevel, s.overboughtLevel, s.oversoldLevel)
+}
+
+// RequiredDataPoints 返回所需数据点
+func (s *KDJStrategy) RequiredDataPoints() int {
+	// K、D 从50开始递推平滑，需要足够的预热数据消除初始值影响
+	return s.period * 5
+}
+
+// SupportedTimeframes 返回支持的时间框架
+func (s *KDJStrategy) SupportedTimeframes() []datasource.Timeframe {
+	return s.supportedTimeframes
+}
+
+// Evaluate 评估策略
+func (s *KDJStrategy) Evaluate(data *MarketData) (*StrategyResult, error) {
+	ctx := NewIndicatorContext(data)
+
+	// 计算KDJ
+	kdj, err := ctx.KDJ(s.period, s.kSmooth, s.dSmooth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate KDJ: %w", err)
+	}
+
+	if len(kdj.K) == 0 {
+		return nil, fmt.Errorf("no KDJ values calculated")
+	}
+
+	k, d, j := kdj.GetLatest()
+
+	result := &StrategyResult{
+		Signal:    SignalNone,
+		Strength:  StrengthNormal,
+		Timestamp: time.Now(),
+		Metadata:  make(map[string]interface{}),
+		Indicators: map[string]interface{}{
+			"kdj_k":      k,
+			"kdj_d":      d,
+			"kdj_j":      j,
+			"kdj_period": s.period,
+			"price":      ctx.LatestPrice(),
+		},
+		Thresholds: map[string]interface{}{
+			"overbought_level": s.overboughtLevel,
+			"oversold_level":   s.oversoldLevel,
+		},
+	}
+
+	result.IndicatorSummary = fmt.Sprintf("KDJ(%d,%d,%d): K %.1f / D %.1f / J %.1f (超买>%.0f, 超卖<%.0f)",
+		s.period, s.kSmooth, s.dSmooth, k, d, j, s.overboughtLevel, s.oversoldLevel)
+
+	// K、D 需要同时进入区域才产生信号，取两者中离阈值较近的一个判定强度
+	zone := oscillatorZone{
+		name:       "KDJ",
+		overbought: s.overboughtLevel,
+		oversold:   s.oversoldLevel,
+		step:       5,
+	}
+	switch kdj.GetSignal(s.overboughtLevel, s.oversoldLevel) {
+	case indicators.KDJSell:
+		zone.value = min(k, d)
+		applyOscillatorZone(result, zone)
+	case indicators.KDJBuy:
+		zone.value = max(k, d)
+		applyOscillatorZone(result, zone)
+	default:
+		result.Signal = SignalNone
+		result.Message = "⚪ KDJ中性区域"
+		result.DetailedAnalysis = fmt.Sprintf("K %.1f / D %.1f 未同时进入超买超卖区域 (%.0f-%.0f)，市场暂无明显信号。<br/>建议继续观察或等待更明确的信号。",
+			k, d, s.oversoldLevel, s.overboughtLevel)
+	}
+	result.DetailedAnalysis += fmt.Sprintf("<br/>📊 J值: %.1f", j)
+
+	// J 值突破 100 或跌破 0 表示极端行情
+	if (result.Signal == SignalSell && j > 100) || (result.Signal == SignalBuy && j < 0) {
+		result.Strength = StrengthStrong
+		result.DetailedAnalysis += fmt.Sprintf("<br/>⚠️ J值 %.1f 超出 0-100 区间，处于极端位置", j)
+	}
+	confirmOscillatorCross(result, kdj.IsGoldenCross(), kdj.IsDeathCross(), "K/D")
+
+	return result, nil
+}
@@ -0,0 +1,136 @@
+package strategy
+
+import (
+	"fmt"
+	"time"
+
+	"ta-watcher/internal/datasource"
+	"ta-watcher/internal/indicators"
+)
+
+// MACDDivergenceStrategy MACD背离策略
+type MACDDivergenceStrategy struct {
+	name                string
+	fastPeriod          int
+	slowPeriod          int
+	signalPeriod        int
+	leftBars            int
+	rightBars           int
+	lookback            int
+	supportedTimeframes []datasource.Timeframe
+}
+
+// NewMACDDivergenceStrategy 创建MACD背离策略
+func NewMACDDivergenceStrategy(fastPeriod, slowPeriod, signalPeriod, leftBars, rightBars, lookback int) *MACDDivergenceStrategy {
+	if fastPeriod <= 0 {
+		fastPeriod = 12
+	}
+	if slowPeriod <= 0 {
+		slowPeriod = 26
+	}
+	if signalPeriod <= 0 {
+		signalPeriod = 9
+	}
+
+	// 确保快周期小于慢周期
+	if fastPeriod >= slowPeriod {
+		fastPeriod, slowPeriod = 12, 26
+	}
+
+	if leftBars <= 0 {
+		leftBars = indicators.DefaultPivotLeftBars
+	}
+	if rightBars <= 0 {
+		rightBars = indicators.DefaultPivotRightBars
+	}
+	if lookback <= 0 {
+		lookback = indicators.DefaultDivergenceLookback
+	}
+
+	return &MACDDivergenceStrategy{
+		name:         fmt.Sprintf("MACDDiv_%d_%d_%d_%d_%d_%d", fastPeriod, slowPeriod, signalPeriod, leftBars, rightBars, lookback),
+		fastPeriod:   fastPeriod,
+		slowPeriod:   slowPeriod,
+		signalPeriod: signalPeriod,
+		leftBars:     leftBars,
+		rightBars:    rightBars,
+		lookback:     lookback,
+		supportedTimeframes: []datasource.Timeframe{
+			datasource.Timeframe15m, datasource.Timeframe30m, datasource.Timeframe1h, datasource.Timeframe2h,
+			datasource.Timeframe4h, datasource.Timeframe6h, datasource.Timeframe12h,
+			datasource.Timeframe1d, datasource.Timeframe3d, datasource.Timeframe1w, datasource.Timeframe1M,
+		},
+	}
+}
+
+// Name 返回策略名称
+func (s *MACDDivergenceStrategy) Name() string {
+	return s.name
+}
+
+// Description 返回策略描述
+func (s *MACDDivergenceStrategy) Description() string {
+	return fmt.Sprintf("MACD背离策略\n• 快线EMA: %d\n• 慢线EMA: %d\n• 信号线EMA: %d\n• 摆动点: 左%d/右%d根K线\n• 最大回看: %d根K线\n• 说明: 比较相邻两个价格摆动点处的MACD线，常规背离提示反转，隐藏背离提示趋势延续；看涨背离位于零轴下方、看跌背离位于零轴上方时信号更强",
+		s.fastPeriod, s.slowPeriod, s.signalPeriod, s.leftBars, s.rightBars, s.lookback)
+}
+
+// RequiredDataPoints 返回所需数据点
+func (s *MACDDivergenceStrategy) RequiredDataPoints() int {
+	return s.slowPeriod + s.signalPeriod + s.lookback + s.leftBars + s.rightBars
+}
+
+// SupportedTimeframes 返回支持的时间框架
+func (s *MACDDivergenceStrategy) SupportedTimeframes() []datasource.Timeframe {
+	return s.supportedTimeframes
+}
+
+// Evaluate 评估策略
+func (s *MACDDivergenceStrategy) Evaluate(data *MarketData) (*StrategyResult, error) {
+	ctx := NewIndicatorContext(data)
+
+	// 计算MACD
+	macd, err := ctx.MACD(s.fastPeriod, s.slowPeriod, s.signalPeriod)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate MACD: %w", err)
+	}
+
+	// 检测MACD线与价格的背离
+	divergences, err := ctx.Divergence(macd.MACD, s.leftBars, s.rightBars, s.lookback)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect MACD divergence: %w", err)
+	}
+
+	macdLine, signalLine, histogram := macd.GetLatest()
+	result := &StrategyResult{
+		Signal:    SignalNone,
+		Strength:  StrengthNormal,
+		Timestamp: time.Now(),
+		Metadata:  make(map[string]interface{}),
+		Indicators: map[string]interface{}{
+			"macd":      macdLine,
+			"signal":    signalLine,
+			"histogram": histogram,
+			"price":     ctx.LatestPrice(),
+		},
+		Thresholds: map[string]interface{}{
+			"fast_period":   s.fastPeriod,
+			"slow_period":   s.slowPeriod,
+			"signal_period": s.signalPeriod,
+		},
+		IndicatorSummary: fmt.Sprintf("MACD(%d,%d,%d): %.4f", s.fastPeriod, s.slowPeriod, s.signalPeriod, macdLine),
+	}
+
+	applyDivergence(result, divergenceCheck{
+		name:   "MACD",
+		result: divergences,
+		extreme: func(d indicators.Divergence) bool {
+			if d.IsBullish() {
+				return d.StartOscillator < 0 && d.EndOscillator < 0
+			}
+			return d.StartOscillator > 0 && d.EndOscillator > 0
+		},
+		extremeOK: "背离的两个摆动点均位于零轴反向一侧，动能衰竭更明显",
+	})
+
+	return result, nil
+}
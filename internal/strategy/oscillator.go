@@ -0,0 +1,97 @@
+package strategy
+
+import (
+	"fmt"
+
+	"ta-watcher/internal/datasource"
+)
+
+// oscillatorZone 超买超卖类振荡指标的区域判定参数
+type oscillatorZone struct {
+	name       string  // 指标名称，用于消息和分析描述
+	value      float64 // 当前指标值
+	overbought float64 // 超买阈值
+	oversold   float64 // 超卖阈值
+	step       float64 // 强度分级步长：超出阈值 step 为中等，超出 2×step 为强
+}
+
+// oscillatorTimeframes 振荡指标策略支持的时间框架
+func oscillatorTimeframes() []datasource.Timeframe {
+	return []datasource.Timeframe{
+		datasource.Timeframe5m, datasource.Timeframe15m, datasource.Timeframe30m,
+		datasource.Timeframe1h, datasource.Timeframe2h, datasource.Timeframe4h,
+		datasource.Timeframe6h, datasource.Timeframe12h,
+		datasource.Timeframe1d, datasource.Timeframe3d, datasource.Timeframe1w, datasource.Timeframe1M,
+	}
+}
+
+// applyOscillatorZone 根据指标所处区域填充信号、强度、消息和详细分析
+func applyOscillatorZone(result *StrategyResult, zone oscillatorZone) {
+	switch {
+	case zone.value >= zone.overbought:
+		// 超买，卖出信号
+		result.Signal = SignalSell
+		result.Message = fmt.Sprintf("🔴 %s超买信号", zone.name)
+		result.DetailedAnalysis = fmt.Sprintf("%s值 %.1f 已达到超买阈值 %.0f 以上，市场可能出现回调。",
+			zone.name, zone.value, zone.overbought)
+
+		excess := zone.value - zone.overbought
+		if excess >= 2*zone.step {
+			result.Strength = StrengthStrong
+			result.DetailedAnalysis += "<br/>📈 超买程度较为严重，信号强度: 强"
+		} else if excess >= zone.step {
+			result.Strength = StrengthNormal
+			result.DetailedAnalysis += "<br/>📊 超买程度适中，信号强度: 中等"
+		} else {
+			result.Strength = StrengthWeak
+			result.DetailedAnalysis += "<br/>📉 刚进入超买区域，信号强度: 弱"
+		}
+
+	case zone.value <= zone.oversold:
+		// 超卖，买入信号
+		result.Signal = SignalBuy
+		result.Message = fmt.Sprintf("🟢 %s超卖信号", zone.name)
+		result.DetailedAnalysis = fmt.Sprintf("%s值 %.1f 已降至超卖阈值 %.0f 以下，市场可能出现反弹。",
+			zone.name, zone.value, zone.oversold)
+
+		excess := zone.oversold - zone.value
+		if excess >= 2*zone.step {
+			result.Strength = StrengthStrong
+			result.DetailedAnalysis += "<br/>📈 超卖程度较为严重，信号强度: 强"
+		} else if excess >= zone.step {
+			result.Strength = StrengthNormal
+			result.DetailedAnalysis += "<br/>📊 超卖程度适中，信号强度: 中等"
+		} else {
+			result.Strength = StrengthWeak
+			result.DetailedAnalysis += "<br/>📉 刚进入超卖区域，信号强度: 弱"
+		}
+
+	default:
+		// 中性区域
+		result.Signal = SignalNone
+		result.Message = fmt.Sprintf("⚪ %s中性区域", zone.name)
+		result.DetailedAnalysis = fmt.Sprintf("%s值 %.1f 处于中性区域 (%.0f-%.0f)，市场暂无明显超买超卖信号。<br/>建议继续观察或等待更明确的信号。",
+			zone.name, zone.value, zone.oversold, zone.overbought)
+	}
+}
+
+// confirmOscillatorCross 快慢线在超买/超卖区内同向交叉时确认信号并提升一级强度
+func confirmOscillatorCross(result *StrategyResult, bullishCross, bearishCross bool, lines string) {
+	result.Metadata["bullish_cross"] = bullishCross
+	result.Metadata["bearish_cross"] = bearishCross
+
+	confirmed := (result.Signal == SignalBuy && bullishCross) || (result.Signal == SignalSell && bearishCross)
+	if confirmed {
+		if result.Strength < StrengthStrong {
+			result.Strength++
+		}
+		result.DetailedAnalysis += fmt.Sprintf("<br/>✨ %s在信号区域内发生交叉，反转信号得到确认", lines)
+		return
+	}
+
+	if bullishCross {
+		result.DetailedAnalysis += fmt.Sprintf("<br/>📈 %s金叉", lines)
+	} else if bearishCross {
+		result.DetailedAnalysis += fmt.Sprintf("<br/>📉 %s死叉", lines)
+	}
+}
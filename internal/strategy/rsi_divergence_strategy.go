@@ -0,0 +1,119 @@
+package strategy
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"ta-watcher/internal/datasource"
+	"ta-watcher/internal/indicators"
+)
+
+// RSIDivergenceStrategy RSI背离策略
+type RSIDivergenceStrategy struct {
+	name                string
+	period              int
+	leftBars            int
+	rightBars           int
+	lookback            int
+	overbought          float64
+	oversold            float64
+	supportedTimeframes []datasource.Timeframe
+}
+
+// NewRSIDivergenceStrategy 创建RSI背离策略
+func NewRSIDivergenceStrategy(period, leftBars, rightBars, lookback int) *RSIDivergenceStrategy {
+	if period <= 0 {
+		period = 14
+	}
+	if leftBars <= 0 {
+		leftBars = indicators.DefaultPivotLeftBars
+	}
+	if rightBars <= 0 {
+		rightBars = indicators.DefaultPivotRightBars
+	}
+	if lookback <= 0 {
+		lookback = indicators.DefaultDivergenceLookback
+	}
+
+	return &RSIDivergenceStrategy{
+		name:                fmt.Sprintf("RSIDiv_%d_%d_%d_%d", period, leftBars, rightBars, lookback),
+		period:              period,
+		leftBars:            leftBars,
+		rightBars:           rightBars,
+		lookback:            lookback,
+		overbought:          indicators.DefaultOverboughtLevel,
+		oversold:            indicators.DefaultOversoldLevel,
+		supportedTimeframes: oscillatorTimeframes(),
+	}
+}
+
+// Name 返回策略名称
+func (s *RSIDivergenceStrategy) Name() string {
+	return s.name
+}
+
+// Description 返回策略描述
+func (s *RSIDivergenceStrategy) Description() string {
+	return fmt.Sprintf("RSI背离策略\n• RSI周期: %d\n• 摆动点: 左%d/右%d根K线\n• 最大回看: %d根K线\n• 说明: 比较相邻两个价格摆动点处的RSI，常规背离提示反转，隐藏背离提示趋势延续；背离时RSI处于超买超卖区信号更强",
+		s.period, s.leftBars, s.rightBars, s.lookback)
+}
+
+// RequiredDataPoints 返回所需数据点
+func (s *RSIDivergenceStrategy) RequiredDataPoints() int {
+	return s.period + s.lookback + s.leftBars + s.rightBars
+}
+
+// SupportedTimeframes 返回支持的时间框架
+func (s *RSIDivergenceStrategy) SupportedTimeframes() []datasource.Timeframe {
+	return s.supportedTimeframes
+}
+
+// Evaluate 评估策略
+func (s *RSIDivergenceStrategy) Evaluate(data *MarketData) (*StrategyResult, error) {
+	ctx := NewIndicatorContext(data)
+
+	// 计算RSI
+	rsi, err := ctx.RSI(s.period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate RSI: %w", err)
+	}
+
+	// 检测背离
+	divergences, err := ctx.Divergence(rsi.Values, s.leftBars, s.rightBars, s.lookback)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect RSI divergence: %w", err)
+	}
+
+	currentRSI := rsi.GetLatest()
+	result := &StrategyResult{
+		Signal:    SignalNone,
+		Strength:  StrengthNormal,
+		Timestamp: time.Now(),
+		Metadata:  make(map[string]interface{}),
+		Indicators: map[string]interface{}{
+			"rsi":        currentRSI,
+			"rsi_period": s.period,
+			"price":      ctx.LatestPrice(),
+		},
+		Thresholds: map[string]interface{}{
+			"overbought": s.overbought,
+			"oversold":   s.oversold,
+		},
+		IndicatorSummary: fmt.Sprintf("RSI(%d): %.1f", s.period, currentRSI),
+	}
+
+	applyDivergence(result, divergenceCheck{
+		name:   "RSI",
+		result: divergences,
+		extreme: func(d indicators.Divergence) bool {
+			if d.IsBullish() {
+				return math.Min(d.StartOscillator, d.EndOscillator) <= s.oversold
+			}
+			return math.Max(d.StartOscillator, d.EndOscillator) >= s.overbought
+		},
+		extremeOK: "背离发生在RSI超买超卖区域，信号可靠性更高",
+	})
+
+	return result, nil
+}
@@ -0,0 +1,117 @@
+package strategy
+
+import (
+	"fmt"
+	"time"
+
+	"ta-watcher/internal/datasource"
+)
+
+// StochasticStrategy 随机指标策略
+type StochasticStrategy struct {
+	name                string
+	kPeriod             int
+	smoothK             int
+	dPeriod             int
+	overboughtLevel     float64
+	oversoldLevel       float64
+	supportedTimeframes []datasource.Timeframe
+}
+
+// NewStochasticStrategy 创建随机指标策略
+func NewStochasticStrategy(kPeriod, smoothK, dPeriod int, overboughtLevel, oversoldLevel float64) *StochasticStrategy {
+	if kPeriod <= 0 {
+		kPeriod = 14 // 默认周期
+	}
+	if smoothK <= 0 {
+		smoothK = 3
+	}
+	if dPeriod <= 0 {
+		dPeriod = 3
+	}
+	if overboughtLevel <= 0 || overboughtLevel >= 100 {
+		overboughtLevel = 80
+	}
+	if oversoldLevel <= 0 || oversoldLevel >= overboughtLevel {
+		oversoldLevel = 20
+	}
+
+	return &StochasticStrategy{
+		name:                fmt.Sprintf("Stoch_%d_%d_%d_%.0f_%.0f", kPeriod, smoothK, dPeriod, overboughtLevel, oversoldLevel),
+		kPeriod:             kPeriod,
+		smoothK:             smoothK,
+		dPeriod:             dPeriod,
+		overboughtLevel:     overboughtLevel,
+		oversoldLevel:       oversoldLevel,
+		supportedTimeframes: oscillatorTimeframes(),
+	}
+}
+
+// Name 返回策略名称
+func (s *StochasticStrategy) Name() string {
+	return s.name
+}
+
+// Description 返回策略描述
+func (s *StochasticStrategy) Description() string {
+	return fmt.Sprintf("随机指标(Stochastic)策略\n• 指标: Stoch(%d,%d,%d)\n• 超买阈值: %.0f\n• 超卖阈值: %.0f\n• 说明: %%K > %.0f 为超买区域(卖出信号), %%K < %.0f 为超卖区域(买入信号)，区域内 %%K/%%D 交叉可确认信号",
+		s.kPeriod, s.smoothK, s.dPeriod, s.overboughtLevel, s.oversoldLevel, s.overboughtLevel, s.oversoldLevel)
+}
+
+// RequiredDataPoints 返回所需数据点
+func (s *StochasticStrategy) RequiredDataPoints() int {
+	return s.kPeriod + s.smoothK + s.dPeriod + 10 // 额外缓冲
+}
+
+// SupportedTimeframes 返回支持的时间框架
+func (s *StochasticStrategy) SupportedTimeframes() []datasource.Timeframe {
+	return s.supportedTimeframes
+}
+
+// Evaluate 评估策略
+func (s *StochasticStrategy) Evaluate(data *MarketData) (*StrategyResult, error) {
+	ctx := NewIndicatorContext(data)
+
+	// 计算随机指标
+	stoch, err := ctx.Stochastic(s.kPeriod, s.smoothK, s.dPeriod)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate Stochastic: %w", err)
+	}
+
+	if len(stoch.K) == 0 {
+		return nil, fmt.Errorf("no Stochastic values calculated")
+	}
+
+	k, d := stoch.GetLatest()
+
+	result := &StrategyResult{
+		Signal:    SignalNone,
+		Strength:  StrengthNormal,
+		Timestamp: time.Now(),
+		Metadata:  make(map[string]interface{}),
+		Indicators: map[string]interface{}{
+			"stoch_k":        k,
+			"stoch_d":        d,
+			"stoch_k_period": s.kPeriod,
+			"price":          ctx.LatestPrice(),
+		},
+		Thresholds: map[string]interface{}{
+			"overbought_level": s.overboughtLevel,
+			"oversold_level":   s.oversoldLevel,
+		},
+	}
+
+	result.IndicatorSummary = fmt.Sprintf("Stoch(%d,%d,%d): %%K %.1f / %%D %.1f (超买>%.0f, 超卖<%.0f)",
+		s.kPeriod, s.smoothK, s.dPeriod, k, d, s.overboughtLevel, s.oversoldLevel)
+
+	applyOscillatorZone(result, oscillatorZone{
+		name:       "随机指标%K",
+		value:      k,
+		overbought: s.overboughtLevel,
+		oversold:   s.oversoldLevel,
+		step:       5,
+	})
+	confirmOscillatorCross(result, stoch.IsBullishCross(), stoch.IsBearishCross(), "%K/%D")
+
+	return result, nil
+}
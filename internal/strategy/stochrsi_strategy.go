@@ -0,0 +1,124 @@
+package strategy
+
+import (
+	"fmt"
+	"time"
+
+	"ta-watcher/internal/datasource"
+)
+
+// StochRSIStrategy StochRSI策略
+type StochRSIStrategy struct {
+	name                string
+	rsiPeriod           int
+	stochPeriod         int
+	kPeriod             int
+	dPeriod             int
+	overboughtLevel     float64
+	oversoldLevel       float64
+	supportedTimeframes []datasource.Timeframe
+}
+
+// NewStochRSIStrategy 创建StochRSI策略
+func NewStochRSIStrategy(rsiPeriod, stochPeriod, kPeriod, dPeriod int, overboughtLevel, oversoldLevel float64) *StochRSIStrategy {
+	if rsiPeriod <= 0 {
+		rsiPeriod = 14 // 默认周期
+	}
+	if stochPeriod <= 0 {
+		stochPeriod = 14
+	}
+	if kPeriod <= 0 {
+		kPeriod = 3
+	}
+	if dPeriod <= 0 {
+		dPeriod = 3
+	}
+	if overboughtLevel <= 0 || overboughtLevel >= 100 {
+		overboughtLevel = 80
+	}
+	if oversoldLevel <= 0 || oversoldLevel >= overboughtLevel {
+		oversoldLevel = 20
+	}
+
+	return &StochRSIStrategy{
+		name:                fmt.Sprintf("StochRSI_%d_%d_%d_%d_%.0f_%.0f", rsiPeriod, stochPeriod, kPeriod, dPeriod, overboughtLevel, oversoldLevel),
+		rsiPeriod:           rsiPeriod,
+		stochPeriod:         stochPeriod,
+		kPeriod:             kPeriod,
+		dPeriod:             dPeriod,
+		overboughtLevel:     overboughtLevel,
+		oversoldLevel:       oversoldLevel,
+		supportedTimeframes: oscillatorTimeframes(),
+	}
+}
+
+// Name 返回策略名称
+func (s *StochRSIStrategy) Name() string {
+	return s.name
+}
+
+// Description 返回策略描述
+func (s *StochRSIStrategy) Description() string {
+	return fmt.Sprintf("StochRSI随机相对强弱指标策略\n• 指标: StochRSI(%d,%d,%d,%d)\n• 超买阈值: %.0f\n• 超卖阈值: %.0f\n• 说明: 对RSI再做随机指标计算，比RSI更灵敏；K > %.0f 为超买区域(卖出信号), K < %.0f 为超卖区域(买入信号)",
+		s.rsiPeriod, s.stochPeriod, s.kPeriod, s.dPeriod, s.overboughtLevel, s.oversoldLevel, s.overboughtLevel, s.oversoldLevel)
+}
+
+// RequiredDataPoints 返回所需数据点
+func (s *StochRSIStrategy) RequiredDataPoints() int {
+	// RSI 本身需要较长的预热期才能稳定
+	return s.rsiPeriod*5 + s.stochPeriod + s.kPeriod + s.dPeriod
+}
+
+// SupportedTimeframes 返回支持的时间框架
+func (s *StochRSIStrategy) SupportedTimeframes() []datasource.Timeframe {
+	return s.supportedTimeframes
+}
+
+// Evaluate 评估策略
+func (s *StochRSIStrategy) Evaluate(data *MarketData) (*StrategyResult, error) {
+	ctx := NewIndicatorContext(data)
+
+	// 计算StochRSI
+	stochRSI, err := ctx.StochRSI(s.rsiPeriod, s.stochPeriod, s.kPeriod, s.dPeriod)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate StochRSI: %w", err)
+	}
+
+	if len(stochRSI.K) == 0 {
+		return nil, fmt.Errorf("no StochRSI values calculated")
+	}
+
+	k, d := stochRSI.GetLatest()
+
+	result := &StrategyResult{
+		Signal:    SignalNone,
+		Strength:  StrengthNormal,
+		Timestamp: time.Now(),
+		Metadata:  make(map[string]interface{}),
+		Indicators: map[string]interface{}{
+			"stochrsi_k":      k,
+			"stochrsi_d":      d,
+			"stochrsi_period": s.rsiPeriod,
+			"price":           ctx.LatestPrice(),
+		},
+		Thresholds: map[string]interface{}{
+			"overbought_level": s.overboughtLevel,
+			"oversold_level":   s.oversoldLevel,
+		},
+	}
+
+	result.IndicatorSummary = fmt.Sprintf("StochRSI(%d,%d,%d,%d): K %.1f / D %.1f (超买>%.0f, 超卖<%.0f)",
+		s.rsiPeriod, s.stochPeriod, s.kPeriod, s.dPeriod, k, d, s.overboughtLevel, s.oversoldLevel)
+
+	// StochRSI 经常触及 0/100 极值，强度分级步长比 RSI 更大
+	applyOscillatorZone(result, oscillatorZone{
+		name:       "StochRSI",
+		value:      k,
+		overbought: s.overboughtLevel,
+		oversold:   s.oversoldLevel,
+		step:       8,
+	})
+	confirmOscillatorCross(result, stochRSI.IsBullishCross(), stochRSI.IsBearishCross(), "StochRSI K/D")
+
+	return result, nil
+}
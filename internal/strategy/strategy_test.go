@@ -354,3 +354,425 @@ func TestStrategyFactory(t *testing.T) {
 		assert.Nil(t, strategy)
 	})
 }
+
+func TestIndicatorContext_Channels(t *testing.T) {
+	prices := make([]float64, 30)
+	for i := range prices {
+		prices[i] = 100 + float64(i%5)
+	}
+	ctx := NewIndicatorContext(createTestMarketData("BTCUSDT", datasource.Timeframe1h, prices))
+
+	bollinger, err := ctx.BollingerBands(20, 2)
+	require.NoError(t, err)
+	assert.Len(t, bollinger.Middle, 11)
+
+	keltner, err := ctx.KeltnerChannels(20, 10, 2)
+	require.NoError(t, err)
+	upper, middle, lower := keltner.GetLatest()
+	assert.True(t, upper > middle && middle > lower)
+
+	donchian, err := ctx.DonchianChannels(20)
+	require.NoError(t, err)
+	upper, _, lower = donchian.GetLatest()
+	assert.InDelta(t, 104*1.002, upper, 1e-9)
+	assert.InDelta(t, 100*0.998, lower, 1e-9)
+}
+
+func TestIndicatorContext_TrendIndicators(t *testing.T) {
+	prices := make([]float64, 40)
+	for i := range prices {
+		prices[i] = 100 + float64(i)
+	}
+	ctx := NewIndicatorContext(createTestMarketData("BTCUSDT", datasource.Timeframe1h, prices))
+
+	atr, err := ctx.ATR(14)
+	require.NoError(t, err)
+	assert.Len(t, atr.Values, 26)
+	assert.Greater(t, atr.GetLatest(), 0.0)
+
+	adx, err := ctx.ADX(14)
+	require.NoError(t, err)
+	value, plusDI, minusDI := adx.GetLatest()
+	assert.Greater(t, plusDI, minusDI)
+	assert.True(t, adx.IsTrending(25), "ADX %v", value)
+
+	sar, err := ctx.ParabolicSAR(0.02, 0.2)
+	require.NoError(t, err)
+	assert.True(t, sar.IsUptrend())
+	assert.Less(t, sar.GetLatest(), ctx.LatestPrice())
+}
+
+func TestOscillatorStrategies(t *testing.T) {
+	// 先震荡上行再连续下跌，使各振荡指标在最新位置进入超卖区
+	falling := make([]float64, 0, 100)
+	for i := 0; i < 80; i++ {
+		falling = append(falling, 100+float64(i)*0.5+float64(i%3))
+	}
+	for i := 1; i <= 20; i++ {
+		falling = append(falling, falling[79]-float64(i)*1.5)
+	}
+	rising := make([]float64, len(falling))
+	for i, price := range falling {
+		rising[i] = 400 - price
+	}
+
+	tests := []struct {
+		name     string
+		strategy Strategy
+		preset   string
+		keyword  string
+	}{
+		{name: "Stochastic", strategy: NewStochasticStrategy(14, 3, 3, 80, 20), preset: "stoch_standard", keyword: "Stoch"},
+		{name: "StochRSI", strategy: NewStochRSIStrategy(14, 14, 3, 3, 80, 20), preset: "stochrsi_standard", keyword: "StochRSI"},
+		{name: "WilliamsR", strategy: NewWilliamsRStrategy(14, -20, -80), preset: "williams_r_standard", keyword: "%R"},
+		{name: "CCI", strategy: NewCCIStrategy(20, 100, -100), preset: "cci_standard", keyword: "CCI"},
+		{name: "KDJ", strategy: NewKDJStrategy(9, 3, 3, 80, 20), preset: "kdj_standard", keyword: "KDJ"},
+	}
+
+	factory := NewFactory()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Contains(t, tt.strategy.Description(), tt.keyword)
+			assert.NotEmpty(t, tt.strategy.SupportedTimeframes())
+			assert.LessOrEqual(t, tt.strategy.RequiredDataPoints(), len(falling))
+
+			preset, err := factory.CreateStrategy(tt.preset)
+			require.NoError(t, err)
+			assert.Equal(t, tt.strategy.Name(), preset.Name())
+			assert.NotEqual(t, "未知策略", factory.GetPresetDescription(tt.preset))
+
+			result, err := tt.strategy.Evaluate(createTestMarketData("BTCUSDT", datasource.Timeframe1h, falling))
+			require.NoError(t, err)
+			assert.Equal(t, SignalBuy, result.Signal, result.DetailedAnalysis)
+			assert.Contains(t, result.Message, "超卖")
+			assert.Contains(t, result.IndicatorSummary, tt.keyword)
+
+			result, err = tt.strategy.Evaluate(createTestMarketData("BTCUSDT", datasource.Timeframe1h, rising))
+			require.NoError(t, err)
+			assert.Equal(t, SignalSell, result.Signal, result.DetailedAnalysis)
+			assert.Contains(t, result.Message, "超买")
+
+			_, err = tt.strategy.Evaluate(createTestMarketData("BTCUSDT", datasource.Timeframe1h, falling[:5]))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestOscillatorStrategies_DefaultParams(t *testing.T) {
+	assert.Equal(t, "Stoch_14_3_3_80_20", NewStochasticStrategy(0, 0, 0, 0, 0).Name())
+	assert.Equal(t, "StochRSI_14_14_3_3_80_20", NewStochRSIStrategy(0, 0, 0, 0, 0, 0).Name())
+	assert.Equal(t, "WilliamsR_14_-20_-80", NewWilliamsRStrategy(0, 20, 0).Name())
+	assert.Equal(t, "CCI_20_100_-100", NewCCIStrategy(0, 0, 0).Name())
+	assert.Equal(t, "KDJ_9_3_3_80_20", NewKDJStrategy(0, 0, 0, 120, 90).Name())
+}
+
+func TestIndicatorContext_VolumeIndicators(t *testing.T) {
+	prices := make([]float64, 30)
+	for i := range prices {
+		prices[i] = 100 + float64(i)
+	}
+	data := createTestMarketData("BTCUSDT", datasource.Timeframe1h, prices)
+	data.Klines[len(data.Klines)-1].Volume = 5000
+	ctx := NewIndicatorContext(data)
+
+	obv, err := ctx.OBV()
+	require.NoError(t, err)
+	assert.Greater(t, obv.GetChange(5), 0.0)
+
+	vwap, err := ctx.AnchoredVWAP(0)
+	require.NoError(t, err)
+	assert.Less(t, vwap.GetLatest(), ctx.LatestPrice())
+
+	session, err := ctx.SessionVWAP(24 * time.Hour)
+	require.NoError(t, err)
+	assert.Len(t, session.Values, len(prices))
+
+	mfi, err := ctx.MFI(14)
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, mfi.GetLatest())
+
+	cmf, err := ctx.CMF(20)
+	require.NoError(t, err)
+	assert.InDelta(t, 0, cmf.GetLatest(), 1e-9, "收盘价位于K线中点时资金流乘数为0")
+
+	assert.True(t, ctx.IsVolumeConfirmed(20, 2.0))
+	assert.False(t, ctx.IsVolumeConfirmed(50, 2.0), "数据不足时不应确认")
+}
+
+func TestTrendStrategies(t *testing.T) {
+	// 长时间下跌后持续上涨，趋势翻转应产生买入信号；镜像序列产生卖出信号
+	rally := make([]float64, 0, 160)
+	for i := 0; i < 120; i++ {
+		rally = append(rally, 200-float64(i)*0.8+float64(i%4))
+	}
+	for i := 1; i <= 40; i++ {
+		rally = append(rally, rally[119]+float64(i)*2)
+	}
+	selloff := make([]float64, len(rally))
+	for i, price := range rally {
+		selloff[i] = 400 - price
+	}
+
+	// firstSignal 逐根K线评估，返回翻转阶段出现的第一个信号
+	firstSignal := func(t *testing.T, s Strategy, prices []float64) *StrategyResult {
+		for end := 121; end <= len(prices); end++ {
+			result, err := s.Evaluate(createTestMarketData("BTCUSDT", datasource.Timeframe1d, prices[:end]))
+			require.NoError(t, err)
+			if result.Signal != SignalNone {
+				return result
+			}
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name     string
+		strategy Strategy
+		preset   string
+		keyword  string
+	}{
+		{name: "Ichimoku", strategy: NewIchimokuStrategy(9, 26, 52, 26), preset: "ichimoku_standard", keyword: "Ichimoku"},
+		{name: "SuperTrend", strategy: NewSuperTrendStrategy(10, 3), preset: "supertrend_standard", keyword: "SuperTrend"},
+	}
+
+	factory := NewFactory()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Contains(t, tt.strategy.Description(), tt.keyword)
+			assert.Contains(t, tt.strategy.SupportedTimeframes(), datasource.Timeframe1w)
+			assert.LessOrEqual(t, tt.strategy.RequiredDataPoints(), 120)
+
+			preset, err := factory.CreateStrategy(tt.preset)
+			require.NoError(t, err)
+			assert.Equal(t, tt.strategy.Name(), preset.Name())
+			assert.NotEqual(t, "未知策略", factory.GetPresetDescription(tt.preset))
+
+			result := firstSignal(t, tt.strategy, rally)
+			require.NotNil(t, result, "上涨翻转阶段应产生信号")
+			assert.Equal(t, SignalBuy, result.Signal, result.DetailedAnalysis)
+			assert.Contains(t, result.Message, "🟢")
+			assert.Contains(t, result.IndicatorSummary, tt.keyword)
+
+			result = firstSignal(t, tt.strategy, selloff)
+			require.NotNil(t, result, "下跌翻转阶段应产生信号")
+			assert.Equal(t, SignalSell, result.Signal, result.DetailedAnalysis)
+			assert.Contains(t, result.Message, "🔴")
+
+			_, err = tt.strategy.Evaluate(createTestMarketData("BTCUSDT", datasource.Timeframe1d, rally[:5]))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestIchimokuStrategy_CloudOutlook(t *testing.T) {
+	prices := make([]float64, 100)
+	for i := range prices {
+		prices[i] = 100 + float64(i)
+	}
+
+	result, err := NewIchimokuStrategy(9, 26, 52, 26).Evaluate(createTestMarketData("BTCUSDT", datasource.Timeframe1w, prices))
+	require.NoError(t, err)
+	assert.Equal(t, "云层上方", result.Metadata["cloud_position"])
+	assert.Equal(t, true, result.Metadata["future_cloud_bullish"])
+	assert.Equal(t, true, result.Metadata["chikou_bullish"])
+	assert.Contains(t, result.DetailedAnalysis, "多头云")
+	assert.Greater(t, result.Indicators["future_senkou_a"], result.Indicators["senkou_a"])
+}
+
+func TestTrendStrategies_DefaultParams(t *testing.T) {
+	assert.Equal(t, "Ichimoku_9_26_52_26", NewIchimokuStrategy(0, 0, 0, 0).Name())
+	assert.Equal(t, "Ichimoku_9_26_52_30", NewIchimokuStrategy(30, 26, 52, 30).Name())
+	assert.Equal(t, "SuperTrend_10_3.0", NewSuperTrendStrategy(0, 0).Name())
+}
+
+func TestDivergenceStrategies(t *testing.T) {
+	// 急跌形成第一个低点，反弹后缓慢震荡下跌创出更低的低点，再回升确认：价格新低而动能减弱
+	bullish := make([]float64, 0, 115)
+	for i := 0; i < 60; i++ {
+		bullish = append(bullish, 100+float64(i%2)*0.5)
+	}
+	for i := 1; i <= 10; i++ {
+		bullish = append(bullish, 100-float64(i)*2)
+	}
+	for i := 1; i <= 10; i++ {
+		bullish = append(bullish, 80+float64(i))
+	}
+	for i := 1; i <= 20; i++ {
+		step := -2.5
+		if i%2 == 0 {
+			step = 1.2
+		}
+		bullish = append(bullish, bullish[len(bullish)-1]+step)
+	}
+	for i := 1; i <= 15; i++ {
+		bullish = append(bullish, bullish[len(bullish)-1]+1)
+	}
+	bearish := make([]float64, len(bullish))
+	for i, price := range bullish {
+		bearish[i] = 300 - price
+	}
+
+	// firstSignal 逐根K线评估，返回第一个背离信号
+	firstSignal := func(t *testing.T, s Strategy, prices []float64) *StrategyResult {
+		for end := 80; end <= len(prices); end++ {
+			result, err := s.Evaluate(createTestMarketData("BTCUSDT", datasource.Timeframe4h, prices[:end]))
+			require.NoError(t, err)
+			if result.Signal != SignalNone {
+				return result
+			}
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name     string
+		strategy Strategy
+		preset   string
+		keyword  string
+	}{
+		{name: "RSI", strategy: NewRSIDivergenceStrategy(14, 5, 5, 60), preset: "rsi_divergence", keyword: "RSI"},
+		{name: "MACD", strategy: NewMACDDivergenceStrategy(12, 26, 9, 5, 5, 60), preset: "macd_divergence", keyword: "MACD"},
+	}
+
+	factory := NewFactory()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Contains(t, tt.strategy.Description(), tt.keyword)
+			assert.Contains(t, tt.strategy.SupportedTimeframes(), datasource.Timeframe4h)
+			assert.Less(t, tt.strategy.RequiredDataPoints(), len(bullish))
+
+			preset, err := factory.CreateStrategy(tt.preset)
+			require.NoError(t, err)
+			assert.Equal(t, tt.strategy.Name(), preset.Name())
+			assert.NotEqual(t, "未知策略", factory.GetPresetDescription(tt.preset))
+
+			result := firstSignal(t, tt.strategy, bullish)
+			require.NotNil(t, result, "应检测到看涨背离")
+			assert.Equal(t, SignalBuy, result.Signal, result.DetailedAnalysis)
+			assert.Equal(t, "常规看涨背离", result.Metadata["divergence_type"])
+			assert.Contains(t, result.Message, "🟢")
+			assert.Contains(t, result.IndicatorSummary, tt.keyword)
+
+			result = firstSignal(t, tt.strategy, bearish)
+			require.NotNil(t, result, "应检测到看跌背离")
+			assert.Equal(t, SignalSell, result.Signal, result.DetailedAnalysis)
+			assert.Equal(t, "常规看跌背离", result.Metadata["divergence_type"])
+
+			// 背离只在确认的那根K线上发出信号
+			result, err = tt.strategy.Evaluate(createTestMarketData("BTCUSDT", datasource.Timeframe4h, bullish))
+			require.NoError(t, err)
+			assert.Equal(t, SignalNone, result.Signal)
+			assert.Contains(t, result.DetailedAnalysis, "最近一次为")
+
+			_, err = tt.strategy.Evaluate(createTestMarketData("BTCUSDT", datasource.Timeframe4h, bullish[:8]))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDivergenceStrategies_DefaultParams(t *testing.T) {
+	assert.Equal(t, "RSIDiv_14_5_5_60", NewRSIDivergenceStrategy(0, 0, 0, 0).Name())
+	assert.Equal(t, "MACDDiv_12_26_9_5_5_60", NewMACDDivergenceStrategy(26, 12, 0, 0, 0, 0).Name())
+}
+
+// createTrendKlines 创建每根实体为1的连续趋势K线，down 为 true 时逐根下跌
+func createTrendKlines(count int, start float64, down bool) *MarketData {
+	prices := make([]float64, count)
+	for i := range prices {
+		if down {
+			prices[i] = start - float64(i)
+		} else {
+			prices[i] = start + float64(i)
+		}
+	}
+
+	data := createTestMarketData("BTCUSDT", datasource.Timeframe4h, prices)
+	for i, k := range data.Klines {
+		k.Open = k.Close + 1
+		if !down {
+			k.Open = k.Close - 1
+		}
+		if i > 0 {
+			k.Open = data.Klines[i-1].Close
+		}
+		k.High = max(k.Open, k.Close) + 0.3
+		k.Low = min(k.Open, k.Close) - 0.3
+	}
+	return data
+}
+
+// appendKline 在市场数据末尾追加一根K线
+func appendKline(data *MarketData, open, high, low, close float64) *MarketData {
+	last := data.Klines[len(data.Klines)-1]
+	data.Klines = append(data.Klines, &datasource.Kline{
+		Symbol:    last.Symbol,
+		OpenTime:  last.CloseTime,
+		CloseTime: last.CloseTime.Add(time.Hour),
+		Open:      open,
+		High:      high,
+		Low:       low,
+		Close:     close,
+		Volume:    1000,
+	})
+	return data
+}
+
+func TestCandlePatternStrategy(t *testing.T) {
+	s := NewCandlePatternStrategy(0.6)
+	assert.Equal(t, "CandlePattern_60", s.Name())
+	assert.Contains(t, s.Description(), "吞没")
+
+	factory := NewFactory()
+	preset, err := factory.CreateStrategy("candle_patterns")
+	require.NoError(t, err)
+	assert.Equal(t, s.Name(), preset.Name())
+	assert.NotEqual(t, "未知策略", factory.GetPresetDescription("candle_patterns_strict"))
+
+	// 下跌后的看涨吞没
+	result, err := s.Evaluate(appendKline(createTrendKlines(30, 130, true), 100.8, 103.2, 100.6, 103))
+	require.NoError(t, err)
+	assert.Equal(t, SignalBuy, result.Signal, result.DetailedAnalysis)
+	assert.Equal(t, StrengthStrong, result.Strength)
+	assert.Contains(t, result.Message, "看涨吞没")
+	assert.Contains(t, result.IndicatorSummary, "看涨吞没")
+
+	// 上涨后的看跌吞没
+	result, err = s.Evaluate(appendKline(createTrendKlines(30, 70, false), 99.2, 99.4, 96.8, 97))
+	require.NoError(t, err)
+	assert.Equal(t, SignalSell, result.Signal, result.DetailedAnalysis)
+	assert.Contains(t, result.Message, "看跌吞没")
+
+	// 十字星只表示犹豫，不触发信号
+	result, err = s.Evaluate(appendKline(createTrendKlines(30, 130, true), 100.5, 101.5, 99.5, 100.52))
+	require.NoError(t, err)
+	assert.Equal(t, SignalNone, result.Signal)
+	assert.Contains(t, result.Metadata["patterns"], "十字星")
+
+	_, err = s.Evaluate(createTestMarketData("BTCUSDT", datasource.Timeframe4h, []float64{1, 2}))
+	assert.Error(t, err)
+}
+
+func TestCandlePatternConfirmStrategy(t *testing.T) {
+	s := NewCandlePatternConfirmStrategy(NewRSIStrategy(14, 70, 30), 0.6)
+	assert.Equal(t, "RSI_14_70_30+CandlePattern_60", s.Name())
+	assert.Contains(t, s.Description(), "RSI_14_70_30")
+	assert.Equal(t, NewRSIStrategy(14, 70, 30).RequiredDataPoints(), s.RequiredDataPoints())
+
+	preset, err := NewFactory().CreateStrategy("rsi_pattern_confirm")
+	require.NoError(t, err)
+	assert.Equal(t, s.Name(), preset.Name())
+
+	// RSI超卖但没有看涨形态（连续阴线构成三只乌鸦），信号被压制
+	result, err := s.Evaluate(createTrendKlines(30, 130, true))
+	require.NoError(t, err)
+	assert.Equal(t, SignalNone, result.Signal, result.DetailedAnalysis)
+	assert.Equal(t, "BUY", result.Metadata["suppressed_signal"])
+	assert.Contains(t, result.Message, "等待K线形态确认")
+
+	// 看涨吞没确认RSI超卖信号
+	result, err = s.Evaluate(appendKline(createTrendKlines(30, 130, true), 100.8, 103.2, 100.6, 103))
+	require.NoError(t, err)
+	assert.Equal(t, SignalBuy, result.Signal, result.DetailedAnalysis)
+	assert.Equal(t, "看涨吞没", result.Metadata["confirm_pattern"])
+	assert.Contains(t, result.DetailedAnalysis, "形态确认")
+}
@@ -0,0 +1,152 @@
+package strategy
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"ta-watcher/internal/datasource"
+)
+
+// SuperTrendStrategy 超级趋势策略
+type SuperTrendStrategy struct {
+	name                string
+	period              int
+	multiplier          float64
+	supportedTimeframes []datasource.Timeframe
+}
+
+// NewSuperTrendStrategy 创建超级趋势策略
+func NewSuperTrendStrategy(period int, multiplier float64) *SuperTrendStrategy {
+	if period <= 0 {
+		period = 10 // 默认周期
+	}
+	if multiplier <= 0 {
+		multiplier = 3
+	}
+
+	return &SuperTrendStrategy{
+		name:       fmt.Sprintf("SuperTrend_%d_%.1f", period, multiplier),
+		period:     period,
+		multiplier: multiplier,
+		supportedTimeframes: []datasource.Timeframe{
+			datasource.Timeframe15m, datasource.Timeframe30m, datasource.Timeframe1h, datasource.Timeframe2h,
+			datasource.Timeframe4h, datasource.Timeframe6h, datasource.Timeframe12h,
+			datasource.Timeframe1d, datasource.Timeframe3d, datasource.Timeframe1w, datasource.Timeframe1M,
+		},
+	}
+}
+
+// Name 返回策略名称
+func (s *SuperTrendStrategy) Name() string {
+	return s.name
+}
+
+// Description 返回策略描述
+func (s *SuperTrendStrategy) Description() string {
+	return fmt.Sprintf("超级趋势(SuperTrend)策略\n• ATR周期: %d\n• ATR倍数: %.1f\n• 说明: 收盘价突破上轨翻转为上升趋势(买入信号)，跌破下轨翻转为下降趋势(卖出信号)",
+		s.period, s.multiplier)
+}
+
+// RequiredDataPoints 返回所需数据点
+func (s *SuperTrendStrategy) RequiredDataPoints() int {
+	// 威尔德ATR需要预热期，轨道还需要若干K线收敛
+	return s.period*3 + 10
+}
+
+// SupportedTimeframes 返回支持的时间框架
+func (s *SuperTrendStrategy) SupportedTimeframes() []datasource.Timeframe {
+	return s.supportedTimeframes
+}
+
+// Evaluate 评估策略
+func (s *SuperTrendStrategy) Evaluate(data *MarketData) (*StrategyResult, error) {
+	ctx := NewIndicatorContext(data)
+
+	// 计算SuperTrend
+	superTrend, err := ctx.SuperTrend(s.period, s.multiplier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate SuperTrend: %w", err)
+	}
+
+	if len(superTrend.Values) < 2 {
+		return nil, fmt.Errorf("insufficient SuperTrend data points")
+	}
+
+	latest := superTrend.GetLatest()
+	currentPrice := ctx.LatestPrice()
+	uptrend := superTrend.IsUptrend()
+	distance := 0.0
+	if currentPrice != 0 {
+		distance = math.Abs(currentPrice-latest) / currentPrice * 100
+	}
+
+	// 统计当前趋势已持续的K线数
+	duration := 0
+	for i := len(superTrend.Uptrend) - 1; i >= 0 && superTrend.Uptrend[i] == uptrend; i-- {
+		duration++
+	}
+
+	result := &StrategyResult{
+		Signal:    SignalNone,
+		Strength:  StrengthNormal,
+		Timestamp: time.Now(),
+		Metadata:  make(map[string]interface{}),
+		Indicators: map[string]interface{}{
+			"supertrend":        latest,
+			"supertrend_period": s.period,
+			"price":             currentPrice,
+		},
+		Thresholds: map[string]interface{}{
+			"multiplier": s.multiplier,
+		},
+	}
+	result.Metadata["uptrend"] = uptrend
+	result.Metadata["trend_duration"] = duration
+	result.Metadata["distance_percent"] = distance
+
+	direction := "下降趋势"
+	if uptrend {
+		direction = "上升趋势"
+	}
+	result.IndicatorSummary = fmt.Sprintf("SuperTrend(%d,%.1f): %.4f, %s", s.period, s.multiplier, latest, direction)
+
+	reversal, up := superTrend.IsReversal()
+	switch {
+	case reversal && up:
+		result.Signal = SignalBuy
+		result.Message = "🟢 SuperTrend翻多信号"
+		result.DetailedAnalysis = fmt.Sprintf("收盘价 %.4f 突破SuperTrend上轨，趋势由下降翻转为上升。<br/>新的支撑位（止损参考）: %.4f，距离 %.2f%%。",
+			currentPrice, latest, distance)
+	case reversal && !up:
+		result.Signal = SignalSell
+		result.Message = "🔴 SuperTrend翻空信号"
+		result.DetailedAnalysis = fmt.Sprintf("收盘价 %.4f 跌破SuperTrend下轨，趋势由上升翻转为下降。<br/>新的阻力位（止损参考）: %.4f，距离 %.2f%%。",
+			currentPrice, latest, distance)
+	default:
+		result.Message = fmt.Sprintf("⚪ SuperTrend维持%s", direction)
+		result.DetailedAnalysis = fmt.Sprintf("SuperTrend %.4f，已维持%s %d 根K线，当前价格 %.4f 距离 %.2f%%。<br/>趋势未发生翻转。",
+			latest, direction, duration, currentPrice, distance)
+		return result, nil
+	}
+
+	// 翻转前的趋势持续越久，翻转的意义越大
+	prevDuration := 0
+	for i := len(superTrend.Uptrend) - 2; i >= 0 && superTrend.Uptrend[i] != up; i-- {
+		prevDuration++
+	}
+	result.Metadata["previous_trend_duration"] = prevDuration
+
+	if prevDuration >= 2*s.period {
+		result.Strength = StrengthStrong
+		result.DetailedAnalysis += fmt.Sprintf("<br/>📈 结束了持续 %d 根K线的趋势，信号强度: 强", prevDuration)
+	} else if prevDuration >= s.period/2 {
+		result.Strength = StrengthNormal
+		result.DetailedAnalysis += fmt.Sprintf("<br/>📊 结束了持续 %d 根K线的趋势，信号强度: 中等", prevDuration)
+	} else {
+		result.Strength = StrengthWeak
+		result.DetailedAnalysis += fmt.Sprintf("<br/>📉 前一趋势仅持续 %d 根K线，可能为震荡，信号强度: 弱", prevDuration)
+	}
+
+	return result, nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"ta-watcher/internal/datasource"
 	"ta-watcher/internal/indicators"
+	"ta-watcher/internal/patterns"
 )
 
 // Signal 策略信号类型
@@ -59,6 +60,14 @@ type MarketData struct {
 	Timeframe datasource.Timeframe // 时间框架
 	Klines    []*datasource.Kline  // K线数据
 	Timestamp time.Time            // 数据时间戳
+
+	// 永续合约数据，仅在配置了衍生品数据源时提供，获取失败时为空
+	FundingRates    []*datasource.FundingRate    // 资金费率历史（按时间升序）
+	OpenInterest    []*datasource.OpenInterest   // 持仓量序列（统计周期不超过K线周期）
+	LongShortRatios []*datasource.LongShortRatio // 多空账户比序列
+
+	// 订单簿快照，仅在启用 order_book_depth 且数据源支持时提供
+	OrderBook *datasource.OrderBook
 }
 
 // StrategyResult 策略评估结果
@@ -174,6 +183,41 @@ func (ctx *IndicatorContext) Volumes() []float64 {
 	return volumes
 }
 
+// OpenTimes 获取K线开盘时间序列
+func (ctx *IndicatorContext) OpenTimes() []time.Time {
+	times := make([]time.Time, len(ctx.data.Klines))
+	for i, kline := range ctx.data.Klines {
+		times[i] = kline.OpenTime
+	}
+	return times
+}
+
+// FundingRates 获取资金费率序列
+func (ctx *IndicatorContext) FundingRates() []float64 {
+	rates := make([]float64, len(ctx.data.FundingRates))
+	for i, fr := range ctx.data.FundingRates {
+		rates[i] = fr.Rate
+	}
+	return rates
+}
+
+// OpenInterestValues 获取持仓名义价值序列
+func (ctx *IndicatorContext) OpenInterestValues() []float64 {
+	values := make([]float64, len(ctx.data.OpenInterest))
+	for i, oi := range ctx.data.OpenInterest {
+		values[i] = oi.Value
+	}
+	return values
+}
+
+// DepthImbalance 获取中间价 ±percent% 范围内的订单簿深度失衡度，无订单簿时 ok 为 false
+func (ctx *IndicatorContext) DepthImbalance(percent float64) (imbalance float64, ok bool) {
+	if ctx.data.OrderBook == nil {
+		return 0, false
+	}
+	return ctx.data.OrderBook.Imbalance(percent), true
+}
+
 // SMA 计算简单移动平均线
 func (ctx *IndicatorContext) SMA(period int) (*indicators.MAResult, error) {
 	return indicators.CalculateSMA(ctx.ClosePrices(), period)
@@ -194,6 +238,125 @@ func (ctx *IndicatorContext) MACD(fastPeriod, slowPeriod, signalPeriod int) (*in
 	return indicators.CalculateMACD(ctx.ClosePrices(), fastPeriod, slowPeriod, signalPeriod)
 }
 
+// BollingerBands 计算布林带
+func (ctx *IndicatorContext) BollingerBands(period int, stdDev float64) (*indicators.BollingerResult, error) {
+	return indicators.CalculateBollingerBands(ctx.ClosePrices(), period, stdDev)
+}
+
+// KeltnerChannels 计算肯特纳通道
+func (ctx *IndicatorContext) KeltnerChannels(emaPeriod, atrPeriod int, multiplier float64) (*indicators.KeltnerResult, error) {
+	return indicators.CalculateKeltnerChannels(ctx.HighPrices(), ctx.LowPrices(), ctx.ClosePrices(), emaPeriod, atrPeriod, multiplier)
+}
+
+// DonchianChannels 计算唐奇安通道
+func (ctx *IndicatorContext) DonchianChannels(period int) (*indicators.DonchianResult, error) {
+	return indicators.CalculateDonchianChannels(ctx.HighPrices(), ctx.LowPrices(), period)
+}
+
+// ATR 计算平均真实波幅
+func (ctx *IndicatorContext) ATR(period int) (*indicators.ATRResult, error) {
+	return indicators.CalculateATR(ctx.HighPrices(), ctx.LowPrices(), ctx.ClosePrices(), period)
+}
+
+// ADX 计算平均趋向指数及 +DI/-DI
+func (ctx *IndicatorContext) ADX(period int) (*indicators.ADXResult, error) {
+	return indicators.CalculateADX(ctx.HighPrices(), ctx.LowPrices(), ctx.ClosePrices(), period)
+}
+
+// ParabolicSAR 计算抛物线转向指标
+func (ctx *IndicatorContext) ParabolicSAR(step, max float64) (*indicators.SARResult, error) {
+	return indicators.CalculateParabolicSAR(ctx.HighPrices(), ctx.LowPrices(), step, max)
+}
+
+// Ichimoku 计算一目均衡表
+func (ctx *IndicatorContext) Ichimoku(tenkanPeriod, kijunPeriod, senkouBPeriod, displacement int) (*indicators.IchimokuResult, error) {
+	return indicators.CalculateIchimoku(ctx.HighPrices(), ctx.LowPrices(), ctx.ClosePrices(), tenkanPeriod, kijunPeriod, senkouBPeriod, displacement)
+}
+
+// SuperTrend 计算超级趋势指标
+func (ctx *IndicatorContext) SuperTrend(period int, multiplier float64) (*indicators.SuperTrendResult, error) {
+	return indicators.CalculateSuperTrend(ctx.HighPrices(), ctx.LowPrices(), ctx.ClosePrices(), period, multiplier)
+}
+
+// Stochastic 计算慢速随机指标
+func (ctx *IndicatorContext) Stochastic(kPeriod, smoothK, dPeriod int) (*indicators.StochasticResult, error) {
+	return indicators.CalculateStochastic(ctx.HighPrices(), ctx.LowPrices(), ctx.ClosePrices(), kPeriod, smoothK, dPeriod)
+}
+
+// StochRSI 计算随机相对强弱指标
+func (ctx *IndicatorContext) StochRSI(rsiPeriod, stochPeriod, kPeriod, dPeriod int) (*indicators.StochRSIResult, error) {
+	return indicators.CalculateStochRSI(ctx.ClosePrices(), rsiPeriod, stochPeriod, kPeriod, dPeriod)
+}
+
+// WilliamsR 计算威廉指标
+func (ctx *IndicatorContext) WilliamsR(period int) (*indicators.WilliamsRResult, error) {
+	return indicators.CalculateWilliamsR(ctx.HighPrices(), ctx.LowPrices(), ctx.ClosePrices(), period)
+}
+
+// CCI 计算顺势指标
+func (ctx *IndicatorContext) CCI(period int) (*indicators.CCIResult, error) {
+	return indicators.CalculateCCI(ctx.HighPrices(), ctx.LowPrices(), ctx.ClosePrices(), period)
+}
+
+// KDJ 计算KDJ随机指标
+func (ctx *IndicatorContext) KDJ(period, kSmooth, dSmooth int) (*indicators.KDJResult, error) {
+	return indicators.CalculateKDJ(ctx.HighPrices(), ctx.LowPrices(), ctx.ClosePrices(), period, kSmooth, dSmooth)
+}
+
+// Pivots 检测摆动高点和低点
+func (ctx *IndicatorContext) Pivots(leftBars, rightBars int) (*indicators.PivotResult, error) {
+	return indicators.CalculatePivots(ctx.HighPrices(), ctx.LowPrices(), leftBars, rightBars)
+}
+
+// Divergence 检测价格与振荡指标序列之间的背离，oscillator 与K线末端对齐
+func (ctx *IndicatorContext) Divergence(oscillator []float64, leftBars, rightBars, maxLookback int) (*indicators.DivergenceResult, error) {
+	return indicators.CalculateDivergence(ctx.HighPrices(), ctx.LowPrices(), oscillator, leftBars, rightBars, maxLookback)
+}
+
+// CandlePatterns 识别最近 bars 根K线内完成的K线形态
+func (ctx *IndicatorContext) CandlePatterns(bars int) []patterns.Match {
+	return patterns.Recent(ctx.data.Klines, bars)
+}
+
+// OBV 计算能量潮指标
+func (ctx *IndicatorContext) OBV() (*indicators.OBVResult, error) {
+	return indicators.CalculateOBV(ctx.ClosePrices(), ctx.Volumes())
+}
+
+// AnchoredVWAP 计算从第 anchor 根K线开始累计的锚定VWAP
+func (ctx *IndicatorContext) AnchoredVWAP(anchor int) (*indicators.VWAPResult, error) {
+	return indicators.CalculateAnchoredVWAP(ctx.HighPrices(), ctx.LowPrices(), ctx.ClosePrices(), ctx.Volumes(), anchor)
+}
+
+// SessionVWAP 计算按时段重置的VWAP
+func (ctx *IndicatorContext) SessionVWAP(session time.Duration) (*indicators.VWAPResult, error) {
+	return indicators.CalculateSessionVWAP(ctx.OpenTimes(), ctx.HighPrices(), ctx.LowPrices(), ctx.ClosePrices(), ctx.Volumes(), session)
+}
+
+// MFI 计算资金流量指标
+func (ctx *IndicatorContext) MFI(period int) (*indicators.MFIResult, error) {
+	return indicators.CalculateMFI(ctx.HighPrices(), ctx.LowPrices(), ctx.ClosePrices(), ctx.Volumes(), period)
+}
+
+// CMF 计算蔡金资金流
+func (ctx *IndicatorContext) CMF(period int) (*indicators.CMFResult, error) {
+	return indicators.CalculateCMF(ctx.HighPrices(), ctx.LowPrices(), ctx.ClosePrices(), ctx.Volumes(), period)
+}
+
+// VolumeSpike 计算成交量 z 分数
+func (ctx *IndicatorContext) VolumeSpike(period int) (*indicators.VolumeSpikeResult, error) {
+	return indicators.CalculateVolumeSpike(ctx.Volumes(), period)
+}
+
+// IsVolumeConfirmed 检查最新K线是否放量，供策略要求成交量确认；数据不足时返回 false
+func (ctx *IndicatorContext) IsVolumeConfirmed(period int, threshold float64) bool {
+	spike, err := ctx.VolumeSpike(period)
+	if err != nil {
+		return false
+	}
+	return spike.IsSpike(threshold)
+}
+
 // LatestPrice 获取最新价格
 func (ctx *IndicatorContext) LatestPrice() float64 {
 	if len(ctx.data.Klines) == 0 {
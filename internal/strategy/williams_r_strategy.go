@@ -0,0 +1,113 @@
+package strategy
+
+import (
+	"fmt"
+	"time"
+
+	"ta-watcher/internal/datasource"
+)
+
+// WilliamsRStrategy 威廉指标策略
+type WilliamsRStrategy struct {
+	name                string
+	period              int
+	overboughtLevel     float64
+	oversoldLevel       float64
+	supportedTimeframes []datasource.Timeframe
+}
+
+// NewWilliamsRStrategy 创建威廉指标策略
+// 阈值取值区间为 -100 到 0，例如超买 -20、超卖 -80
+func NewWilliamsRStrategy(period int, overboughtLevel, oversoldLevel float64) *WilliamsRStrategy {
+	if period <= 0 {
+		period = 14 // 默认周期
+	}
+	if overboughtLevel >= 0 || overboughtLevel <= -100 {
+		overboughtLevel = -20
+	}
+	if oversoldLevel >= overboughtLevel || oversoldLevel <= -100 {
+		oversoldLevel = -80
+	}
+
+	return &WilliamsRStrategy{
+		name:                fmt.Sprintf("WilliamsR_%d_%.0f_%.0f", period, overboughtLevel, oversoldLevel),
+		period:              period,
+		overboughtLevel:     overboughtLevel,
+		oversoldLevel:       oversoldLevel,
+		supportedTimeframes: oscillatorTimeframes(),
+	}
+}
+
+// Name 返回策略名称
+func (s *WilliamsRStrategy) Name() string {
+	return s.name
+}
+
+// Description 返回策略描述
+func (s *WilliamsRStrategy) Description() string {
+	return fmt.Sprintf("威廉指标(%%R)策略\n• 指标: %%R-%d\n• 超买阈值: %.0f\n• 超卖阈值: %.0f\n• 说明: %%R > %.0f 为超买区域(卖出信号), %%R < %.0f 为超卖区域(买入信号)",
+		s.period, s.overboughtLevel, s.oversoldLevel, s.overboughtLevel, s.oversoldLevel)
+}
+
+// RequiredDataPoints 返回所需数据点
+func (s *WilliamsRStrategy) RequiredDataPoints() int {
+	return s.period + 10 // 额外缓冲
+}
+
+// SupportedTimeframes 返回支持的时间框架
+func (s *WilliamsRStrategy) SupportedTimeframes() []datasource.Timeframe {
+	return s.supportedTimeframes
+}
+
+// Evaluate 评估策略
+func (s *WilliamsRStrategy) Evaluate(data *MarketData) (*StrategyResult, error) {
+	ctx := NewIndicatorContext(data)
+
+	// 计算威廉指标
+	williamsR, err := ctx.WilliamsR(s.period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate Williams %%R: %w", err)
+	}
+
+	if len(williamsR.Values) == 0 {
+		return nil, fmt.Errorf("no Williams %%R values calculated")
+	}
+
+	latest := williamsR.GetLatest()
+
+	result := &StrategyResult{
+		Signal:    SignalNone,
+		Strength:  StrengthNormal,
+		Timestamp: time.Now(),
+		Metadata:  make(map[string]interface{}),
+		Indicators: map[string]interface{}{
+			"williams_r":        latest,
+			"williams_r_period": s.period,
+			"price":             ctx.LatestPrice(),
+		},
+		Thresholds: map[string]interface{}{
+			"overbought_level": s.overboughtLevel,
+			"oversold_level":   s.oversoldLevel,
+		},
+	}
+
+	result.IndicatorSummary = fmt.Sprintf("%%R-%d: %.1f (超买>%.0f, 超卖<%.0f)",
+		s.period, latest, s.overboughtLevel, s.oversoldLevel)
+
+	applyOscillatorZone(result, oscillatorZone{
+		name:       "威廉指标%R",
+		value:      latest,
+		overbought: s.overboughtLevel,
+		oversold:   s.oversoldLevel,
+		step:       5,
+	})
+
+	// 添加趋势信息
+	if len(williamsR.Values) >= 2 {
+		previous := williamsR.Values[len(williamsR.Values)-2]
+		result.Metadata["williams_r_previous"] = previous
+		result.Metadata["williams_r_trend"] = latest - previous
+	}
+
+	return result, nil
+}
@@ -2,15 +2,21 @@ package watcher
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"ta-watcher/internal/assets"
+	"ta-watcher/internal/bars"
 	"ta-watcher/internal/config"
 	"ta-watcher/internal/datasource"
+	"ta-watcher/internal/indicators"
 	"ta-watcher/internal/notifiers"
+	"ta-watcher/internal/patterns"
 	"ta-watcher/internal/strategy"
 )
 
@@ -23,6 +29,16 @@ type Watcher struct {
 	rateCalculator  *assets.RateCalculator
 	signals         []SignalInfo // 简单存储信号信息
 	lastReportTime  time.Time
+	streaming       bool // 是否优先使用 WebSocket 推送
+	closedOnly      bool // 是否只使用已收盘的K线
+	repairPolicy    datasource.RepairPolicy
+	derivatives     datasource.DerivativesDataSource // 永续合约数据源，未配置时为 nil
+	orderBookDepth  int                              // 获取的订单簿档位数，0 表示不获取
+	depthRange      float64                          // 统计深度的价格范围（±%）
+	bridgeCurrency  string                           // 交叉汇率换算使用的桥接货币（assets.base_currency）
+	barSpecs        []bars.Spec                      // 由逐笔成交构建的K线规格（watcher.bars）
+	barSeries       map[string]*bars.Series          // 按 交易对+规格 跨轮询累积的K线序列
+	barsMu          sync.Mutex                       // 保护 barSeries
 }
 
 // SignalInfo 简单的信号信息结构
@@ -32,12 +48,24 @@ type SignalInfo struct {
 	Signal             strategy.Signal
 	Strategy           string
 	Timestamp          time.Time
-	Message            string                   // 策略提供的简短消息
-	IndicatorSummary   string                   // 指标摘要
-	DetailedAnalysis   string                   // 详细分析
-	AllIndicators      map[string]interface{}   // 所有指标值
-	Thresholds         map[string]interface{}   // 策略阈值
-	MultiTimeframeData map[string]TimeframeData // 多时间框架数据
+	DataSource         string                       // 提供K线数据的数据源
+	CandleClosed       bool                         // 信号所依据的最新K线是否已收盘
+	DataIssues         []datasource.QualityIssue    // K线数据质量问题（已按策略修复）
+	Liquidity          *datasource.OrderBookSummary // 订单簿流动性摘要，未获取时为 nil
+	Volume             *VolumeSummary               // 信号K线的成交量概况，数据不足时为 nil
+	Patterns           []patterns.Match             // 最新已收盘K线上完成的K线形态
+	Message            string                       // 策略提供的简短消息
+	IndicatorSummary   string                       // 指标摘要
+	DetailedAnalysis   string                       // 详细分析
+	AllIndicators      map[string]interface{}       // 所有指标值
+	Thresholds         map[string]interface{}       // 策略阈值
+	MultiTimeframeData map[string]TimeframeData     // 多时间框架数据
+}
+
+// VolumeSummary 信号K线的成交量概况
+type VolumeSummary struct {
+	Volume float64 // 最新K线成交量
+	ZScore float64 // 相对之前K线的成交量 z 分数
 }
 
 // TimeframeData 时间框架数据
@@ -53,11 +81,16 @@ type TimeframeData struct {
 // New 创建新的监控器
 func New(cfg *config.Config) (*Watcher, error) {
 	factory := datasource.NewFactory()
-	ds, err := factory.CreateDataSource(cfg.DataSource.Primary, cfg)
+	ds, err := factory.CreateFromConfig(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create data source: %w", err)
 	}
 
+	derivatives, err := factory.CreateDerivatives(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create derivatives data source: %w", err)
+	}
+
 	strategyFactory := strategy.NewFactory()
 	strategies := []strategy.Strategy{}
 
@@ -66,6 +99,15 @@ func New(cfg *config.Config) (*Watcher, error) {
 		strategies = append(strategies, rsiStrategy)
 	}
 
+	barSpecs := make([]bars.Spec, 0, len(cfg.Watcher.Bars))
+	for _, s := range cfg.Watcher.Bars {
+		spec, err := bars.ParseSpec(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid watcher.bars: %w", err)
+		}
+		barSpecs = append(barSpecs, spec)
+	}
+
 	// 创建通知管理器
 	notifierManager := notifiers.NewManager()
 	var emailNotifier *notifiers.EmailNotifier
@@ -84,7 +126,7 @@ func New(cfg *config.Config) (*Watcher, error) {
 	// 创建汇率计算器
 	rateCalculator := assets.NewRateCalculator(ds)
 
-	return &Watcher{
+	w := &Watcher{
 		dataSource:      ds,
 		strategies:      strategies,
 		notifierManager: notifierManager,
@@ -92,7 +134,56 @@ func New(cfg *config.Config) (*Watcher, error) {
 		rateCalculator:  rateCalculator,
 		signals:         make([]SignalInfo, 0),
 		lastReportTime:  time.Now(),
-	}, nil
+		streaming:       cfg.Watcher.Streaming,
+		closedOnly:      cfg.Watcher.ClosedCandlesOnly,
+		repairPolicy:    datasource.RepairPolicy(cfg.Watcher.DataQuality),
+		derivatives:     derivatives,
+		orderBookDepth:  cfg.Watcher.OrderBookDepth,
+		depthRange:      cfg.Watcher.DepthRangePercent,
+		bridgeCurrency:  cfg.Assets.BaseCurrency,
+		barSpecs:        barSpecs,
+	}
+
+	// 多数据源共识出现价格分歧时发送系统告警
+	if reporter, ok := datasource.AsDivergenceReporter(ds); ok {
+		reporter.SetDivergenceHandler(w.alertDivergence)
+	}
+
+	return w, nil
+}
+
+// alertDivergence 数据源价格分歧超过阈值时通过通知管理器发送系统告警
+func (w *Watcher) alertDivergence(d datasource.Divergence) {
+	names := make([]string, 0, len(d.Closes))
+	for name := range d.Closes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var closes strings.Builder
+	for _, name := range names {
+		closes.WriteString(fmt.Sprintf("\n• %s: %.8g", name, d.Closes[name]))
+	}
+
+	notification := &notifiers.Notification{
+		ID:    fmt.Sprintf("divergence-%s-%s-%d", d.Symbol, d.Timeframe, d.OpenTime.Unix()),
+		Type:  notifiers.TypeSystemAlert,
+		Asset: d.Symbol,
+		Title: fmt.Sprintf("TA Watcher 数据源价格分歧 - %s %s", d.Symbol, d.Timeframe),
+		Message: fmt.Sprintf("⚠️ %s %s K线（%s）各数据源收盘价不一致，%s\n\n中位数: %.8g%s\n\n信号使用中位数计算，请核实该交易所报价是否异常。",
+			d.Symbol, d.Timeframe, d.OpenTime.Format("2006-01-02 15:04"), d.Summary(), d.Median, closes.String()),
+		Data: map[string]interface{}{
+			"source":        d.Source,
+			"deviation_bps": d.DeviationBps,
+			"median":        d.Median,
+			"closes":        d.Closes,
+		},
+		Timestamp: time.Now(),
+	}
+
+	if err := w.notifierManager.Send(notification); err != nil {
+		log.Printf("❌ 发送数据源分歧告警失败: %v", err)
+	}
 }
 
 // Start 启动监控
@@ -104,11 +195,23 @@ func (w *Watcher) Start(ctx context.Context) error {
 	cancelCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	for _, symbol := range symbols {
-		for _, tf := range timeframes {
+	for _, tf := range timeframes {
+		if w.streaming {
+			err := w.WatchStream(cancelCtx, symbols, tf)
+			if err == nil {
+				continue
+			}
+			log.Printf("⚠️ %s 实时推送不可用，改为定时轮询: %v", tf, err)
+		}
+		for _, symbol := range symbols {
 			go w.Watch(cancelCtx, symbol, tf)
 		}
 	}
+	for _, spec := range w.barSpecs {
+		for _, symbol := range symbols {
+			go w.WatchBars(cancelCtx, symbol, spec)
+		}
+	}
 
 	// 创建定时报告发送器（每10分钟检查一次是否需要发送报告）
 	reportTicker := time.NewTicker(10 * time.Minute)
@@ -132,12 +235,7 @@ func (w *Watcher) Start(ctx context.Context) error {
 
 // Watch 监控单个交易对
 func (w *Watcher) Watch(ctx context.Context, symbol string, timeframe datasource.Timeframe) error {
-	maxDataPoints := 50
-	for _, strat := range w.strategies {
-		if required := strat.RequiredDataPoints(); required > maxDataPoints {
-			maxDataPoints = required
-		}
-	}
+	maxDataPoints := w.maxDataPoints()
 
 	ticker := time.NewTicker(2 * time.Minute)
 	defer ticker.Stop()
@@ -154,6 +252,64 @@ func (w *Watcher) Watch(ctx context.Context, symbol string, timeframe datasource
 	}
 }
 
+// WatchBars 定时获取近期成交，构建指定规格的K线并分析
+func (w *Watcher) WatchBars(ctx context.Context, symbol string, spec bars.Spec) error {
+	maxDataPoints := w.maxDataPoints()
+
+	ticker := time.NewTicker(2 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := w.analyzeBars(ctx, symbol, spec, maxDataPoints); err != nil {
+				log.Printf("❌ 分析 %s (%s) 时出错: %v", symbol, spec, err)
+			}
+		}
+	}
+}
+
+// WatchStream 通过 WebSocket 订阅多个交易对，K线收盘时立即分析
+// 数据源不支持推送或订阅失败时返回错误，调用方可改用 Watch 轮询
+func (w *Watcher) WatchStream(ctx context.Context, symbols []string, timeframe datasource.Timeframe) error {
+	streaming, ok := datasource.AsStreaming(w.dataSource)
+	if !ok {
+		return fmt.Errorf("data source %s does not support streaming", w.dataSource.Name())
+	}
+
+	klines, err := streaming.SubscribeKlines(ctx, symbols, timeframe)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe klines: %w", err)
+	}
+
+	log.Printf("📡 已订阅 %s 实时K线: %s", timeframe, strings.Join(symbols, ", "))
+	maxDataPoints := w.maxDataPoints()
+
+	go func() {
+		for kline := range klines {
+			log.Printf("🕯️ %s %s K线收盘: %.8f", kline.Symbol, timeframe, kline.Close)
+			if err := w.analyzeSymbol(ctx, kline.Symbol, timeframe, maxDataPoints); err != nil {
+				log.Printf("❌ 分析 %s 时出错: %v", kline.Symbol, err)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// maxDataPoints 计算所有策略需要的最大数据点数
+func (w *Watcher) maxDataPoints() int {
+	maxDataPoints := 50
+	for _, strat := range w.strategies {
+		if required := strat.RequiredDataPoints(); required > maxDataPoints {
+			maxDataPoints = required
+		}
+	}
+	return maxDataPoints
+}
+
 // analyzeSymbol 分析交易对
 func (w *Watcher) analyzeSymbol(ctx context.Context, symbol string, timeframe datasource.Timeframe, maxDataPoints int) error {
 	endTime := time.Now()
@@ -200,7 +356,13 @@ func (w *Watcher) analyzeSymbol(ctx context.Context, symbol string, timeframe da
 
 	// 尝试直接获取K线数据
 	klines, err := w.dataSource.GetKlines(ctx, symbol, timeframe, startTime, endTime, maxDataPoints*2)
+	directPair := err == nil
 	if err != nil {
+		// 限流或服务不可用时直接返回，避免额外的交叉汇率探测请求
+		if datasource.IsTransient(err) && !errors.Is(err, datasource.ErrSymbolNotFound) {
+			return fmt.Errorf("数据源暂时不可用: %w", err)
+		}
+
 		// 如果直接获取失败，判断是否为交叉汇率对并尝试计算
 		log.Printf("🔍 直接获取 %s 失败，判断是否为交叉汇率对: %v", symbol, err)
 		isCrossRatePair := w.isCrossRatePair(symbol)
@@ -216,6 +378,14 @@ func (w *Watcher) analyzeSymbol(ctx context.Context, symbol string, timeframe da
 		}
 	}
 
+	klines, issues, err := w.prepareKlines(klines, timeframe)
+	if err != nil {
+		return fmt.Errorf("K线数据质量检查失败: %w", err)
+	}
+	if len(issues) > 0 {
+		log.Printf("🩹 [%s %s] 数据质量问题: %s", symbol, timeframe, summarizeIssues(issues))
+	}
+
 	if len(klines) < maxDataPoints {
 		log.Printf("⚠️ [%s %s] 数据不足: %d/%d", symbol, timeframe, len(klines), maxDataPoints)
 		return fmt.Errorf("数据点不足: 需要 %d，实际 %d", maxDataPoints, len(klines))
@@ -227,6 +397,88 @@ func (w *Watcher) analyzeSymbol(ctx context.Context, symbol string, timeframe da
 		Klines:    klines,
 		Timestamp: time.Now(),
 	}
+	if directPair {
+		// 交叉汇率对没有对应的永续合约和订单簿
+		w.loadDerivatives(ctx, marketData)
+		w.loadOrderBook(ctx, marketData)
+	}
+
+	w.evaluateStrategies(marketData, w.servedBy(symbol, timeframe), issues)
+	return nil
+}
+
+// analyzeBars 由近期逐笔成交增量构建指定规格的K线并分析，仅支持直接交易对
+// 单次获取的成交通常不足以生成足够的K线，序列在多次轮询间累积，数据点足够后才开始评估策略
+func (w *Watcher) analyzeBars(ctx context.Context, symbol string, spec bars.Spec, maxDataPoints int) error {
+	provider, ok := datasource.AsTradeProvider(w.dataSource)
+	if !ok {
+		return fmt.Errorf("数据源 %s 不支持获取逐笔成交", w.dataSource.Name())
+	}
+
+	// 按交易所单次上限获取近期成交，与之前轮询累积的K线合并
+	trades, err := provider.GetRecentTrades(ctx, symbol, 0)
+	if err != nil {
+		return fmt.Errorf("获取逐笔成交失败: %w", err)
+	}
+
+	timeframe := spec.Timeframe()
+	series := w.barSeriesFor(symbol, spec, maxDataPoints*2)
+	added, gap := series.Add(trades)
+	if gap {
+		log.Printf("⚠️ [%s %s] 两次轮询之间成交超过单次获取上限，可能有成交遗漏", symbol, timeframe)
+	}
+
+	klines := w.filterKlines(series.Klines())
+	if len(klines) < maxDataPoints {
+		log.Printf("⏳ [%s %s] 累积K线中: %d/%d（本次新增 %d 笔成交）", symbol, timeframe, len(klines), maxDataPoints, added)
+		return fmt.Errorf("数据点不足: 需要 %d，实际 %d", maxDataPoints, len(klines))
+	}
+
+	marketData := &strategy.MarketData{
+		Symbol:    symbol,
+		Timeframe: timeframe,
+		Klines:    klines,
+		Timestamp: time.Now(),
+	}
+	// 永续合约统计按时间周期聚合，不适用于成交驱动的K线
+	w.loadOrderBook(ctx, marketData)
+
+	source := w.dataSource.Name()
+	if ds, ok := provider.(datasource.DataSource); ok {
+		source = ds.Name()
+	}
+	w.evaluateStrategies(marketData, source, nil)
+	return nil
+}
+
+// barSeriesFor 返回交易对在指定规格下的K线序列，首次使用时创建
+func (w *Watcher) barSeriesFor(symbol string, spec bars.Spec, limit int) *bars.Series {
+	w.barsMu.Lock()
+	defer w.barsMu.Unlock()
+
+	if w.barSeries == nil {
+		w.barSeries = make(map[string]*bars.Series)
+	}
+	key := symbol + "|" + spec.String()
+	series, ok := w.barSeries[key]
+	if !ok {
+		series = bars.NewSeries(symbol, spec, limit)
+		w.barSeries[key] = series
+	}
+	return series
+}
+
+// evaluateStrategies 对市场数据运行所有策略，记录触发的信号
+func (w *Watcher) evaluateStrategies(marketData *strategy.MarketData, dataSource string, issues []datasource.QualityIssue) {
+	symbol, timeframe, klines := marketData.Symbol, marketData.Timeframe, marketData.Klines
+
+	var liquidity *datasource.OrderBookSummary
+	if marketData.OrderBook != nil {
+		summary := marketData.OrderBook.Summary(w.depthRange)
+		liquidity = &summary
+	}
+	volume := summarizeVolume(klines)
+	candlePatterns := closedPatterns(klines)
 
 	for _, strat := range w.strategies {
 		result, err := strat.Evaluate(marketData)
@@ -241,7 +493,8 @@ func (w *Watcher) analyzeSymbol(ctx context.Context, symbol string, timeframe da
 				// 触发信号时，使用策略提供的消息
 				log.Printf("🚨 [%s %s] %s", symbol, timeframe, result.Message)
 				// 记录信号
-				w.recordSignal(symbol, timeframe, strat.Name(), result)
+				candleClosed := klines[len(klines)-1].IsClosed
+				w.recordSignal(symbol, timeframe, strat.Name(), dataSource, candleClosed, issues, liquidity, volume, candlePatterns, result)
 			} else {
 				// 正常状态，显示简化信息
 				if len(result.Message) > 0 {
@@ -250,12 +503,49 @@ func (w *Watcher) analyzeSymbol(ctx context.Context, symbol string, timeframe da
 			}
 		}
 	}
+}
 
-	return nil
+// derivativesHistory 每次获取的资金费率/持仓量/多空比数据点数
+const derivativesHistory = 100
+
+// loadDerivatives 获取永续合约数据填充到市场数据中，单项失败只记录日志
+func (w *Watcher) loadDerivatives(ctx context.Context, data *strategy.MarketData) {
+	if w.derivatives == nil {
+		return
+	}
+
+	var err error
+	if data.FundingRates, err = w.derivatives.GetFundingRates(ctx, data.Symbol, time.Time{}, time.Time{}, derivativesHistory); err != nil {
+		log.Printf("⚠️ [%s] 获取资金费率失败: %v", data.Symbol, err)
+	}
+	if data.OpenInterest, err = w.derivatives.GetOpenInterest(ctx, data.Symbol, data.Timeframe, time.Time{}, time.Time{}, derivativesHistory); err != nil {
+		log.Printf("⚠️ [%s] 获取持仓量失败: %v", data.Symbol, err)
+	}
+	if data.LongShortRatios, err = w.derivatives.GetLongShortRatio(ctx, data.Symbol, data.Timeframe, time.Time{}, time.Time{}, derivativesHistory); err != nil {
+		log.Printf("⚠️ [%s] 获取多空比失败: %v", data.Symbol, err)
+	}
+}
+
+// loadOrderBook 获取订单簿快照填充到市场数据中，数据源不支持或获取失败时保持为空
+func (w *Watcher) loadOrderBook(ctx context.Context, data *strategy.MarketData) {
+	if w.orderBookDepth <= 0 {
+		return
+	}
+	provider, ok := datasource.AsOrderBookProvider(w.dataSource)
+	if !ok {
+		return
+	}
+
+	book, err := provider.GetOrderBook(ctx, data.Symbol, w.orderBookDepth)
+	if err != nil {
+		log.Printf("⚠️ [%s] 获取订单簿失败: %v", data.Symbol, err)
+		return
+	}
+	data.OrderBook = book
 }
 
 // recordSignal 将信号添加到信号列表并检查是否发送报告
-func (w *Watcher) recordSignal(symbol string, timeframe datasource.Timeframe, strategyName string, result *strategy.StrategyResult) {
+func (w *Watcher) recordSignal(symbol string, timeframe datasource.Timeframe, strategyName, dataSource string, candleClosed bool, issues []datasource.QualityIssue, liquidity *datasource.OrderBookSummary, volume *VolumeSummary, candlePatterns []patterns.Match, result *strategy.StrategyResult) {
 	if w.emailNotifier == nil {
 		return
 	}
@@ -270,6 +560,12 @@ func (w *Watcher) recordSignal(symbol string, timeframe datasource.Timeframe, st
 		Signal:             result.Signal,
 		Strategy:           strategyName,
 		Timestamp:          time.Now(),
+		DataSource:         dataSource,
+		CandleClosed:       candleClosed,
+		DataIssues:         issues,
+		Liquidity:          liquidity,
+		Volume:             volume,
+		Patterns:           candlePatterns,
 		Message:            result.Message,
 		IndicatorSummary:   result.IndicatorSummary,
 		DetailedAnalysis:   result.DetailedAnalysis,
@@ -283,6 +579,142 @@ func (w *Watcher) recordSignal(symbol string, timeframe datasource.Timeframe, st
 		symbol, result.Signal.String(), result.IndicatorSummary)
 }
 
+// prepareKlines 按数据质量策略修复K线，再按 closed_candles_only 过滤
+func (w *Watcher) prepareKlines(klines []*datasource.Kline, timeframe datasource.Timeframe) ([]*datasource.Kline, []datasource.QualityIssue, error) {
+	repaired, issues, err := datasource.RepairKlines(klines, timeframe, w.repairPolicy)
+	if err != nil {
+		return nil, issues, err
+	}
+	return w.filterKlines(repaired), issues, nil
+}
+
+// summarizeLiquidity 格式化订单簿流动性摘要，用于报告
+func summarizeLiquidity(l *datasource.OrderBookSummary) string {
+	return fmt.Sprintf("价差 %.2f bps，±%.1f%% 深度 买 %s / 卖 %s，失衡 %+.0f%%",
+		l.SpreadBps, l.RangePercent, formatNotional(l.BidDepth), formatNotional(l.AskDepth), l.Imbalance*100)
+}
+
+// summarizeVolume 计算最新K线的成交量及其 z 分数，K线不足时返回 nil
+func summarizeVolume(klines []*datasource.Kline) *VolumeSummary {
+	volumes := make([]float64, len(klines))
+	for i, kline := range klines {
+		volumes[i] = kline.Volume
+	}
+
+	spike, err := indicators.CalculateDefaultVolumeSpike(volumes)
+	if err != nil {
+		return nil
+	}
+	return &VolumeSummary{
+		Volume: volumes[len(volumes)-1],
+		ZScore: spike.GetLatest(),
+	}
+}
+
+// formatVolume 格式化成交量概况，放量时附加标记，用于报告
+func formatVolume(v *VolumeSummary) string {
+	if v == nil {
+		return "-"
+	}
+
+	text := fmt.Sprintf("%s (z %+.1f)", formatNotional(v.Volume), v.ZScore)
+	if v.ZScore >= indicators.DefaultVolumeSpikeThreshold {
+		text += " 🔥放量"
+	}
+	return text
+}
+
+// closedPatterns 返回在最新已收盘K线上完成的形态
+// 未收盘K线的影线和实体仍在变化，在其上识别的形态收盘时可能已不成立，因此先去掉
+func closedPatterns(klines []*datasource.Kline) []patterns.Match {
+	if n := len(klines); n > 0 && !klines[n-1].IsClosed {
+		klines = klines[:n-1]
+	}
+	return patterns.Latest(klines)
+}
+
+// formatPatterns 格式化K线形态列表，标注方向和置信度，用于报告
+func formatPatterns(matches []patterns.Match) string {
+	parts := make([]string, len(matches))
+	for i, m := range matches {
+		parts[i] = fmt.Sprintf("%s（%s，置信度 %.0f%%）", m.Type, m.Direction, m.Confidence*100)
+	}
+	return strings.Join(parts, "、")
+}
+
+// formatNotional 以 K/M/B 缩写金额
+func formatNotional(v float64) string {
+	switch {
+	case v >= 1e9:
+		return fmt.Sprintf("%.2fB", v/1e9)
+	case v >= 1e6:
+		return fmt.Sprintf("%.2fM", v/1e6)
+	case v >= 1e3:
+		return fmt.Sprintf("%.2fK", v/1e3)
+	default:
+		return fmt.Sprintf("%.2f", v)
+	}
+}
+
+// summarizeIssues 汇总数据质量问题，用于日志和报告
+func summarizeIssues(issues []datasource.QualityIssue) string {
+	counts := make(map[datasource.IssueType]int)
+	for _, issue := range issues {
+		counts[issue.Type] += issue.Count
+	}
+
+	labels := []struct {
+		issueType datasource.IssueType
+		label     string
+	}{
+		{datasource.IssueGap, "缺失"},
+		{datasource.IssueDuplicate, "重复"},
+		{datasource.IssueZeroVolume, "零成交量"},
+		{datasource.IssueInvalid, "无效"},
+	}
+	var parts []string
+	for _, l := range labels {
+		if n := counts[l.issueType]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%s%d根", l.label, n))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// filterKlines 启用 closed_candles_only 时去掉尚未收盘的K线
+func (w *Watcher) filterKlines(klines []*datasource.Kline) []*datasource.Kline {
+	if !w.closedOnly {
+		return klines
+	}
+	return datasource.ClosedOnly(klines)
+}
+
+// repairPolicyName 返回当前数据修复策略名称
+func (w *Watcher) repairPolicyName() string {
+	if w.repairPolicy == "" {
+		return string(datasource.RepairForwardFill)
+	}
+	return string(w.repairPolicy)
+}
+
+// candleStatus 返回K线收盘状态的显示文本
+func candleStatus(closed bool) string {
+	if closed {
+		return "已收盘"
+	}
+	return "未收盘"
+}
+
+// servedBy 返回为指定交易对和时间框架提供数据的数据源名称
+func (w *Watcher) servedBy(symbol string, timeframe datasource.Timeframe) string {
+	if reporter, ok := w.dataSource.(datasource.SourceReporter); ok {
+		if name := reporter.ServedBy(symbol, timeframe); name != "" {
+			return name
+		}
+	}
+	return w.dataSource.Name()
+}
+
 // checkAndSendReport 检查并发送报告
 func (w *Watcher) checkAndSendReport() {
 	if w.emailNotifier == nil {
@@ -395,6 +827,7 @@ func (w *Watcher) createTradingReportNotification(reason string) *notifiers.Noti
 				<th style="padding: 12px 10px; text-align: left; font-weight: 600; color: #2c3e50; border-bottom: 2px solid #e5e5e5;">时间框架</th>
 				<th style="padding: 12px 10px; text-align: left; font-weight: 600; color: #2c3e50; border-bottom: 2px solid #e5e5e5;">信号类型</th>
 				<th style="padding: 12px 10px; text-align: left; font-weight: 600; color: #2c3e50; border-bottom: 2px solid #e5e5e5;">核心指标</th>
+				<th style="padding: 12px 10px; text-align: left; font-weight: 600; color: #2c3e50; border-bottom: 2px solid #e5e5e5;">成交量</th>
 				<th style="padding: 12px 10px; text-align: left; font-weight: 600; color: #2c3e50; border-bottom: 2px solid #e5e5e5;">触发时间</th>
 			</tr>
 		</thead>
@@ -449,8 +882,9 @@ func (w *Watcher) createTradingReportNotification(reason string) *notifiers.Noti
 					</span>
 				</td>
 				<td style="padding: 10px; font-family: monospace; color: %s; font-size: 12px;">%s</td>
+				<td style="padding: 10px; font-family: monospace; color: #666; font-size: 12px;">%s</td>
 				<td style="padding: 10px; color: #666; font-family: monospace; font-size: 12px;">%s</td>
-			</tr>`, i+1, signal.Symbol, timeframeDisplay, signalColor, signalIcon, signalText, signalColor, coreIndicator, signal.Timestamp.In(loc).Format("15:04:05")))
+			</tr>`, i+1, signal.Symbol, timeframeDisplay, signalColor, signalIcon, signalText, signalColor, coreIndicator, formatVolume(signal.Volume), signal.Timestamp.In(loc).Format("15:04:05")))
 		}
 
 		messageBuilder.WriteString(`</tbody></table></div></div>`)
@@ -515,13 +949,31 @@ func (w *Watcher) createTradingReportNotification(reason string) *notifiers.Noti
 				<div style="padding: 6px 12px; background: %s; color: white; border-radius: 16px; font-size: 13px; font-weight: 600;">%s %s</div>
 			</div>
 			<div style="font-size: 13px; color: #666; background: rgba(255,255,255,0.8); padding: 6px 10px; border-radius: 4px; display: inline-block;">
-				📈 %s | 🔍 %s | ⏰ %s
+				📈 %s | 🔍 %s | 🌐 %s | 🕯️ %s | ⏰ %s
 			</div>
-		</div>`, signalBgColor, i+1, signalColor, signalIcon, signal.Symbol, signalColor, signalText, signalEmoji, timeframeDisplay, signal.Strategy, signal.Timestamp.In(loc).Format("15:04:05")))
+		</div>`, signalBgColor, i+1, signalColor, signalIcon, signal.Symbol, signalColor, signalText, signalEmoji, timeframeDisplay, signal.Strategy, signal.DataSource, candleStatus(signal.CandleClosed), signal.Timestamp.In(loc).Format("15:04:05")))
 
 		// 信号内容区域 - 传统风格
 		messageBuilder.WriteString(`<div style="padding: 20px; background: #ffffff;">`)
 
+		// 数据质量提示
+		if len(signal.DataIssues) > 0 {
+			messageBuilder.WriteString(fmt.Sprintf(`<div style="margin-bottom: 15px; padding: 8px 12px; background: #fff8e1; border-left: 3px solid #f0ad4e; border-radius: 4px; font-size: 13px; color: #8a6d3b;">⚠️ 数据质量：%s（已按 %s 策略处理）</div>`,
+				summarizeIssues(signal.DataIssues), w.repairPolicyName()))
+		}
+
+		// 流动性提示
+		if signal.Liquidity != nil {
+			messageBuilder.WriteString(fmt.Sprintf(`<div style="margin-bottom: 15px; padding: 8px 12px; background: #e8f4fd; border-left: 3px solid #4a90e2; border-radius: 4px; font-size: 13px; color: #31708f;">💧 流动性：%s</div>`,
+				summarizeLiquidity(signal.Liquidity)))
+		}
+
+		// K线形态提示
+		if len(signal.Patterns) > 0 {
+			messageBuilder.WriteString(fmt.Sprintf(`<div style="margin-bottom: 15px; padding: 8px 12px; background: #f5f0fa; border-left: 3px solid #8e6bbf; border-radius: 4px; font-size: 13px; color: #5b4380;">🕯️ K线形态：%s</div>`,
+				formatPatterns(signal.Patterns)))
+		}
+
 		// 指标摘要 - 传统风格突出显示
 		messageBuilder.WriteString(fmt.Sprintf(`<div style="margin-bottom: 15px; padding: 15px; background: linear-gradient(135deg, rgba(74, 144, 226, 0.08) 0%%, rgba(53, 122, 189, 0.08) 100%%); border: 1px solid %s; border-radius: 6px; position: relative;">
 			<div style="position: absolute; top: -8px; left: 12px; background: white; padding: 0 8px; font-size: 11px; font-weight: 600; color: %s;">核心指标</div>
@@ -730,6 +1182,7 @@ func (w *Watcher) createTradingReportNotification(reason string) *notifiers.Noti
 			"indicator_summary": signal.IndicatorSummary,
 			"detailed_analysis": signal.DetailedAnalysis,
 			"strategy":          signal.Strategy,
+			"data_source":       signal.DataSource,
 			"timestamp":         signal.Timestamp,
 			"indicators":        signal.AllIndicators,
 			"thresholds":        signal.Thresholds,
@@ -824,6 +1277,14 @@ func (w *Watcher) RunSingleCheck(ctx context.Context, symbols []string, timefram
 			}
 			checkCount++
 		}
+		for _, spec := range w.barSpecs {
+			log.Printf("📊 分析 %s (%s)...", symbol, spec)
+			if err := w.analyzeBars(ctx, symbol, spec, maxDataPoints); err != nil {
+				log.Printf("❌ %s (%s): %v", symbol, spec, err)
+				continue
+			}
+			checkCount++
+		}
 	}
 
 	log.Printf("✅ 单次检查完成 - 成功检查了 %d 个组合", checkCount)
@@ -850,11 +1311,26 @@ func (w *Watcher) IsRunning() bool {
 
 // GetStatus 获取状态 (兼容接口)
 func (w *Watcher) GetStatus() map[string]interface{} {
-	return map[string]interface{}{
+	status := map[string]interface{}{
 		"running":     true,
 		"data_source": w.dataSource.Name(),
 		"strategies":  len(w.strategies),
 	}
+
+	if failover, ok := w.dataSource.(*datasource.FailoverDataSource); ok {
+		status["active_source"] = failover.ActiveSource()
+		status["source_health"] = failover.Health()
+	}
+
+	if cacheStats := datasource.CollectCacheStats(w.dataSource); len(cacheStats) > 0 {
+		status["cache_stats"] = cacheStats
+	}
+
+	if breakers := datasource.CircuitBreakerStates(); len(breakers) > 0 {
+		status["circuit_breakers"] = breakers
+	}
+
+	return status
 }
 
 // collectMultiTimeframeData 收集指定交易对在所有时间框架的数据
@@ -964,6 +1440,19 @@ func (w *Watcher) collectMultiTimeframeData(symbol string, signalTimeframe strin
 			continue
 		}
 
+		klines, _, err = w.prepareKlines(klines, tf)
+		if err != nil {
+			multiData[tfStr] = TimeframeData{
+				Timeframe:        timeframeDisplay,
+				Indicators:       make(map[string]interface{}),
+				IndicatorSummary: "数据质量检查失败",
+				DetailedAnalysis: err.Error(),
+				HasSignal:        false,
+				SignalType:       strategy.SignalNone,
+			}
+			continue
+		}
+
 		// 使用与主逻辑相同的数据充足性检查
 		if len(klines) < maxDataPoints {
 			// 数据不足，记录详细信息
@@ -1051,16 +1540,37 @@ func (w *Watcher) isCrossRatePair(symbol string) bool {
 		}
 	}
 
-	// 对于其他交易对，尝试直接获取少量数据来判断是否为真实交易对
-	log.Printf("🔍 [%s] 不包含稳定币后缀，尝试获取数据验证", symbol)
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second) // 增加到30秒
 	defer cancel()
 
+	// 数据源提供交易对元数据时，以交易所列出的交易对为准
+	if provider, ok := datasource.AsSymbolInfoProvider(w.dataSource); ok {
+		info, err := provider.SymbolInfo(ctx, symbol)
+		switch {
+		case err == nil:
+			log.Printf("✅ [%s] 交易所存在该交易对（%s），判定为直接交易对", symbol, info.Status)
+			return false
+		case errors.Is(err, datasource.ErrSymbolNotFound) && !datasource.IsTransient(err):
+			log.Printf("🔍 [%s] 交易所不存在该交易对，判定为交叉汇率对", symbol)
+			return true
+		default:
+			log.Printf("⚠️ [%s] 获取交易对元数据失败，改为获取数据验证: %v", symbol, err)
+		}
+	}
+
+	// 对于其他交易对，尝试直接获取少量数据来判断是否为真实交易对
+	log.Printf("🔍 [%s] 不包含稳定币后缀，尝试获取数据验证", symbol)
+
 	// 尝试获取最近1小时的1个数据点来验证交易对是否存在
 	endTime := time.Now()
 	startTime := endTime.Add(-time.Hour)
 
 	_, err := w.dataSource.GetKlines(ctx, symbol, datasource.Timeframe1h, startTime, endTime, 1)
+	if err != nil && datasource.IsTransient(err) && !errors.Is(err, datasource.ErrSymbolNotFound) {
+		// 暂时性错误无法说明交易对是否存在，按直接交易对处理
+		log.Printf("⚠️ [%s] 数据源暂时不可用，无法判断是否为交叉汇率对: %v", symbol, err)
+		return false
+	}
 	if err != nil {
 		// 如果直接获取失败，则认为是交叉汇率对，需要通过计算获得
 		log.Printf("🔍 [%s] 直接获取失败，判定为交叉汇率对: %v", symbol, err)
@@ -1075,46 +1585,26 @@ func (w *Watcher) isCrossRatePair(symbol string) bool {
 // getCrossRateKlines 获取交叉汇率对的K线数据
 func (w *Watcher) getCrossRateKlines(ctx context.Context, symbol string, timeframe datasource.Timeframe, startTime, endTime time.Time, limit int) ([]*datasource.Kline, error) {
 	// 解析交叉汇率对的基础货币和报价货币
-	baseSymbol, quoteSymbol, err := w.parseCrossRatePair(symbol)
+	baseSymbol, quoteSymbol, err := w.parseCrossRatePair(ctx, symbol)
 	if err != nil {
 		return nil, fmt.Errorf("解析交叉汇率对失败: %w", err)
 	}
 
-	// 使用USDT作为桥接货币
-	bridgeCurrency := "USD"
+	// 与资产验证使用同一基准货币桥接，由数据源映射为各交易所的原生交易对
+	bridgeCurrency := w.bridgeCurrency
+	if bridgeCurrency == "" {
+		bridgeCurrency = config.DefaultConfig().Assets.BaseCurrency
+	}
 
-	// 调用assets包的CalculateRate方法
 	return w.rateCalculator.CalculateRate(ctx, baseSymbol, quoteSymbol, bridgeCurrency, timeframe, startTime, endTime, limit)
 }
 
 // parseCrossRatePair 解析交叉汇率对，返回基础货币和报价货币
-func (w *Watcher) parseCrossRatePair(symbol string) (baseSymbol, quoteSymbol string, err error) {
-	// 常见的加密货币符号，按市值排序（作为可能的分割点）
-	knownSymbols := []string{"BTC", "ETH", "BNB", "ADA", "SOL", "DOT", "MATIC", "AVAX", "LINK", "UNI"}
-
-	// 尝试从后往前匹配已知符号作为报价货币
-	for _, quote := range knownSymbols {
-		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
-			baseSymbol = symbol[:len(symbol)-len(quote)]
-			quoteSymbol = quote
-
-			// 验证基础货币也是已知符号
-			for _, base := range knownSymbols {
-				if baseSymbol == base {
-					return baseSymbol, quoteSymbol, nil
-				}
-			}
-		}
-	}
-
-	// 如果无法解析，尝试常见的3-3或4-3分割
-	if len(symbol) == 6 {
-		// 3-3分割，如ETHBTC
-		return symbol[:3], symbol[3:], nil
-	} else if len(symbol) == 7 {
-		// 可能是4-3分割，如LINKBTC
-		return symbol[:4], symbol[4:], nil
+// 币种拆分以数据源的交易对元数据为准（见 datasource.ParseSymbol）
+func (w *Watcher) parseCrossRatePair(ctx context.Context, symbol string) (baseSymbol, quoteSymbol string, err error) {
+	baseSymbol, quoteSymbol, err = datasource.ParseSymbol(ctx, w.dataSource, symbol)
+	if err != nil {
+		return "", "", fmt.Errorf("无法解析交叉汇率对 %s: %w", symbol, err)
 	}
-
-	return "", "", fmt.Errorf("无法解析交叉汇率对: %s", symbol)
+	return baseSymbol, quoteSymbol, nil
 }
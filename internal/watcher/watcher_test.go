@@ -2,10 +2,18 @@ package watcher
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	"ta-watcher/internal/assets"
+	"ta-watcher/internal/bars"
 	"ta-watcher/internal/config"
+	"ta-watcher/internal/datasource"
+	"ta-watcher/internal/notifiers"
+	"ta-watcher/internal/patterns"
+	"ta-watcher/internal/strategy"
 )
 
 func TestNew(t *testing.T) {
@@ -101,3 +109,576 @@ func TestWatcher_ContextCancellation(t *testing.T) {
 		t.Errorf("Expected context deadline exceeded, got: %v", err)
 	}
 }
+
+func TestWatcher_WatchStreamUnsupported(t *testing.T) {
+	cfg := &config.Config{
+		DataSource: config.DataSourceConfig{
+			Primary: "file",
+			File:    config.FileConfig{Directory: t.TempDir()},
+		},
+	}
+
+	w, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if err := w.WatchStream(context.Background(), []string{"BTCUSDT"}, "1h"); err == nil {
+		t.Error("WatchStream() should fail for data source without streaming support")
+	}
+}
+
+func TestWatcher_FilterKlines(t *testing.T) {
+	now := time.Now()
+	klines := []*datasource.Kline{
+		{OpenTime: now.Add(-2 * time.Hour), CloseTime: now.Add(-time.Hour), IsClosed: true},
+		{OpenTime: now.Add(-time.Hour), CloseTime: now.Add(time.Hour), IsClosed: false},
+	}
+
+	w := &Watcher{closedOnly: true}
+	if got := w.filterKlines(klines); len(got) != 1 || !got[0].IsClosed {
+		t.Errorf("closed_candles_only should drop forming kline, got %d klines", len(got))
+	}
+
+	w.closedOnly = false
+	if got := w.filterKlines(klines); len(got) != 2 {
+		t.Errorf("all klines should be kept when closed_candles_only is disabled, got %d", len(got))
+	}
+}
+
+func TestWatcher_PrepareKlines(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	klines := []*datasource.Kline{
+		{OpenTime: base, CloseTime: base.Add(time.Hour - time.Millisecond), Open: 1, High: 1, Low: 1, Close: 1, Volume: 1, IsClosed: true},
+		{OpenTime: base.Add(2 * time.Hour), CloseTime: base.Add(3*time.Hour - time.Millisecond), Open: 2, High: 2, Low: 2, Close: 2, Volume: 1, IsClosed: true},
+	}
+
+	w := &Watcher{repairPolicy: datasource.RepairForwardFill}
+	got, issues, err := w.prepareKlines(klines, datasource.Timeframe1h)
+	if err != nil || len(got) != 3 {
+		t.Fatalf("forward fill should close the gap, got %d klines, err %v", len(got), err)
+	}
+	if summary := summarizeIssues(issues); summary != "缺失1根" {
+		t.Errorf("summarizeIssues() = %q", summary)
+	}
+
+	w.repairPolicy = datasource.RepairFail
+	if _, _, err := w.prepareKlines(klines, datasource.Timeframe1h); err == nil {
+		t.Error("fail policy should reject klines with gaps")
+	}
+}
+
+// failingDataSource 总是返回指定错误的数据源，记录调用次数
+type failingDataSource struct {
+	err   error
+	calls int
+}
+
+func (f *failingDataSource) Name() string { return "failing" }
+
+func (f *failingDataSource) GetKlines(ctx context.Context, symbol string, timeframe datasource.Timeframe, startTime, endTime time.Time, limit int) ([]*datasource.Kline, error) {
+	f.calls++
+	return nil, f.err
+}
+
+func (f *failingDataSource) IsSymbolValid(ctx context.Context, symbol string) (bool, error) {
+	return false, f.err
+}
+
+func TestWatcher_AnalyzeSymbolTransientError(t *testing.T) {
+	ds := &failingDataSource{err: fmt.Errorf("binance API returned status: 503: %w", datasource.ErrUnavailable)}
+	w := &Watcher{dataSource: ds}
+
+	// ETHBTC 不含稳定币后缀，非暂时性错误时会额外探测是否为交叉汇率对
+	if err := w.analyzeSymbol(context.Background(), "ETHBTC", datasource.Timeframe1h, 10); err == nil {
+		t.Fatal("analyzeSymbol() should fail when data source is unavailable")
+	}
+	if ds.calls != 1 {
+		t.Errorf("transient error should not trigger cross rate probing, got %d calls", ds.calls)
+	}
+}
+
+// symbolInfoSource 提供交易对元数据的测试数据源
+type symbolInfoSource struct {
+	failingDataSource
+	infos []*datasource.SymbolInfo
+}
+
+func (s *symbolInfoSource) SymbolInfo(ctx context.Context, symbol string) (*datasource.SymbolInfo, error) {
+	for _, info := range s.infos {
+		if info.Symbol == symbol {
+			return info, nil
+		}
+	}
+	return nil, datasource.ErrSymbolNotFound
+}
+
+func (s *symbolInfoSource) Symbols(ctx context.Context) ([]*datasource.SymbolInfo, error) {
+	return s.infos, nil
+}
+
+func TestWatcher_CrossRatePairFromSymbolInfo(t *testing.T) {
+	ds := &symbolInfoSource{
+		failingDataSource: failingDataSource{err: datasource.ErrSymbolNotFound},
+		infos: []*datasource.SymbolInfo{
+			{Symbol: "ETHBTC", Base: "ETH", Quote: "BTC"},
+			{Symbol: "LINKUSDT", Base: "LINK", Quote: "USDT"},
+		},
+	}
+	w := &Watcher{dataSource: ds}
+
+	if w.isCrossRatePair("ETHBTC") {
+		t.Error("ETHBTC is listed on the exchange and should be a direct pair")
+	}
+	if !w.isCrossRatePair("LINKETH") {
+		t.Error("LINKETH is not listed and should be a cross rate pair")
+	}
+	if ds.calls != 0 {
+		t.Errorf("symbol info should avoid probing klines, got %d calls", ds.calls)
+	}
+
+	base, quote, err := w.parseCrossRatePair(context.Background(), "LINKETH")
+	if err != nil || base != "LINK" || quote != "ETH" {
+		t.Errorf("parseCrossRatePair() = %s/%s, err %v", base, quote, err)
+	}
+}
+
+// stubDerivatives 返回固定数据的衍生品数据源，持仓量接口失败
+type stubDerivatives struct{}
+
+func (stubDerivatives) Name() string { return "stub_futures" }
+
+func (stubDerivatives) GetFundingRates(ctx context.Context, symbol string, startTime, endTime time.Time, limit int) ([]*datasource.FundingRate, error) {
+	return []*datasource.FundingRate{{Symbol: symbol, Rate: 0.0003}}, nil
+}
+
+func (stubDerivatives) GetOpenInterest(ctx context.Context, symbol string, period datasource.Timeframe, startTime, endTime time.Time, limit int) ([]*datasource.OpenInterest, error) {
+	return nil, datasource.ErrUnavailable
+}
+
+func (stubDerivatives) GetLongShortRatio(ctx context.Context, symbol string, period datasource.Timeframe, startTime, endTime time.Time, limit int) ([]*datasource.LongShortRatio, error) {
+	return []*datasource.LongShortRatio{{Symbol: symbol, Ratio: 1.2}}, nil
+}
+
+func TestWatcher_LoadDerivatives(t *testing.T) {
+	data := &strategy.MarketData{Symbol: "BTCUSDT", Timeframe: datasource.Timeframe1h}
+
+	// 未配置衍生品数据源时不填充
+	(&Watcher{}).loadDerivatives(context.Background(), data)
+	if data.FundingRates != nil {
+		t.Fatal("derivatives should be empty without a derivatives data source")
+	}
+
+	w := &Watcher{derivatives: stubDerivatives{}}
+	w.loadDerivatives(context.Background(), data)
+	if len(data.FundingRates) != 1 || data.FundingRates[0].Rate != 0.0003 {
+		t.Errorf("FundingRates = %v", data.FundingRates)
+	}
+	if data.OpenInterest != nil {
+		t.Errorf("failed open interest request should leave series empty, got %v", data.OpenInterest)
+	}
+	if len(data.LongShortRatios) != 1 {
+		t.Errorf("LongShortRatios = %v", data.LongShortRatios)
+	}
+
+	ctx := strategy.NewIndicatorContext(data)
+	if rates := ctx.FundingRates(); len(rates) != 1 || rates[0] != 0.0003 {
+		t.Errorf("IndicatorContext.FundingRates() = %v", rates)
+	}
+}
+
+// orderBookSource 支持订单簿的数据源
+type orderBookSource struct {
+	failingDataSource
+	depth int
+}
+
+func (s *orderBookSource) GetOrderBook(ctx context.Context, symbol string, depth int) (*datasource.OrderBook, error) {
+	s.depth = depth
+	bids := []datasource.PriceLevel{{Price: 99, Quantity: 3}, {Price: 100, Quantity: 1}}
+	asks := []datasource.PriceLevel{{Price: 101, Quantity: 1}}
+	return datasource.NewOrderBook(symbol, time.Now(), bids, asks, depth), nil
+}
+
+func TestWatcher_LoadOrderBook(t *testing.T) {
+	source := &orderBookSource{}
+	data := &strategy.MarketData{Symbol: "BTCUSDT", Timeframe: datasource.Timeframe1h}
+
+	// 未启用时不获取
+	(&Watcher{dataSource: source}).loadOrderBook(context.Background(), data)
+	if data.OrderBook != nil || source.depth != 0 {
+		t.Fatal("order book should not be fetched when order_book_depth is 0")
+	}
+
+	w := &Watcher{dataSource: datasource.NewFailoverDataSource(source, &failingDataSource{}, time.Minute), orderBookDepth: 20, depthRange: 2}
+	w.loadOrderBook(context.Background(), data)
+	if data.OrderBook == nil || source.depth != 20 {
+		t.Fatalf("order book should be fetched through wrapped source, depth %d", source.depth)
+	}
+
+	if imbalance, ok := strategy.NewIndicatorContext(data).DepthImbalance(2); !ok || imbalance <= 0 {
+		t.Errorf("DepthImbalance() = %v, %v, want positive", imbalance, ok)
+	}
+
+	summary := data.OrderBook.Summary(w.depthRange)
+	if got := summarizeLiquidity(&summary); got != "价差 99.50 bps，±2.0% 深度 买 397.00 / 卖 101.00，失衡 +59%" {
+		t.Errorf("summarizeLiquidity() = %q", got)
+	}
+}
+
+// klineSource 返回固定K线的数据源
+type klineSource struct {
+	failingDataSource
+	name   string
+	klines []*datasource.Kline
+}
+
+func (k *klineSource) Name() string { return k.name }
+
+func (k *klineSource) GetKlines(ctx context.Context, symbol string, timeframe datasource.Timeframe, startTime, endTime time.Time, limit int) ([]*datasource.Kline, error) {
+	return k.klines, nil
+}
+
+// recordingNotifier 记录收到的通知
+type recordingNotifier struct {
+	sent []*notifiers.Notification
+}
+
+func (r *recordingNotifier) Send(n *notifiers.Notification) error {
+	r.sent = append(r.sent, n)
+	return nil
+}
+func (r *recordingNotifier) Close() error    { return nil }
+func (r *recordingNotifier) IsEnabled() bool { return true }
+func (r *recordingNotifier) Name() string    { return "recording" }
+
+func TestWatcher_DivergenceAlert(t *testing.T) {
+	start := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	kline := func(close float64) []*datasource.Kline {
+		return []*datasource.Kline{{Symbol: "BTCUSDT", OpenTime: start, Open: close, High: close, Low: close, Close: close, IsClosed: true}}
+	}
+	consensus := datasource.NewConsensusDataSource([]datasource.DataSource{
+		&klineSource{name: "binance", klines: kline(100)},
+		&klineSource{name: "coinbase", klines: kline(100.1)},
+		&klineSource{name: "okx", klines: kline(120)},
+	}, 50)
+
+	recorder := &recordingNotifier{}
+	manager := notifiers.NewManager()
+	manager.AddNotifier(recorder)
+
+	w := &Watcher{dataSource: consensus, notifierManager: manager}
+	if reporter, ok := datasource.AsDivergenceReporter(w.dataSource); ok {
+		reporter.SetDivergenceHandler(w.alertDivergence)
+	}
+
+	if _, err := consensus.GetKlines(context.Background(), "BTCUSDT", datasource.Timeframe1h, time.Time{}, time.Time{}, 10); err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+	if len(recorder.sent) != 1 {
+		t.Fatalf("expected 1 system alert, got %d", len(recorder.sent))
+	}
+	n := recorder.sent[0]
+	if n.Type != notifiers.TypeSystemAlert || n.Asset != "BTCUSDT" || n.Data["source"] != "okx" {
+		t.Errorf("unexpected notification: %+v", n)
+	}
+}
+
+// pairSource 只提供指定交易对K线的数据源，记录请求过的交易对
+type pairSource struct {
+	failingDataSource
+	klines    map[string][]*datasource.Kline
+	requested []string
+}
+
+func (p *pairSource) GetKlines(ctx context.Context, symbol string, timeframe datasource.Timeframe, startTime, endTime time.Time, limit int) ([]*datasource.Kline, error) {
+	p.requested = append(p.requested, symbol)
+	if klines, ok := p.klines[symbol]; ok {
+		return klines, nil
+	}
+	return nil, datasource.ErrSymbolNotFound
+}
+
+func TestWatcher_CrossRateUsesBaseCurrency(t *testing.T) {
+	start := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	kline := func(symbol string, close float64) []*datasource.Kline {
+		return []*datasource.Kline{{Symbol: symbol, OpenTime: start, Open: close, High: close, Low: close, Close: close}}
+	}
+	ds := &pairSource{klines: map[string][]*datasource.Kline{
+		"ETHUSDT": kline("ETHUSDT", 4000),
+		"BTCUSDT": kline("BTCUSDT", 100000),
+	}}
+
+	cfg := config.DefaultConfig()
+	w := &Watcher{dataSource: ds, rateCalculator: assets.NewRateCalculator(ds), bridgeCurrency: cfg.Assets.BaseCurrency}
+
+	klines, err := w.getCrossRateKlines(context.Background(), "ETHBTC", datasource.Timeframe1h, start, start.Add(time.Hour), 10)
+	if err != nil {
+		t.Fatalf("getCrossRateKlines() error = %v (requested %v)", err, ds.requested)
+	}
+	if len(klines) != 1 || klines[0].Close != 0.04 {
+		t.Errorf("unexpected cross rate klines: %+v", klines)
+	}
+	if want := []string{"ETHUSDT", "BTCUSDT"}; len(ds.requested) != 2 || ds.requested[0] != want[0] || ds.requested[1] != want[1] {
+		t.Errorf("requested pairs = %v, want %v", ds.requested, want)
+	}
+}
+
+func TestWatcher_CrossRateRepairModes(t *testing.T) {
+	start := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	series := func(symbol string, price float64) []*datasource.Kline {
+		klines := make([]*datasource.Kline, 20)
+		for i := range klines {
+			open := start.Add(time.Duration(i) * time.Hour)
+			p := price * (1 + float64(i)*0.001)
+			klines[i] = &datasource.Kline{
+				Symbol: symbol, OpenTime: open, CloseTime: open.Add(time.Hour - time.Millisecond),
+				Open: p, High: p * 1.01, Low: p * 0.99, Close: p, Volume: 10, IsClosed: true,
+			}
+		}
+		return klines
+	}
+
+	for _, policy := range []datasource.RepairPolicy{datasource.RepairForwardFill, datasource.RepairDrop, datasource.RepairFail} {
+		t.Run(string(policy), func(t *testing.T) {
+			ds := &pairSource{klines: map[string][]*datasource.Kline{
+				"ETHUSDT": series("ETHUSDT", 4000),
+				"BTCUSDT": series("BTCUSDT", 100000),
+			}}
+			capture := &capturingStrategy{}
+			w := &Watcher{
+				dataSource:     ds,
+				rateCalculator: assets.NewRateCalculator(ds),
+				bridgeCurrency: "USDT",
+				repairPolicy:   policy,
+				strategies:     []strategy.Strategy{capture},
+			}
+
+			// 交叉汇率K线没有成交量，不应被当作零成交量问题丢弃或拒绝
+			if err := w.analyzeSymbol(context.Background(), "ETHBTC", datasource.Timeframe1h, 10); err != nil {
+				t.Fatalf("analyzeSymbol() error = %v", err)
+			}
+			if capture.data == nil || len(capture.data.Klines) != 20 {
+				t.Fatalf("strategy should receive all 20 cross rate klines, got %+v", capture.data)
+			}
+
+			klines, err := w.getCrossRateKlines(context.Background(), "ETHBTC", datasource.Timeframe1h, start, start.Add(20*time.Hour), 40)
+			if err != nil {
+				t.Fatalf("getCrossRateKlines() error = %v", err)
+			}
+			if _, issues, err := w.prepareKlines(klines, datasource.Timeframe1h); err != nil || len(issues) != 0 {
+				t.Errorf("cross rate klines should have no quality issues, got %s (err %v)", summarizeIssues(issues), err)
+			}
+		})
+	}
+}
+
+// tradeSource 提供逐笔成交的数据源
+type tradeSource struct {
+	failingDataSource
+	trades []*datasource.Trade
+}
+
+func (s *tradeSource) GetRecentTrades(ctx context.Context, symbol string, limit int) ([]*datasource.Trade, error) {
+	return s.trades, nil
+}
+
+// capturingStrategy 记录收到的市场数据
+type capturingStrategy struct {
+	data *strategy.MarketData
+}
+
+func (c *capturingStrategy) Name() string                                { return "capture" }
+func (c *capturingStrategy) Description() string                         { return "records market data" }
+func (c *capturingStrategy) RequiredDataPoints() int                     { return 3 }
+func (c *capturingStrategy) SupportedTimeframes() []datasource.Timeframe { return nil }
+func (c *capturingStrategy) Evaluate(data *strategy.MarketData) (*strategy.StrategyResult, error) {
+	c.data = data
+	return &strategy.StrategyResult{Signal: strategy.SignalNone}, nil
+}
+
+func TestWatcher_AnalyzeBars(t *testing.T) {
+	start := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	trades := make([]*datasource.Trade, 0)
+	for i := 0; i < 7; i++ {
+		trades = append(trades, &datasource.Trade{Symbol: "BTCUSDT", Time: start.Add(time.Duration(i) * time.Second), Price: 100 + float64(i), Quantity: 5})
+	}
+	spec, err := bars.ParseSpec("volume:10")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	capture := &capturingStrategy{}
+	w := &Watcher{dataSource: &tradeSource{trades: trades}, strategies: []strategy.Strategy{capture}, closedOnly: true}
+	if err := w.analyzeBars(context.Background(), "BTCUSDT", spec, 3); err != nil {
+		t.Fatalf("analyzeBars() error = %v", err)
+	}
+
+	// 35 个单位构成 3 根已完成的成交量K线，未完成的第4根被 closed_candles_only 过滤
+	if capture.data == nil || capture.data.Timeframe != "volume:10" || len(capture.data.Klines) != 3 {
+		t.Fatalf("strategy should receive 3 volume bars, got %+v", capture.data)
+	}
+	if k := capture.data.Klines[1]; k.Open != 102 || k.Close != 103 || k.Volume != 10 {
+		t.Errorf("unexpected volume bar %+v", k)
+	}
+
+	if err := w.analyzeBars(context.Background(), "BTCUSDT", spec, 4); err == nil {
+		t.Error("analyzeBars() should fail when there are not enough bars")
+	}
+	if err := (&Watcher{dataSource: &failingDataSource{}}).analyzeBars(context.Background(), "BTCUSDT", spec, 3); err == nil {
+		t.Error("analyzeBars() should fail when the data source has no trades")
+	}
+}
+
+// requiringStrategy 需要指定数据点数的记录策略
+type requiringStrategy struct {
+	capturingStrategy
+	required int
+}
+
+func (r *requiringStrategy) RequiredDataPoints() int { return r.required }
+
+func TestWatcher_AnalyzeBarsAccumulatesAcrossPolls(t *testing.T) {
+	start := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	history := make([]*datasource.Trade, 0, 6000)
+	for i := 0; i < 6000; i++ {
+		history = append(history, &datasource.Trade{
+			Symbol:   "BTCUSDT",
+			ID:       fmt.Sprint(i),
+			Time:     start.Add(time.Duration(i) * time.Second),
+			Price:    100 + float64(i%50),
+			Quantity: 1,
+		})
+	}
+	spec, err := bars.ParseSpec("volume:100")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	strat := &requiringStrategy{required: 50}
+	source := &tradeSource{}
+	w := &Watcher{dataSource: source, strategies: []strategy.Strategy{strat}, closedOnly: true}
+	maxDataPoints := w.maxDataPoints()
+
+	// 每次轮询返回最近1000笔成交（与上次重叠100笔），单次只够生成10根成交量K线
+	polls := 0
+	for end := 1000; end <= len(history); end += 900 {
+		source.trades = history[end-1000 : end]
+		err := w.analyzeBars(context.Background(), "BTCUSDT", spec, maxDataPoints)
+		polls++
+		if err == nil {
+			break
+		}
+		if strat.data != nil {
+			t.Fatalf("strategy should not run before %d bars are available", maxDataPoints)
+		}
+	}
+
+	if strat.data == nil {
+		t.Fatalf("volume bar strategy never evaluated after %d polls", polls)
+	}
+	if polls < 2 {
+		t.Errorf("a single poll should not produce %d volume bars", maxDataPoints)
+	}
+	// 重叠的成交不能重复计入
+	klines := strat.data.Klines
+	if len(klines) < maxDataPoints {
+		t.Fatalf("strategy received %d bars, want at least %d", len(klines), maxDataPoints)
+	}
+	for i, k := range klines {
+		if k.Volume != 100 || !k.OpenTime.Equal(start.Add(time.Duration(i*100)*time.Second)) {
+			t.Fatalf("bar %d should cover trades %d-%d exactly once, got %+v", i, i*100, i*100+99, k)
+		}
+	}
+}
+
+func TestNew_InvalidBarSpec(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Watcher.Bars = []string{"volume:100", "tick:5"}
+	if _, err := New(cfg); err == nil {
+		t.Error("New() should reject an invalid bar spec")
+	}
+}
+
+func TestWatcher_VolumeSummary(t *testing.T) {
+	klines := make([]*datasource.Kline, 25)
+	for i := range klines {
+		klines[i] = &datasource.Kline{Close: 100, Volume: 1000 + float64(i%2)*100}
+	}
+	if summarizeVolume(klines[:20]) != nil {
+		t.Fatal("summarizeVolume() should be nil without enough klines")
+	}
+	if got := formatVolume(nil); got != "-" {
+		t.Errorf("formatVolume(nil) = %q", got)
+	}
+
+	klines[24].Volume = 5000
+	volume := summarizeVolume(klines)
+	if volume == nil || volume.Volume != 5000 || volume.ZScore < 2 {
+		t.Fatalf("summarizeVolume() = %+v, want spike", volume)
+	}
+	if got := formatVolume(volume); !strings.Contains(got, "5.00K") || !strings.Contains(got, "放量") {
+		t.Errorf("formatVolume() = %q", got)
+	}
+
+	w := &Watcher{signals: []SignalInfo{{
+		Symbol:    "BTCUSDT",
+		Timeframe: "1h",
+		Signal:    strategy.SignalBuy,
+		Timestamp: time.Now(),
+		Volume:    volume,
+	}}}
+	report := w.createTradingReportNotification("测试")
+	if !strings.Contains(report.Message, "成交量") || !strings.Contains(report.Message, formatVolume(volume)) {
+		t.Error("report should contain the volume column")
+	}
+}
+
+func TestClosedPatterns_SkipsFormingCandle(t *testing.T) {
+	start := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	klines := make([]*datasource.Kline, 0, 6)
+	for i := 0; i < 5; i++ {
+		open := 100 + float64(i)*5
+		klines = append(klines, &datasource.Kline{OpenTime: start.Add(time.Duration(i) * time.Hour), Open: open, High: open + 5.5, Low: open - 0.5, Close: open + 5, IsClosed: true})
+	}
+	// 未收盘的十字星
+	doji := &datasource.Kline{OpenTime: start.Add(5 * time.Hour), Open: 125, High: 127, Low: 123, Close: 125}
+	klines = append(klines, doji)
+
+	for _, m := range closedPatterns(klines) {
+		if m.Index == len(klines)-1 {
+			t.Errorf("patterns on the forming candle should be skipped, got %+v", m)
+		}
+	}
+
+	doji.IsClosed = true
+	found := false
+	for _, m := range closedPatterns(klines) {
+		found = found || (m.Type == patterns.Doji && m.Index == len(klines)-1)
+	}
+	if !found {
+		t.Error("doji should be detected once the candle has closed")
+	}
+}
+
+func TestWatcher_CandlePatternsInReport(t *testing.T) {
+	matches := []patterns.Match{
+		{Type: patterns.BullishEngulfing, Start: 8, Index: 9, Direction: patterns.Bullish, Confidence: 0.85},
+		{Type: patterns.InsideBar, Start: 8, Index: 9, Direction: patterns.Neutral, Confidence: 0.6},
+	}
+	if got := formatPatterns(matches); got != "看涨吞没（看涨，置信度 85%）、内包线（中性，置信度 60%）" {
+		t.Errorf("formatPatterns() = %q", got)
+	}
+
+	w := &Watcher{signals: []SignalInfo{
+		{Symbol: "BTCUSDT", Timeframe: "1h", Signal: strategy.SignalBuy, Timestamp: time.Now(), Patterns: matches},
+		{Symbol: "ETHUSDT", Timeframe: "1h", Signal: strategy.SignalSell, Timestamp: time.Now()},
+	}}
+	report := w.createTradingReportNotification("测试")
+	if !strings.Contains(report.Message, "K线形态："+formatPatterns(matches)) {
+		t.Error("report should list the detected candle patterns")
+	}
+	if strings.Count(report.Message, "K线形态：") != 1 {
+		t.Error("signals without patterns should not render the pattern box")
+	}
+}